@@ -0,0 +1,328 @@
+// Package bundler uploads signed ANS-104 data items to bundler services.
+//
+// Bundlers such as Turbo, Irys and Liteseed accept data items over HTTP,
+// bundle them and post the bundles to Arweave. They share the shape of
+// their API but not its routes: a Service describes the routes of one
+// bundler, and Turbo, Irys and Liteseed return the services of the public
+// ones. A Client posts data items to a service and reads the price of an
+// upload and the balance of an account.
+//
+// Turbo and Irys answer an upload with a receipt signed by the bundler,
+// committing it to include the item before a deadline height. Set
+// VerifyReceipts to reject receipts whose signature does not verify.
+//
+// Example usage:
+//
+//	b := bundler.New(bundler.Turbo())
+//	b.VerifyReceipts = true
+//	receipt, err := b.PostDataItem(item)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%s is stored before block %d\n", receipt.ID, receipt.DeadlineHeight)
+package bundler
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/liteseed"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// URLs of public bundlers
+const (
+	TURBO_URL         = "https://upload.ardrive.io"  // Turbo upload service
+	TURBO_PAYMENT_URL = "https://payment.ardrive.io" // Turbo payment service, answering price and balance requests
+	IRYS_URL          = "https://node1.irys.xyz"     // Irys node accepting data items paid in AR
+)
+
+// RECEIPT_PREFIX is the first deep hash element of signed receipts
+const RECEIPT_PREFIX = "Bundlr"
+
+// Service describes the HTTP API of a bundler.
+//
+// Routes are relative to URL, unless they are absolute URLs themselves.
+// {size} in PriceRoute is replaced with the size in bytes and {address} in
+// BalanceRoute with the address of the account.
+type Service struct {
+	URL          string // Base URL of the bundler
+	UploadRoute  string // Route data items are posted to
+	PriceRoute   string // Route of the price of {size} bytes
+	BalanceRoute string // Route of the balance of {address}
+}
+
+// Turbo returns the service of the Turbo bundler; prices and balances are in winc, Turbo credits.
+func Turbo() Service {
+	return Service{
+		URL:          TURBO_URL,
+		UploadRoute:  "v1/tx",
+		PriceRoute:   TURBO_PAYMENT_URL + "/v1/price/bytes/{size}",
+		BalanceRoute: TURBO_PAYMENT_URL + "/v1/account/balance/arweave?address={address}",
+	}
+}
+
+// Irys returns the service of an Irys node accepting items paid in AR; prices and balances are in winston.
+func Irys() Service {
+	return Service{
+		URL:          IRYS_URL,
+		UploadRoute:  "tx/arweave",
+		PriceRoute:   "price/arweave/{size}",
+		BalanceRoute: "account/balance/arweave?address={address}",
+	}
+}
+
+// Liteseed returns the service of the Liteseed bundler; prices and balances are in winston.
+//
+// Liteseed uploads are paid per item, see the liteseed package for the
+// payment flow.
+func Liteseed() Service {
+	return Service{
+		URL:          liteseed.DEFAULT_URL,
+		UploadRoute:  "tx",
+		PriceRoute:   "price/{size}",
+		BalanceRoute: "account/{address}/balance",
+	}
+}
+
+// Client represents an HTTP client for a bundler service.
+type Client struct {
+	Client         *http.Client // HTTP client with configured timeout
+	Service        Service      // Routes of the bundler
+	VerifyReceipts bool         // Reject receipts that are not signed by the bundler, see Receipt.Verify
+}
+
+// Receipt is the bundler's answer to an upload.
+//
+// Bundlers fill the fields they support: Turbo and Irys sign their
+// receipts, while Liteseed only returns the ID.
+type Receipt struct {
+	ID             string `json:"id"`                       // Data item ID
+	Owner          string `json:"owner,omitempty"`          // Address of the data item owner
+	Version        string `json:"version,omitempty"`        // Version of the receipt format
+	Timestamp      int64  `json:"timestamp,omitempty"`      // Time the bundler received the item, in milliseconds since the Unix epoch
+	DeadlineHeight int64  `json:"deadlineHeight,omitempty"` // Block height the item is stored on Arweave by
+	Winc           string `json:"winc,omitempty"`           // Credits charged for the upload
+	Public         string `json:"public,omitempty"`         // Owner of the bundler key signing the receipt
+	Signature      string `json:"signature,omitempty"`      // Signature of the receipt
+}
+
+// New creates a bundler client for service with a 30-second request timeout.
+//
+// Example:
+//
+//	b := bundler.New(bundler.Irys())
+func New(service Service) *Client {
+	return &Client{
+		Client:  &http.Client{Timeout: 30 * time.Second},
+		Service: service,
+	}
+}
+
+// Close closes the idle connections of the client's HTTP client.
+func (c *Client) Close() error {
+	c.Client.CloseIdleConnections()
+	return nil
+}
+
+// PostDataItem uploads a signed data item to the bundler.
+//
+// The item is streamed, so large streaming items are never held in memory.
+//
+// Returns the bundler's receipt. The error has code goar.ErrInvalidInput if
+// the item is not signed, goar.ErrItemTooLarge if the bundler refuses its
+// size, goar.ErrInvalidSignature if VerifyReceipts is set and the receipt
+// does not verify, and goar.ErrDecode if the receipt is for another item.
+//
+// Example:
+//
+//	receipt, err := b.PostDataItem(signedItem)
+func (c *Client) PostDataItem(item *data_item.DataItem) (*Receipt, error) {
+	return c.PostDataItemContext(context.Background(), item)
+}
+
+// PostDataItemContext is PostDataItem bound to ctx.
+func (c *Client) PostDataItemContext(ctx context.Context, item *data_item.DataItem) (*Receipt, error) {
+	if item.ID == "" || item.Signature == "" {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "data item not signed")
+	}
+	body, w := io.Pipe()
+	go func() {
+		w.CloseWithError(item.WriteRawTo(w))
+	}()
+	defer body.Close()
+
+	b, err := c.request(ctx, http.MethodPost, c.Service.UploadRoute, body, item.RawSize())
+	if err != nil {
+		return nil, err
+	}
+	var receipt Receipt
+	if err := json.Unmarshal(b, &receipt); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	if receipt.ID != item.ID {
+		return nil, goar.Errorf(goar.ErrDecode, "bundler returned receipt for %s instead of %s", receipt.ID, item.ID)
+	}
+	if c.VerifyReceipts {
+		if err := receipt.Verify(); err != nil {
+			return nil, err
+		}
+	}
+	return &receipt, nil
+}
+
+// GetPrice returns the price of uploading size bytes of data items, in the unit of the service.
+//
+// Example:
+//
+//	price, err := b.GetPrice(item.RawSize())
+func (c *Client) GetPrice(size int64) (*big.Int, error) {
+	return c.GetPriceContext(context.Background(), size)
+}
+
+// GetPriceContext is GetPrice bound to ctx.
+func (c *Client) GetPriceContext(ctx context.Context, size int64) (*big.Int, error) {
+	route := strings.ReplaceAll(c.Service.PriceRoute, "{size}", strconv.FormatInt(size, 10))
+	b, err := c.request(ctx, http.MethodGet, route, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseAmount(b, "winc", "price")
+}
+
+// GetBalance returns the balance of the account of address, in the unit of the service.
+func (c *Client) GetBalance(address string) (*big.Int, error) {
+	return c.GetBalanceContext(context.Background(), address)
+}
+
+// GetBalanceContext is GetBalance bound to ctx.
+func (c *Client) GetBalanceContext(ctx context.Context, address string) (*big.Int, error) {
+	route := strings.ReplaceAll(c.Service.BalanceRoute, "{address}", address)
+	b, err := c.request(ctx, http.MethodGet, route, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	return parseAmount(b, "winc", "balance")
+}
+
+// Verify checks that the receipt is signed by the key in Public.
+//
+// The signature covers the deep hash of RECEIPT_PREFIX, Version, ID,
+// DeadlineHeight and Timestamp.
+//
+// Returns an error with code goar.ErrInvalidSignature if the receipt is not
+// signed or the signature does not verify.
+func (r *Receipt) Verify() error {
+	if r.Signature == "" || r.Public == "" {
+		return goar.Errorf(goar.ErrInvalidSignature, "receipt of %s is not signed", r.ID)
+	}
+	publicKey, err := crypto.GetPublicKeyFromOwner(r.Public)
+	if err != nil {
+		return goar.Wrap(goar.ErrInvalidSignature, err)
+	}
+	signature, err := crypto.Base64URLDecode(r.Signature)
+	if err != nil {
+		return goar.Wrap(goar.ErrInvalidSignature, err)
+	}
+	hash := r.hash()
+	if err := crypto.Verify(hash[:], signature, publicKey); err != nil {
+		return goar.Wrap(goar.ErrInvalidSignature, err)
+	}
+	return nil
+}
+
+// hash returns the deep hash signed by the bundler
+func (r *Receipt) hash() [48]byte {
+	return crypto.DeepHash([][]byte{
+		[]byte(RECEIPT_PREFIX),
+		[]byte(r.Version),
+		[]byte(r.ID),
+		[]byte(strconv.FormatInt(r.DeadlineHeight, 10)),
+		[]byte(strconv.FormatInt(r.Timestamp, 10)),
+	})
+}
+
+// request sends body of size bytes to route and returns the response body
+func (c *Client) request(ctx context.Context, method string, route string, body io.Reader, size int64) ([]byte, error) {
+	target := route
+	if !strings.HasPrefix(route, "http://") && !strings.HasPrefix(route, "https://") {
+		target = strings.TrimSuffix(c.Service.URL, "/") + "/" + route
+	}
+	req, err := http.NewRequestWithContext(ctx, method, target, body)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	if body != nil {
+		req.ContentLength = size
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrNetwork, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrNetwork, err)
+	}
+	if resp.StatusCode >= 400 {
+		return nil, statusError(resp.StatusCode, b)
+	}
+	return b, nil
+}
+
+// parseAmount reads an integer amount answered as a bare number, a JSON
+// string, or the first of fields present in a JSON object
+func parseAmount(b []byte, fields ...string) (*big.Int, error) {
+	b = bytes.TrimSpace(b)
+	if len(b) > 0 && b[0] == '{' {
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(b, &object); err != nil {
+			return nil, goar.Wrap(goar.ErrDecode, err)
+		}
+		var found bool
+		for _, field := range fields {
+			if b, found = object[field]; found {
+				break
+			}
+		}
+		if !found {
+			return nil, goar.Errorf(goar.ErrDecode, "response has none of the fields %s", strings.Join(fields, ", "))
+		}
+	}
+	s := strings.Trim(string(b), `"`)
+	amount, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return nil, goar.Errorf(goar.ErrDecode, "invalid amount %q", s)
+	}
+	return amount, nil
+}
+
+// statusError classifies a failed bundler response
+func statusError(code int, body []byte) error {
+	err := fmt.Errorf("%d: %s", code, string(body))
+	switch {
+	case code == http.StatusNotFound:
+		return goar.Wrap(goar.ErrNotFound, err)
+	case code == http.StatusTooManyRequests:
+		return goar.Wrap(goar.ErrRateLimited, err)
+	case code == http.StatusRequestEntityTooLarge:
+		return goar.Wrap(goar.ErrItemTooLarge, err)
+	case code >= 500:
+		return goar.Wrap(goar.ErrGateway, err)
+	default:
+		return goar.Wrap(goar.ErrBadRequest, err)
+	}
+}
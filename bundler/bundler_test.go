@@ -0,0 +1,176 @@
+package bundler
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newBundler serves a Turbo-like upload route signing receipts with s, and tamper applied to each receipt
+func newBundler(t *testing.T, s *signer.Signer, tamper func(*Receipt)) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/tx", func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(raw)), r.ContentLength)
+		item, err := data_item.Decode(raw)
+		if err != nil || item.Verify() != nil {
+			http.Error(w, "invalid data item", http.StatusBadRequest)
+			return
+		}
+		receipt := &Receipt{ID: item.ID, Owner: item.GetOwnerAddress(), Version: "1.0.0", Timestamp: 1700000000000, DeadlineHeight: 1300000, Winc: "0", Public: s.Owner()}
+		hash := receipt.hash()
+		signature, err := crypto.Sign(hash[:], s.PrivateKey)
+		require.NoError(t, err)
+		receipt.Signature = crypto.Base64URLEncode(signature)
+		if tamper != nil {
+			tamper(receipt)
+		}
+		_ = json.NewEncoder(w).Encode(receipt)
+	})
+	mux.HandleFunc("GET /v1/price/bytes/{size}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"winc":"` + r.PathValue("size") + `0","adjustments":[]}`))
+	})
+	mux.HandleFunc("GET /price/arweave/{size}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.PathValue("size") + "1"))
+	})
+	mux.HandleFunc("GET /account/balance/arweave", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"balance":"42","address":"` + r.URL.Query().Get("address") + `"}`))
+	})
+	mux.HandleFunc("GET /v1/account/balance/arweave", func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "slow down", http.StatusTooManyRequests)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPostDataItem(t *testing.T) {
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+	item := data_item.New([]byte("bundled"), "", "", nil)
+	require.NoError(t, item.Sign(s))
+
+	service := func(url string) Service {
+		return Service{URL: url, UploadRoute: "v1/tx"}
+	}
+
+	t.Run("Receipt", func(t *testing.T) {
+		b := New(service(newBundler(t, s, nil).URL))
+		b.VerifyReceipts = true
+		receipt, err := b.PostDataItem(item)
+		require.NoError(t, err)
+		assert.Equal(t, item.ID, receipt.ID)
+		assert.Equal(t, s.Address, receipt.Owner)
+		assert.Equal(t, int64(1300000), receipt.DeadlineHeight)
+	})
+
+	t.Run("Streaming item", func(t *testing.T) {
+		streamed, err := data_item.NewFromStream(io.MultiReader(io.LimitReader(zeros{}, 1<<20)), 1<<20, "", "", nil)
+		require.NoError(t, err)
+		defer streamed.Close()
+		require.NoError(t, streamed.Sign(s))
+		receipt, err := New(service(newBundler(t, s, nil).URL)).PostDataItem(streamed)
+		require.NoError(t, err)
+		assert.Equal(t, streamed.ID, receipt.ID)
+	})
+
+	t.Run("Tampered receipt", func(t *testing.T) {
+		server := newBundler(t, s, func(r *Receipt) { r.DeadlineHeight++ })
+		b := New(service(server.URL))
+		_, err := b.PostDataItem(item)
+		require.NoError(t, err)
+
+		b.VerifyReceipts = true
+		_, err = b.PostDataItem(item)
+		assert.ErrorIs(t, err, goar.ErrInvalidSignature)
+	})
+
+	t.Run("Unsigned receipt", func(t *testing.T) {
+		b := New(service(newBundler(t, s, func(r *Receipt) { r.Signature = "" }).URL))
+		b.VerifyReceipts = true
+		_, err := b.PostDataItem(item)
+		assert.ErrorIs(t, err, goar.ErrInvalidSignature)
+	})
+
+	t.Run("Receipt of another item", func(t *testing.T) {
+		b := New(service(newBundler(t, s, func(r *Receipt) { r.ID = "other" }).URL))
+		_, err := b.PostDataItem(item)
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+
+	t.Run("Unsigned item", func(t *testing.T) {
+		_, err := New(service("http://unused")).PostDataItem(data_item.New([]byte("bundled"), "", "", nil))
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+	})
+
+	t.Run("Rejected item", func(t *testing.T) {
+		tampered := item.Clone()
+		tampered.Signature = crypto.Base64URLEncode(make([]byte, 512))
+		require.NoError(t, tampered.Encode())
+		_, err := New(service(newBundler(t, s, nil).URL)).PostDataItem(tampered)
+		assert.ErrorIs(t, err, goar.ErrBadRequest)
+	})
+}
+
+func TestGetPrice(t *testing.T) {
+	server := newBundler(t, nil, nil)
+
+	turbo := New(Service{PriceRoute: server.URL + "/v1/price/bytes/{size}"})
+	price, err := turbo.GetPrice(1024)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10240), price)
+
+	irys := New(Service{URL: server.URL + "/", PriceRoute: "price/arweave/{size}"})
+	price, err = irys.GetPrice(1024)
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(10241), price)
+}
+
+func TestGetBalance(t *testing.T) {
+	server := newBundler(t, nil, nil)
+
+	balance, err := New(Service{URL: server.URL, BalanceRoute: "account/balance/arweave?address={address}"}).GetBalance("owner")
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), balance)
+
+	_, err = New(Service{URL: server.URL, BalanceRoute: "v1/account/balance/arweave?address={address}"}).GetBalance("owner")
+	assert.ErrorIs(t, err, goar.ErrRateLimited)
+}
+
+func TestParseAmount(t *testing.T) {
+	for body, expected := range map[string]int64{
+		`123`:                  123,
+		` "123"` + "\n":        123,
+		`{"winc":"7"}`:         7,
+		`{"price":8}`:          8,
+		`{"balance":"9"}`:      9,
+		`{"winc":1,"price":2}`: 1,
+	} {
+		amount, err := parseAmount([]byte(body), "winc", "price", "balance")
+		require.NoError(t, err, body)
+		assert.Equal(t, big.NewInt(expected), amount, body)
+	}
+	for _, body := range []string{``, `1.5`, `{"other":"1"}`, `{`} {
+		_, err := parseAmount([]byte(body), "winc")
+		assert.ErrorIs(t, err, goar.ErrDecode, body)
+	}
+}
+
+// zeros reads an endless stream of zero bytes
+type zeros struct{}
+
+func (zeros) Read(p []byte) (int, error) {
+	clear(p)
+	return len(p), nil
+}
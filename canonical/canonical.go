@@ -0,0 +1,230 @@
+// Package canonical encodes JSON deterministically, following the JSON
+// Canonicalization Scheme of RFC 8785.
+//
+// Application-layer records that are hashed or signed, such as receipts,
+// split indexes and multisig envelopes, must serialize to the same bytes in
+// every language, or their hashes differ. Canonical JSON has no whitespace,
+// object keys sorted by their UTF-16 code units, strings escaped minimally
+// and numbers in their shortest ECMAScript form, so any RFC 8785
+// implementation reproduces it.
+//
+// Numbers are IEEE 754 doubles: integers above 2^53 lose precision and must
+// be encoded as strings, as Arweave does for winston amounts.
+//
+// Example usage:
+//
+//	data, err := canonical.Marshal(receipt)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	hash, err := canonical.Hash(receipt)
+package canonical
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+)
+
+// Marshal returns the canonical JSON encoding of v.
+//
+// v is encoded with encoding/json first, so struct tags and Marshaler
+// implementations apply, then canonicalized with Transform.
+//
+// Returns an error with code goar.ErrInvalidInput if v cannot be encoded or
+// holds a number that is not a finite double.
+func Marshal(v any) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(v); err != nil {
+		return nil, goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	return Transform(buf.Bytes())
+}
+
+// Transform returns the canonical form of the JSON document data.
+//
+// Returns an error with code goar.ErrDecode if data is not a single valid
+// JSON value, holds an object with duplicate keys, a string that is not
+// valid UTF-8 or a number that is not a finite double.
+//
+// Example:
+//
+//	out, _ := canonical.Transform([]byte(`{"b": 1.50, "a": "é"}`))
+//	fmt.Println(string(out)) // {"a":"é","b":1.5}
+func Transform(data []byte) ([]byte, error) {
+	if !utf8.Valid(data) {
+		return nil, goar.Errorf(goar.ErrDecode, "invalid UTF-8")
+	}
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	if err := encodeValue(&buf, dec); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	if _, err := dec.Token(); err != io.EOF {
+		return nil, goar.Errorf(goar.ErrDecode, "unexpected data after JSON value")
+	}
+	return buf.Bytes(), nil
+}
+
+// Hash returns the SHA-256 of the canonical JSON encoding of v.
+func Hash(v any) ([]byte, error) {
+	data, err := Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return crypto.SHA256(data), nil
+}
+
+// encodeValue reads the next value from dec and writes its canonical form to buf
+func encodeValue(buf *bytes.Buffer, dec *json.Decoder) error {
+	t, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	switch v := t.(type) {
+	case json.Delim:
+		if v == '[' {
+			return encodeArray(buf, dec)
+		}
+		if v == '{' {
+			return encodeObject(buf, dec)
+		}
+		return fmt.Errorf("unexpected %q", v)
+	case string:
+		encodeString(buf, v)
+	case json.Number:
+		n, err := formatNumber(v)
+		if err != nil {
+			return err
+		}
+		buf.WriteString(n)
+	case bool:
+		buf.WriteString(strconv.FormatBool(v))
+	case nil:
+		buf.WriteString("null")
+	}
+	return nil
+}
+
+// encodeArray writes the rest of an array whose opening bracket was read
+func encodeArray(buf *bytes.Buffer, dec *json.Decoder) error {
+	buf.WriteByte('[')
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if err := encodeValue(buf, dec); err != nil {
+			return err
+		}
+	}
+	buf.WriteByte(']')
+	_, err := dec.Token()
+	return err
+}
+
+// encodeObject writes the rest of an object whose opening brace was read, with its members sorted
+func encodeObject(buf *bytes.Buffer, dec *json.Decoder) error {
+	type member struct {
+		key   string
+		value []byte
+	}
+	var members []member
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key := t.(string)
+		if slices.ContainsFunc(members, func(m member) bool { return m.key == key }) {
+			return fmt.Errorf("duplicate key %q", key)
+		}
+		var value bytes.Buffer
+		if err := encodeValue(&value, dec); err != nil {
+			return err
+		}
+		members = append(members, member{key, value.Bytes()})
+	}
+	if _, err := dec.Token(); err != nil {
+		return err
+	}
+
+	// RFC 8785 orders keys by their UTF-16 code units, not their UTF-8 bytes
+	slices.SortFunc(members, func(a, b member) int {
+		return slices.Compare(utf16.Encode([]rune(a.key)), utf16.Encode([]rune(b.key)))
+	})
+	buf.WriteByte('{')
+	for i, m := range members {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		encodeString(buf, m.key)
+		buf.WriteByte(':')
+		buf.Write(m.value)
+	}
+	buf.WriteByte('}')
+	return nil
+}
+
+// encodeString writes s quoted, escaping only quotes, backslashes and control characters
+func encodeString(buf *bytes.Buffer, s string) {
+	buf.WriteByte('"')
+	for _, r := range s {
+		switch r {
+		case '"':
+			buf.WriteString(`\"`)
+		case '\\':
+			buf.WriteString(`\\`)
+		case '\b':
+			buf.WriteString(`\b`)
+		case '\f':
+			buf.WriteString(`\f`)
+		case '\n':
+			buf.WriteString(`\n`)
+		case '\r':
+			buf.WriteString(`\r`)
+		case '\t':
+			buf.WriteString(`\t`)
+		default:
+			if r < 0x20 {
+				fmt.Fprintf(buf, `\u%04x`, r)
+			} else {
+				buf.WriteRune(r)
+			}
+		}
+	}
+	buf.WriteByte('"')
+}
+
+// formatNumber returns n in the shortest form of ECMAScript Number.prototype.toString
+func formatNumber(n json.Number) (string, error) {
+	f, err := strconv.ParseFloat(string(n), 64)
+	if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+		return "", errors.New("number " + string(n) + " is not a finite double")
+	}
+	if f == 0 {
+		return "0", nil
+	}
+	abs := math.Abs(f)
+	if abs >= 1e-6 && abs < 1e21 {
+		return strconv.FormatFloat(f, 'f', -1, 64), nil
+	}
+	// Go writes exponents with at least two digits, ECMAScript with as few as possible
+	s := strconv.FormatFloat(f, 'e', -1, 64)
+	mantissa, exponent, _ := strings.Cut(s, "e")
+	sign, digits := exponent[:1], strings.TrimLeft(exponent[1:], "0")
+	return mantissa + "e" + sign + digits, nil
+}
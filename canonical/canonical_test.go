@@ -0,0 +1,80 @@
+package canonical
+
+import (
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransform verifies canonicalization against the RFC 8785 examples
+func TestTransform(t *testing.T) {
+	tests := []struct {
+		name     string
+		input    string
+		expected string
+	}{
+		{
+			name:     "RFC 8785 example",
+			input:    `{"numbers": [333333333.33333329, 1E30, 4.50, 2e-3, 0.000000000000000000000000001], "string": "\u20ac$\u000F\u000aA'\u0042\u0022\u005c\\\"\/", "literals": [null, true, false]}`,
+			expected: `{"literals":[null,true,false],"numbers":[333333333.3333333,1e+30,4.5,0.002,1e-27],"string":"€$\u000f\nA'B\"\\\\\"/"}`,
+		},
+		{
+			name:     "UTF-16 key order",
+			input:    `{"\u20ac": 1, "\r": 2, "\ufb33": 3, "1": 4, "\ud83d\ude00": 5, "\u0080": 6, "\u00f6": 7}`,
+			expected: "{\"\\r\":2,\"1\":4,\"\u0080\":6,\"ö\":7,\"€\":1,\"😀\":5,\"\ufb33\":3}",
+		},
+		{
+			name:     "Nested",
+			input:    ` { "b" : [ { "d" : 1 , "c" : -0 } ] , "a" : { } } `,
+			expected: `{"a":{},"b":[{"c":0,"d":1}]}`,
+		},
+		{
+			name:     "No HTML escaping",
+			input:    `"<a href=\"x\">&</a>"`,
+			expected: `"<a href=\"x\">&</a>"`,
+		},
+		{
+			name:     "Numbers",
+			input:    `[1, -1.5, 100, 1e21, 1e20, 1e-7, 0.000001, 9007199254740993]`,
+			expected: `[1,-1.5,100,1e+21,100000000000000000000,1e-7,0.000001,9007199254740992]`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			out, err := Transform([]byte(tt.input))
+			require.NoError(t, err)
+			assert.Equal(t, tt.expected, string(out))
+		})
+	}
+
+	for _, input := range []string{`{"a":1,"a":2}`, `{"a":1`, `{} {}`, `1e400`, "\"\xff\"", ``} {
+		_, err := Transform([]byte(input))
+		assert.Equal(t, goar.ErrDecode, goar.CodeOf(err), input)
+	}
+}
+
+// TestMarshal verifies structs encode canonically and hash reproducibly
+func TestMarshal(t *testing.T) {
+	type receipt struct {
+		ID     string            `json:"id"`
+		Owner  string            `json:"owner"`
+		Size   int64             `json:"size"`
+		Tags   map[string]string `json:"tags"`
+		Status string            `json:"status,omitempty"`
+	}
+	r := receipt{ID: "abc", Owner: "<owner>", Size: 1024, Tags: map[string]string{"z": "1", "a": "2"}}
+
+	out, err := Marshal(r)
+	require.NoError(t, err)
+	assert.Equal(t, `{"id":"abc","owner":"<owner>","size":1024,"tags":{"a":"2","z":"1"}}`, string(out))
+
+	hash, err := Hash(r)
+	require.NoError(t, err)
+	assert.Equal(t, crypto.SHA256(out), hash)
+
+	_, err = Marshal(func() {})
+	assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err))
+}
@@ -0,0 +1,233 @@
+// Package chaos injects gateway faults into the HTTP requests of a client.
+//
+// A Transport is an http.RoundTripper that forwards requests to a real
+// transport, but adds latency, answers with 429 and 5xx responses, truncates
+// response bodies and resets connections at configurable rates. It is used
+// to check that retry and backoff logic copes with a misbehaving gateway,
+// in goar's own tests and in downstream code.
+//
+// Faults are drawn from a seeded source, so a failing run can be replayed
+// with the same seed as long as requests are sent in the same order.
+//
+// Example usage:
+//
+//	func TestUploadSurvivesFaults(t *testing.T) {
+//		ft := chaos.New(chaos.Faults{ServerErrorRate: 0.2, ResetRate: 0.1}, 1)
+//		c := ft.Client(server.URL)
+//		...
+//		t.Logf("injected faults: %+v", ft.Stats())
+//	}
+package chaos
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/liteseed/goar/client"
+)
+
+// SERVER_ERROR_CODES are the status codes of injected server errors
+var SERVER_ERROR_CODES = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Faults sets how often each fault is injected.
+//
+// Rates are probabilities between 0 and 1, drawn independently for every
+// request. A request gets at most one of a reset, a 429 or a 5xx, checked
+// in that order, and is never forwarded when it gets one. Latency and
+// truncation apply to forwarded requests.
+type Faults struct {
+	LatencyRate     float64       // Rate of requests delayed by up to Latency
+	Latency         time.Duration // Maximum added latency, drawn uniformly
+	RateLimitRate   float64       // Rate of 429 Too Many Requests responses
+	RetryAfter      time.Duration // Retry-After of 429 responses, rounded up to seconds; omitted if 0
+	ServerErrorRate float64       // Rate of responses with one of SERVER_ERROR_CODES
+	TruncateRate    float64       // Rate of response bodies cut short, failing with io.ErrUnexpectedEOF
+	ResetRate       float64       // Rate of requests failing with a connection reset
+}
+
+// Stats counts the requests seen and the faults injected by a Transport.
+type Stats struct {
+	Requests     int // Requests received
+	Delayed      int // Requests delayed
+	RateLimited  int // 429 responses
+	ServerErrors int // 5xx responses
+	Truncated    int // Truncated bodies
+	Resets       int // Connection resets
+}
+
+// Transport is an http.RoundTripper injecting Faults into the requests it forwards.
+//
+// It is safe for concurrent use.
+type Transport struct {
+	Faults
+	Transport http.RoundTripper // Transport requests are forwarded to, http.DefaultTransport if nil
+
+	mu    sync.Mutex
+	rand  *rand.Rand
+	stats Stats
+}
+
+// New creates a Transport injecting faults, drawn from a source seeded with seed.
+func New(faults Faults, seed int64) *Transport {
+	return &Transport{Faults: faults, rand: rand.New(rand.NewSource(seed))}
+}
+
+// Client returns a goar client for gateway whose requests go through the transport.
+func (t *Transport) Client(gateway string) *client.Client {
+	c := client.New(gateway)
+	c.Client.Transport = t
+	return c
+}
+
+// Stats returns the number of requests and injected faults so far.
+func (t *Transport) Stats() Stats {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.stats
+}
+
+// RoundTrip forwards req, possibly injecting a fault.
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	f := t.draw()
+	if f.reset {
+		closeBody(req)
+		return nil, &net.OpError{Op: "read", Net: "tcp", Err: os.NewSyscallError("read", syscall.ECONNRESET)}
+	}
+	if f.code != 0 {
+		closeBody(req)
+		return response(req, f.code, t.RetryAfter), nil
+	}
+	if f.delay > 0 {
+		if err := sleep(req.Context(), f.delay); err != nil {
+			closeBody(req)
+			return nil, err
+		}
+	}
+
+	transport := t.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil || !f.truncate {
+		return resp, err
+	}
+	body, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	resp.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body[:f.cut(len(body))]), failingReader{}))
+	return resp, nil
+}
+
+// fault is the fault drawn for one request
+type fault struct {
+	reset    bool
+	code     int
+	delay    time.Duration
+	truncate bool
+	cut      func(n int) int // Length a body of n bytes is truncated to
+}
+
+// draw picks the faults of the next request and counts them
+func (t *Transport) draw() fault {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.rand == nil {
+		t.rand = rand.New(rand.NewSource(time.Now().UnixNano()))
+	}
+	t.stats.Requests++
+
+	var f fault
+	switch {
+	case t.hit(t.ResetRate):
+		f.reset = true
+		t.stats.Resets++
+	case t.hit(t.RateLimitRate):
+		f.code = http.StatusTooManyRequests
+		t.stats.RateLimited++
+	case t.hit(t.ServerErrorRate):
+		f.code = SERVER_ERROR_CODES[t.rand.Intn(len(SERVER_ERROR_CODES))]
+		t.stats.ServerErrors++
+	default:
+		if t.Latency > 0 && t.hit(t.LatencyRate) {
+			f.delay = time.Duration(t.rand.Int63n(int64(t.Latency) + 1))
+			t.stats.Delayed++
+		}
+		if t.hit(t.TruncateRate) {
+			f.truncate = true
+			t.stats.Truncated++
+			// Drawn now so that the sequence does not depend on response timing
+			fraction := t.rand.Float64()
+			f.cut = func(n int) int { return int(fraction * float64(n)) }
+		}
+	}
+	return f
+}
+
+// hit draws whether a fault of the given rate happens
+func (t *Transport) hit(rate float64) bool {
+	return rate > 0 && t.rand.Float64() < rate
+}
+
+// response builds an injected error response to req
+func response(req *http.Request, code int, retryAfter time.Duration) *http.Response {
+	body := fmt.Sprintf("%d %s (injected)", code, http.StatusText(code))
+	header := http.Header{"Content-Type": {"text/plain"}}
+	if code == http.StatusTooManyRequests && retryAfter > 0 {
+		header.Set("Retry-After", fmt.Sprint(int((retryAfter+time.Second-1)/time.Second)))
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", code, http.StatusText(code)),
+		StatusCode:    code,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(strings.NewReader(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+}
+
+// sleep waits for d or until ctx is done
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// closeBody closes the body of a request that is not forwarded, as a RoundTripper must
+func closeBody(req *http.Request) {
+	if req.Body != nil {
+		req.Body.Close()
+	}
+}
+
+// failingReader fails every read, ending truncated bodies
+type failingReader struct{}
+
+func (failingReader) Read([]byte) (int, error) {
+	return 0, io.ErrUnexpectedEOF
+}
@@ -0,0 +1,107 @@
+package chaos
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTransport verifies each fault reaches the client with the expected error code
+func TestTransport(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"network":"arweave.N.1","height":42}`))
+	}))
+	defer server.Close()
+
+	tests := []struct {
+		name   string
+		faults Faults
+		code   goar.ErrorCode
+	}{
+		{"None", Faults{}, goar.ErrUnknown},
+		{"Reset", Faults{ResetRate: 1}, goar.ErrNetwork},
+		{"Rate limit", Faults{RateLimitRate: 1}, goar.ErrRateLimited},
+		{"Server error", Faults{ServerErrorRate: 1}, goar.ErrGateway},
+		{"Truncate", Faults{TruncateRate: 1}, goar.ErrNetwork},
+		{"Latency", Faults{LatencyRate: 1, Latency: time.Millisecond}, goar.ErrUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ft := New(tt.faults, 1)
+			info, err := ft.Client(server.URL).GetNetworkInfo()
+			if tt.code == goar.ErrUnknown {
+				require.NoError(t, err)
+				assert.Equal(t, int64(42), info.Height)
+			} else {
+				assert.Equal(t, tt.code, goar.CodeOf(err))
+			}
+			assert.Equal(t, 1, ft.Stats().Requests)
+		})
+	}
+
+	t.Run("Reset error", func(t *testing.T) {
+		_, err := (&http.Client{Transport: New(Faults{ResetRate: 1}, 1)}).Get(server.URL)
+		assert.ErrorIs(t, err, syscall.ECONNRESET)
+	})
+
+	t.Run("Truncated body", func(t *testing.T) {
+		resp, err := (&http.Client{Transport: New(Faults{TruncateRate: 1}, 1)}).Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		_, err = io.ReadAll(resp.Body)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("Retry-After", func(t *testing.T) {
+		ft := New(Faults{RateLimitRate: 1, RetryAfter: 1500 * time.Millisecond}, 1)
+		resp, err := (&http.Client{Transport: ft}).Get(server.URL)
+		require.NoError(t, err)
+		defer resp.Body.Close()
+		assert.Equal(t, http.StatusTooManyRequests, resp.StatusCode)
+		assert.Equal(t, "2", resp.Header.Get("Retry-After"))
+	})
+
+	t.Run("Canceled during latency", func(t *testing.T) {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+		defer cancel()
+		req, err := http.NewRequestWithContext(ctx, http.MethodGet, server.URL, nil)
+		require.NoError(t, err)
+		_, err = (&http.Client{Transport: New(Faults{LatencyRate: 1, Latency: time.Hour}, 1)}).Do(req)
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+}
+
+// TestRates verifies faults are injected at roughly their rates, reproducibly for a seed
+func TestRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	faults := Faults{ResetRate: 0.1, RateLimitRate: 0.1, ServerErrorRate: 0.2, TruncateRate: 0.1}
+	run := func() Stats {
+		c := &http.Client{Transport: New(faults, 7)}
+		for i := 0; i < 1000; i++ {
+			resp, err := c.Get(server.URL)
+			if err == nil {
+				_, _ = io.ReadAll(resp.Body)
+				resp.Body.Close()
+			}
+		}
+		return (c.Transport.(*Transport)).Stats()
+	}
+
+	stats := run()
+	assert.Equal(t, 1000, stats.Requests)
+	assert.InDelta(t, 100, stats.Resets, 40)
+	assert.InDelta(t, 90, stats.RateLimited, 40)
+	assert.InDelta(t, 160, stats.ServerErrors, 50)
+	assert.InDelta(t, 64, stats.Truncated, 35)
+	assert.Equal(t, stats, run())
+}
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liteseed/goar"
+)
+
+// MAX_ANCHOR_DEPTH is the number of blocks after which the network rejects a block anchor
+const MAX_ANCHOR_DEPTH = 50
+
+// GetAnchorDepth returns how many blocks the anchor block is behind the current height.
+//
+// Parameters:
+//   - anchor: A block hash, as returned by GetTransactionAnchor
+//
+// Returns the depth in blocks, or an error if the anchor is not a known
+// block (goar.ErrNotFound) or a request fails.
+//
+// Example:
+//
+//	depth, err := client.GetAnchorDepth(tx.LastTx)
+//	if err == nil {
+//		fmt.Printf("Anchor is %d blocks old\n", depth)
+//	}
+func (c *Client) GetAnchorDepth(anchor string) (int64, error) {
+	return c.GetAnchorDepthContext(context.Background(), anchor)
+}
+
+// GetAnchorDepthContext is GetAnchorDepth bound to ctx.
+func (c *Client) GetAnchorDepthContext(ctx context.Context, anchor string) (int64, error) {
+	block, err := c.GetBlockByIDContext(ctx, anchor)
+	if err != nil {
+		return 0, err
+	}
+	info, err := c.GetNetworkInfoContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	depth := info.Height - int64(block.Height)
+	if depth < 0 {
+		// The gateway serving /info lags behind the one serving the block
+		depth = 0
+	}
+	return depth, nil
+}
+
+// CheckAnchor verifies that anchor is a block at most maxDepth blocks deep.
+//
+// Anchors close to MAX_ANCHOR_DEPTH may expire before the transaction is
+// mined, so callers usually pass a smaller depth to leave a safety margin.
+//
+// Returns nil if the anchor is recent enough, an error with code
+// goar.ErrAnchorExpired if it is too old or not a block, or the request
+// error otherwise.
+func (c *Client) CheckAnchor(anchor string, maxDepth int64) error {
+	return c.CheckAnchorContext(context.Background(), anchor, maxDepth)
+}
+
+// CheckAnchorContext is CheckAnchor bound to ctx.
+func (c *Client) CheckAnchorContext(ctx context.Context, anchor string, maxDepth int64) error {
+	depth, err := c.GetAnchorDepthContext(ctx, anchor)
+	if errors.Is(err, goar.ErrNotFound) {
+		return goar.Errorf(goar.ErrAnchorExpired, "anchor %s is not a known block", anchor)
+	}
+	if err != nil {
+		return err
+	}
+	if depth > maxDepth {
+		return goar.Errorf(goar.ErrAnchorExpired, "anchor %s is %d blocks deep, more than %d", anchor, depth, maxDepth)
+	}
+	return nil
+}
+
+// GetRecentAnchor retrieves the transaction anchor and checks its depth.
+//
+// Gateways behind caches may serve an anchor that is already old. If the
+// first anchor is deeper than maxDepth it is fetched once more before
+// giving up, so no signing work is spent on a transaction that would be
+// rejected.
+//
+// Returns the anchor, or an error with code goar.ErrAnchorExpired if the
+// gateway keeps serving a stale anchor.
+//
+// Example:
+//
+//	anchor, err := client.GetRecentAnchor(client.MAX_ANCHOR_DEPTH / 2)
+//	if err != nil {
+//		log.Printf("No usable anchor: %v", err)
+//		return
+//	}
+//	tx.LastTx = anchor
+func (c *Client) GetRecentAnchor(maxDepth int64) (string, error) {
+	return c.GetRecentAnchorContext(context.Background(), maxDepth)
+}
+
+// GetRecentAnchorContext is GetRecentAnchor bound to ctx.
+func (c *Client) GetRecentAnchorContext(ctx context.Context, maxDepth int64) (string, error) {
+	var err error
+	for attempt := 0; attempt < 2; attempt++ {
+		var anchor string
+		anchor, err = c.GetTransactionAnchorContext(ctx)
+		if err != nil {
+			return "", err
+		}
+		err = c.CheckAnchorContext(ctx, anchor, maxDepth)
+		if err == nil {
+			return anchor, nil
+		}
+		if !errors.Is(err, goar.ErrAnchorExpired) {
+			return "", err
+		}
+	}
+	return "", err
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// anchorServer serves blocks "old" at height 100 and "new" at height 145 with the network at height 150.
+// The anchor endpoint returns the anchors in order, repeating the last one.
+func anchorServer(t *testing.T, anchors ...string) (*httptest.Server, *atomic.Int32) {
+	heights := map[string]int{"old": 100, "new": 145}
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tx_anchor":
+			n := int(calls.Add(1)) - 1
+			if n >= len(anchors) {
+				n = len(anchors) - 1
+			}
+			_, _ = w.Write([]byte(anchors[n]))
+		case "/info":
+			_, _ = w.Write([]byte(`{"height": 150}`))
+		case "/block/hash/old", "/block/hash/new":
+			_, _ = fmt.Fprintf(w, `{"height": %d}`, heights[r.URL.Path[len("/block/hash/"):]])
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte("Not Found."))
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server, &calls
+}
+
+func TestAnchorDepth(t *testing.T) {
+	server, _ := anchorServer(t, "new")
+	c := New(server.URL)
+
+	depth, err := c.GetAnchorDepth("old")
+	require.NoError(t, err)
+	assert.Equal(t, int64(50), depth)
+
+	assert.NoError(t, c.CheckAnchor("new", 25))
+	assert.ErrorIs(t, c.CheckAnchor("old", 25), goar.ErrAnchorExpired)
+	assert.ErrorIs(t, c.CheckAnchor("unknown", 25), goar.ErrAnchorExpired)
+}
+
+func TestGetRecentAnchor(t *testing.T) {
+	t.Run("Fresh", func(t *testing.T) {
+		server, calls := anchorServer(t, "new")
+		anchor, err := New(server.URL).GetRecentAnchor(25)
+		require.NoError(t, err)
+		assert.Equal(t, "new", anchor)
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("Refreshed", func(t *testing.T) {
+		server, calls := anchorServer(t, "old", "new")
+		anchor, err := New(server.URL).GetRecentAnchor(25)
+		require.NoError(t, err)
+		assert.Equal(t, "new", anchor)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("Stale", func(t *testing.T) {
+		server, calls := anchorServer(t, "old")
+		_, err := New(server.URL).GetRecentAnchor(25)
+		assert.ErrorIs(t, err, goar.ErrAnchorExpired)
+		assert.Equal(t, int32(2), calls.Load())
+	})
+}
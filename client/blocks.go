@@ -0,0 +1,114 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+)
+
+// Settings of BlockStream
+const (
+	BLOCK_POLL_INTERVAL = 30 * time.Second // Delay before asking again for a block past the tip of the chain
+	REORG_WINDOW        = 50               // Number of recent blocks BlockStream can roll back in a reorganization
+)
+
+// BlockEvent is a block emitted by BlockStream, or the error ending the stream.
+type BlockEvent struct {
+	Block    *Block   // The next block of the chain, nil with Err
+	Orphaned []*Block // Blocks emitted before that left the chain in a reorganization, highest first; Block replaces the lowest
+	Err      error    // Error ending the stream, which is closed after it
+}
+
+// BlockStream walks the chain from height fromHeight, emitting each block in order on the returned channel.
+//
+// Once the tip of the chain is reached, the next height is polled every
+// PollInterval of the client, or BLOCK_POLL_INTERVAL if it is 0. Each
+// block must link to the previous one through its previous_block. When it
+// does not, the chain was reorganized: the previous block is orphaned and
+// its height fetched again, up to REORG_WINDOW blocks back. The next event
+// lists the orphaned blocks, whose transactions an indexer must then
+// forget, and carries the block that replaces the lowest one.
+//
+// The channel is closed once ctx is done or the client closed, or after
+// an event carrying the error of a failed request or of a reorganization
+// deeper than REORG_WINDOW. The stream stops early only through ctx,
+// which must be cancelled when the caller stops reading, or Close.
+//
+// Example:
+//
+//	ctx, cancel := context.WithCancel(context.Background())
+//	defer cancel()
+//	for event := range c.BlockStream(ctx, 1_000_000) {
+//		if event.Err != nil {
+//			log.Fatal(event.Err)
+//		}
+//		for _, orphan := range event.Orphaned {
+//			index.Forget(orphan.Txs)
+//		}
+//		index.Add(event.Block.Height, event.Block.Txs)
+//	}
+func (c *Client) BlockStream(ctx context.Context, fromHeight int64) <-chan BlockEvent {
+	events := make(chan BlockEvent)
+	ctx, cancel := c.bind(ctx)
+	go func() {
+		defer cancel()
+		defer close(events)
+		emit := func(event BlockEvent) bool {
+			select {
+			case events <- event:
+				return true
+			case <-ctx.Done():
+				return false
+			}
+		}
+
+		interval := c.PollInterval
+		if interval <= 0 {
+			interval = BLOCK_POLL_INTERVAL
+		}
+		var chain, orphaned []*Block // Recent blocks emitted, oldest first, and the blocks orphaned since the last event
+		for height := fromHeight; ctx.Err() == nil; {
+			b, err := c.GetBlockByHeightContext(ctx, strconv.FormatInt(height, 10))
+			if errors.Is(err, goar.ErrNotFound) {
+				if !retry.Sleep(c.getClock(), interval, ctx.Done()) {
+					return
+				}
+				continue
+			}
+			if err != nil {
+				if ctx.Err() == nil {
+					emit(BlockEvent{Err: err})
+				}
+				return
+			}
+
+			if len(chain) > 0 && b.PreviousBlock != chain[len(chain)-1].IndepHash {
+				orphan := chain[len(chain)-1]
+				chain = chain[:len(chain)-1]
+				orphaned = append(orphaned, orphan)
+				if len(chain) == 0 && int64(orphan.Height) > fromHeight {
+					emit(BlockEvent{Err: fmt.Errorf("reorganization deeper than %d blocks below height %d", REORG_WINDOW, height)})
+					return
+				}
+				height--
+				continue
+			}
+
+			if !emit(BlockEvent{Block: b, Orphaned: orphaned}) {
+				return
+			}
+			orphaned = nil
+			chain = append(chain, b)
+			if len(chain) > REORG_WINDOW {
+				chain = chain[1:]
+			}
+			height++
+		}
+	}()
+	return events
+}
@@ -0,0 +1,129 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar/internal/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chainGateway serves blocks by height from a chain that tests can reorganize
+type chainGateway struct {
+	mu     sync.Mutex
+	blocks map[int64]*Block
+}
+
+func (g *chainGateway) set(height int64, hash string, previous string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.blocks[height] = &Block{Height: uint64(height), IndepHash: hash, PreviousBlock: previous}
+}
+
+func TestBlockStream(t *testing.T) {
+	g := &chainGateway{blocks: map[int64]*Block{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /block/height/{height}", func(w http.ResponseWriter, r *http.Request) {
+		height, _ := strconv.ParseInt(r.PathValue("height"), 10, 64)
+		if height == 99 {
+			http.Error(w, "down", http.StatusInternalServerError)
+			return
+		}
+		g.mu.Lock()
+		b, ok := g.blocks[height]
+		g.mu.Unlock()
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		_ = json.NewEncoder(w).Encode(b)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	newClient := func() *Client {
+		c := New(server.URL)
+		c.clock = retry.NewFakeClock(time.Unix(0, 0))
+		return c
+	}
+	next := func(t *testing.T, events <-chan BlockEvent) BlockEvent {
+		select {
+		case event, ok := <-events:
+			require.True(t, ok, "stream closed")
+			require.NoError(t, event.Err)
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("no block emitted")
+			return BlockEvent{}
+		}
+	}
+	hashes := func(blocks []*Block) []string {
+		var h []string
+		for _, b := range blocks {
+			h = append(h, b.IndepHash)
+		}
+		return h
+	}
+
+	t.Run("Reorganization", func(t *testing.T) {
+		g.set(10, "a", "genesis")
+		g.set(11, "b", "a")
+		g.set(12, "c", "b")
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		events := newClient().BlockStream(ctx, 10)
+
+		for _, hash := range []string{"a", "b", "c"} {
+			event := next(t, events)
+			assert.Equal(t, hash, event.Block.IndepHash)
+			assert.Empty(t, event.Orphaned)
+		}
+
+		g.mu.Lock()
+		g.blocks[11] = &Block{Height: 11, IndepHash: "b2", PreviousBlock: "a"}
+		g.blocks[12] = &Block{Height: 12, IndepHash: "c2", PreviousBlock: "b2"}
+		g.blocks[13] = &Block{Height: 13, IndepHash: "d2", PreviousBlock: "c2"}
+		g.mu.Unlock()
+
+		event := next(t, events)
+		assert.Equal(t, "b2", event.Block.IndepHash)
+		assert.Equal(t, []string{"c", "b"}, hashes(event.Orphaned))
+		assert.Equal(t, "c2", next(t, events).Block.IndepHash)
+		event = next(t, events)
+		assert.Equal(t, "d2", event.Block.IndepHash)
+		assert.Empty(t, event.Orphaned)
+
+		cancel()
+		for range events {
+		}
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		g.set(98, "x", "w")
+		events := newClient().BlockStream(context.Background(), 98)
+		assert.Equal(t, "x", next(t, events).Block.IndepHash)
+		event := <-events
+		assert.Error(t, event.Err)
+		_, ok := <-events
+		assert.False(t, ok)
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		c := newClient()
+		c.PollInterval = time.Second
+		ctx, cancel := context.WithCancel(context.Background())
+		events := c.BlockStream(ctx, 1000)
+		require.Eventually(t, func() bool { return len(c.clock.(*retry.FakeClock).Sleeps()) >= 2 }, 5*time.Second, time.Millisecond)
+		cancel()
+		for range events {
+		}
+		assert.Equal(t, time.Second, c.clock.(*retry.FakeClock).Sleeps()[0])
+	})
+}
@@ -9,6 +9,9 @@
 // - Block and network information retrieval
 // - Data uploading and chunk management
 //
+// Every request method has a Context variant, e.g. GetTransactionByIDContext,
+// which cancels the request or stops waiting when its context is done.
+//
 // Example usage:
 //
 //	client := client.New("https://arweave.net")
@@ -27,11 +30,16 @@
 package client
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"net/http"
+	"sync"
 	"time"
 
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
 	"github.com/liteseed/goar/transaction"
 )
 
@@ -41,30 +49,63 @@ import (
 // Arweave HTTP API endpoints. It includes automatic timeout handling
 // and error management for network operations.
 type Client struct {
-	Client  *http.Client // HTTP client with configured timeout
-	Gateway string       // Base URL of the Arweave gateway
+	Client        *http.Client  // HTTP client with configured timeout, see SetTransport; may be replaced by any *http.Client
+	UserAgent     string        // User-Agent header of every request, Go's default if empty; see WithUserAgent
+	Header        http.Header   // Headers added to every request, before those of SetGatewayHeaders; see WithHeaders
+	Gateway       string        // Base URL of the Arweave gateway, see SetGateway to change it while in use
+	RequestSigner RequestSigner // Optional signer applied to every outgoing request
+	ClockSkew     time.Duration // Estimated offset of the gateway clock from the local clock, see SyncClock
+	Retry         *RetryPolicy  // Optional policy retrying failed requests, nil to send every request once; see WithoutRetry
+	PollInterval  time.Duration // Fixed delay between two polls of WaitForConfirmation and BlockStream, 0 for their defaults
+	Compat        Compat        // Adaptations to development gateways such as arlocal, CompatNone by default
+
+	DisableCoalescing bool                   // Send identical concurrent GET requests separately instead of sharing one
+	flightMu          sync.Mutex             // Guards flights
+	flights           map[string]*flight     // GET requests in flight, keyed by URL
+	streams           chan struct{}          // Semaphore bounding concurrent requests, nil for no limit
+	clock             retry.Clock            // Time source of polling delays, retry.SystemClock if nil
+	gatewayMu         sync.RWMutex           // Guards Gateway and headers
+	headers           map[string]http.Header // Headers added to requests by host, see SetGatewayHeaders
+	pool              *pool                  // Gateways of a client created with NewPool, nil for a single gateway
+	closed            chan struct{}          // Closed by Close to stop background work, see closedChan
+	closedInit        sync.Once              // Creates closed
+	closeOnce         sync.Once              // Closes closed
+	compatMu          sync.Mutex             // Guards detected
+	detected          *bool                  // Whether the gateway is arlocal, once detected for CompatAuto
 }
 
 // New creates a new Arweave client with default settings.
 //
-// The client is configured with a 10-second timeout for all HTTP requests.
-// This timeout applies to individual requests, not the overall operation time.
+// The client is configured with a DEFAULT_TIMEOUT of 10 seconds for all HTTP
+// requests. This timeout applies to individual requests, not the overall
+// operation time. Options change it along with the proxy, the TLS settings
+// and the headers of every request, e.g. the API key of a private gateway.
 //
 // Parameters:
 //   - gateway: The base URL of the Arweave gateway (e.g., "https://arweave.net")
+//   - opts: Optional settings, applied in order
 //
 // Returns a configured Client instance ready for use.
 //
 // Example:
 //
 //	client := New("https://arweave.net")
-//	// or use a custom gateway
-//	client := New("https://my-arweave-node.com")
-func New(gateway string) *Client {
-	return &Client{
-		Client:  &http.Client{Timeout: time.Second * 10},
+//	// or use a private gateway behind a corporate proxy
+//	proxy, _ := url.Parse("http://proxy.internal:3128")
+//	client := New("https://my-arweave-node.com",
+//		WithTimeout(time.Minute),
+//		WithProxy(http.ProxyURL(proxy)),
+//		WithHeaders(http.Header{"X-Api-Key": {os.Getenv("GATEWAY_KEY")}}),
+//	)
+func New(gateway string, opts ...Option) *Client {
+	c := &Client{
+		Client:  &http.Client{Timeout: DEFAULT_TIMEOUT},
 		Gateway: gateway,
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c
 }
 
 // GetTransactionByID retrieves a complete transaction by its ID.
@@ -88,12 +129,17 @@ func New(gateway string) *Client {
 //	}
 //	fmt.Printf("Transaction from: %s\n", tx.Owner)
 func (c *Client) GetTransactionByID(id string) (*transaction.Transaction, error) {
-	body, err := c.get(fmt.Sprintf("tx/%s", id))
+	return c.GetTransactionByIDContext(context.Background(), id)
+}
+
+// GetTransactionByIDContext is GetTransactionByID bound to ctx.
+func (c *Client) GetTransactionByIDContext(ctx context.Context, id string) (*transaction.Transaction, error) {
+	body, err := c.getContext(ctx, fmt.Sprintf("tx/%s", id))
 	if err != nil {
 		return nil, err
 	}
 	t := &transaction.Transaction{}
-	err = json.Unmarshal(body, t)
+	err = decodeJSON(body, t)
 	if err != nil {
 		return nil, err
 	}
@@ -109,8 +155,9 @@ func (c *Client) GetTransactionByID(id string) (*transaction.Transaction, error)
 // Parameters:
 //   - id: The transaction ID to check status for
 //
-// Returns TransactionStatus with confirmation details or an error if
-// the transaction cannot be found.
+// Returns TransactionStatus with confirmation details, an unconfirmed
+// status while the transaction is pending, or an error if the transaction
+// cannot be found.
 //
 // Example:
 //
@@ -123,16 +170,26 @@ func (c *Client) GetTransactionByID(id string) (*transaction.Transaction, error)
 //		fmt.Printf("Transaction confirmed in block %s\n", status.BlockIndepHash)
 //	}
 func (c *Client) GetTransactionStatus(id string) (*TransactionStatus, error) {
-	body, err := c.get(fmt.Sprintf("tx/%s/status", id))
+	return c.GetTransactionStatusContext(context.Background(), id)
+}
+
+// GetTransactionStatusContext is GetTransactionStatus bound to ctx.
+func (c *Client) GetTransactionStatusContext(ctx context.Context, id string) (*TransactionStatus, error) {
+	body, err := c.getContext(ctx, fmt.Sprintf("tx/%s/status", id))
 	if err != nil {
 		return nil, err
 	}
+	// Gateways answer 202 Pending while the transaction is in the mempool
+	if string(body) == "Pending" {
+		return &TransactionStatus{}, nil
+	}
 
 	t := &TransactionStatus{}
-	err = json.Unmarshal(body, t)
+	err = decodeJSON(body, t)
 	if err != nil {
 		return nil, err
 	}
+	t.Confirmed = t.NumberOfConfirmations > 0 || (t.BlockIndepHash != "" && c.arlocal(ctx))
 	return t, nil
 }
 
@@ -160,7 +217,12 @@ func (c *Client) GetTransactionStatus(id string) (*TransactionStatus, error) {
 //	}
 //	fmt.Printf("Transaction tags: %s\n", tags)
 func (c *Client) GetTransactionField(id string, field string) (string, error) {
-	body, err := c.get(fmt.Sprintf("tx/%s/%s", id, field))
+	return c.GetTransactionFieldContext(context.Background(), id, field)
+}
+
+// GetTransactionFieldContext is GetTransactionField bound to ctx.
+func (c *Client) GetTransactionFieldContext(ctx context.Context, id string, field string) (string, error) {
+	body, err := c.getContext(ctx, fmt.Sprintf("tx/%s/%s", id, field))
 	if err != nil {
 		return "", err
 	}
@@ -188,13 +250,40 @@ func (c *Client) GetTransactionField(id string, field string) (string, error) {
 //	}
 //	fmt.Printf("Downloaded %d bytes\n", len(data))
 func (c *Client) GetTransactionData(id string) ([]byte, error) {
-	body, err := c.get(id)
+	return c.GetTransactionDataContext(context.Background(), id)
+}
+
+// GetTransactionDataContext is GetTransactionData bound to ctx.
+func (c *Client) GetTransactionDataContext(ctx context.Context, id string) ([]byte, error) {
+	body, err := c.getContext(ctx, id)
 	if err != nil {
 		return nil, err
 	}
 	return body, nil
 }
 
+// GetRawData retrieves the data of a transaction or data item as it was signed, from /raw/<id>.
+//
+// Unlike GetTransactionData, the gateway does not resolve manifests, so
+// the data of a manifest is its JSON rather than the content of its index.
+// The /raw route is served by ar.io gateways.
+//
+// Example:
+//
+//	data, err := client.GetRawData("ABC123...")
+func (c *Client) GetRawData(id string) ([]byte, error) {
+	return c.GetRawDataContext(context.Background(), id)
+}
+
+// GetRawDataContext is GetRawData bound to ctx.
+func (c *Client) GetRawDataContext(ctx context.Context, id string) ([]byte, error) {
+	body, err := c.getContext(ctx, fmt.Sprintf("raw/%s", id))
+	if errors.Is(err, goar.ErrNotFound) && c.arlocal(ctx) {
+		return c.getContext(ctx, id)
+	}
+	return body, err
+}
+
 // GetTransactionPrice calculates the cost to store data of a given size.
 //
 // This method queries the network for the current transaction fee based
@@ -217,12 +306,19 @@ func (c *Client) GetTransactionData(id string) ([]byte, error) {
 //	}
 //	fmt.Printf("Cost for 1KB: %s Winston\n", price)
 func (c *Client) GetTransactionPrice(size int, target string) (string, error) {
+	return c.GetTransactionPriceContext(context.Background(), size, target)
+}
+
+// GetTransactionPriceContext is GetTransactionPrice bound to ctx.
+func (c *Client) GetTransactionPriceContext(ctx context.Context, size int, target string) (string, error) {
 	url := fmt.Sprintf("price/%d/%s", size, target)
-	body, err := c.get(url)
+	body, err := c.getContext(ctx, url)
 	if err != nil {
 		return "", err
 	}
-
+	if c.arlocal(ctx) {
+		return roundPrice(string(body)), nil
+	}
 	return string(body), nil
 }
 
@@ -244,7 +340,12 @@ func (c *Client) GetTransactionPrice(size int, target string) (string, error) {
 //	}
 //	fmt.Printf("Current anchor: %s\n", anchor)
 func (c *Client) GetTransactionAnchor() (string, error) {
-	body, err := c.get("tx_anchor")
+	return c.GetTransactionAnchorContext(context.Background())
+}
+
+// GetTransactionAnchorContext is GetTransactionAnchor bound to ctx.
+func (c *Client) GetTransactionAnchorContext(ctx context.Context) (string, error) {
+	body, err := c.getContext(ctx, "tx_anchor")
 	if err != nil {
 		return "", err
 	}
@@ -274,11 +375,19 @@ func (c *Client) GetTransactionAnchor() (string, error) {
 //		fmt.Println("Transaction submitted successfully")
 //	}
 func (c *Client) SubmitTransaction(tx *transaction.Transaction) (int, error) {
-	b, err := json.Marshal(tx)
+	return c.SubmitTransactionContext(context.Background(), tx)
+}
+
+// SubmitTransactionContext is SubmitTransaction bound to ctx.
+func (c *Client) SubmitTransactionContext(ctx context.Context, tx *transaction.Transaction) (int, error) {
+	// Gateways only need the header, not the chunks kept by MarshalJSON
+	header := *tx
+	header.ChunkData = nil
+	b, err := json.Marshal(&header)
 	if err != nil {
 		return -1, err
 	}
-	return c.post("tx", b)
+	return c.postContext(withDefaultSticky(ctx, tx.DataRoot), "tx", b)
 }
 
 // GetWalletBalance retrieves the current AR token balance for a wallet.
@@ -302,7 +411,12 @@ func (c *Client) SubmitTransaction(tx *transaction.Transaction) (int, error) {
 //	}
 //	fmt.Printf("Wallet balance: %s Winston\n", balance)
 func (c *Client) GetWalletBalance(address string) (string, error) {
-	body, err := c.get(fmt.Sprintf("wallet/%s/balance", address))
+	return c.GetWalletBalanceContext(context.Background(), address)
+}
+
+// GetWalletBalanceContext is GetWalletBalance bound to ctx.
+func (c *Client) GetWalletBalanceContext(ctx context.Context, address string) (string, error) {
+	body, err := c.getContext(ctx, fmt.Sprintf("wallet/%s/balance", address))
 	if err != nil {
 		return "", err
 	}
@@ -330,7 +444,12 @@ func (c *Client) GetWalletBalance(address string) (string, error) {
 //	}
 //	fmt.Printf("Last transaction: %s\n", lastTx)
 func (c *Client) GetLastTransactionID(address string) (string, error) {
-	body, err := c.get(fmt.Sprintf("wallet/%s/last_tx", address))
+	return c.GetLastTransactionIDContext(context.Background(), address)
+}
+
+// GetLastTransactionIDContext is GetLastTransactionID bound to ctx.
+func (c *Client) GetLastTransactionIDContext(ctx context.Context, address string) (string, error) {
+	body, err := c.getContext(ctx, fmt.Sprintf("wallet/%s/last_tx", address))
 	if err != nil {
 		return "", err
 	}
@@ -358,12 +477,17 @@ func (c *Client) GetLastTransactionID(address string) (string, error) {
 //	}
 //	fmt.Printf("Block height: %d, TX count: %d\n", block.Height, len(block.Txs))
 func (c *Client) GetBlockByID(id string) (*Block, error) {
-	body, err := c.get(fmt.Sprintf("block/hash/%s", id))
+	return c.GetBlockByIDContext(context.Background(), id)
+}
+
+// GetBlockByIDContext is GetBlockByID bound to ctx.
+func (c *Client) GetBlockByIDContext(ctx context.Context, id string) (*Block, error) {
+	body, err := c.getContext(ctx, fmt.Sprintf("block/hash/%s", id))
 	if err != nil {
 		return nil, err
 	}
 	b := &Block{}
-	err = json.Unmarshal(body, b)
+	err = decodeJSON(body, b)
 	if err != nil {
 		return nil, err
 	}
@@ -391,12 +515,17 @@ func (c *Client) GetBlockByID(id string) (*Block, error) {
 //	}
 //	fmt.Printf("Block at height 1M: %s\n", block.IndepHash)
 func (c *Client) GetBlockByHeight(height string) (*Block, error) {
-	body, err := c.get(fmt.Sprintf("block/hash/%s", height))
+	return c.GetBlockByHeightContext(context.Background(), height)
+}
+
+// GetBlockByHeightContext is GetBlockByHeight bound to ctx.
+func (c *Client) GetBlockByHeightContext(ctx context.Context, height string) (*Block, error) {
+	body, err := c.getContext(ctx, fmt.Sprintf("block/height/%s", height))
 	if err != nil {
 		return nil, err
 	}
 	b := &Block{}
-	err = json.Unmarshal(body, b)
+	err = decodeJSON(body, b)
 	if err != nil {
 		return nil, err
 	}
@@ -421,12 +550,17 @@ func (c *Client) GetBlockByHeight(height string) (*Block, error) {
 //	}
 //	fmt.Printf("Network height: %d, Peers: %d\n", info.Height, info.Peers)
 func (c *Client) GetNetworkInfo() (*NetworkInfo, error) {
-	body, err := c.get("info")
+	return c.GetNetworkInfoContext(context.Background())
+}
+
+// GetNetworkInfoContext is GetNetworkInfo bound to ctx.
+func (c *Client) GetNetworkInfoContext(ctx context.Context) (*NetworkInfo, error) {
+	body, err := c.getContext(ctx, "info")
 	if err != nil {
 		return nil, err
 	}
 	n := NetworkInfo{}
-	err = json.Unmarshal(body, &n)
+	err = decodeJSON(body, &n)
 	if err != nil {
 		return nil, err
 	}
@@ -457,9 +591,99 @@ func (c *Client) GetNetworkInfo() (*NetworkInfo, error) {
 //		fmt.Println("Chunk uploaded successfully")
 //	}
 func (c *Client) UploadChunk(chunk *transaction.GetChunkResult) (int, error) {
+	return c.UploadChunkContext(context.Background(), chunk)
+}
+
+// UploadChunkContext is UploadChunk bound to ctx.
+func (c *Client) UploadChunkContext(ctx context.Context, chunk *transaction.GetChunkResult) (int, error) {
 	b, err := json.Marshal(chunk)
 	if err != nil {
 		return -1, err
 	}
-	return c.post("chunk", b)
+	return c.postContext(withDefaultSticky(ctx, chunk.DataRoot), "chunk", b)
+}
+
+// GetTransactionOffset retrieves the position of a transaction's data in the weave.
+//
+// The returned Offset is the absolute weave offset of the last byte of the
+// transaction's data, and Size is the data size in bytes. The absolute offset
+// of the first byte is therefore Offset - Size + 1.
+//
+// Parameters:
+//   - id: The transaction ID
+//
+// Returns the TransactionOffset, or an error if the transaction is unknown
+// or its data has not been synced by the node.
+//
+// Example:
+//
+//	offset, err := client.GetTransactionOffset("ABC123...")
+//	if err != nil {
+//		log.Printf("Failed to get offset: %v", err)
+//		return
+//	}
+//	fmt.Printf("Data starts at %d\n", offset.Offset-offset.Size+1)
+func (c *Client) GetTransactionOffset(id string) (*transaction.TransactionOffset, error) {
+	return c.GetTransactionOffsetContext(context.Background(), id)
+}
+
+// GetTransactionOffsetContext is GetTransactionOffset bound to ctx.
+func (c *Client) GetTransactionOffsetContext(ctx context.Context, id string) (*transaction.TransactionOffset, error) {
+	body, err := c.getContext(ctx, fmt.Sprintf("tx/%s/offset", id))
+	if err != nil {
+		return nil, err
+	}
+	// Nodes encode both values as strings
+	o := struct {
+		Size   json.Number `json:"size"`
+		Offset json.Number `json:"offset"`
+	}{}
+	if err = decodeJSON(body, &o); err != nil {
+		return nil, err
+	}
+	size, err := o.Size.Int64()
+	if err != nil {
+		return nil, err
+	}
+	offset, err := o.Offset.Int64()
+	if err != nil {
+		return nil, err
+	}
+	return &transaction.TransactionOffset{Size: size, Offset: offset}, nil
+}
+
+// GetChunk retrieves the chunk containing the given absolute weave offset.
+//
+// The chunk is returned together with its data_path (the Merkle proof
+// against the transaction's data_root) and tx_path (the proof against the
+// block's tx_root), all base64url-encoded.
+//
+// Parameters:
+//   - offset: An absolute weave offset within the chunk
+//
+// Returns the TransactionChunk, or an error if the chunk is not available.
+//
+// Example:
+//
+//	chunk, err := client.GetChunk(offset.Offset - offset.Size + 1)
+//	if err != nil {
+//		log.Printf("Failed to get chunk: %v", err)
+//		return
+//	}
+//	fmt.Printf("Chunk data: %s\n", chunk.Chunk)
+func (c *Client) GetChunk(offset int64) (*transaction.TransactionChunk, error) {
+	return c.GetChunkContext(context.Background(), offset)
+}
+
+// GetChunkContext is GetChunk bound to ctx.
+func (c *Client) GetChunkContext(ctx context.Context, offset int64) (*transaction.TransactionChunk, error) {
+	body, err := c.getContext(ctx, fmt.Sprintf("chunk/%d", offset))
+	if err != nil {
+		return nil, err
+	}
+	chunk := &transaction.TransactionChunk{}
+	if err = decodeJSON(body, chunk); err != nil {
+		return nil, err
+	}
+	return chunk, nil
 }
@@ -12,17 +12,18 @@ import (
 )
 
 func mint(t *testing.T, c *Client, address string) {
-	res, err := c.get("mint/" + address + "/1000000000000")
+	dev := &DevClient{Client: c}
+	balance, err := dev.Mint(address, "1000000000000")
 	if err != nil {
 		panic(0)
 	}
-	t.Logf("Balance: %s", string(res))
+	t.Logf("Balance: %s", balance)
 	mine(c)
 }
 
 func mine(c *Client) {
-	_, err := c.get("mine")
-	if err != nil {
+	dev := &DevClient{Client: c}
+	if err := dev.Mine(1); err != nil {
 		panic(0)
 	}
 }
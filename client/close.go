@@ -0,0 +1,44 @@
+package client
+
+import "context"
+
+// Close stops the background work of the client and closes its idle connections.
+//
+// RunHealthChecks, ReselectGateway and BlockStream return as if their
+// context were done, whether they were started before or after Close, so
+// a server embedding the client can shut down without tracking their
+// contexts. Requests in flight are not interrupted, and requests sent
+// after Close still work but open new connections. Close is safe to call
+// several times and always returns nil.
+//
+// Example:
+//
+//	c := client.New("https://arweave.net")
+//	defer c.Close()
+//	go c.RunHealthChecks(context.Background(), time.Minute)
+func (c *Client) Close() error {
+	closed := c.closedChan()
+	c.closeOnce.Do(func() { close(closed) })
+	c.Client.CloseIdleConnections()
+	return nil
+}
+
+// closedChan returns the channel closed by Close
+func (c *Client) closedChan() chan struct{} {
+	c.closedInit.Do(func() { c.closed = make(chan struct{}) })
+	return c.closed
+}
+
+// bind returns a context done when ctx is done or the client is closed; cancel must be called to release it
+func (c *Client) bind(ctx context.Context) (context.Context, context.CancelFunc) {
+	ctx, cancel := context.WithCancel(ctx)
+	closed := c.closedChan()
+	go func() {
+		select {
+		case <-closed:
+			cancel()
+		case <-ctx.Done():
+		}
+	}()
+	return ctx, cancel
+}
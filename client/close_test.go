@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClose(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+
+	waitDone := func(t *testing.T, done <-chan struct{}) {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("background work did not stop")
+		}
+	}
+
+	t.Run("Stops background work", func(t *testing.T) {
+		c := New(server.URL)
+		c.PollInterval = time.Millisecond
+
+		events := c.BlockStream(context.Background(), 1)
+		checks := make(chan struct{})
+		go func() {
+			c.RunHealthChecks(context.Background(), time.Millisecond)
+			close(checks)
+		}()
+		reselect := make(chan struct{})
+		go func() {
+			c.ReselectGateway(context.Background(), []string{server.URL}, time.Millisecond)
+			close(reselect)
+		}()
+
+		require.NoError(t, c.Close())
+		streamed := make(chan struct{})
+		go func() {
+			for range events {
+			}
+			close(streamed)
+		}()
+		waitDone(t, streamed)
+		waitDone(t, checks)
+		waitDone(t, reselect)
+	})
+
+	t.Run("After close", func(t *testing.T) {
+		c := New(server.URL)
+		require.NoError(t, c.Close())
+		require.NoError(t, c.Close())
+
+		_, ok := <-c.BlockStream(context.Background(), 1)
+		assert.False(t, ok)
+		_, err := c.GetNetworkInfo()
+		// Requests still reach the gateway
+		assert.ErrorIs(t, err, goar.ErrNotFound)
+	})
+}
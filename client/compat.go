@@ -0,0 +1,73 @@
+package client
+
+import (
+	"context"
+	"math/big"
+	"strings"
+)
+
+// Compat selects how a Client adapts to gateways that differ from the live network, see the Compat field.
+//
+// Code written for the live network then runs unchanged against a local
+// gateway in tests and development. Under CompatArlocal:
+//   - GetTransactionPrice rounds the fractional prices of arlocal up to whole winston
+//   - GetTransactionStatus reports transactions in a block as confirmed, which
+//     arlocal counts as 0 confirmations until the next block
+//   - GetRawData falls back to /<id>, as arlocal has no /raw route
+//   - GetPendingTransactions returns no transactions if /tx/pending is missing
+//   - GetTime returns the local time if /time is missing, arlocal running locally
+//
+// Example:
+//
+//	c := client.New(os.Getenv("GATEWAY"))
+//	c.Compat = client.CompatAuto
+type Compat int
+
+const (
+	CompatNone    Compat = iota // Gateways of the live network, the default
+	CompatArlocal               // arlocal, the local development gateway
+	CompatAuto                  // CompatArlocal if the network name in /info starts with ARLOCAL_NETWORK, detected on first use
+)
+
+// ARLOCAL_NETWORK is the prefix of the network name arlocal reports in /info
+const ARLOCAL_NETWORK = "arlocal"
+
+// arlocal reports whether the client adapts requests to arlocal, detecting it for CompatAuto.
+//
+// A failed detection is tried again on the next adapted request.
+func (c *Client) arlocal(ctx context.Context) bool {
+	switch c.Compat {
+	case CompatArlocal:
+		return true
+	case CompatAuto:
+		c.compatMu.Lock()
+		defer c.compatMu.Unlock()
+		if c.detected == nil {
+			info, err := c.GetNetworkInfoContext(ctx)
+			if err != nil {
+				return false
+			}
+			detected := strings.HasPrefix(info.Network, ARLOCAL_NETWORK)
+			c.detected = &detected
+		}
+		return *c.detected
+	}
+	return false
+}
+
+// roundPrice rounds a decimal price up to whole winston, returning other values unchanged
+func roundPrice(price string) string {
+	price = strings.TrimSpace(price)
+	whole, fraction, ok := strings.Cut(price, ".")
+	if !ok {
+		return price
+	}
+	n, ok := new(big.Int).SetString(whole, 10)
+	if !ok || strings.Trim(fraction, "0123456789") != "" {
+		return price
+	}
+	if strings.Trim(fraction, "0") != "" {
+		n.Add(n, big.NewInt(1))
+	}
+	return n.String()
+}
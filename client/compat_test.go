@@ -0,0 +1,112 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newArlocal returns a server answering like arlocal and counting its /info requests
+func newArlocal(t *testing.T, infos *atomic.Int32) *httptest.Server {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /info", func(w http.ResponseWriter, r *http.Request) {
+		infos.Add(1)
+		_, _ = w.Write([]byte(`{"network":"arlocal.N.1","version":1,"release":1,"height":1}`))
+	})
+	mux.HandleFunc("GET /price/{size}/", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("65595.508"))
+	})
+	mux.HandleFunc("GET /tx/{id}/status", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"block_height":1,"block_indep_hash":"hash","number_of_confirmations":0}`))
+	})
+	mux.HandleFunc("GET /{id}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("id") != "id" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte("data of id"))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCompat(t *testing.T) {
+	var infos atomic.Int32
+	server := newArlocal(t, &infos)
+
+	t.Run("None", func(t *testing.T) {
+		c := New(server.URL)
+		price, err := c.GetTransactionPrice(1000, "")
+		require.NoError(t, err)
+		assert.Equal(t, "65595.508", price)
+		status, err := c.GetTransactionStatus("id")
+		require.NoError(t, err)
+		assert.False(t, status.Confirmed)
+		_, err = c.GetRawData("id")
+		assert.ErrorIs(t, err, goar.ErrNotFound)
+		_, err = c.GetPendingTransactions()
+		assert.ErrorIs(t, err, goar.ErrNotFound)
+		assert.Zero(t, infos.Load())
+	})
+
+	for _, compat := range []Compat{CompatArlocal, CompatAuto} {
+		c := New(server.URL)
+		c.Compat = compat
+		price, err := c.GetTransactionPrice(1000, "")
+		require.NoError(t, err)
+		assert.Equal(t, "65596", price)
+		status, err := c.GetTransactionStatus("id")
+		require.NoError(t, err)
+		assert.True(t, status.Confirmed)
+		data, err := c.GetRawData("id")
+		require.NoError(t, err)
+		assert.Equal(t, "data of id", string(data))
+		pending, err := c.GetPendingTransactions()
+		require.NoError(t, err)
+		assert.Empty(t, pending)
+		_, err = c.GetTime()
+		assert.NoError(t, err)
+	}
+	// Detected once by the CompatAuto client
+	assert.Equal(t, int32(1), infos.Load())
+
+	t.Run("Auto on the live network", func(t *testing.T) {
+		mux := http.NewServeMux()
+		mux.HandleFunc("GET /info", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"network":"arweave.N.1"}`))
+		})
+		mux.HandleFunc("GET /price/{size}/", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("65595.508"))
+		})
+		server := httptest.NewServer(mux)
+		defer server.Close()
+		c := New(server.URL)
+		c.Compat = CompatAuto
+		price, err := c.GetTransactionPrice(1000, "")
+		require.NoError(t, err)
+		assert.Equal(t, "65595.508", price)
+	})
+
+	t.Run("NewDev", func(t *testing.T) {
+		assert.Equal(t, CompatArlocal, NewDev(server.URL).Compat)
+	})
+}
+
+func TestRoundPrice(t *testing.T) {
+	for price, expected := range map[string]string{
+		"100":        "100",
+		"100.0":      "100",
+		"100.000001": "101",
+		" 99.5\n":    "100",
+		"1e3":        "1e3",
+		"abc.5":      "abc.5",
+	} {
+		assert.Equal(t, expected, roundPrice(price), price)
+	}
+}
@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+)
+
+// Polling settings of WaitForConfirmation
+const (
+	CONFIRMATION_POLL_BASE = 5 * time.Second // Delay before the second status request
+	CONFIRMATION_POLL_MAX  = time.Minute     // Upper bound of the delay between two status requests
+)
+
+// WaitForConfirmation polls the status of transaction id until it has at least confirmations confirmations.
+//
+// The delay between two requests doubles from CONFIRMATION_POLL_BASE up to
+// CONFIRMATION_POLL_MAX, with jitter, unless the client has a PollInterval,
+// which is then waited between every two requests. A transaction that is not found yet,
+// e.g. still pending in the mempool, is polled again.
+//
+// Parameters:
+//   - id: The transaction ID
+//   - confirmations: The number of confirmations to wait for, at least 1
+//   - timeout: How long to wait, 0 for no limit
+//
+// Returns the last status once confirmed, or an error if a request fails
+// or timeout elapses first.
+//
+// Example:
+//
+//	status, err := client.WaitForConfirmation(tx.ID, 10, 30*time.Minute)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Confirmed in block %d\n", status.BlockHeight)
+func (c *Client) WaitForConfirmation(id string, confirmations int, timeout time.Duration) (*TransactionStatus, error) {
+	return c.WaitForConfirmationContext(context.Background(), id, confirmations, timeout)
+}
+
+// WaitForConfirmationContext is WaitForConfirmation bound to ctx.
+func (c *Client) WaitForConfirmationContext(ctx context.Context, id string, confirmations int, timeout time.Duration) (*TransactionStatus, error) {
+	confirmations = max(confirmations, 1)
+	backoff := retry.Backoff{Base: CONFIRMATION_POLL_BASE, Max: CONFIRMATION_POLL_MAX, Exponential: true, Jitter: 0.2}
+	if c.PollInterval > 0 {
+		backoff = retry.Backoff{Base: c.PollInterval, Max: c.PollInterval}
+	}
+
+	var status *TransactionStatus
+	err := retry.PollContext(ctx, c.getClock(), backoff, timeout, func() (bool, error) {
+		s, err := c.GetTransactionStatusContext(ctx, id)
+		if errors.Is(err, goar.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		status = s
+		return s.NumberOfConfirmations >= confirmations, nil
+	})
+	if errors.Is(err, retry.ErrTimeout) {
+		return status, fmt.Errorf("timed out waiting for %d confirmations of %s", confirmations, id)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// getClock returns the clock polling delays are waited on
+func (c *Client) getClock() retry.Clock {
+	if c.clock == nil {
+		return retry.SystemClock
+	}
+	return c.clock
+}
@@ -0,0 +1,73 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar/internal/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWaitForConfirmation(t *testing.T) {
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch calls.Add(1) {
+		case 1:
+			http.NotFound(w, r)
+		case 2:
+			w.WriteHeader(http.StatusAccepted)
+			_, _ = w.Write([]byte("Pending"))
+		case 3:
+			_, _ = w.Write([]byte(`{"block_height":10,"block_indep_hash":"hash","number_of_confirmations":1}`))
+		default:
+			_, _ = w.Write([]byte(`{"block_height":10,"block_indep_hash":"hash","number_of_confirmations":3}`))
+		}
+	}))
+	defer server.Close()
+
+	t.Run("Confirmed", func(t *testing.T) {
+		c := New(server.URL)
+		clock := retry.NewFakeClock(time.Unix(0, 0))
+		c.clock = clock
+
+		status, err := c.WaitForConfirmation("tx", 3, 0)
+		require.NoError(t, err)
+		assert.True(t, status.Confirmed)
+		assert.Equal(t, 3, status.NumberOfConfirmations)
+		assert.Equal(t, int32(4), calls.Load())
+
+		sleeps := clock.Sleeps()
+		require.Len(t, sleeps, 3)
+		for i, d := range sleeps {
+			expected := CONFIRMATION_POLL_BASE << i
+			assert.True(t, d > expected*8/10 && d <= expected, "sleep %d: %s", i, d)
+		}
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		calls.Store(0)
+		c := New(server.URL)
+		c.clock = retry.NewFakeClock(time.Unix(0, 0))
+
+		status, err := c.WaitForConfirmation("tx", 10, time.Minute)
+		assert.ErrorContains(t, err, "timed out")
+		require.NotNil(t, status)
+		assert.Equal(t, 3, status.NumberOfConfirmations)
+	})
+
+	t.Run("Interval", func(t *testing.T) {
+		calls.Store(0)
+		c := New(server.URL)
+		clock := retry.NewFakeClock(time.Unix(0, 0))
+		c.clock = clock
+		c.PollInterval = 2 * time.Second
+
+		_, err := c.WaitForConfirmation("tx", 3, 0)
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{2 * time.Second, 2 * time.Second, 2 * time.Second}, clock.Sleeps())
+	})
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"fmt"
+)
+
+// DevClient is a Client for a local development gateway such as arlocal.
+//
+// It adds the endpoints of arlocal that have no equivalent on the live
+// network, so tests running against it can fund wallets and confirm
+// transactions without reaching into the client.
+//
+// Example:
+//
+//	dev := client.NewDev("http://localhost:1984")
+//	if _, err := dev.Mint(s.Address, "1000000000000"); err != nil {
+//		log.Fatal(err)
+//	}
+//	_, err := dev.SubmitTransaction(tx)
+//	...
+//	err = dev.Mine(1)
+type DevClient struct {
+	*Client
+}
+
+// NewDev creates a DevClient for the development gateway, with the settings of New and CompatArlocal.
+func NewDev(gateway string) *DevClient {
+	c := New(gateway)
+	c.Compat = CompatArlocal
+	return &DevClient{Client: c}
+}
+
+// Mint adds amount Winston to the balance of a wallet.
+//
+// Parameters:
+//   - address: The wallet address to fund
+//   - amount: The amount in Winston
+//
+// Returns the new balance of the wallet in Winston as reported by the gateway.
+// The balance is available at once; Mine is not needed.
+func (d *DevClient) Mint(address string, amount string) (string, error) {
+	return d.MintContext(context.Background(), address, amount)
+}
+
+// MintContext is Mint bound to ctx.
+func (d *DevClient) MintContext(ctx context.Context, address string, amount string) (string, error) {
+	body, err := d.devGet(ctx, fmt.Sprintf("mint/%s/%s", address, amount))
+	if err != nil {
+		return "", err
+	}
+	return string(body), nil
+}
+
+// Mine mines n blocks, confirming the pending transactions in the first one.
+func (d *DevClient) Mine(n int) error {
+	return d.MineContext(context.Background(), n)
+}
+
+// MineContext is Mine bound to ctx.
+func (d *DevClient) MineContext(ctx context.Context, n int) error {
+	_, err := d.devGet(ctx, fmt.Sprintf("mine/%d", n))
+	return err
+}
+
+// devGet requests a development route. The routes change the state of the
+// gateway, so concurrent requests are never coalesced as GETs are.
+func (d *DevClient) devGet(ctx context.Context, route string) ([]byte, error) {
+	u, err := d.url(route)
+	if err != nil {
+		return nil, err
+	}
+	return d.fetch(ctx, u)
+}
@@ -0,0 +1,67 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDevClient(t *testing.T) {
+	var mined atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /mint/{address}/{amount}", func(w http.ResponseWriter, r *http.Request) {
+		if r.PathValue("address") != "addr" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		_, _ = w.Write([]byte(r.PathValue("amount")))
+	})
+	mux.HandleFunc("GET /mine/{qty}", func(w http.ResponseWriter, r *http.Request) {
+		n, err := strconv.Atoi(r.PathValue("qty"))
+		if err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		mined.Add(int32(n))
+		_, _ = w.Write([]byte(`{"network":"arweave.localnet"}`))
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	dev := NewDev(server.URL)
+
+	t.Run("Mint", func(t *testing.T) {
+		balance, err := dev.Mint("addr", "1000")
+		require.NoError(t, err)
+		assert.Equal(t, "1000", balance)
+
+		_, err = dev.Mint("other", "1000")
+		assert.Equal(t, goar.ErrBadRequest, goar.CodeOf(err))
+	})
+
+	t.Run("Mine", func(t *testing.T) {
+		mined.Store(0)
+		require.NoError(t, dev.Mine(3))
+		assert.Equal(t, int32(3), mined.Load())
+	})
+
+	t.Run("ConcurrentMinesAreNotCoalesced", func(t *testing.T) {
+		mined.Store(0)
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				assert.NoError(t, dev.Mine(1))
+			}()
+		}
+		wg.Wait()
+		assert.Equal(t, int32(5), mined.Load())
+	})
+}
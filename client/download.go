@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction"
+)
+
+// DownloadChunkedData downloads the data of a transaction chunk by chunk and writes it to w.
+//
+// The data root and size are taken from the transaction header and the
+// position of the data from /tx/<id>/offset. Every chunk fetched from
+// /chunk/<offset> is validated against the data root with its data_path
+// before it is written, so w only ever receives verified data, and no more
+// than one chunk is held in memory. Unlike GetTransactionData, this works
+// for data of any size and only trusts the gateway for the transaction
+// header, whose data root can be checked against a block with
+// VerifyChunkInBlock.
+//
+// Parameters:
+//   - id: The transaction ID
+//   - w: The destination of the data
+//
+// Returns the number of bytes written. The error has code
+// goar.ErrInvalidSignature if a chunk does not verify against the data
+// root, goar.ErrDecode if a chunk cannot be decoded, or the code of the
+// failed request. Data written before the error is valid.
+//
+// Example:
+//
+//	f, err := os.Create("data.bin")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	n, err := client.DownloadChunkedData("ABC123...", f)
+func (c *Client) DownloadChunkedData(id string, w io.Writer) (int64, error) {
+	return c.DownloadChunkedDataContext(context.Background(), id, w)
+}
+
+// DownloadChunkedDataContext is DownloadChunkedData bound to ctx.
+func (c *Client) DownloadChunkedDataContext(ctx context.Context, id string, w io.Writer) (int64, error) {
+	tx, err := c.GetTransactionByIDContext(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	size, err := strconv.ParseInt(tx.DataSize, 10, 64)
+	if err != nil {
+		return 0, goar.Wrap(goar.ErrDecode, err)
+	}
+	if size == 0 {
+		return 0, nil
+	}
+	dataRoot, err := crypto.Base64URLDecode(tx.DataRoot)
+	if err != nil {
+		return 0, goar.Wrap(goar.ErrDecode, err)
+	}
+	offset, err := c.GetTransactionOffsetContext(ctx, id)
+	if err != nil {
+		return 0, err
+	}
+	if offset.Size != size {
+		return 0, goar.Errorf(goar.ErrInvalidSignature, "gateway reports %d bytes of data, the transaction has %d", offset.Size, size)
+	}
+
+	start := offset.Offset - size + 1
+	var written int64
+	for written < size {
+		chunk, err := c.GetChunkContext(ctx, start+written)
+		if err != nil {
+			return written, err
+		}
+		data, err := verifyChunk(chunk, dataRoot, written, size)
+		if err != nil {
+			return written, fmt.Errorf("chunk at offset %d: %w", written, err)
+		}
+		n, err := w.Write(data)
+		written += int64(n)
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// verifyChunk decodes a downloaded chunk and checks that it starts at
+// offset within the data of size bytes whose Merkle root is dataRoot
+func verifyChunk(chunk *transaction.TransactionChunk, dataRoot []byte, offset int64, size int64) ([]byte, error) {
+	data, err := crypto.Base64URLDecode(chunk.Chunk)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	dataPath, err := crypto.Base64URLDecode(chunk.DataPath)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	result, err := transaction.ValidateChunk(dataRoot, int(offset), int(size), data, dataPath)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrInvalidSignature, err)
+	}
+	if int64(result.LeftBound) != offset {
+		return nil, goar.Errorf(goar.ErrInvalidSignature, "chunk starts at %d", result.LeftBound)
+	}
+	return data, nil
+}
+
+// VerifyChunkInBlock verifies a chunk downloaded from an absolute weave
+// offset against the header of the block holding it.
+//
+// The tx_path of the chunk is validated against the tx_root of the block,
+// which proves the data root of the chunk's transaction, then the data_path
+// against that data root. Nothing returned by the gateway is trusted: only
+// the block header, which callers check independently, e.g. against the
+// indep_hash reported by several peers.
+//
+// Parameters:
+//   - block: The header of the block holding the chunk
+//   - offset: The absolute weave offset the chunk was requested at with GetChunk
+//   - chunk: The chunk
+//
+// Returns the verified chunk data and the raw data root of its transaction.
+// The error has code goar.ErrInvalidSignature if a proof does not verify or
+// offset is outside the block, or goar.ErrDecode if the chunk or block
+// cannot be decoded.
+//
+// Example:
+//
+//	chunk, err := client.GetChunk(offset)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	data, dataRoot, err := client.VerifyChunkInBlock(block, offset, chunk)
+func VerifyChunkInBlock(block *Block, offset int64, chunk *transaction.TransactionChunk) ([]byte, []byte, error) {
+	txRoot, err := crypto.Base64URLDecode(block.TxRoot)
+	if err != nil {
+		return nil, nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	txPath, err := crypto.Base64URLDecode(chunk.TxPath)
+	if err != nil {
+		return nil, nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	dataPath, err := crypto.Base64URLDecode(chunk.DataPath)
+	if err != nil {
+		return nil, nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	if len(txPath) < transaction.HASH_SIZE+transaction.NOTE_SIZE {
+		return nil, nil, goar.Errorf(goar.ErrInvalidSignature, "chunk has no tx_path")
+	}
+
+	// Weave offsets count from 1, block boundaries from 0
+	blockStart := int64(block.WeaveSize) - int64(block.BlockSize)
+	blockOffset := offset - 1 - blockStart
+	if blockOffset < 0 || blockOffset >= int64(block.BlockSize) {
+		return nil, nil, goar.Errorf(goar.ErrInvalidSignature, "offset %d is outside block %d", offset, block.Height)
+	}
+	dataRoot := txPath[len(txPath)-transaction.HASH_SIZE-transaction.NOTE_SIZE : len(txPath)-transaction.NOTE_SIZE]
+	tx, err := transaction.ValidateTxPath(txRoot, int(blockOffset), int(block.BlockSize), dataRoot, txPath)
+	if err != nil {
+		return nil, nil, goar.Wrap(goar.ErrInvalidSignature, fmt.Errorf("tx_path: %w", err))
+	}
+
+	data, err := crypto.Base64URLDecode(chunk.Chunk)
+	if err != nil {
+		return nil, nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	if _, err := transaction.ValidateChunk(dataRoot, int(blockOffset)-tx.LeftBound, tx.RightBound-tx.LeftBound, data, dataPath); err != nil {
+		return nil, nil, goar.Wrap(goar.ErrInvalidSignature, fmt.Errorf("data_path: %w", err))
+	}
+	return data, dataRoot, nil
+}
@@ -0,0 +1,142 @@
+package client
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestDownloadChunkedData verifies data is reassembled from verified chunks
+func TestDownloadChunkedData(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	tx := transaction.NewDataTransaction(nil, nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	tx.ID = "downloaded"
+	src := bytes.NewReader(data)
+
+	const end = int64(10_000_000)
+	start := end - int64(len(data)) + 1
+	tamper := -1
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tx/{id}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(tx)
+	})
+	mux.HandleFunc("GET /tx/{id}/offset", func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"size":"%d","offset":"%d"}`, len(data), end)
+	})
+	mux.HandleFunc("GET /chunk/{offset}", func(w http.ResponseWriter, r *http.Request) {
+		offset, err := strconv.ParseInt(r.PathValue("offset"), 10, 64)
+		require.NoError(t, err)
+		chunks, err := tx.ChunksInRange(offset-start, offset-start+1, src)
+		require.NoError(t, err)
+		b, err := chunks[0].Bytes()
+		require.NoError(t, err)
+		if chunks[0].Index == tamper {
+			b[0] ^= 0xff
+		}
+		_ = json.NewEncoder(w).Encode(transaction.TransactionChunk{
+			Chunk:    crypto.Base64URLEncode(b),
+			DataPath: crypto.Base64URLEncode(chunks[0].Proof),
+		})
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	c := New(server.URL)
+
+	var buf bytes.Buffer
+	n, err := c.DownloadChunkedData(tx.ID, &buf)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(data)), n)
+	assert.Equal(t, data, buf.Bytes())
+
+	t.Run("Tampered chunk", func(t *testing.T) {
+		tamper = 2
+		defer func() { tamper = -1 }()
+		var buf bytes.Buffer
+		n, err := c.DownloadChunkedData(tx.ID, &buf)
+		assert.Equal(t, goar.ErrInvalidSignature, goar.CodeOf(err))
+		assert.Equal(t, int64(2*transaction.MAX_CHUNK_SIZE), n)
+		assert.Equal(t, data[:n], buf.Bytes())
+	})
+}
+
+// TestVerifyChunkInBlock verifies chunks are proven against the block header
+func TestVerifyChunkInBlock(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	tx := transaction.NewDataTransaction(nil, nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	dataRoot, err := crypto.Base64URLDecode(tx.DataRoot)
+	require.NoError(t, err)
+
+	// A block holding the transaction followed by another one of 100 bytes
+	note := func(n int) []byte {
+		b := make([]byte, transaction.NOTE_SIZE)
+		binary.BigEndian.PutUint64(b[transaction.NOTE_SIZE-8:], uint64(n))
+		return b
+	}
+	leaf := func(root []byte, end int) []byte {
+		return crypto.SHA256(append(crypto.SHA256(root), crypto.SHA256(note(end))...))
+	}
+	other := crypto.SHA256([]byte("other"))
+	size := len(data)
+	left, right := leaf(dataRoot, size), leaf(other, size+100)
+	txRoot := crypto.SHA256(append(append(crypto.SHA256(left), crypto.SHA256(right)...), crypto.SHA256(note(size))...))
+	txPath := append(append(append(append(append([]byte{}, left...), right...), note(size)...), dataRoot...), note(size)...)
+	block := &Block{Height: 42, TxRoot: crypto.Base64URLEncode(txRoot), WeaveSize: uint64(5_000_000 + size + 100), BlockSize: uint64(size + 100)}
+
+	src := bytes.NewReader(data)
+	chunkAt := func(i int) (int64, *transaction.TransactionChunk) {
+		info, err := tx.ChunkAt(i, src)
+		require.NoError(t, err)
+		b, err := info.Bytes()
+		require.NoError(t, err)
+		return 5_000_000 + info.Start + 1, &transaction.TransactionChunk{
+			Chunk:    crypto.Base64URLEncode(b),
+			DataPath: crypto.Base64URLEncode(info.Proof),
+			TxPath:   crypto.Base64URLEncode(txPath),
+		}
+	}
+
+	for i := range tx.ChunkData.Chunks {
+		offset, chunk := chunkAt(i)
+		got, root, err := VerifyChunkInBlock(block, offset, chunk)
+		require.NoError(t, err)
+		assert.Equal(t, dataRoot, root)
+		assert.Equal(t, data[i*transaction.MAX_CHUNK_SIZE:(i+1)*transaction.MAX_CHUNK_SIZE], got)
+	}
+
+	t.Run("Invalid", func(t *testing.T) {
+		offset, chunk := chunkAt(1)
+		tests := []struct {
+			name   string
+			block  *Block
+			offset int64
+			chunk  transaction.TransactionChunk
+		}{
+			{"Other block", &Block{TxRoot: crypto.Base64URLEncode(other), WeaveSize: block.WeaveSize, BlockSize: block.BlockSize}, offset, *chunk},
+			{"Outside block", block, 5_000_000, *chunk},
+			{"Wrong offset", block, offset + transaction.MAX_CHUNK_SIZE, *chunk},
+			{"No tx_path", block, offset, transaction.TransactionChunk{Chunk: chunk.Chunk, DataPath: chunk.DataPath}},
+			{"Wrong chunk", block, offset, transaction.TransactionChunk{Chunk: crypto.Base64URLEncode(data[:transaction.MAX_CHUNK_SIZE]), DataPath: chunk.DataPath, TxPath: chunk.TxPath}},
+		}
+		for _, tt := range tests {
+			_, _, err := VerifyChunkInBlock(tt.block, tt.offset, &tt.chunk)
+			assert.Equal(t, goar.ErrInvalidSignature, goar.CodeOf(err), tt.name)
+		}
+	})
+}
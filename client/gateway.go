@@ -0,0 +1,152 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+)
+
+// GatewayLatency is the result of probing a gateway.
+type GatewayLatency struct {
+	Gateway string        // Base URL of the gateway
+	Latency time.Duration // Time to fetch /info, valid when Err is nil
+	Err     error         // Why the gateway could not be used, nil if it answered
+}
+
+// ProbeGateways fetches /info from every candidate concurrently and measures the time each takes.
+//
+// The results are sorted by latency, gateways that failed last. Probes are
+// bound to ctx and to the timeout of the client's HTTP client.
+func (c *Client) ProbeGateways(ctx context.Context, candidates []string) []GatewayLatency {
+	results := make([]GatewayLatency, len(candidates))
+	var wg sync.WaitGroup
+	for i, gateway := range candidates {
+		wg.Add(1)
+		go func(i int, gateway string) {
+			defer wg.Done()
+			latency, err := c.probe(ctx, gateway)
+			results[i] = GatewayLatency{Gateway: gateway, Latency: latency, Err: err}
+		}(i, gateway)
+	}
+	wg.Wait()
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if (results[i].Err == nil) != (results[j].Err == nil) {
+			return results[i].Err == nil
+		}
+		return results[i].Latency < results[j].Latency
+	})
+	return results
+}
+
+// probe returns the time taken to fetch the /info of gateway
+func (c *Client) probe(ctx context.Context, gateway string) (time.Duration, error) {
+	u, err := joinURL(gateway, "info")
+	if err != nil {
+		return 0, goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	if err != nil {
+		return 0, goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	c.applyHeaders(req)
+	start := time.Now()
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return 0, networkError(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, goar.Wrap(goar.ErrNetwork, err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return 0, statusError(resp.StatusCode, body)
+	}
+	var info NetworkInfo
+	if err := decodeJSON(body, &info); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
+
+// PickFastestGateway returns the candidate answering /info the fastest.
+//
+// Parameters:
+//   - ctx: Bounds the probes
+//   - candidates: Base URLs of the gateways to choose from
+//
+// Returns an error with code goar.ErrNetwork if no candidate answered.
+//
+// Example:
+//
+//	gateway, err := c.PickFastestGateway(ctx, []string{"https://arweave.net", "https://g8way.io"})
+func (c *Client) PickFastestGateway(ctx context.Context, candidates []string) (string, error) {
+	results := c.ProbeGateways(ctx, candidates)
+	if len(results) == 0 {
+		return "", goar.Errorf(goar.ErrInvalidInput, "no gateway candidates")
+	}
+	if results[0].Err != nil {
+		return "", goar.Errorf(goar.ErrNetwork, "no gateway answered, first error: %v", results[0].Err)
+	}
+	return results[0].Gateway, nil
+}
+
+// NewFastest creates a client using the candidate gateway answering the fastest, see PickFastestGateway.
+//
+// Example:
+//
+//	c, err := client.NewFastest(ctx, []string{"https://arweave.net", "https://g8way.io"})
+func NewFastest(ctx context.Context, candidates []string) (*Client, error) {
+	c := New("")
+	gateway, err := c.PickFastestGateway(ctx, candidates)
+	if err != nil {
+		return nil, err
+	}
+	c.SetGateway(gateway)
+	return c, nil
+}
+
+// SetGateway changes the gateway requests are sent to. It is safe to call
+// while requests are in flight, unlike assigning the Gateway field.
+func (c *Client) SetGateway(gateway string) {
+	c.gatewayMu.Lock()
+	defer c.gatewayMu.Unlock()
+	c.Gateway = gateway
+}
+
+// CurrentGateway returns the gateway requests are sent to. Use it instead
+// of reading the Gateway field while ReselectGateway runs.
+func (c *Client) CurrentGateway() string {
+	c.gatewayMu.RLock()
+	defer c.gatewayMu.RUnlock()
+	return c.Gateway
+}
+
+// ReselectGateway switches the client to the fastest candidate every interval until ctx is done or the client is closed.
+//
+// It blocks, so it is usually run in its own goroutine. Rounds in which no
+// candidate answers keep the current gateway. It returns at once if
+// interval is not positive.
+//
+// Example:
+//
+//	go c.ReselectGateway(ctx, candidates, 10*time.Minute)
+func (c *Client) ReselectGateway(ctx context.Context, candidates []string, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ctx, cancel := c.bind(ctx)
+	defer cancel()
+	for retry.Sleep(c.getClock(), interval, ctx.Done()) {
+		if gateway, err := c.PickFastestGateway(ctx, candidates); err == nil {
+			c.SetGateway(gateway)
+		}
+	}
+}
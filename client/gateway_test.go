@@ -0,0 +1,82 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newInfoServer answers /info after delay and calls probed on every request
+func newInfoServer(t *testing.T, delay time.Duration, probed func()) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if probed != nil {
+			probed()
+		}
+		time.Sleep(delay)
+		_, _ = w.Write([]byte(`{"network":"arweave.N.1","height":10}`))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestPickFastestGateway(t *testing.T) {
+	slow := newInfoServer(t, 100*time.Millisecond, nil)
+	fast := newInfoServer(t, 0, nil)
+	broken := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "down", http.StatusBadGateway)
+	}))
+	defer broken.Close()
+	c := New("")
+
+	results := c.ProbeGateways(context.Background(), []string{broken.URL, slow.URL, fast.URL})
+	require.Len(t, results, 3)
+	assert.Equal(t, fast.URL, results[0].Gateway)
+	assert.Equal(t, slow.URL, results[1].Gateway)
+	assert.GreaterOrEqual(t, results[1].Latency, 100*time.Millisecond)
+	assert.ErrorIs(t, results[2].Err, goar.ErrGateway)
+
+	gateway, err := c.PickFastestGateway(context.Background(), []string{slow.URL, fast.URL})
+	require.NoError(t, err)
+	assert.Equal(t, fast.URL, gateway)
+
+	_, err = c.PickFastestGateway(context.Background(), []string{broken.URL})
+	assert.ErrorIs(t, err, goar.ErrNetwork)
+
+	_, err = c.PickFastestGateway(context.Background(), nil)
+	assert.ErrorIs(t, err, goar.ErrInvalidInput)
+
+	c, err = NewFastest(context.Background(), []string{slow.URL, fast.URL, broken.URL})
+	require.NoError(t, err)
+	assert.Equal(t, fast.URL, c.CurrentGateway())
+}
+
+func TestReselectGateway(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var probes atomic.Int32
+	slow := newInfoServer(t, 100*time.Millisecond, nil)
+	fast := newInfoServer(t, 0, func() {
+		// Stop after the second round
+		if probes.Add(1) == 2 {
+			cancel()
+		}
+	})
+
+	c := New(slow.URL)
+	clock := retry.NewFakeClock(time.Unix(0, 0))
+	c.clock = clock
+	c.ReselectGateway(ctx, []string{slow.URL, fast.URL}, time.Minute)
+
+	assert.Equal(t, fast.URL, c.CurrentGateway())
+	assert.Equal(t, int32(2), probes.Load())
+	assert.Equal(t, time.Minute, clock.Sleeps()[0])
+}
@@ -0,0 +1,74 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+
+	"github.com/liteseed/goar"
+)
+
+type graphQLRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]any `json:"variables,omitempty"`
+}
+
+type graphQLResponse struct {
+	Data   json.RawMessage `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+	} `json:"errors"`
+}
+
+// GraphQL runs a query against the gateway's /graphql endpoint.
+//
+// Parameters:
+//   - query: The GraphQL query
+//   - variables: Optional query variables
+//   - v: Pointer to a value the data field of the response is decoded into
+//
+// Returns an error with code goar.ErrBadRequest if the gateway reports
+// query errors, or the request or decoding error otherwise.
+//
+// Example:
+//
+//	var result struct {
+//		Transactions struct {
+//			Edges []struct {
+//				Node struct{ ID string } `json:"node"`
+//			} `json:"edges"`
+//		} `json:"transactions"`
+//	}
+//	query := `query($owner: String!) { transactions(owners: [$owner], first: 10) { edges { node { id } } } }`
+//	err := client.GraphQL(query, map[string]any{"owner": address}, &result)
+func (c *Client) GraphQL(query string, variables map[string]any, v any) error {
+	return c.GraphQLContext(context.Background(), query, variables, v)
+}
+
+// GraphQLContext is GraphQL bound to ctx.
+func (c *Client) GraphQLContext(ctx context.Context, query string, variables map[string]any, v any) error {
+	payload, err := json.Marshal(graphQLRequest{Query: query, Variables: variables})
+	if err != nil {
+		return err
+	}
+	_, body, err := c.postJSONContext(ctx, "graphql", payload)
+	if err != nil {
+		return err
+	}
+
+	var resp graphQLResponse
+	if err := decodeJSON(body, &resp); err != nil {
+		return err
+	}
+	if len(resp.Errors) > 0 {
+		messages := make([]string, len(resp.Errors))
+		for i, e := range resp.Errors {
+			messages[i] = e.Message
+		}
+		return goar.Errorf(goar.ErrBadRequest, "graphql: %s", strings.Join(messages, "; "))
+	}
+	if v == nil || len(resp.Data) == 0 {
+		return nil
+	}
+	return decodeJSON(resp.Data, v)
+}
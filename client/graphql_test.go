@@ -0,0 +1,36 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGraphQL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "/graphql", r.URL.Path)
+		if r.URL.Query().Has("fail") {
+			_, _ = w.Write([]byte(`{"errors": [{"message": "Cannot query field \"foo\""}]}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"transaction": {"id": "abc"}}}`))
+	}))
+	defer server.Close()
+
+	var result struct {
+		Transaction struct {
+			ID string `json:"id"`
+		} `json:"transaction"`
+	}
+	require.NoError(t, New(server.URL).GraphQL(`{ transaction(id: "abc") { id } }`, nil, &result))
+	assert.Equal(t, "abc", result.Transaction.ID)
+
+	err := New(server.URL+"?fail=1").GraphQL(`{ foo }`, nil, nil)
+	assert.ErrorIs(t, err, goar.ErrBadRequest)
+	assert.ErrorContains(t, err, "Cannot query field")
+}
@@ -0,0 +1,87 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/liteseed/goar"
+)
+
+// SetGatewayHeaders sets headers added to every request sent to the host of gateway.
+//
+// Private gateways often require an API key header. Headers are matched by
+// host, so they also apply while probing candidates and after switching
+// gateways with SetGateway or ReselectGateway, and each candidate of a pool
+// can have its own key. They are added before the RequestSigner runs and
+// replace any header of the same name. A nil or empty header removes the
+// headers of the host. It is safe to call while requests are in flight.
+//
+// Parameters:
+//   - gateway: A URL of the gateway, e.g. its base URL; only the host and port are used
+//   - header: The headers to add
+//
+// Returns an error with code goar.ErrInvalidInput if gateway has no host.
+//
+// Example:
+//
+//	err := c.SetGatewayHeaders("https://gateway.example.com", http.Header{"X-Api-Key": {os.Getenv("GATEWAY_KEY")}})
+func (c *Client) SetGatewayHeaders(gateway string, header http.Header) error {
+	host, err := hostOf(gateway)
+	if err != nil {
+		return err
+	}
+	c.gatewayMu.Lock()
+	defer c.gatewayMu.Unlock()
+	if len(header) == 0 {
+		delete(c.headers, host)
+		return nil
+	}
+	if c.headers == nil {
+		c.headers = make(map[string]http.Header)
+	}
+	canonical := make(http.Header, len(header))
+	for name, values := range header {
+		canonical[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+	c.headers[host] = canonical
+	return nil
+}
+
+// GatewayHeaders returns a copy of the headers set for the host of gateway, nil if there are none.
+func (c *Client) GatewayHeaders(gateway string) http.Header {
+	host, err := hostOf(gateway)
+	if err != nil {
+		return nil
+	}
+	c.gatewayMu.RLock()
+	defer c.gatewayMu.RUnlock()
+	return c.headers[host].Clone()
+}
+
+// applyHeaders adds the client's headers and those set for the host of req
+func (c *Client) applyHeaders(req *http.Request) {
+	if c.UserAgent != "" {
+		req.Header.Set("User-Agent", c.UserAgent)
+	}
+	for name, values := range c.Header {
+		req.Header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+	}
+	c.gatewayMu.RLock()
+	defer c.gatewayMu.RUnlock()
+	for name, values := range c.headers[strings.ToLower(req.URL.Host)] {
+		req.Header[name] = append([]string(nil), values...)
+	}
+}
+
+// hostOf returns the lowercased host and port of gateway
+func hostOf(gateway string) (string, error) {
+	u, err := url.Parse(gateway)
+	if err != nil {
+		return "", goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	if u.Host == "" {
+		return "", goar.Errorf(goar.ErrInvalidInput, "gateway %q has no host", gateway)
+	}
+	return strings.ToLower(u.Host), nil
+}
@@ -0,0 +1,98 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGatewayHeaders verifies headers are sent only to the gateway they are set for
+func TestGatewayHeaders(t *testing.T) {
+	// newKeyServer answers /info and stores the API key of the last request
+	newKeyServer := func(key *atomic.Value) *httptest.Server {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key.Store(r.Header.Get("X-Api-Key"))
+			_, _ = w.Write([]byte(`{"network":"arweave.N.1","height":10}`))
+		}))
+		t.Cleanup(server.Close)
+		return server
+	}
+	var keyA, keyB atomic.Value
+	a, b := newKeyServer(&keyA), newKeyServer(&keyB)
+
+	c := New(a.URL)
+	require.NoError(t, c.SetGatewayHeaders(a.URL+"/some/path", http.Header{"x-api-key": {"secret"}}))
+	assert.Equal(t, "secret", c.GatewayHeaders(a.URL).Get("X-Api-Key"))
+	assert.Nil(t, c.GatewayHeaders(b.URL))
+
+	_, err := c.GetNetworkInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "secret", keyA.Load())
+
+	t.Run("Other gateway", func(t *testing.T) {
+		c.SetGateway(b.URL)
+		defer c.SetGateway(a.URL)
+		_, err := c.GetNetworkInfo()
+		require.NoError(t, err)
+		assert.Equal(t, "", keyB.Load())
+	})
+
+	t.Run("Probe", func(t *testing.T) {
+		keyA.Store("")
+		results := c.ProbeGateways(context.Background(), []string{a.URL, b.URL})
+		require.Len(t, results, 2)
+		assert.Equal(t, "secret", keyA.Load())
+		assert.Equal(t, "", keyB.Load())
+	})
+
+	t.Run("Before the request signer", func(t *testing.T) {
+		var seen string
+		c.RequestSigner = RequestSignerFunc(func(req *http.Request, body []byte) error {
+			seen = req.Header.Get("X-Api-Key")
+			return nil
+		})
+		defer func() { c.RequestSigner = nil }()
+		_, err := c.GetNetworkInfo()
+		require.NoError(t, err)
+		assert.Equal(t, "secret", seen)
+	})
+
+	t.Run("Removed", func(t *testing.T) {
+		require.NoError(t, c.SetGatewayHeaders(a.URL, nil))
+		_, err := c.GetNetworkInfo()
+		require.NoError(t, err)
+		assert.Equal(t, "", keyA.Load())
+	})
+
+	err = c.SetGatewayHeaders("not a url", http.Header{"X-Api-Key": {"secret"}})
+	assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err))
+}
+
+// TestClientHeaders verifies the user agent and client headers are sent to every gateway
+func TestClientHeaders(t *testing.T) {
+	var header atomic.Value
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header.Store(r.Header.Clone())
+		_, _ = w.Write([]byte(`{"network":"arweave.N.1","height":10}`))
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	c.UserAgent = "my-service/1.0"
+	c.Header = http.Header{"x-api-key": {"client"}, "X-Trace": {"abc"}}
+	require.NoError(t, c.SetGatewayHeaders(server.URL, http.Header{"X-Api-Key": {"gateway"}}))
+
+	_, err := c.GetNetworkInfo()
+	require.NoError(t, err)
+	sent := header.Load().(http.Header)
+	assert.Equal(t, "my-service/1.0", sent.Get("User-Agent"))
+	assert.Equal(t, "abc", sent.Get("X-Trace"))
+	// Gateway headers take precedence
+	assert.Equal(t, []string{"gateway"}, sent.Values("X-Api-Key"))
+}
@@ -0,0 +1,57 @@
+package client
+
+import (
+	"context"
+	"errors"
+
+	"github.com/liteseed/goar"
+)
+
+// GetPendingTransactions retrieves the IDs of the transactions in the mempool of the gateway.
+//
+// Pending transactions have been accepted by the node but are not mined
+// into a block yet.
+//
+// Returns the pending transaction IDs, or an error if the request fails.
+//
+// Example:
+//
+//	pending, err := client.GetPendingTransactions()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%d transactions pending\n", len(pending))
+func (c *Client) GetPendingTransactions() ([]string, error) {
+	return c.GetPendingTransactionsContext(context.Background())
+}
+
+// GetPendingTransactionsContext is GetPendingTransactions bound to ctx.
+func (c *Client) GetPendingTransactionsContext(ctx context.Context) ([]string, error) {
+	body, err := c.getContext(ctx, "tx/pending")
+	if errors.Is(err, goar.ErrNotFound) && c.arlocal(ctx) {
+		return []string{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	ids := []string{}
+	err = decodeJSON(body, &ids)
+	if err != nil {
+		return nil, err
+	}
+	return ids, nil
+}
+
+// GetMempoolSize returns the number of transactions in the mempool of the gateway, see GetPendingTransactions.
+func (c *Client) GetMempoolSize() (int, error) {
+	return c.GetMempoolSizeContext(context.Background())
+}
+
+// GetMempoolSizeContext is GetMempoolSize bound to ctx.
+func (c *Client) GetMempoolSizeContext(ctx context.Context) (int, error) {
+	ids, err := c.GetPendingTransactionsContext(ctx)
+	if err != nil {
+		return 0, err
+	}
+	return len(ids), nil
+}
@@ -0,0 +1,37 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetPendingTransactions(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tx/pending", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`["a","b","c"]`))
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	c := New(server.URL)
+
+	pending, err := c.GetPendingTransactions()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, pending)
+
+	size, err := c.GetMempoolSize()
+	require.NoError(t, err)
+	assert.Equal(t, 3, size)
+
+	t.Run("Invalid", func(t *testing.T) {
+		mux.HandleFunc("GET /broken/tx/pending", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{}`))
+		})
+		_, err := New(server.URL + "/broken").GetMempoolSize()
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
@@ -0,0 +1,112 @@
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// number decodes a JSON integer into *p, accepting it as a string too, as some gateways and endpoints send it
+type number[T int | int64 | uint64] struct {
+	p *T
+}
+
+// UnmarshalJSON decodes 12, "12" or null, which leaves *p unchanged; an empty string is 0.
+func (n number[T]) UnmarshalJSON(b []byte) error {
+	s := string(b)
+	if s == "null" {
+		return nil
+	}
+	if len(s) > 0 && s[0] == '"' {
+		if err := json.Unmarshal(b, &s); err != nil {
+			return err
+		}
+		if s == "" {
+			*n.p = 0
+			return nil
+		}
+	}
+	if ^T(0) < 0 {
+		v, err := strconv.ParseInt(s, 10, 64)
+		if err != nil || int64(T(v)) != v {
+			return fmt.Errorf("invalid integer %s", b)
+		}
+		*n.p = T(v)
+		return nil
+	}
+	v, err := strconv.ParseUint(s, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid integer %s", b)
+	}
+	*n.p = T(v)
+	return nil
+}
+
+// blockFields has the fields of Block without its JSON methods
+type blockFields Block
+
+// UnmarshalJSON decodes a block, accepting its integer fields as numbers or strings.
+func (b *Block) UnmarshalJSON(data []byte) error {
+	v := struct {
+		*blockFields
+		Timestamp    number[uint64] `json:"timestamp"`
+		LastRetarget number[uint64] `json:"last_retarget"`
+		Height       number[uint64] `json:"height"`
+		RewardPool   number[uint64] `json:"reward_pool"`
+		WeaveSize    number[uint64] `json:"weave_size"`
+		BlockSize    number[uint64] `json:"block_size"`
+	}{
+		blockFields:  (*blockFields)(b),
+		Timestamp:    number[uint64]{&b.Timestamp},
+		LastRetarget: number[uint64]{&b.LastRetarget},
+		Height:       number[uint64]{&b.Height},
+		RewardPool:   number[uint64]{&b.RewardPool},
+		WeaveSize:    number[uint64]{&b.WeaveSize},
+		BlockSize:    number[uint64]{&b.BlockSize},
+	}
+	return json.Unmarshal(data, &v)
+}
+
+// networkInfoFields has the fields of NetworkInfo without its JSON methods
+type networkInfoFields NetworkInfo
+
+// UnmarshalJSON decodes network information, accepting its integer fields as numbers or strings.
+func (n *NetworkInfo) UnmarshalJSON(data []byte) error {
+	v := struct {
+		*networkInfoFields
+		Version          number[int64] `json:"version"`
+		Release          number[int64] `json:"release"`
+		Height           number[int64] `json:"height"`
+		Blocks           number[int64] `json:"blocks"`
+		Peers            number[int64] `json:"peers"`
+		QueueLength      number[int64] `json:"queue_length"`
+		NodeStateLatency number[int64] `json:"node_state_latency"`
+	}{
+		networkInfoFields: (*networkInfoFields)(n),
+		Version:           number[int64]{&n.Version},
+		Release:           number[int64]{&n.Release},
+		Height:            number[int64]{&n.Height},
+		Blocks:            number[int64]{&n.Blocks},
+		Peers:             number[int64]{&n.Peers},
+		QueueLength:       number[int64]{&n.QueueLength},
+		NodeStateLatency:  number[int64]{&n.NodeStateLatency},
+	}
+	return json.Unmarshal(data, &v)
+}
+
+// transactionStatusFields has the fields of TransactionStatus without its JSON methods
+type transactionStatusFields TransactionStatus
+
+// UnmarshalJSON decodes a transaction status, accepting its integer fields as numbers or strings.
+func (t *TransactionStatus) UnmarshalJSON(data []byte) error {
+	v := struct {
+		*transactionStatusFields
+		BlockHeight           number[int] `json:"block_height"`
+		NumberOfConfirmations number[int] `json:"number_of_confirmations"`
+	}{
+		transactionStatusFields: (*transactionStatusFields)(t),
+		BlockHeight:             number[int]{&t.BlockHeight},
+		NumberOfConfirmations:   number[int]{&t.NumberOfConfirmations},
+	}
+	return json.Unmarshal(data, &v)
+}
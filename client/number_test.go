@@ -0,0 +1,76 @@
+package client
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Synthetic block with the fields of /block/height/{height} on a 2.6+ node,
+// where sizes and the reward pool are strings. The hashes and values are made
+// up, as only the types of the fields are under test.
+const blockSample = `{
+	"nonce": "W3Tb7ZfC4kB8r2ZIr0vYdDPsbqF3l5mAPWQxOTXDg8c",
+	"previous_block": "vC7R5nVOXl1OY8zP0Y1y3Ht6yOgFg7K0qgkSlVj0WcmwXo4nJ6bVE9_sQ0i1r8pB",
+	"timestamp": 1700000000,
+	"last_retarget": 1699999880,
+	"diff": "115792089039110416381168735183054150226615356102409520064626271565224592457728",
+	"height": 1300000,
+	"hash": "AAAAAF3bW6Xy8B1W0GvE3FJqk1rJx4qv3NjKkQ0Yc6E",
+	"indep_hash": "k3o0cV0vYkF0Cz1nR2d5Ul7CKsV2yQW4Zg7bYH4qW2T0Q8l1WJ4YqC1zP0kYtR9v",
+	"txs": ["3WAX8H4bPwqfnoI1RNk-tKJHQcVv7dHYzhn2MbnJ0oQ"],
+	"tx_root": "bxzl4C3f9dSlCUvN1rZg7rrVvCk1fe2qk4OxPh-pyjA",
+	"wallet_list": "ph6E-3mCxyDzqAk1oKQZl5gT2YfY4fH4ffV1fGG6SqJOqrZ1dUo9lQnWq8hKbY6z",
+	"reward_addr": "4kTgyOvb2oJQ0u5GH5vJfI5z4Xo8pXmC0eqtnjH-3Hw",
+	"tags": [],
+	"reward_pool": "1123456789012345678",
+	"weave_size": "242123456789012",
+	"block_size": "262144",
+	"cumulative_diff": "9323848",
+	"hash_list_merkle": "yLjOfTn9Kg8uM5YgL5NfiXbD8W8M3b1F9l5F0vQd1Y2M0sPWqf2XoZy3sC3WfR7v"
+}`
+
+func TestNumberFields(t *testing.T) {
+	t.Run("Block with string sizes", func(t *testing.T) {
+		var b Block
+		require.NoError(t, json.Unmarshal([]byte(blockSample), &b))
+		assert.Equal(t, uint64(1300000), b.Height)
+		assert.Equal(t, uint64(1700000000), b.Timestamp)
+		assert.Equal(t, uint64(1123456789012345678), b.RewardPool)
+		assert.Equal(t, uint64(242123456789012), b.WeaveSize)
+		assert.Equal(t, uint64(262144), b.BlockSize)
+		assert.Equal(t, "k3o0cV0vYkF0Cz1nR2d5Ul7CKsV2yQW4Zg7bYH4qW2T0Q8l1WJ4YqC1zP0kYtR9v", b.IndepHash)
+		assert.Len(t, b.Txs, 1)
+
+		// Encoded with numbers, as by older nodes, it decodes the same
+		encoded, err := json.Marshal(b)
+		require.NoError(t, err)
+		var again Block
+		require.NoError(t, json.Unmarshal(encoded, &again))
+		assert.Equal(t, b, again)
+	})
+
+	t.Run("Network info", func(t *testing.T) {
+		var n NetworkInfo
+		require.NoError(t, json.Unmarshal([]byte(`{"network":"arweave.N.1","version":5,"release":"69","height":"1300000","current":"k3o0","blocks":1300001,"peers":"","queue_length":null,"node_state_latency":1}`), &n))
+		assert.Equal(t, NetworkInfo{Network: "arweave.N.1", Version: 5, Release: 69, Height: 1300000, Current: "k3o0", Blocks: 1300001, NodeStateLatency: 1}, n)
+	})
+
+	t.Run("Transaction status", func(t *testing.T) {
+		var s TransactionStatus
+		require.NoError(t, json.Unmarshal([]byte(`{"block_height":"1300000","block_indep_hash":"k3o0","number_of_confirmations":12}`), &s))
+		assert.Equal(t, 1300000, s.BlockHeight)
+		assert.Equal(t, 12, s.NumberOfConfirmations)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		for _, payload := range []string{`{"height":"tall"}`, `{"height":-1}`, `{"height":1.5}`, `{"height":"18446744073709551616"}`} {
+			var b Block
+			assert.Error(t, json.Unmarshal([]byte(payload), &b), payload)
+		}
+		var s TransactionStatus
+		assert.Error(t, json.Unmarshal([]byte(`{"block_height":"x"}`), &s))
+	})
+}
@@ -0,0 +1,85 @@
+package client
+
+import (
+	"crypto/tls"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// DEFAULT_TIMEOUT bounds whole requests of a client created by New, unless WithTimeout or WithHTTPClient is given
+const DEFAULT_TIMEOUT = 10 * time.Second
+
+// Option configures a Client created by New.
+//
+// Options are applied in order, so WithHTTPClient should come before the
+// options it would otherwise replace. They never modify an *http.Client or
+// transport given to them: the client works on copies.
+type Option func(c *Client)
+
+// WithTimeout sets the timeout of whole requests, replacing DEFAULT_TIMEOUT. 0 means no timeout.
+func WithTimeout(timeout time.Duration) Option {
+	return func(c *Client) {
+		hc := *c.Client
+		hc.Timeout = timeout
+		c.Client = &hc
+	}
+}
+
+// WithHTTPClient sends requests with hc, e.g. a client instrumented for tracing.
+// Its timeout replaces DEFAULT_TIMEOUT.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.Client = hc
+	}
+}
+
+// WithProxy sends requests through proxy, e.g. http.ProxyURL, instead of the
+// proxy of the HTTPS_PROXY, HTTP_PROXY and NO_PROXY environment variables.
+func WithProxy(proxy func(*http.Request) (*url.URL, error)) Option {
+	return func(c *Client) {
+		c.transport().Proxy = proxy
+	}
+}
+
+// WithTLSConfig sets the TLS configuration of the connections to the gateway, e.g. custom root CAs.
+func WithTLSConfig(config *tls.Config) Option {
+	return func(c *Client) {
+		c.transport().TLSClientConfig = config.Clone()
+	}
+}
+
+// WithUserAgent sets the User-Agent header of every request, see Client.UserAgent.
+func WithUserAgent(userAgent string) Option {
+	return func(c *Client) {
+		c.UserAgent = userAgent
+	}
+}
+
+// WithHeaders adds headers to every request, e.g. the API key of a private
+// gateway, see Client.Header. Headers of the same name as earlier ones
+// replace them.
+func WithHeaders(header http.Header) Option {
+	return func(c *Client) {
+		if c.Header == nil {
+			c.Header = make(http.Header, len(header))
+		}
+		for name, values := range header {
+			c.Header[http.CanonicalHeaderKey(name)] = append([]string(nil), values...)
+		}
+	}
+}
+
+// transport returns a copy of the client's *http.Transport, or of
+// http.DefaultTransport if it has none, installed on a copy of its *http.Client
+func (c *Client) transport() *http.Transport {
+	t, ok := c.Client.Transport.(*http.Transport)
+	if !ok {
+		t = http.DefaultTransport.(*http.Transport)
+	}
+	t = t.Clone()
+	hc := *c.Client
+	hc.Transport = t
+	c.Client = &hc
+	return t
+}
@@ -0,0 +1,82 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestOptions verifies the options of New
+func TestOptions(t *testing.T) {
+	t.Run("Defaults", func(t *testing.T) {
+		c := New("https://arweave.net")
+		assert.Equal(t, DEFAULT_TIMEOUT, c.Client.Timeout)
+		assert.Empty(t, c.UserAgent)
+		assert.Nil(t, c.Header)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		assert.Equal(t, time.Minute, New("https://arweave.net", WithTimeout(time.Minute)).Client.Timeout)
+	})
+
+	t.Run("HTTP client", func(t *testing.T) {
+		hc := &http.Client{Timeout: time.Second}
+		c := New("https://arweave.net", WithHTTPClient(hc), WithTimeout(time.Minute))
+		assert.Equal(t, time.Minute, c.Client.Timeout)
+		// The given client is not modified
+		assert.Equal(t, time.Second, hc.Timeout)
+	})
+
+	t.Run("Proxy and headers", func(t *testing.T) {
+		var proxied atomic.Value
+		var header atomic.Value
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied.Store(r.URL.String())
+			header.Store(r.Header.Clone())
+			_, _ = w.Write([]byte("OK"))
+		}))
+		defer proxy.Close()
+		proxyURL, err := url.Parse(proxy.URL)
+		require.NoError(t, err)
+
+		c := New("http://gateway.invalid",
+			WithProxy(http.ProxyURL(proxyURL)),
+			WithUserAgent("my-service/1.0"),
+			WithHeaders(http.Header{"x-api-key": {"first"}, "X-Trace": {"abc"}}),
+			WithHeaders(http.Header{"X-Api-Key": {"secret"}}),
+		)
+		_, err = c.get("info")
+		require.NoError(t, err)
+		assert.Equal(t, "http://gateway.invalid/info", proxied.Load())
+		sent := header.Load().(http.Header)
+		assert.Equal(t, "my-service/1.0", sent.Get("User-Agent"))
+		assert.Equal(t, []string{"secret"}, sent.Values("X-Api-Key"))
+		assert.Equal(t, "abc", sent.Get("X-Trace"))
+	})
+
+	t.Run("TLS config", func(t *testing.T) {
+		server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("OK"))
+		}))
+		defer server.Close()
+
+		_, err := New(server.URL).get("info")
+		assert.Error(t, err)
+
+		hc := server.Client()
+		config := hc.Transport.(*http.Transport).TLSClientConfig
+		c := New(server.URL, WithHTTPClient(&http.Client{}), WithTLSConfig(config))
+		_, err = c.get("info")
+		require.NoError(t, err)
+		// The default transport is not modified
+		assert.NotSame(t, http.DefaultTransport, c.Client.Transport)
+		_, err = New(server.URL).get("info")
+		assert.Error(t, err)
+	})
+}
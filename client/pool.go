@@ -0,0 +1,343 @@
+package client
+
+import (
+	"context"
+	"hash/fnv"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+)
+
+// Defaults of the zero PoolOptions
+const (
+	DEFAULT_FAILURE_THRESHOLD = 3                // Consecutive failures opening the circuit of a gateway
+	DEFAULT_BREAKER_COOLDOWN  = 30 * time.Second // Time an open circuit rejects requests
+)
+
+// PoolOptions configures how a client created with NewPool spreads requests over its gateways.
+type PoolOptions struct {
+	FailureThreshold int           // Consecutive failures opening the circuit of a gateway, DEFAULT_FAILURE_THRESHOLD if 0
+	Cooldown         time.Duration // Time an open circuit rejects requests before letting one through, DEFAULT_BREAKER_COOLDOWN if 0
+}
+
+// GatewayState is the health of a gateway of a pool, see GatewayStates.
+type GatewayState struct {
+	Gateway   string        // Base URL of the gateway
+	Failures  int           // Consecutive failed requests and health checks
+	OpenUntil time.Time     // End of the cooldown of an open circuit, zero if the circuit is closed
+	Latency   time.Duration // Latency of the last successful health check, 0 if none
+}
+
+// Available reports whether the gateway receives requests at time now.
+func (s GatewayState) Available(now time.Time) bool {
+	return !now.Before(s.OpenUntil)
+}
+
+// pool holds the gateways of a client created with NewPool
+type pool struct {
+	opts     PoolOptions
+	mu       sync.Mutex
+	gateways []GatewayState
+	next     int // Gateway the next request without a sticky key starts at
+}
+
+// stickyKey is the context key set by WithStickyGateway
+type stickyKey struct{}
+
+// NewPool creates a client that load-balances and fails over between several gateways.
+//
+// Requests are spread over the gateways in turn. A request failing on a
+// gateway, because it cannot be reached or answers with a 5xx or 429
+// status, is sent at once to the next one, so it only fails when every
+// gateway does; a RetryPolicy then retries the whole round. A gateway
+// failing opts.FailureThreshold times in a row has its circuit opened: it
+// receives no requests for opts.Cooldown, after which a single success
+// closes it again. CheckGateways and RunHealthChecks update the circuits
+// without waiting for requests to fail.
+//
+// Requests with a sticky key, see WithStickyGateway, always go to the same
+// available gateway. UploadChunk and SubmitTransaction use the data root as
+// the key, so the chunks of a transaction are uploaded to the gateway that
+// received its header.
+//
+// The Gateway field of the client is the first gateway and only serves to
+// build request URLs; SetGateway and ReselectGateway must not be used.
+//
+// Parameters:
+//   - gateways: Base URLs of the gateways, e.g. arweave.net, ar.io gateways or self-hosted nodes
+//   - opts: Circuit breaker settings, the zero value for the defaults
+//
+// Returns an error with code goar.ErrInvalidInput if gateways is empty or
+// contains an invalid URL.
+//
+// Example:
+//
+//	c, err := client.NewPool([]string{"https://arweave.net", "https://ar-io.dev", "http://my-node:1984"}, client.PoolOptions{})
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	go c.RunHealthChecks(ctx, time.Minute)
+func NewPool(gateways []string, opts PoolOptions) (*Client, error) {
+	if len(gateways) == 0 {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "no gateways")
+	}
+	p := &pool{opts: opts}
+	for _, gateway := range gateways {
+		if _, err := url.Parse(gateway); err != nil {
+			return nil, goar.Wrap(goar.ErrInvalidInput, err)
+		}
+		p.gateways = append(p.gateways, GatewayState{Gateway: gateway})
+	}
+	c := New(gateways[0])
+	c.pool = p
+	return c, nil
+}
+
+// WithStickyGateway returns a context whose requests to a pool all go to
+// the same gateway for the same key while that gateway is available.
+//
+// Example:
+//
+//	ctx = client.WithStickyGateway(ctx, tx.DataRoot)
+func WithStickyGateway(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, stickyKey{}, key)
+}
+
+// withDefaultSticky sets key as the sticky key of ctx unless it has one
+func withDefaultSticky(ctx context.Context, key string) context.Context {
+	if key == "" || ctx.Value(stickyKey{}) != nil {
+		return ctx
+	}
+	return WithStickyGateway(ctx, key)
+}
+
+// GatewayStates returns the health of the gateways of a client created with NewPool, nil for other clients.
+func (c *Client) GatewayStates() []GatewayState {
+	if c.pool == nil {
+		return nil
+	}
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+	return slices.Clone(c.pool.gateways)
+}
+
+// CircuitWait returns how long until a gateway of the pool accepts requests
+// again: 0 if one does now or the client was not created with NewPool.
+//
+// Requests sent while every circuit is open fail at once without reaching a
+// gateway, so callers retrying in a loop, such as the uploader, wait this
+// long first instead of counting the failures.
+func (c *Client) CircuitWait() time.Duration {
+	if c.pool == nil {
+		return 0
+	}
+	now := c.getClock().Now()
+	c.pool.mu.Lock()
+	defer c.pool.mu.Unlock()
+	var wait time.Duration
+	for i, state := range c.pool.gateways {
+		if state.Available(now) {
+			return 0
+		}
+		if d := state.OpenUntil.Sub(now); i == 0 || d < wait {
+			wait = d
+		}
+	}
+	return wait
+}
+
+// CheckGateways fetches /info from every gateway of the pool and updates their circuits.
+//
+// A gateway answering closes its circuit and records its latency; one
+// failing counts as a failed request. It does nothing on a client not
+// created with NewPool.
+func (c *Client) CheckGateways(ctx context.Context) {
+	if c.pool == nil {
+		return
+	}
+	gateways := make([]string, 0, len(c.pool.gateways))
+	for _, state := range c.GatewayStates() {
+		gateways = append(gateways, state.Gateway)
+	}
+	now := c.getClock().Now()
+	for _, result := range c.ProbeGateways(ctx, gateways) {
+		c.pool.record(result.Gateway, result.Err == nil, now)
+		if result.Err == nil {
+			c.pool.setLatency(result.Gateway, result.Latency)
+		}
+	}
+}
+
+// RunHealthChecks calls CheckGateways every interval until ctx is done or the client is closed.
+//
+// It blocks, so it is usually run in its own goroutine. It returns at once
+// if interval is not positive.
+//
+// Example:
+//
+//	go c.RunHealthChecks(ctx, time.Minute)
+func (c *Client) RunHealthChecks(ctx context.Context, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ctx, cancel := c.bind(ctx)
+	defer cancel()
+	for retry.Sleep(c.getClock(), interval, ctx.Done()) {
+		c.CheckGateways(ctx)
+	}
+}
+
+// sendPool sends the request to the gateways of the pool in order until one does not fail
+func (c *Client) sendPool(req *http.Request, payload []byte) (int, []byte, http.Header, error) {
+	base, parseErr := url.Parse(c.CurrentGateway())
+	if parseErr != nil || req.URL.Host != base.Host {
+		// Not a request to the gateway
+		return c.send(req, payload)
+	}
+	route := strings.TrimPrefix(req.URL.Path, base.Path)
+
+	sticky, _ := req.Context().Value(stickyKey{}).(string)
+	order := c.pool.order(sticky, c.getClock().Now())
+	if len(order) == 0 {
+		return -1, nil, nil, goar.Errorf(goar.ErrNetwork, "every gateway has an open circuit")
+	}
+
+	var (
+		code   int
+		body   []byte
+		header http.Header
+		err    error
+	)
+	for i, gateway := range order {
+		// The request of the caller keeps its URL for the rounds of a RetryPolicy
+		attempt := req.Clone(req.Context())
+		if i > 0 {
+			if attempt = rewind(req); attempt == nil {
+				return code, body, header, err
+			}
+		}
+		u, joinErr := joinURL(gateway, route)
+		if joinErr == nil {
+			attempt.URL, joinErr = url.Parse(u)
+		}
+		if joinErr != nil {
+			return -1, nil, nil, goar.Wrap(goar.ErrInvalidInput, joinErr)
+		}
+		attempt.URL.RawQuery = req.URL.RawQuery
+		attempt.Host = ""
+
+		code, body, header, err = c.send(attempt, payload)
+		if req.Context().Err() != nil {
+			return code, body, header, err
+		}
+		failed := gatewayFailed(code, err)
+		c.pool.record(gateway, !failed, c.getClock().Now())
+		if !failed {
+			return code, body, header, err
+		}
+	}
+	return code, body, header, err
+}
+
+// gatewayFailed reports whether the outcome of a request counts against the gateway
+func gatewayFailed(code int, err error) bool {
+	if err != nil {
+		return goar.CodeOf(err) == goar.ErrNetwork || goar.CodeOf(err) == goar.ErrDial
+	}
+	return code == http.StatusTooManyRequests || code >= 500
+}
+
+// order returns the available gateways in the order a request tries them
+func (p *pool) order(sticky string, now time.Time) []string {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	var available []string
+	for _, state := range p.gateways {
+		if state.Available(now) {
+			available = append(available, state.Gateway)
+		}
+	}
+	if len(available) == 0 {
+		return nil
+	}
+
+	if sticky != "" {
+		// Rendezvous hashing: a key keeps its gateway as others come and go
+		slices.SortStableFunc(available, func(a string, b string) int {
+			ha, hb := rendezvous(sticky, a), rendezvous(sticky, b)
+			switch {
+			case ha > hb:
+				return -1
+			case ha < hb:
+				return 1
+			}
+			return 0
+		})
+		return available
+	}
+
+	start := p.next % len(available)
+	p.next++
+	return append(available[start:], available[:start]...)
+}
+
+// record updates the circuit of gateway after a request or health check
+func (p *pool) record(gateway string, ok bool, now time.Time) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	i := slices.IndexFunc(p.gateways, func(s GatewayState) bool { return s.Gateway == gateway })
+	if i < 0 {
+		return
+	}
+	state := &p.gateways[i]
+	if ok {
+		state.Failures = 0
+		state.OpenUntil = time.Time{}
+		return
+	}
+	state.Failures++
+	if state.Failures >= p.threshold() {
+		state.OpenUntil = now.Add(p.cooldown())
+	}
+}
+
+// setLatency records the latency of a successful health check of gateway
+func (p *pool) setLatency(gateway string, latency time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if i := slices.IndexFunc(p.gateways, func(s GatewayState) bool { return s.Gateway == gateway }); i >= 0 {
+		p.gateways[i].Latency = latency
+	}
+}
+
+// threshold returns the number of consecutive failures opening a circuit
+func (p *pool) threshold() int {
+	if p.opts.FailureThreshold <= 0 {
+		return DEFAULT_FAILURE_THRESHOLD
+	}
+	return p.opts.FailureThreshold
+}
+
+// cooldown returns the time an open circuit rejects requests
+func (p *pool) cooldown() time.Duration {
+	if p.opts.Cooldown <= 0 {
+		return DEFAULT_BREAKER_COOLDOWN
+	}
+	return p.opts.Cooldown
+}
+
+// rendezvous returns the weight of gateway for a sticky key
+func rendezvous(key string, gateway string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(key))
+	_, _ = h.Write([]byte{0})
+	_, _ = h.Write([]byte(gateway))
+	return h.Sum64()
+}
@@ -0,0 +1,196 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// poolGateway is a test gateway of a pool answering with status and counting requests
+type poolGateway struct {
+	server *httptest.Server
+	status atomic.Int32
+	calls  atomic.Int32
+}
+
+func newPoolGateway(t *testing.T, status int) *poolGateway {
+	g := &poolGateway{}
+	g.status.Store(int32(status))
+	g.server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.calls.Add(1)
+		w.WriteHeader(int(g.status.Load()))
+		_, _ = w.Write([]byte(`{"network":"arweave.N.1","height":10}`))
+	}))
+	t.Cleanup(g.server.Close)
+	return g
+}
+
+func newTestPool(t *testing.T, opts PoolOptions, gateways ...*poolGateway) (*Client, *retry.FakeClock) {
+	urls := make([]string, len(gateways))
+	for i, g := range gateways {
+		urls[i] = g.server.URL
+	}
+	c, err := NewPool(urls, opts)
+	require.NoError(t, err)
+	c.DisableCoalescing = true
+	clock := retry.NewFakeClock(time.Unix(0, 0))
+	c.clock = clock
+	return c, clock
+}
+
+func TestNewPool(t *testing.T) {
+	_, err := NewPool(nil, PoolOptions{})
+	assert.ErrorIs(t, err, goar.ErrInvalidInput)
+
+	_, err = NewPool([]string{"http://a", "://bad"}, PoolOptions{})
+	assert.ErrorIs(t, err, goar.ErrInvalidInput)
+
+	assert.Nil(t, New("http://a").GatewayStates())
+}
+
+func TestPoolLoadBalances(t *testing.T) {
+	a := newPoolGateway(t, http.StatusOK)
+	b := newPoolGateway(t, http.StatusOK)
+	c, _ := newTestPool(t, PoolOptions{}, a, b)
+
+	for range 4 {
+		_, err := c.GetNetworkInfo()
+		require.NoError(t, err)
+	}
+	assert.Equal(t, int32(2), a.calls.Load())
+	assert.Equal(t, int32(2), b.calls.Load())
+}
+
+func TestPoolFailover(t *testing.T) {
+	down := newPoolGateway(t, http.StatusBadGateway)
+	up := newPoolGateway(t, http.StatusOK)
+	c, clock := newTestPool(t, PoolOptions{FailureThreshold: 2, Cooldown: time.Minute}, down, up)
+
+	t.Run("FailsOver", func(t *testing.T) {
+		for range 4 {
+			_, err := c.GetNetworkInfo()
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(4), up.calls.Load())
+	})
+
+	t.Run("OpensCircuit", func(t *testing.T) {
+		assert.Equal(t, int32(2), down.calls.Load())
+		states := c.GatewayStates()
+		require.Len(t, states, 2)
+		assert.Equal(t, 2, states[0].Failures)
+		assert.False(t, states[0].Available(clock.Now()))
+		assert.True(t, states[1].Available(clock.Now()))
+	})
+
+	t.Run("ClosesCircuitAfterCooldown", func(t *testing.T) {
+		down.status.Store(http.StatusOK)
+		<-clock.After(time.Minute)
+		for range 2 {
+			_, err := c.GetNetworkInfo()
+			require.NoError(t, err)
+		}
+		assert.Equal(t, int32(3), down.calls.Load())
+		assert.Equal(t, 0, c.GatewayStates()[0].Failures)
+	})
+
+	t.Run("CircuitWait", func(t *testing.T) {
+		assert.Zero(t, c.CircuitWait())
+		assert.Zero(t, New("http://a").CircuitWait())
+	})
+
+	t.Run("EveryGatewayDown", func(t *testing.T) {
+		down.status.Store(http.StatusServiceUnavailable)
+		up.status.Store(http.StatusServiceUnavailable)
+		_, err := c.GetNetworkInfo()
+		assert.ErrorIs(t, err, goar.ErrGateway)
+		_, err = c.GetNetworkInfo()
+		assert.ErrorIs(t, err, goar.ErrGateway)
+
+		_, err = c.GetNetworkInfo()
+		assert.ErrorIs(t, err, goar.ErrNetwork)
+		assert.Equal(t, time.Minute, c.CircuitWait())
+
+		<-clock.After(20 * time.Second)
+		assert.Equal(t, 40*time.Second, c.CircuitWait())
+	})
+}
+
+func TestPoolDoesNotFailOverClientErrors(t *testing.T) {
+	a := newPoolGateway(t, http.StatusNotFound)
+	b := newPoolGateway(t, http.StatusNotFound)
+	c, _ := newTestPool(t, PoolOptions{}, a, b)
+
+	_, err := c.GetNetworkInfo()
+	assert.ErrorIs(t, err, goar.ErrNotFound)
+	assert.Equal(t, int32(1), a.calls.Load()+b.calls.Load())
+	for _, state := range c.GatewayStates() {
+		assert.Zero(t, state.Failures)
+	}
+}
+
+func TestPoolStickyChunks(t *testing.T) {
+	gateways := []*poolGateway{
+		newPoolGateway(t, http.StatusOK),
+		newPoolGateway(t, http.StatusOK),
+		newPoolGateway(t, http.StatusOK),
+	}
+	c, _ := newTestPool(t, PoolOptions{}, gateways...)
+
+	_, err := c.SubmitTransaction(&transaction.Transaction{DataRoot: "root"})
+	require.NoError(t, err)
+	for range 5 {
+		_, err := c.UploadChunk(&transaction.GetChunkResult{DataRoot: "root"})
+		require.NoError(t, err)
+	}
+
+	var used int
+	for _, g := range gateways {
+		switch g.calls.Load() {
+		case 0:
+		case 6:
+			used++
+		default:
+			t.Fatalf("gateway received %d of the 6 requests", g.calls.Load())
+		}
+	}
+	assert.Equal(t, 1, used)
+
+	t.Run("FailsOverWhenDown", func(t *testing.T) {
+		var sticky *poolGateway
+		for _, g := range gateways {
+			if g.calls.Load() > 0 {
+				sticky = g
+			}
+		}
+		sticky.status.Store(http.StatusBadGateway)
+		_, err := c.UploadChunk(&transaction.GetChunkResult{DataRoot: "root"})
+		require.NoError(t, err)
+		assert.Equal(t, int32(7), sticky.calls.Load())
+	})
+}
+
+func TestCheckGateways(t *testing.T) {
+	a := newPoolGateway(t, http.StatusOK)
+	b := newPoolGateway(t, http.StatusBadGateway)
+	c, clock := newTestPool(t, PoolOptions{FailureThreshold: 1}, a, b)
+
+	c.CheckGateways(context.Background())
+	states := c.GatewayStates()
+	assert.True(t, states[0].Available(clock.Now()))
+	assert.Positive(t, states[0].Latency)
+	assert.False(t, states[1].Available(clock.Now()))
+
+	b.status.Store(http.StatusOK)
+	c.CheckGateways(context.Background())
+	assert.True(t, c.GatewayStates()[1].Available(clock.Now()))
+}
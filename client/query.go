@@ -0,0 +1,190 @@
+package client
+
+import (
+	"fmt"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/liteseed/goar"
+)
+
+// Operators of TagFilter.Op
+const (
+	TAG_OP_EQ  = "EQ"  // The tag has one of the values, the gateway default
+	TAG_OP_NEQ = "NEQ" // The tag has none of the values
+)
+
+// Match modes of TagFilter.Match, supported by ar.io gateways
+const (
+	TAG_MATCH_EXACT     = "EXACT"     // Values are compared as they are, the gateway default
+	TAG_MATCH_WILDCARD  = "WILDCARD"  // Values may contain * wildcards
+	TAG_MATCH_FUZZY_AND = "FUZZY_AND" // The value contains every word of a filter value
+	TAG_MATCH_FUZZY_OR  = "FUZZY_OR"  // The value contains a word of a filter value
+)
+
+// Fragment is a named GraphQL selection of the fields of a transaction, see TransactionQuery.Fields.
+type Fragment struct {
+	Name   string // GraphQL name of the fragment
+	Fields string // Selection set on the Transaction type, without the enclosing braces
+}
+
+// TRANSACTION_FIELDS selects every field of TransactionNode.
+var TRANSACTION_FIELDS = Fragment{
+	Name: "TransactionFields",
+	Fields: `id anchor signature recipient
+  owner { address key }
+  fee { winston ar }
+  quantity { winston ar }
+  data { size type }
+  tags { name value }
+  block { id timestamp height previous }
+  bundledIn { id }`,
+}
+
+// ID_FIELDS selects only the ID of transactions, for queries that page through many matches.
+var ID_FIELDS = Fragment{Name: "IDFields", Fields: "id"}
+
+// queryVariable is a variable a transactions query may declare
+type queryVariable struct {
+	name      string // Name of the variable and of the transactions argument
+	graphType string // GraphQL type of the variable
+}
+
+// queryVariables are the variables of a transactions query in declaration order
+var queryVariables = []queryVariable{
+	{"ids", "[ID!]"},
+	{"owners", "[String!]"},
+	{"recipients", "[String!]"},
+	{"tags", "[TagFilter!]"},
+	{"bundledIn", "[ID!]"},
+	{"block", "BlockFilter"},
+	{"first", "Int"},
+	{"after", "String"},
+	{"sort", "SortOrder"},
+}
+
+// graphQLName matches valid GraphQL names
+var graphQLName = regexp.MustCompile(`^[_A-Za-z][_0-9A-Za-z]*$`)
+
+// Build returns the GraphQL query and variables of the page of q after the cursor after.
+//
+// Every filter value is passed as a variable, never written into the query
+// text, so values from untrusted input cannot change the query. Only the
+// variables of set filters are declared. The fields of each transaction
+// are selected with the fragment q.Fields.
+//
+// Returns an error with code goar.ErrInvalidInput if q is malformed, e.g.
+// has an unknown sort order, a tag filter without a name, or a height
+// range whose minimum is above its maximum.
+//
+// Example:
+//
+//	query, variables, err := q.Build("")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	err = c.GraphQL(query, variables, &result)
+func (q *TransactionQuery) Build(after string) (string, map[string]any, error) {
+	if err := q.validate(); err != nil {
+		return "", nil, err
+	}
+	fragment := q.fragment()
+	variables := q.variables(after)
+
+	var declarations, arguments []string
+	for _, v := range queryVariables {
+		if _, ok := variables[v.name]; ok {
+			declarations = append(declarations, fmt.Sprintf("$%s: %s", v.name, v.graphType))
+			arguments = append(arguments, fmt.Sprintf("%s: $%s", v.name, v.name))
+		}
+	}
+	selection := "..." + fragment.Name
+	if q.MinDataSize > 0 || q.MaxDataSize > 0 {
+		// Needed to filter by size whatever the fragment selects
+		selection += " data { size }"
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "query(%s) {\n", strings.Join(declarations, ", "))
+	fmt.Fprintf(&b, "  transactions(%s) {\n", strings.Join(arguments, ", "))
+	b.WriteString("    pageInfo { hasNextPage }\n")
+	fmt.Fprintf(&b, "    edges { cursor node { %s } }\n", selection)
+	b.WriteString("  }\n}\n")
+	fmt.Fprintf(&b, "fragment %s on Transaction {\n  %s\n}", fragment.Name, fragment.Fields)
+	return b.String(), variables, nil
+}
+
+// fragment returns the fields selected by q
+func (q *TransactionQuery) fragment() Fragment {
+	if q.Fields == nil {
+		return TRANSACTION_FIELDS
+	}
+	return *q.Fields
+}
+
+// validate checks the filters of q before they are sent
+func (q *TransactionQuery) validate() error {
+	invalid := func(format string, args ...any) error {
+		return goar.Errorf(goar.ErrInvalidInput, "transaction query: "+format, args...)
+	}
+	if q.Sort != "" && q.Sort != SORT_HEIGHT_ASC && q.Sort != SORT_HEIGHT_DESC {
+		return invalid("unknown sort order %q", q.Sort)
+	}
+	if q.First < 0 {
+		return invalid("negative page size %d", q.First)
+	}
+	if q.MinHeight < 0 || q.MaxHeight < 0 || (q.MaxHeight > 0 && q.MinHeight > q.MaxHeight) {
+		return invalid("invalid height range [%d, %d]", q.MinHeight, q.MaxHeight)
+	}
+	if q.MinDataSize < 0 || q.MaxDataSize < 0 || (q.MaxDataSize > 0 && q.MinDataSize > q.MaxDataSize) {
+		return invalid("invalid data size range [%d, %d]", q.MinDataSize, q.MaxDataSize)
+	}
+	for _, filter := range q.Tags {
+		if filter.Name == "" {
+			return invalid("tag filter without a name")
+		}
+		if filter.Op != "" && filter.Op != TAG_OP_EQ && filter.Op != TAG_OP_NEQ {
+			return invalid("unknown tag operator %q", filter.Op)
+		}
+		if filter.Match != "" && !slices.Contains([]string{TAG_MATCH_EXACT, TAG_MATCH_WILDCARD, TAG_MATCH_FUZZY_AND, TAG_MATCH_FUZZY_OR}, filter.Match) {
+			return invalid("unknown tag match %q", filter.Match)
+		}
+	}
+
+	fragment := q.fragment()
+	if !graphQLName.MatchString(fragment.Name) {
+		return invalid("invalid fragment name %q", fragment.Name)
+	}
+	depth := 0
+	for _, r := range fragment.Fields {
+		switch r {
+		case '{':
+			depth++
+		case '}':
+			depth--
+		case '"', '#', '$':
+			return invalid("fragment %s selects fields only, found %q", fragment.Name, r)
+		}
+		if depth < 0 {
+			break
+		}
+	}
+	if depth != 0 || strings.TrimSpace(fragment.Fields) == "" {
+		return invalid("fragment %s has an invalid selection", fragment.Name)
+	}
+	return nil
+}
+
+// matchesSize reports whether a transaction of the data size reported by GraphQL passes the size filters of q
+func (q *TransactionQuery) matchesSize(size string) bool {
+	if q.MinDataSize <= 0 && q.MaxDataSize <= 0 {
+		return true
+	}
+	n, err := strconv.ParseInt(size, 10, 64)
+	if err != nil {
+		return false
+	}
+	return n >= q.MinDataSize && (q.MaxDataSize <= 0 || n <= q.MaxDataSize)
+}
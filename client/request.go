@@ -2,55 +2,271 @@ package client
 
 import (
 	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
+	"net"
+	"net/http"
 	"net/url"
 	"path"
+	"strings"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
 )
 
+func (c *Client) url(route string) (string, error) {
+	return joinURL(c.CurrentGateway(), route)
+}
+
+// joinURL appends route to the path of the gateway URL base
+func joinURL(base string, route string) (string, error) {
+	u, err := url.Parse(base)
+	if err != nil {
+		return "", err
+	}
+
+	u.Path = path.Join(u.Path, route)
+	return u.String(), nil
+}
+
+// do sends the request through the client's middleware and returns the response body.
+// Failed attempts are retried according to the client's RetryPolicy.
+func (c *Client) do(req *http.Request, payload []byte) (int, []byte, error) {
+	policy := c.retryPolicy(req.Context())
+	for attempt := 1; ; attempt++ {
+		code, body, header, err := c.sendOnce(req, payload)
+		if attempt >= policy.attempts() {
+			return code, body, err
+		}
+		delay, ok := policy.delay(attempt, code, header, err, c.getClock().Now())
+		if !ok {
+			return code, body, err
+		}
+		next := rewind(req)
+		if next == nil {
+			return code, body, err
+		}
+		if !retry.Sleep(c.getClock(), delay, req.Context().Done()) {
+			return -1, nil, goar.Wrap(goar.ErrNetwork, req.Context().Err())
+		}
+		req = next
+	}
+}
+
+// sendOnce sends the request once, to the gateways of the pool in turn if the client has one.
+func (c *Client) sendOnce(req *http.Request, payload []byte) (int, []byte, http.Header, error) {
+	if c.pool != nil {
+		return c.sendPool(req, payload)
+	}
+	return c.send(req, payload)
+}
+
+// send sends the request once and returns the response body and headers.
+func (c *Client) send(req *http.Request, payload []byte) (int, []byte, http.Header, error) {
+	c.applyHeaders(req)
+	if c.RequestSigner != nil {
+		if err := c.RequestSigner.SignRequest(req, payload); err != nil {
+			return -1, nil, nil, err
+		}
+	}
+
+	if c.streams != nil {
+		select {
+		case c.streams <- struct{}{}:
+		case <-req.Context().Done():
+			return -1, nil, nil, goar.Wrap(goar.ErrNetwork, req.Context().Err())
+		}
+		defer func() { <-c.streams }()
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return -1, nil, nil, networkError(err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return -1, nil, nil, goar.Wrap(goar.ErrNetwork, err)
+	}
+	return resp.StatusCode, body, resp.Header, nil
+}
+
+// get fetches route, see getContext.
 func (c *Client) get(route string) ([]byte, error) {
-	u, err := url.Parse(c.Gateway)
+	return c.getContext(context.Background(), route)
+}
+
+// getContext fetches route. Identical GETs in flight at the same time share a single request
+// unless DisableCoalescing is set.
+//
+// A shared request is not canceled with the context of the caller that
+// started it, so that the other callers still get the response; each caller
+// stops waiting as soon as its own ctx is done, and the request is canceled
+// once no caller is waiting for it.
+func (c *Client) getContext(ctx context.Context, route string) ([]byte, error) {
+	u, err := c.url(route)
 	if err != nil {
 		return nil, err
 	}
+	if c.DisableCoalescing {
+		return c.fetch(ctx, u)
+	}
 
-	u.Path = path.Join(u.Path, route)
+	f := c.join(ctx, u)
+	select {
+	case <-f.done:
+	case <-ctx.Done():
+		c.leave(u, f)
+		return nil, goar.Wrap(goar.ErrNetwork, ctx.Err())
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	if f.shared {
+		// Every caller owns its body
+		return bytes.Clone(f.body), nil
+	}
+	return f.body, nil
+}
+
+// flight is a GET request shared by the callers waiting for its response
+type flight struct {
+	done    chan struct{}      // Closed once body and err are set
+	body    []byte             // Response body
+	err     error              // Error of the request
+	waiters int                // Callers waiting for the response, guarded by Client.flightMu
+	shared  bool               // Whether more than one caller joined, final once done is closed
+	cancel  context.CancelFunc // Cancels the request
+}
 
-	resp, err := c.Client.Get(u.String())
+// join returns the request in flight for u, starting it with the values of ctx if there is none.
+func (c *Client) join(ctx context.Context, u string) *flight {
+	c.flightMu.Lock()
+	defer c.flightMu.Unlock()
+	f, ok := c.flights[u]
+	if ok {
+		f.shared = true
+	} else {
+		fctx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight{done: make(chan struct{}), cancel: cancel}
+		if c.flights == nil {
+			c.flights = make(map[string]*flight)
+		}
+		c.flights[u] = f
+		go func() {
+			body, err := c.fetch(fctx, u)
+			c.flightMu.Lock()
+			if c.flights[u] == f {
+				delete(c.flights, u)
+			}
+			f.body, f.err = body, err
+			c.flightMu.Unlock()
+			cancel()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	return f
+}
+
+// leave stops waiting for f, canceling its request if no caller is left.
+//
+// A canceled request is forgotten at once, so later callers start a new one.
+func (c *Client) leave(u string, f *flight) {
+	c.flightMu.Lock()
+	defer c.flightMu.Unlock()
+	f.waiters--
+	if f.waiters == 0 {
+		f.cancel()
+		if c.flights[u] == f {
+			delete(c.flights, u)
+		}
+	}
+}
+
+// fetch sends a GET request to u and returns the response body.
+func (c *Client) fetch(ctx context.Context, u string) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	code, body, err := c.do(req, nil)
 	if err != nil {
 		return nil, err
 	}
 
-	if resp.StatusCode >= 400 {
-		return nil, fmt.Errorf("%d: %s", resp.StatusCode, string(body))
+	if code >= 400 {
+		return nil, statusError(code, body)
 	}
 	return body, nil
 }
 
 func (c *Client) post(route string, payload []byte) (int, error) {
-	u, err := url.Parse(c.Gateway)
+	return c.postContext(context.Background(), route, payload)
+}
+
+func (c *Client) postContext(ctx context.Context, route string, payload []byte) (int, error) {
+	code, _, err := c.postJSONContext(ctx, route, payload)
+	return code, err
+}
+
+// postJSONContext sends payload as JSON to route and returns the status code and response body.
+func (c *Client) postJSONContext(ctx context.Context, route string, payload []byte) (int, []byte, error) {
+	u, err := c.url(route)
 	if err != nil {
-		return -1, err
+		return -1, nil, err
 	}
 
-	u.Path = path.Join(u.Path, route)
-	resp, err := c.Client.Post(u.String(), "application/json", bytes.NewBuffer(payload))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, u, bytes.NewBuffer(payload))
 	if err != nil {
-		return -1, err
+		return -1, nil, err
 	}
+	req.Header.Set("Content-Type", "application/json")
 
-	body, err := io.ReadAll(resp.Body)
+	code, body, err := c.do(req, payload)
 	if err != nil {
-		return -1, err
+		return -1, nil, err
 	}
-	code := resp.StatusCode
 	if code >= 400 {
-		return resp.StatusCode, fmt.Errorf("%d: %s", resp.StatusCode, string(body))
+		return code, nil, statusError(code, body)
+	}
+	return code, body, nil
+}
+
+// networkError classifies a failed request: goar.ErrDial if no connection could be established, goar.ErrNetwork otherwise.
+func networkError(err error) error {
+	var dnsErr *net.DNSError
+	var opErr *net.OpError
+	if errors.As(err, &dnsErr) || (errors.As(err, &opErr) && opErr.Op == "dial") {
+		return goar.Wrap(goar.ErrDial, err)
 	}
-	return code, nil
+	return goar.Wrap(goar.ErrNetwork, err)
+}
+
+// statusError builds the error for an HTTP error response, classified by status code and body.
+func statusError(code int, body []byte) error {
+	err := fmt.Errorf("%d: %s", code, string(body))
+	message := strings.ToLower(string(body))
+	switch {
+	case code == http.StatusNotFound:
+		return goar.Wrap(goar.ErrNotFound, err)
+	case code == http.StatusTooManyRequests:
+		return goar.Wrap(goar.ErrRateLimited, err)
+	case strings.Contains(message, "anchor") || strings.Contains(message, "last_tx"):
+		return goar.Wrap(goar.ErrAnchorExpired, err)
+	case code >= 500:
+		return goar.Wrap(goar.ErrGateway, err)
+	default:
+		return goar.Wrap(goar.ErrBadRequest, err)
+	}
+}
+
+// decodeJSON unmarshals a response body, classifying failures as decode errors.
+func decodeJSON(body []byte, v any) error {
+	return goar.Wrap(goar.ErrDecode, json.Unmarshal(body, v))
 }
@@ -0,0 +1,223 @@
+package client
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusError(t *testing.T) {
+	testCases := []struct {
+		code int
+		body string
+		want goar.ErrorCode
+	}{
+		{404, "Not Found.", goar.ErrNotFound},
+		{429, "Too Many Requests", goar.ErrRateLimited},
+		{400, "Invalid anchor (last_tx).", goar.ErrAnchorExpired},
+		{503, "Service Unavailable", goar.ErrGateway},
+		{400, "invalid_json", goar.ErrBadRequest},
+	}
+	for _, tc := range testCases {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(tc.code)
+			_, _ = w.Write([]byte(tc.body))
+		}))
+		c := New(server.URL)
+
+		_, err := c.GetTransactionAnchor()
+		assert.ErrorIs(t, err, tc.want)
+		_, err = c.post("tx", nil)
+		assert.ErrorIs(t, err, tc.want)
+		server.Close()
+	}
+
+	t.Run("Network", func(t *testing.T) {
+		_, err := New("http://127.0.0.1:1").GetTransactionAnchor()
+		assert.ErrorIs(t, err, goar.ErrNetwork)
+	})
+
+	t.Run("Decode", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("not json"))
+		}))
+		defer server.Close()
+		_, err := New(server.URL).GetNetworkInfo()
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
+
+// TestCoalescing verifies that identical concurrent GETs share one request
+func TestCoalescing(t *testing.T) {
+	const callers = 8
+
+	run := func(t *testing.T, c *Client, calls *atomic.Int32, release chan struct{}) [][]byte {
+		var started, done sync.WaitGroup
+		bodies := make([][]byte, callers)
+		for i := 0; i < callers; i++ {
+			started.Add(1)
+			done.Add(1)
+			go func() {
+				defer done.Done()
+				started.Done()
+				body, err := c.GetTransactionData("id")
+				assert.NoError(t, err)
+				bodies[i] = body
+			}()
+		}
+		started.Wait()
+		time.Sleep(50 * time.Millisecond)
+		close(release)
+		done.Wait()
+		return bodies
+	}
+
+	newServer := func(calls *atomic.Int32, release chan struct{}) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			<-release
+			_, _ = w.Write([]byte("data"))
+		}))
+	}
+
+	t.Run("Shared", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		server := newServer(&calls, release)
+		defer server.Close()
+
+		bodies := run(t, New(server.URL), &calls, release)
+		assert.Equal(t, int32(1), calls.Load())
+
+		// Callers own their copy of the body
+		bodies[0][0] = 'X'
+		for _, body := range bodies[1:] {
+			assert.Equal(t, []byte("data"), body)
+		}
+	})
+
+	t.Run("Disabled", func(t *testing.T) {
+		var calls atomic.Int32
+		release := make(chan struct{})
+		server := newServer(&calls, release)
+		defer server.Close()
+
+		c := New(server.URL)
+		c.DisableCoalescing = true
+		run(t, c, &calls, release)
+		assert.Equal(t, int32(callers), calls.Load())
+	})
+
+	t.Run("Canceled once every caller left", func(t *testing.T) {
+		reached := make(chan struct{})
+		canceled := make(chan struct{})
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			close(reached)
+			<-r.Context().Done()
+			close(canceled)
+		}))
+		defer server.Close()
+		c := New(server.URL)
+		u, err := c.url("id")
+		assert.NoError(t, err)
+		waiters := func(n int) func() bool {
+			return func() bool {
+				c.flightMu.Lock()
+				defer c.flightMu.Unlock()
+				f := c.flights[u]
+				return f != nil && f.waiters == n
+			}
+		}
+
+		first, cancelFirst := context.WithCancel(context.Background())
+		second, cancelSecond := context.WithCancel(context.Background())
+		var done sync.WaitGroup
+		for _, ctx := range []context.Context{first, second} {
+			done.Add(1)
+			go func() {
+				defer done.Done()
+				_, err := c.GetTransactionDataContext(ctx, "id")
+				assert.ErrorIs(t, err, context.Canceled)
+			}()
+		}
+		// Wait for the request to reach the server with both callers waiting for it
+		<-reached
+		assert.Eventually(t, waiters(2), 5*time.Second, time.Millisecond)
+
+		cancelFirst()
+		assert.Eventually(t, waiters(1), 5*time.Second, time.Millisecond)
+		select {
+		case <-canceled:
+			t.Fatal("request canceled while a caller is waiting")
+		case <-time.After(50 * time.Millisecond):
+		}
+		cancelSecond()
+		select {
+		case <-canceled:
+		case <-time.After(5 * time.Second):
+			t.Fatal("request still running after every caller left")
+		}
+		done.Wait()
+	})
+}
+
+func TestContext(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		select {
+		case <-release:
+		case <-r.Context().Done():
+		}
+		_, _ = w.Write([]byte("data"))
+	}))
+	defer server.Close()
+	defer close(release)
+
+	expired := func() context.Context {
+		ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+		t.Cleanup(cancel)
+		return ctx
+	}
+
+	t.Run("Get", func(t *testing.T) {
+		for _, coalescing := range []bool{true, false} {
+			c := New(server.URL)
+			c.DisableCoalescing = !coalescing
+			_, err := c.GetTransactionDataContext(expired(), "id")
+			assert.ErrorIs(t, err, context.DeadlineExceeded)
+			assert.ErrorIs(t, err, goar.ErrNetwork)
+		}
+	})
+
+	t.Run("Post", func(t *testing.T) {
+		_, err := New(server.URL).SubmitTransactionContext(expired(), &transaction.Transaction{})
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Waiting for a stream", func(t *testing.T) {
+		c := New(server.URL)
+		c.SetTransport(TransportOptions{MaxStreamsPerHost: 1})
+		// Hold the only stream
+		held, cancel := context.WithTimeout(context.Background(), time.Second)
+		defer cancel()
+		go func() { _, _ = c.GetTransactionDataContext(held, "held") }()
+		time.Sleep(5 * time.Millisecond)
+		_, err := c.GetNetworkInfoContext(expired())
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+	})
+
+	t.Run("Polling", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := New(server.URL).WaitForConfirmationContext(ctx, "id", 1, time.Minute)
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,160 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+)
+
+// RETRYABLE_STATUS_CODES are the status codes retried by a RetryPolicy without StatusCodes
+var RETRYABLE_STATUS_CODES = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Defaults of the zero RetryPolicy
+const (
+	DEFAULT_RETRY_ATTEMPTS   = 3                      // Attempts per request, including the first
+	DEFAULT_RETRY_BASE_DELAY = 500 * time.Millisecond // Delay before the first retry
+	DEFAULT_RETRY_MAX_DELAY  = 30 * time.Second       // Upper bound of the delay between attempts
+)
+
+// RetryPolicy configures how a Client retries failed requests.
+//
+// A request is retried when it does not reach the gateway, e.g. on a
+// connection reset, or when the gateway answers with one of StatusCodes.
+// Attempts are separated by an exponential backoff with jitter, or by the
+// delay of the Retry-After header of the response when it is longer. The
+// zero value is a usable policy with the DEFAULT_RETRY_* settings.
+//
+// Requests are sent again as they are, so the headers of the client and
+// its RequestSigner are applied on every attempt. Arweave treats repeated
+// transactions and chunks as the same upload, so POSTs are retried too.
+type RetryPolicy struct {
+	MaxAttempts   int           // Attempts per request including the first, DEFAULT_RETRY_ATTEMPTS if 0; 1 disables retries
+	BaseDelay     time.Duration // Delay before the first retry, doubled on each attempt; DEFAULT_RETRY_BASE_DELAY if 0
+	MaxDelay      time.Duration // Upper bound of the backoff delay, DEFAULT_RETRY_MAX_DELAY if 0
+	Jitter        float64       // Maximum fraction of the backoff delay removed at random, between 0 and 1
+	StatusCodes   []int         // Status codes retried, RETRYABLE_STATUS_CODES if nil
+	MaxRetryAfter time.Duration // Longest Retry-After waited for, MaxDelay if 0; a longer one fails the request at once
+}
+
+// noRetryKey is the context key set by WithoutRetry
+type noRetryKey struct{}
+
+// WithoutRetry returns a context whose requests are sent once, ignoring the client's RetryPolicy.
+//
+// Example:
+//
+//	// Fail fast: the caller has its own fallback
+//	tx, err := c.GetTransactionByIDContext(client.WithoutRetry(ctx), id)
+func WithoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// retryPolicy returns the policy of a request with ctx, nil if it is sent once
+func (c *Client) retryPolicy(ctx context.Context) *RetryPolicy {
+	if ctx.Value(noRetryKey{}) != nil {
+		return nil
+	}
+	return c.Retry
+}
+
+// attempts returns the maximum number of attempts of a request
+func (p *RetryPolicy) attempts() int {
+	if p == nil {
+		return 1
+	}
+	if p.MaxAttempts == 0 {
+		return DEFAULT_RETRY_ATTEMPTS
+	}
+	return p.MaxAttempts
+}
+
+// delay returns how long to wait after the given failed attempt, or false if the request must not be retried
+func (p *RetryPolicy) delay(attempt int, code int, header http.Header, err error, now time.Time) (time.Duration, bool) {
+	switch {
+	case err != nil:
+		if goar.CodeOf(err) != goar.ErrNetwork && goar.CodeOf(err) != goar.ErrDial {
+			return 0, false
+		}
+		if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+			return 0, false
+		}
+	case !slices.Contains(p.statusCodes(), code):
+		return 0, false
+	}
+
+	backoff := retry.Backoff{
+		Base:        p.BaseDelay,
+		Max:         p.MaxDelay,
+		Exponential: true,
+		Jitter:      p.Jitter,
+	}
+	if backoff.Base <= 0 {
+		backoff.Base = DEFAULT_RETRY_BASE_DELAY
+	}
+	if backoff.Max <= 0 {
+		backoff.Max = DEFAULT_RETRY_MAX_DELAY
+	}
+	d := backoff.Delay(attempt)
+
+	if after, ok := retryAfter(header, now); ok {
+		limit := p.MaxRetryAfter
+		if limit <= 0 {
+			limit = backoff.Max
+		}
+		if after > limit {
+			return 0, false
+		}
+		d = max(d, after)
+	}
+	return d, true
+}
+
+// statusCodes returns the status codes retried by the policy
+func (p *RetryPolicy) statusCodes() []int {
+	if p.StatusCodes == nil {
+		return RETRYABLE_STATUS_CODES
+	}
+	return p.StatusCodes
+}
+
+// retryAfter parses the Retry-After header of a response, in seconds or as an HTTP date
+func retryAfter(header http.Header, now time.Time) (time.Duration, bool) {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		return time.Duration(seconds) * time.Second, true
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return max(at.Sub(now), 0), true
+	}
+	return 0, false
+}
+
+// rewind returns a copy of req that can be sent again, or nil if its body cannot be read again
+func rewind(req *http.Request) *http.Request {
+	next := req.Clone(req.Context())
+	if req.Body != nil && req.Body != http.NoBody {
+		if req.GetBody == nil {
+			return nil
+		}
+		body, err := req.GetBody()
+		if err != nil {
+			return nil
+		}
+		next.Body = body
+	}
+	return next
+}
@@ -0,0 +1,148 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestRetryPolicy verifies failed requests are retried with backoff and Retry-After
+func TestRetryPolicy(t *testing.T) {
+	// The first failures requests of each test are answered with respond
+	var requests, failures atomic.Int32
+	var respond func(w http.ResponseWriter)
+	var bodies []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		b, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(b))
+		if requests.Add(1) <= failures.Load() {
+			respond(w)
+			return
+		}
+		_, _ = w.Write([]byte(`{"network":"arweave.N.1","height":42}`))
+	}))
+	defer server.Close()
+
+	setup := func(n int32, r func(w http.ResponseWriter), policy *RetryPolicy) (*Client, *retry.FakeClock) {
+		requests.Store(0)
+		failures.Store(n)
+		respond = r
+		bodies = nil
+		clock := retry.NewFakeClock(time.Unix(1_700_000_000, 0))
+		c := New(server.URL)
+		c.DisableCoalescing = true
+		c.Retry = policy
+		c.clock = clock
+		return c, clock
+	}
+	status := func(code int) func(w http.ResponseWriter) {
+		return func(w http.ResponseWriter) { w.WriteHeader(code) }
+	}
+
+	t.Run("Backoff", func(t *testing.T) {
+		c, clock := setup(2, status(http.StatusServiceUnavailable), &RetryPolicy{})
+		info, err := c.GetNetworkInfo()
+		require.NoError(t, err)
+		assert.Equal(t, int64(42), info.Height)
+		assert.Equal(t, int32(3), requests.Load())
+		assert.Equal(t, []time.Duration{DEFAULT_RETRY_BASE_DELAY, 2 * DEFAULT_RETRY_BASE_DELAY}, clock.Sleeps())
+	})
+
+	t.Run("Attempts exhausted", func(t *testing.T) {
+		c, _ := setup(5, status(http.StatusBadGateway), &RetryPolicy{MaxAttempts: 4, BaseDelay: time.Millisecond})
+		_, err := c.GetNetworkInfo()
+		assert.Equal(t, goar.ErrGateway, goar.CodeOf(err))
+		assert.Equal(t, int32(4), requests.Load())
+	})
+
+	t.Run("Not retryable", func(t *testing.T) {
+		c, clock := setup(1, status(http.StatusInternalServerError), &RetryPolicy{})
+		_, err := c.GetNetworkInfo()
+		assert.Equal(t, goar.ErrGateway, goar.CodeOf(err))
+		assert.Equal(t, int32(1), requests.Load())
+		assert.Empty(t, clock.Sleeps())
+	})
+
+	t.Run("Retry-After", func(t *testing.T) {
+		c, clock := setup(1, func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}, &RetryPolicy{})
+		_, err := c.GetNetworkInfo()
+		require.NoError(t, err)
+		assert.Equal(t, []time.Duration{7 * time.Second}, clock.Sleeps())
+	})
+
+	t.Run("Retry-After too long", func(t *testing.T) {
+		c, _ := setup(1, func(w http.ResponseWriter) {
+			w.Header().Set("Retry-After", "3600")
+			w.WriteHeader(http.StatusTooManyRequests)
+		}, &RetryPolicy{})
+		_, err := c.GetNetworkInfo()
+		assert.Equal(t, goar.ErrRateLimited, goar.CodeOf(err))
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("Connection reset", func(t *testing.T) {
+		c, _ := setup(1, func(w http.ResponseWriter) {
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			conn.Close()
+		}, &RetryPolicy{})
+		_, err := c.GetNetworkInfo()
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), requests.Load())
+	})
+
+	t.Run("POST body sent again", func(t *testing.T) {
+		c, _ := setup(1, status(http.StatusServiceUnavailable), &RetryPolicy{})
+		code, err := c.post("chunk", []byte(`{"chunk":"abc"}`))
+		require.NoError(t, err)
+		assert.Equal(t, http.StatusOK, code)
+		assert.Equal(t, []string{`{"chunk":"abc"}`, `{"chunk":"abc"}`}, bodies)
+	})
+
+	t.Run("WithoutRetry", func(t *testing.T) {
+		c, _ := setup(1, status(http.StatusServiceUnavailable), &RetryPolicy{})
+		_, err := c.GetNetworkInfoContext(WithoutRetry(context.Background()))
+		assert.Equal(t, goar.ErrGateway, goar.CodeOf(err))
+		assert.Equal(t, int32(1), requests.Load())
+	})
+
+	t.Run("No policy", func(t *testing.T) {
+		c, _ := setup(1, status(http.StatusServiceUnavailable), nil)
+		_, err := c.GetNetworkInfo()
+		assert.Equal(t, goar.ErrGateway, goar.CodeOf(err))
+		assert.Equal(t, int32(1), requests.Load())
+	})
+}
+
+// TestRetryAfter verifies both forms of the Retry-After header are parsed
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		value    string
+		expected time.Duration
+		ok       bool
+	}{
+		{"", 0, false},
+		{"12", 12 * time.Second, true},
+		{"Mon, 01 Jan 2024 00:01:00 GMT", time.Minute, true},
+		{"Sun, 31 Dec 2023 00:00:00 GMT", 0, true},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		d, ok := retryAfter(http.Header{"Retry-After": {tt.value}}, now)
+		assert.Equal(t, tt.ok, ok, tt.value)
+		assert.Equal(t, tt.expected, d, tt.value)
+	}
+}
@@ -0,0 +1,257 @@
+package client
+
+import (
+	"context"
+
+	"github.com/liteseed/goar/tag"
+)
+
+// Sort orders of TransactionQuery.Sort
+const (
+	SORT_HEIGHT_DESC = "HEIGHT_DESC" // Newest first, the gateway default
+	SORT_HEIGHT_ASC  = "HEIGHT_ASC"  // Oldest first
+)
+
+// DEFAULT_PAGE_SIZE is the number of transactions per page when TransactionQuery.First is 0
+const DEFAULT_PAGE_SIZE = 100
+
+// TagFilter matches transactions having a tag Name with any of Values.
+type TagFilter struct {
+	Name   string   `json:"name"`
+	Values []string `json:"values"`
+	Op     string   `json:"op,omitempty"`    // TAG_OP_EQ or TAG_OP_NEQ, the gateway default if empty
+	Match  string   `json:"match,omitempty"` // One of the TAG_MATCH_* modes, the gateway default if empty
+}
+
+// TransactionQuery filters the transactions returned by SearchTransactions.
+//
+// Empty fields do not filter. Every set field must match. The query sent to
+// the gateway is built by Build.
+//
+// Gateways cannot filter by data size, so MinDataSize and MaxDataSize are
+// applied to each page once fetched: pages may hold fewer than First
+// transactions, or none, while more still match.
+type TransactionQuery struct {
+	IDs        []string    // Transaction or data item IDs
+	Owners     []string    // Addresses of the signers
+	Recipients []string    // Target addresses
+	Tags       []TagFilter // Tags that must all be present
+	BundledIn  []string    // IDs of the bundles containing the data items
+	MinHeight  int64       // Lowest block height, 0 for no bound
+	MaxHeight  int64       // Highest block height, 0 for no bound
+	Sort       string      // SORT_HEIGHT_DESC or SORT_HEIGHT_ASC, the gateway default if empty
+	First      int         // Page size, DEFAULT_PAGE_SIZE if 0
+
+	MinDataSize int64     // Smallest data size in bytes, 0 for no bound
+	MaxDataSize int64     // Largest data size in bytes, 0 for no bound
+	Fields      *Fragment // Fields fetched for each transaction, TRANSACTION_FIELDS if nil
+}
+
+// Amount is a quantity of AR as reported by GraphQL.
+type Amount struct {
+	Winston string `json:"winston"`
+	AR      string `json:"ar"`
+}
+
+// BlockRef is the block a transaction was mined in.
+type BlockRef struct {
+	ID        string `json:"id"`
+	Timestamp int64  `json:"timestamp"`
+	Height    int64  `json:"height"`
+	Previous  string `json:"previous"`
+}
+
+// TransactionNode is a transaction or data item returned by SearchTransactions.
+type TransactionNode struct {
+	ID        string `json:"id"`
+	Anchor    string `json:"anchor"`
+	Signature string `json:"signature"`
+	Recipient string `json:"recipient"`
+	Owner     struct {
+		Address string `json:"address"`
+		Key     string `json:"key"`
+	} `json:"owner"`
+	Fee      Amount `json:"fee"`
+	Quantity Amount `json:"quantity"`
+	Data     struct {
+		Size string `json:"size"`
+		Type string `json:"type"`
+	} `json:"data"`
+	Tags      []tag.Tag `json:"tags"`  // Decoded tags
+	Block     *BlockRef `json:"block"` // nil while pending
+	BundledIn *struct {
+		ID string `json:"id"`
+	} `json:"bundledIn"` // nil unless the node is a data item
+	Cursor string `json:"-"` // Pagination cursor of the node
+}
+
+// TransactionPage is one page of SearchTransactions results.
+type TransactionPage struct {
+	Transactions []TransactionNode
+	HasNextPage  bool   // Whether more transactions match
+	Cursor       string // Cursor of the last transaction, to pass to the next SearchTransactions call
+}
+
+type searchResult struct {
+	Transactions struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Edges []struct {
+			Cursor string          `json:"cursor"`
+			Node   TransactionNode `json:"node"`
+		} `json:"edges"`
+	} `json:"transactions"`
+}
+
+// variables returns the GraphQL variables of the query, leaving unset filters out
+func (q *TransactionQuery) variables(after string) map[string]any {
+	v := map[string]any{}
+	if len(q.IDs) > 0 {
+		v["ids"] = q.IDs
+	}
+	if len(q.Owners) > 0 {
+		v["owners"] = q.Owners
+	}
+	if len(q.Recipients) > 0 {
+		v["recipients"] = q.Recipients
+	}
+	if len(q.Tags) > 0 {
+		v["tags"] = q.Tags
+	}
+	if len(q.BundledIn) > 0 {
+		v["bundledIn"] = q.BundledIn
+	}
+	if q.MinHeight > 0 || q.MaxHeight > 0 {
+		block := map[string]int64{}
+		if q.MinHeight > 0 {
+			block["min"] = q.MinHeight
+		}
+		if q.MaxHeight > 0 {
+			block["max"] = q.MaxHeight
+		}
+		v["block"] = block
+	}
+	if q.Sort != "" {
+		v["sort"] = q.Sort
+	}
+	v["first"] = DEFAULT_PAGE_SIZE
+	if q.First > 0 {
+		v["first"] = q.First
+	}
+	if after != "" {
+		v["after"] = after
+	}
+	return v
+}
+
+// SearchTransactions returns a page of the transactions and data items matching q.
+//
+// Parameters:
+//   - ctx: Bounds the request
+//   - q: The filters of the search
+//   - after: Cursor of the previous page, empty for the first page
+//
+// Returns an error with code goar.ErrInvalidInput if q is malformed,
+// goar.ErrBadRequest if the gateway rejects the query, or the request or
+// decoding error otherwise.
+//
+// Example:
+//
+//	q := &client.TransactionQuery{Tags: []client.TagFilter{{Name: "App-Name", Values: []string{"goar"}}}}
+//	page, err := c.SearchTransactions(ctx, q, "")
+//	for err == nil && page.HasNextPage {
+//		page, err = c.SearchTransactions(ctx, q, page.Cursor)
+//	}
+func (c *Client) SearchTransactions(ctx context.Context, q *TransactionQuery, after string) (*TransactionPage, error) {
+	query, variables, err := q.Build(after)
+	if err != nil {
+		return nil, err
+	}
+	var result searchResult
+	if err := c.GraphQLContext(ctx, query, variables, &result); err != nil {
+		return nil, err
+	}
+	page := &TransactionPage{
+		Transactions: make([]TransactionNode, 0, len(result.Transactions.Edges)),
+		HasNextPage:  result.Transactions.PageInfo.HasNextPage,
+		Cursor:       after,
+	}
+	for _, edge := range result.Transactions.Edges {
+		// The cursor advances over filtered out transactions too
+		page.Cursor = edge.Cursor
+		if !q.matchesSize(edge.Node.Data.Size) {
+			continue
+		}
+		node := edge.Node
+		node.Cursor = edge.Cursor
+		page.Transactions = append(page.Transactions, node)
+	}
+	if len(result.Transactions.Edges) == 0 {
+		page.HasNextPage = false
+	}
+	return page, nil
+}
+
+// TransactionIterator walks every transaction matching a query, fetching pages as needed.
+//
+// Example:
+//
+//	it := c.Transactions(ctx, q)
+//	for it.Next() {
+//		fmt.Println(it.Transaction().ID)
+//	}
+//	if err := it.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+type TransactionIterator struct {
+	c       *Client
+	ctx     context.Context
+	q       *TransactionQuery
+	page    *TransactionPage
+	index   int
+	current *TransactionNode
+	err     error
+}
+
+// Transactions returns an iterator over all the transactions matching q.
+func (c *Client) Transactions(ctx context.Context, q *TransactionQuery) *TransactionIterator {
+	return &TransactionIterator{c: c, ctx: ctx, q: q}
+}
+
+// Next advances to the next transaction, fetching the next page when the
+// current one is exhausted. It returns false at the end or on error.
+func (it *TransactionIterator) Next() bool {
+	if it.err != nil {
+		return false
+	}
+	for it.page == nil || it.index >= len(it.page.Transactions) {
+		if it.page != nil && !it.page.HasNextPage {
+			it.current = nil
+			return false
+		}
+		after := ""
+		if it.page != nil {
+			after = it.page.Cursor
+		}
+		it.page, it.err = it.c.SearchTransactions(it.ctx, it.q, after)
+		if it.err != nil {
+			it.current = nil
+			return false
+		}
+		it.index = 0
+	}
+	it.current = &it.page.Transactions[it.index]
+	it.index++
+	return true
+}
+
+// Transaction returns the current transaction, nil before the first call to Next or after the end.
+func (it *TransactionIterator) Transaction() *TransactionNode {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *TransactionIterator) Err() error {
+	return it.err
+}
@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSearchGateway serves 5 transactions in pages, recording the variables of every query
+func newSearchGateway(t *testing.T, variables *[]map[string]any) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req graphQLRequest
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		*variables = append(*variables, req.Variables)
+
+		start := 0
+		if after, ok := req.Variables["after"].(string); ok {
+			start, _ = strconv.Atoi(after)
+		}
+		first := int(req.Variables["first"].(float64))
+		end := min(start+first, 5)
+		edges := []string{}
+		for i := start; i < end; i++ {
+			edges = append(edges, fmt.Sprintf(`{"cursor": "%d", "node": {"id": "tx%d", "owner": {"address": "owner"}, "tags": [{"name": "App-Name", "value": "goar"}], "block": {"height": %d}, "bundledIn": null}}`, i+1, i, 100+i))
+		}
+		fmt.Fprintf(w, `{"data": {"transactions": {"pageInfo": {"hasNextPage": %t}, "edges": [%s]}}}`, end < 5, strings.Join(edges, ","))
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestSearchTransactions(t *testing.T) {
+	var variables []map[string]any
+	c := New(newSearchGateway(t, &variables).URL)
+	q := &TransactionQuery{
+		Owners:    []string{"owner"},
+		Tags:      []TagFilter{{Name: "App-Name", Values: []string{"goar"}}},
+		BundledIn: []string{"bundle"},
+		MinHeight: 100,
+		Sort:      SORT_HEIGHT_ASC,
+		First:     2,
+	}
+
+	page, err := c.SearchTransactions(context.Background(), q, "")
+	require.NoError(t, err)
+	require.Len(t, page.Transactions, 2)
+	assert.True(t, page.HasNextPage)
+	assert.Equal(t, "2", page.Cursor)
+	assert.Equal(t, "tx0", page.Transactions[0].ID)
+	assert.Equal(t, "owner", page.Transactions[0].Owner.Address)
+	assert.Equal(t, []tag.Tag{{Name: "App-Name", Value: "goar"}}, page.Transactions[0].Tags)
+	assert.Equal(t, int64(101), page.Transactions[1].Block.Height)
+	assert.Nil(t, page.Transactions[1].BundledIn)
+
+	v := variables[0]
+	assert.Equal(t, []any{"owner"}, v["owners"])
+	assert.Equal(t, []any{"bundle"}, v["bundledIn"])
+	assert.Equal(t, map[string]any{"min": float64(100)}, v["block"])
+	assert.Equal(t, SORT_HEIGHT_ASC, v["sort"])
+	assert.NotContains(t, v, "recipients")
+	assert.NotContains(t, v, "after")
+
+	page, err = c.SearchTransactions(context.Background(), q, page.Cursor)
+	require.NoError(t, err)
+	assert.Equal(t, "tx2", page.Transactions[0].ID)
+	assert.Equal(t, "2", variables[1]["after"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = c.SearchTransactions(ctx, q, "")
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.ErrorIs(t, err, goar.ErrNetwork)
+}
+
+func TestTransactionIterator(t *testing.T) {
+	var variables []map[string]any
+	c := New(newSearchGateway(t, &variables).URL)
+
+	it := c.Transactions(context.Background(), &TransactionQuery{First: 2})
+	assert.Nil(t, it.Transaction())
+	var ids []string
+	for it.Next() {
+		ids = append(ids, it.Transaction().ID)
+	}
+	require.NoError(t, it.Err())
+	assert.Equal(t, []string{"tx0", "tx1", "tx2", "tx3", "tx4"}, ids)
+	assert.Len(t, variables, 3)
+	assert.Nil(t, it.Transaction())
+	assert.False(t, it.Next())
+
+	// The default page size fetches everything at once
+	variables = nil
+	it = c.Transactions(context.Background(), &TransactionQuery{})
+	for it.Next() {
+	}
+	assert.Len(t, variables, 1)
+	assert.Equal(t, float64(DEFAULT_PAGE_SIZE), variables[0]["first"])
+}
+
+func TestTransactionQueryBuild(t *testing.T) {
+	t.Run("DeclaresSetFilters", func(t *testing.T) {
+		q := &TransactionQuery{
+			Owners:    []string{`owner") { edges { node { id } } } #`},
+			Tags:      []TagFilter{{Name: "Content-Type", Values: []string{"image/*"}, Match: TAG_MATCH_WILDCARD}},
+			MinHeight: 10,
+			MaxHeight: 20,
+		}
+		query, variables, err := q.Build("cursor")
+		require.NoError(t, err)
+		assert.Contains(t, query, "query($owners: [String!], $tags: [TagFilter!], $block: BlockFilter, $first: Int, $after: String)")
+		assert.Contains(t, query, "transactions(owners: $owners, tags: $tags, block: $block, first: $first, after: $after)")
+		assert.Contains(t, query, "node { ...TransactionFields }")
+		assert.Contains(t, query, "fragment TransactionFields on Transaction {")
+		assert.NotContains(t, query, "owner\")")
+		assert.NotContains(t, query, "$ids")
+		assert.Equal(t, q.Owners, variables["owners"])
+		assert.Equal(t, map[string]int64{"min": 10, "max": 20}, variables["block"])
+
+		b, err := json.Marshal(variables["tags"])
+		require.NoError(t, err)
+		assert.JSONEq(t, `[{"name": "Content-Type", "values": ["image/*"], "match": "WILDCARD"}]`, string(b))
+	})
+
+	t.Run("Fragment", func(t *testing.T) {
+		q := &TransactionQuery{Fields: &ID_FIELDS, MaxDataSize: 100}
+		query, _, err := q.Build("")
+		require.NoError(t, err)
+		assert.Contains(t, query, "node { ...IDFields data { size } }")
+		assert.Contains(t, query, "fragment IDFields on Transaction {\n  id\n}")
+	})
+
+	for name, q := range map[string]*TransactionQuery{
+		"Sort":            {Sort: "RANDOM"},
+		"First":           {First: -1},
+		"Heights":         {MinHeight: 20, MaxHeight: 10},
+		"DataSizes":       {MinDataSize: -1},
+		"TagName":         {Tags: []TagFilter{{Values: []string{"v"}}}},
+		"TagOp":           {Tags: []TagFilter{{Name: "n", Op: "LIKE"}}},
+		"TagMatch":        {Tags: []TagFilter{{Name: "n", Match: "REGEX"}}},
+		"FragmentName":    {Fields: &Fragment{Name: "bad name", Fields: "id"}},
+		"FragmentBraces":  {Fields: &Fragment{Name: "F", Fields: "id } transactions {"}},
+		"FragmentLiteral": {Fields: &Fragment{Name: "F", Fields: `tags(name: "x") { value }`}},
+		"FragmentEmpty":   {Fields: &Fragment{Name: "F"}},
+	} {
+		t.Run("Invalid"+name, func(t *testing.T) {
+			_, _, err := q.Build("")
+			assert.ErrorIs(t, err, goar.ErrInvalidInput)
+		})
+	}
+}
+
+func TestSearchTransactionsDataSize(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"data": {"transactions": {"pageInfo": {"hasNextPage": true}, "edges": [
+			{"cursor": "1", "node": {"id": "small", "data": {"size": "10"}}},
+			{"cursor": "2", "node": {"id": "large", "data": {"size": "5000"}}},
+			{"cursor": "3", "node": {"id": "huge", "data": {"size": "900000"}}}
+		]}}}`))
+	}))
+	t.Cleanup(server.Close)
+	c := New(server.URL)
+
+	page, err := c.SearchTransactions(context.Background(), &TransactionQuery{MinDataSize: 100, MaxDataSize: 10000}, "")
+	require.NoError(t, err)
+	require.Len(t, page.Transactions, 1)
+	assert.Equal(t, "large", page.Transactions[0].ID)
+	assert.Equal(t, "2", page.Transactions[0].Cursor)
+	assert.Equal(t, "3", page.Cursor)
+	assert.True(t, page.HasNextPage)
+
+	_, err = c.SearchTransactions(context.Background(), &TransactionQuery{Sort: "RANDOM"}, "")
+	assert.ErrorIs(t, err, goar.ErrInvalidInput)
+}
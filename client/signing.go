@@ -0,0 +1,76 @@
+package client
+
+import (
+	"crypto/rand"
+	"net/http"
+
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/signer"
+)
+
+// Header names used by NonceSigner
+const (
+	HeaderNonce     = "x-nonce"      // Random per-request nonce
+	HeaderSignature = "x-signature"  // Base64url-encoded signature of the nonce
+	HeaderPublicKey = "x-public-key" // Base64url-encoded owner of the signing wallet
+)
+
+// RequestSigner authenticates outgoing HTTP requests.
+//
+// Some gateways and bundlers only serve privileged endpoints to callers that
+// prove ownership of a wallet. A RequestSigner attached to the Client is
+// invoked for every request right before it is sent, and may add whatever
+// headers the remote scheme requires.
+//
+// Parameters:
+//   - req: The outgoing request, which may be modified in place
+//   - body: The request payload (nil for requests without a body)
+//
+// Returns an error to abort the request.
+type RequestSigner interface {
+	SignRequest(req *http.Request, body []byte) error
+}
+
+// RequestSignerFunc adapts an ordinary function to the RequestSigner interface.
+type RequestSignerFunc func(req *http.Request, body []byte) error
+
+// SignRequest calls f(req, body).
+func (f RequestSignerFunc) SignRequest(req *http.Request, body []byte) error {
+	return f(req, body)
+}
+
+// NonceSigner signs requests with an Arweave wallet using the nonce scheme
+// common to bundler services: a random nonce is signed with RSA-PSS and sent
+// together with the wallet's public key.
+type NonceSigner struct {
+	Signer *signer.Signer // Wallet key used to sign the nonce
+}
+
+// NewNonceSigner creates a NonceSigner for the given wallet key.
+//
+// Example:
+//
+//	c := client.New("https://node2.bundlr.network")
+//	c.RequestSigner = client.NewNonceSigner(s)
+func NewNonceSigner(s *signer.Signer) *NonceSigner {
+	return &NonceSigner{Signer: s}
+}
+
+// SignRequest adds the x-nonce, x-signature and x-public-key headers to req.
+func (ns *NonceSigner) SignRequest(req *http.Request, _ []byte) error {
+	rawNonce := make([]byte, 32)
+	if _, err := rand.Read(rawNonce); err != nil {
+		return err
+	}
+	nonce := crypto.Base64URLEncode(rawNonce)
+
+	signature, err := crypto.Sign([]byte(nonce), ns.Signer.PrivateKey)
+	if err != nil {
+		return err
+	}
+
+	req.Header.Set(HeaderNonce, nonce)
+	req.Header.Set(HeaderSignature, crypto.Base64URLEncode(signature))
+	req.Header.Set(HeaderPublicKey, ns.Signer.Owner())
+	return nil
+}
@@ -0,0 +1,77 @@
+package client
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNonceSigner(t *testing.T) {
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+
+	var headers http.Header
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		headers = r.Header.Clone()
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	c.RequestSigner = NewNonceSigner(s)
+
+	t.Run("GET", func(t *testing.T) {
+		_, err := c.GetTransactionAnchor()
+		require.NoError(t, err)
+
+		nonce := headers.Get(HeaderNonce)
+		assert.NotEmpty(t, nonce)
+		assert.Equal(t, s.Owner(), headers.Get(HeaderPublicKey))
+
+		signature, err := crypto.Base64URLDecode(headers.Get(HeaderSignature))
+		require.NoError(t, err)
+		publicKey, err := crypto.GetPublicKeyFromOwner(headers.Get(HeaderPublicKey))
+		require.NoError(t, err)
+		assert.NoError(t, crypto.Verify([]byte(nonce), signature, publicKey))
+	})
+
+	t.Run("POST", func(t *testing.T) {
+		_, err := c.post("chunk", []byte("{}"))
+		require.NoError(t, err)
+		assert.NotEmpty(t, headers.Get(HeaderSignature))
+		assert.Equal(t, "application/json", headers.Get("Content-Type"))
+	})
+}
+
+func TestRequestSignerFunc(t *testing.T) {
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("x-api-key"))
+	}))
+	defer server.Close()
+
+	c := New(server.URL)
+	c.RequestSigner = RequestSignerFunc(func(req *http.Request, b []byte) error {
+		body = b
+		req.Header.Set("x-api-key", "secret")
+		return nil
+	})
+
+	_, err := c.post("tx", []byte("payload"))
+	require.NoError(t, err)
+	assert.Equal(t, []byte("payload"), body)
+
+	t.Run("Abort on error", func(t *testing.T) {
+		c.RequestSigner = RequestSignerFunc(func(*http.Request, []byte) error {
+			return errors.New("denied")
+		})
+		_, err := c.get("info")
+		assert.EqualError(t, err, "denied")
+	})
+}
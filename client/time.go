@@ -0,0 +1,115 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liteseed/goar"
+)
+
+// GetTime retrieves the gateway's current time.
+//
+// The /time endpoint reports Unix time with a resolution of one second.
+//
+// Returns the gateway time, or an error if the request fails or the
+// response is not a Unix timestamp.
+//
+// Example:
+//
+//	now, err := client.GetTime()
+//	if err != nil {
+//		log.Printf("Failed to get time: %v", err)
+//		return
+//	}
+//	fmt.Printf("Gateway time: %s\n", now)
+func (c *Client) GetTime() (time.Time, error) {
+	return c.GetTimeContext(context.Background())
+}
+
+// GetTimeContext is GetTime bound to ctx.
+func (c *Client) GetTimeContext(ctx context.Context) (time.Time, error) {
+	body, err := c.getContext(ctx, "time")
+	if errors.Is(err, goar.ErrNotFound) && c.arlocal(ctx) {
+		return time.Now().Truncate(time.Second), nil
+	}
+	if err != nil {
+		return time.Time{}, err
+	}
+	seconds, err := strconv.ParseInt(strings.TrimSpace(string(body)), 10, 64)
+	if err != nil {
+		return time.Time{}, goar.Wrap(goar.ErrDecode, err)
+	}
+	return time.Unix(seconds, 0), nil
+}
+
+// EstimateClockSkew estimates how far the gateway's clock is ahead of the local clock.
+//
+// The gateway time is compared with the local time halfway through the
+// request, which cancels out symmetric network latency. If the gateway does
+// not serve /time, the timestamp of the current block is used instead; blocks
+// are mined every two minutes on average, so this fallback only detects
+// coarse skew.
+//
+// Returns the estimated skew (negative if the local clock is ahead), or an
+// error if neither source is available.
+//
+// Example:
+//
+//	skew, err := client.EstimateClockSkew()
+//	if err == nil && skew.Abs() > time.Minute {
+//		log.Printf("Local clock is off by %s", skew)
+//	}
+func (c *Client) EstimateClockSkew() (time.Duration, error) {
+	return c.EstimateClockSkewContext(context.Background())
+}
+
+// EstimateClockSkewContext is EstimateClockSkew bound to ctx.
+func (c *Client) EstimateClockSkewContext(ctx context.Context) (time.Duration, error) {
+	start := time.Now()
+	now, err := c.GetTimeContext(ctx)
+	if err == nil {
+		local := start.Add(time.Since(start) / 2)
+		// The gateway truncates to whole seconds, so its time is on average half a second behind
+		return now.Add(500 * time.Millisecond).Sub(local), nil
+	}
+
+	info, infoErr := c.GetNetworkInfoContext(ctx)
+	if infoErr != nil {
+		return 0, err
+	}
+	block, blockErr := c.GetBlockByIDContext(ctx, info.Current)
+	if blockErr != nil {
+		return 0, err
+	}
+	return time.Unix(int64(block.Timestamp), 0).Sub(time.Now()), nil
+}
+
+// SyncClock estimates the clock skew with the gateway and stores it in ClockSkew.
+//
+// Call it once after creating the client, or periodically for long-running
+// processes, so that Now and the deadlines derived from it follow the
+// gateway's clock.
+func (c *Client) SyncClock() error {
+	return c.SyncClockContext(context.Background())
+}
+
+// SyncClockContext is SyncClock bound to ctx.
+func (c *Client) SyncClockContext(ctx context.Context) error {
+	skew, err := c.EstimateClockSkewContext(ctx)
+	if err != nil {
+		return err
+	}
+	c.ClockSkew = skew
+	return nil
+}
+
+// Now returns the current time corrected by ClockSkew.
+//
+// Retry delays and deadlines computed against the gateway should use Now
+// rather than time.Now, so that every timestamp comes from the same clock.
+func (c *Client) Now() time.Time {
+	return time.Now().Add(c.ClockSkew)
+}
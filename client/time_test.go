@@ -0,0 +1,73 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestClockSkew verifies skew estimation from the time endpoint and the current block
+func TestClockSkew(t *testing.T) {
+	ahead := time.Hour
+
+	t.Run("Time endpoint", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			require.Equal(t, "/time", r.URL.Path)
+			fmt.Fprintf(w, "%d", time.Now().Add(ahead).Unix())
+		}))
+		defer server.Close()
+
+		c := New(server.URL)
+		now, err := c.GetTime()
+		require.NoError(t, err)
+		assert.WithinDuration(t, time.Now().Add(ahead), now, 2*time.Second)
+
+		require.NoError(t, c.SyncClock())
+		assert.InDelta(t, ahead.Seconds(), c.ClockSkew.Seconds(), 1.5)
+		assert.WithinDuration(t, time.Now().Add(ahead), c.Now(), 2*time.Second)
+	})
+
+	t.Run("Block timestamp fallback", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case "/info":
+				fmt.Fprint(w, `{"current":"abc"}`)
+			case "/block/hash/abc":
+				fmt.Fprintf(w, `{"timestamp":%d}`, time.Now().Add(-ahead).Unix())
+			default:
+				http.NotFound(w, r)
+			}
+		}))
+		defer server.Close()
+
+		skew, err := New(server.URL).EstimateClockSkew()
+		require.NoError(t, err)
+		assert.InDelta(t, -ahead.Seconds(), skew.Seconds(), 1.5)
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		server := httptest.NewServer(http.NotFoundHandler())
+		defer server.Close()
+
+		c := New(server.URL)
+		err := c.SyncClock()
+		assert.ErrorIs(t, err, goar.ErrNotFound)
+		assert.Zero(t, c.ClockSkew)
+	})
+
+	t.Run("Invalid time", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			fmt.Fprint(w, "noon")
+		}))
+		defer server.Close()
+
+		_, err := New(server.URL).GetTime()
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
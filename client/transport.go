@@ -0,0 +1,109 @@
+package client
+
+import (
+	"context"
+	"crypto/tls"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+// IP version preferences for TransportOptions.Network
+const (
+	NetworkAny  = "tcp"  // Connect over IPv4 or IPv6, racing both when the gateway has both
+	NetworkIPv4 = "tcp4" // Connect over IPv4 only
+	NetworkIPv6 = "tcp6" // Connect over IPv6 only
+)
+
+// DEFAULT_DIAL_TIMEOUT bounds DNS resolution and TCP connection when TransportOptions.DialTimeout is 0
+const DEFAULT_DIAL_TIMEOUT = 30 * time.Second
+
+// TransportOptions configures the connections a Client opens to its gateway.
+type TransportOptions struct {
+	HTTP2             bool                                  // Negotiate HTTP/2 over TLS, so parallel requests share connections as streams
+	MaxConnsPerHost   int                                   // Maximum number of connections to the gateway, 0 for no limit
+	MaxStreamsPerHost int                                   // Maximum number of concurrent requests to the gateway, 0 for no limit
+	TLSConfig         *tls.Config                           // Optional TLS configuration, e.g. custom root CAs
+	DialTimeout       time.Duration                         // Timeout for DNS resolution and TCP connection, DEFAULT_DIAL_TIMEOUT if 0
+	Network           string                                // IP version to connect over, one of NetworkAny (default), NetworkIPv4 or NetworkIPv6
+	FallbackDelay     time.Duration                         // Head start of IPv6 over IPv4 with NetworkAny, 300ms if 0, negative to disable racing
+	Proxy             func(*http.Request) (*url.URL, error) // Proxy of each request, e.g. http.ProxyURL; http.ProxyFromEnvironment if nil
+	Timeout           time.Duration                         // Timeout of whole requests, replacing DEFAULT_TIMEOUT or WithTimeout; unchanged if 0
+}
+
+// SetTransport replaces the client's HTTP transport according to opts.
+//
+// With HTTP2 enabled, gateways that support it multiplex concurrent
+// requests over few connections, which reduces connection setup and head
+// of line blocking during parallel chunk uploads. MaxStreamsPerHost bounds
+// the number of requests in flight regardless of the protocol, and is used
+// by the uploader as its default upper concurrency bound.
+//
+// Behind a corporate proxy, the HTTPS_PROXY, HTTP_PROXY and NO_PROXY
+// environment variables are used unless Proxy is set.
+//
+// Connections failing at the DNS or TCP level, e.g. when IPv6 is broken or
+// egress is filtered, fail requests with goar.ErrDial, which is distinct
+// from HTTP status errors and also matches goar.ErrNetwork.
+//
+// Example:
+//
+//	c := client.New("https://arweave.net")
+//	c.SetTransport(client.TransportOptions{HTTP2: true, MaxStreamsPerHost: 16})
+//
+//	proxy, _ := url.Parse("http://proxy.internal:3128")
+//	c.SetTransport(client.TransportOptions{Proxy: http.ProxyURL(proxy), Timeout: time.Minute})
+func (c *Client) SetTransport(opts TransportOptions) {
+	t := http.DefaultTransport.(*http.Transport).Clone()
+	t.DialContext = dialer(opts)
+	t.ForceAttemptHTTP2 = opts.HTTP2
+	if !opts.HTTP2 {
+		// A non-nil empty map disables HTTP/2 negotiation
+		t.TLSNextProto = map[string]func(string, *tls.Conn) http.RoundTripper{}
+	}
+	t.MaxConnsPerHost = opts.MaxConnsPerHost
+	if opts.MaxStreamsPerHost > t.MaxIdleConnsPerHost {
+		t.MaxIdleConnsPerHost = opts.MaxStreamsPerHost
+	}
+	if opts.TLSConfig != nil {
+		t.TLSClientConfig = opts.TLSConfig.Clone()
+	}
+	if opts.Proxy != nil {
+		t.Proxy = opts.Proxy
+	}
+	c.Client.Transport = t
+	if opts.Timeout > 0 {
+		c.Client.Timeout = opts.Timeout
+	}
+
+	c.streams = nil
+	if opts.MaxStreamsPerHost > 0 {
+		c.streams = make(chan struct{}, opts.MaxStreamsPerHost)
+	}
+}
+
+// MaxStreams returns the maximum number of concurrent requests set with SetTransport, or 0 for no limit.
+func (c *Client) MaxStreams() int {
+	return cap(c.streams)
+}
+
+// dialer returns the DialContext function of a transport configured by opts.
+// Dials are bound to the request context as well as to the dial timeout.
+func dialer(opts TransportOptions) func(ctx context.Context, network string, address string) (net.Conn, error) {
+	timeout := opts.DialTimeout
+	if timeout <= 0 {
+		timeout = DEFAULT_DIAL_TIMEOUT
+	}
+	d := &net.Dialer{
+		Timeout:       timeout,
+		KeepAlive:     30 * time.Second,
+		FallbackDelay: opts.FallbackDelay,
+	}
+	return func(ctx context.Context, network string, address string) (net.Conn, error) {
+		if opts.Network != "" {
+			network = opts.Network
+		}
+		return d.DialContext(ctx, network, address)
+	}
+}
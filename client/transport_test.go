@@ -0,0 +1,131 @@
+package client
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetTransport(t *testing.T) {
+	var proto atomic.Int32
+	var inFlight, peak atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		proto.Store(int32(r.ProtoMajor))
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+		}
+		time.Sleep(10 * time.Millisecond)
+		_, _ = w.Write([]byte("OK"))
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+	tlsConfig := server.Client().Transport.(*http.Transport).TLSClientConfig
+
+	t.Run("HTTP/2", func(t *testing.T) {
+		c := New(server.URL)
+		c.SetTransport(TransportOptions{HTTP2: true, TLSConfig: tlsConfig})
+		_, err := c.get("info")
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), proto.Load())
+		assert.Zero(t, c.MaxStreams())
+	})
+
+	t.Run("HTTP/1.1", func(t *testing.T) {
+		c := New(server.URL)
+		c.SetTransport(TransportOptions{TLSConfig: tlsConfig})
+		_, err := c.get("info")
+		require.NoError(t, err)
+		assert.Equal(t, int32(1), proto.Load())
+	})
+
+	t.Run("Stream limit", func(t *testing.T) {
+		peak.Store(0)
+		c := New(server.URL)
+		c.DisableCoalescing = true
+		c.SetTransport(TransportOptions{HTTP2: true, MaxStreamsPerHost: 2, TLSConfig: tlsConfig})
+		assert.Equal(t, 2, c.MaxStreams())
+
+		var wg sync.WaitGroup
+		for i := 0; i < 8; i++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				_, err := c.get("info")
+				assert.NoError(t, err)
+			}()
+		}
+		wg.Wait()
+		assert.LessOrEqual(t, int(peak.Load()), 2)
+	})
+
+	t.Run("Proxy and timeout", func(t *testing.T) {
+		var proxied atomic.Value
+		proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			proxied.Store(r.URL.String())
+			_, _ = w.Write([]byte("OK"))
+		}))
+		defer proxy.Close()
+		proxyURL, err := url.Parse(proxy.URL)
+		require.NoError(t, err)
+
+		c := New("http://gateway.invalid")
+		c.SetTransport(TransportOptions{Proxy: http.ProxyURL(proxyURL), Timeout: time.Minute})
+		_, err = c.get("info")
+		require.NoError(t, err)
+		assert.Equal(t, "http://gateway.invalid/info", proxied.Load())
+		assert.Equal(t, time.Minute, c.Client.Timeout)
+
+		c.SetTransport(TransportOptions{})
+		assert.Equal(t, time.Minute, c.Client.Timeout)
+	})
+}
+
+func TestDialErrors(t *testing.T) {
+	t.Run("Connection refused", func(t *testing.T) {
+		_, err := New("http://127.0.0.1:1").GetTransactionAnchor()
+		assert.ErrorIs(t, err, goar.ErrDial)
+		assert.ErrorIs(t, err, goar.ErrNetwork)
+	})
+
+	t.Run("IP version", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte("anchor"))
+		}))
+		defer server.Close()
+
+		c := New(server.URL)
+		c.SetTransport(TransportOptions{Network: NetworkIPv4, DialTimeout: time.Second})
+		anchor, err := c.GetTransactionAnchor()
+		require.NoError(t, err)
+		assert.Equal(t, "anchor", anchor)
+
+		// The test server listens on an IPv4 address only
+		c.SetTransport(TransportOptions{Network: NetworkIPv6, DialTimeout: time.Second})
+		_, err = c.GetTransactionAnchor()
+		assert.ErrorIs(t, err, goar.ErrDial)
+	})
+
+	t.Run("Not a dial error", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Close the connection without a response
+			conn, _, err := w.(http.Hijacker).Hijack()
+			require.NoError(t, err)
+			_ = conn.Close()
+		}))
+		defer server.Close()
+
+		_, err := New(server.URL).GetTransactionAnchor()
+		assert.ErrorIs(t, err, goar.ErrNetwork)
+		assert.NotErrorIs(t, err, goar.ErrDial)
+	})
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemSign(t *testing.T) {
+	data := bytes.Repeat([]byte("archive "), 4096)
+
+	t.Run("Stdin to stdout", func(t *testing.T) {
+		var out bytes.Buffer
+		err := run([]string{"item", "sign", "-wallet", "../../test/signer.json", "-size", "32768", "-tag", "Content-Type=application/x-tar", "-tag", "App-Name=goar"}, io.MultiReader(bytes.NewReader(data)), &out)
+		require.NoError(t, err)
+
+		item, err := data_item.Decode(out.Bytes())
+		require.NoError(t, err)
+		assert.NoError(t, item.Verify())
+		assert.Equal(t, data, item.RawData())
+		tags, err := item.GetTags()
+		require.NoError(t, err)
+		require.Len(t, *tags, 2)
+		assert.Equal(t, "application/x-tar", (*tags)[0].Value)
+	})
+
+	t.Run("File to file", func(t *testing.T) {
+		dir := t.TempDir()
+		in := filepath.Join(dir, "data.bin")
+		out := filepath.Join(dir, "data.item")
+		require.NoError(t, os.WriteFile(in, data, 0o600))
+
+		err := run([]string{"item", "sign", "-wallet", "../../test/signer.json", "-in", in, "-out", out}, nil, nil)
+		require.NoError(t, err)
+
+		raw, err := os.ReadFile(out)
+		require.NoError(t, err)
+		item, err := data_item.Decode(raw)
+		require.NoError(t, err)
+		assert.NoError(t, item.Verify())
+		assert.Equal(t, data, item.RawData())
+	})
+
+	t.Run("Short input", func(t *testing.T) {
+		err := run([]string{"item", "sign", "-wallet", "../../test/signer.json", "-size", "40000"}, bytes.NewBuffer(data), io.Discard)
+		assert.Error(t, err)
+	})
+
+	t.Run("Usage", func(t *testing.T) {
+		assert.Error(t, run([]string{"item"}, nil, io.Discard))
+		assert.Error(t, run([]string{"item", "sign"}, nil, io.Discard))
+		assert.Error(t, run([]string{"item", "sign", "-wallet", "../../test/signer.json", "-tag", "novalue"}, nil, io.Discard))
+	})
+}
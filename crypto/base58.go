@@ -0,0 +1,75 @@
+package crypto
+
+import (
+	"errors"
+	"math/big"
+	"strings"
+)
+
+// BASE58_ALPHABET is the Bitcoin base58 alphabet, also used by Solana
+const BASE58_ALPHABET = "123456789ABCDEFGHJKLMNPQRSTUVWXYZabcdefghijkmnopqrstuvwxyz"
+
+// Base58Encode encodes bytes to a base58 string.
+//
+// Leading zero bytes are encoded as leading '1' characters, as in Bitcoin
+// and Solana addresses.
+//
+// Parameters:
+//   - data: The byte data to encode
+//
+// Returns the base58-encoded string representation.
+//
+// Example:
+//
+//	address := Base58Encode(publicKey)
+//	fmt.Printf("Solana address: %s\n", address)
+func Base58Encode(data []byte) string {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == 0 {
+		zeros++
+	}
+
+	n := new(big.Int).SetBytes(data)
+	radix := big.NewInt(58)
+	mod := new(big.Int)
+	var out []byte
+	for n.Sign() > 0 {
+		n.DivMod(n, radix, mod)
+		out = append(out, BASE58_ALPHABET[mod.Int64()])
+	}
+	for i := 0; i < zeros; i++ {
+		out = append(out, BASE58_ALPHABET[0])
+	}
+	for i, j := 0, len(out)-1; i < j; i, j = i+1, j-1 {
+		out[i], out[j] = out[j], out[i]
+	}
+	return string(out)
+}
+
+// Base58Decode decodes a base58 string to bytes.
+//
+// It is the inverse operation of Base58Encode.
+//
+// Parameters:
+//   - data: The base58-encoded string to decode
+//
+// Returns the decoded bytes or an error if the string contains characters
+// outside of the base58 alphabet.
+func Base58Decode(data string) ([]byte, error) {
+	zeros := 0
+	for zeros < len(data) && data[zeros] == BASE58_ALPHABET[0] {
+		zeros++
+	}
+
+	n := new(big.Int)
+	radix := big.NewInt(58)
+	for _, c := range data[zeros:] {
+		i := strings.IndexRune(BASE58_ALPHABET, c)
+		if i < 0 {
+			return nil, errors.New("invalid base58 character")
+		}
+		n.Mul(n, radix)
+		n.Add(n, big.NewInt(int64(i)))
+	}
+	return append(make([]byte, zeros), n.Bytes()...), nil
+}
@@ -0,0 +1,27 @@
+package crypto
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBase58 verifies base58 encoding against known vectors
+func TestBase58(t *testing.T) {
+	vectors := map[string][]byte{
+		"":                                 {},
+		"2NEpo7TZRRrLZSi2U":                []byte("Hello World!"),
+		"112":                              {0, 0, 1},
+		"11111111111111111111111111111111": make([]byte, 32),
+	}
+	for encoded, data := range vectors {
+		assert.Equal(t, encoded, Base58Encode(data))
+		decoded, err := Base58Decode(encoded)
+		require.NoError(t, err)
+		assert.Equal(t, data, decoded)
+	}
+
+	_, err := Base58Decode("0OIl")
+	assert.Error(t, err)
+}
@@ -6,8 +6,12 @@ package crypto
 
 import (
 	"encoding/base64"
+	"strings"
 )
 
+// base64URLReplacer maps the standard base64 alphabet to the URL-safe one
+var base64URLReplacer = strings.NewReplacer("+", "-", "/", "_")
+
 // Base64URLEncode encodes bytes to a Base64URL string.
 //
 // This function uses base64 URL encoding without padding as specified
@@ -31,9 +35,11 @@ func Base64URLEncode(data []byte) string {
 
 // Base64URLDecode decodes a Base64URL string to bytes.
 //
-// This function decodes base64 URL-encoded strings without padding
-// as used throughout the Arweave protocol. It's the inverse operation
-// of Base64URLEncode.
+// This function decodes base64 URL-encoded strings as used throughout
+// the Arweave protocol. It's the inverse operation of Base64URLEncode.
+// Like arweave-js, it also accepts padded strings and the standard
+// base64 alphabet, which other tools and gateways sometimes produce, so
+// that values decode the same wherever they come from.
 //
 // Parameters:
 //   - data: The base64url-encoded string to decode
@@ -50,5 +56,14 @@ func Base64URLEncode(data []byte) string {
 //	fmt.Printf("Decoded: %s\n", string(decoded))
 //	// Output: Hello, Arweave!
 func Base64URLDecode(data string) ([]byte, error) {
+	data = strings.TrimRight(data, "=")
+	if strings.ContainsAny(data, "+/") {
+		data = base64URLReplacer.Replace(data)
+	}
 	return base64.RawURLEncoding.DecodeString(data)
 }
+
+// Base64URLDecodedLen returns the length in bytes of the data encoded by a Base64URLEncode string of n characters.
+func Base64URLDecodedLen(n int) int {
+	return base64.RawURLEncoding.DecodedLen(n)
+}
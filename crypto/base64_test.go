@@ -0,0 +1,51 @@
+package crypto
+
+import (
+	"bytes"
+	"encoding/base64"
+	"testing"
+	"testing/quick"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBase64URL(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		roundTrip := func(data []byte) bool {
+			decoded, err := Base64URLDecode(Base64URLEncode(data))
+			return err == nil && bytes.Equal(data, decoded)
+		}
+		require.NoError(t, quick.Check(roundTrip, nil))
+	})
+
+	t.Run("canonical form", func(t *testing.T) {
+		canonical := func(data []byte) bool {
+			encoded := base64.RawURLEncoding.EncodeToString(data)
+			decoded, err := Base64URLDecode(encoded)
+			return err == nil && Base64URLEncode(decoded) == encoded && Base64URLDecodedLen(len(encoded)) == len(data)
+		}
+		require.NoError(t, quick.Check(canonical, nil))
+	})
+
+	t.Run("other encodings", func(t *testing.T) {
+		encodings := []*base64.Encoding{base64.URLEncoding, base64.StdEncoding, base64.RawStdEncoding}
+		lenient := func(data []byte) bool {
+			for _, e := range encodings {
+				decoded, err := Base64URLDecode(e.EncodeToString(data))
+				if err != nil || !bytes.Equal(data, decoded) {
+					return false
+				}
+			}
+			return true
+		}
+		require.NoError(t, quick.Check(lenient, nil))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, s := range []string{"a", "ab$c", "ab=c"} {
+			_, err := Base64URLDecode(s)
+			assert.Error(t, err, s)
+		}
+	})
+}
@@ -1,6 +1,7 @@
 package crypto
 
 import (
+	"context"
 	"crypto/sha512"
 	"fmt"
 	"io"
@@ -30,13 +31,21 @@ func DeepHash(data any) [48]byte {
 // It takes a reader and the data size, and computes the same hash as DeepHash would
 // for the equivalent []byte, but without loading all data into memory.
 func DeepHashStream(reader io.Reader, dataSize int64) ([48]byte, error) {
+	return DeepHashStreamContext(context.Background(), reader, dataSize)
+}
+
+// DeepHashStreamContext is DeepHashStream bound to ctx.
+//
+// ctx is checked before each read from reader, so that hashing large data
+// can be aborted; ctx.Err() is then returned.
+func DeepHashStreamContext(ctx context.Context, reader io.Reader, dataSize int64) ([48]byte, error) {
 	// Create the tag hash (same as DeepHash for []byte)
 	tag := append([]byte("blob"), []byte(fmt.Sprint(dataSize))...)
 	tagHashed := sha512.Sum384(tag)
 
 	// Stream the data through SHA512
 	dataHasher := sha512.New384()
-	_, err := io.Copy(dataHasher, reader)
+	_, err := io.Copy(dataHasher, &contextReader{ctx: ctx, r: reader})
 	if err != nil {
 		return [48]byte{}, err
 	}
@@ -51,6 +60,11 @@ func DeepHashStream(reader io.Reader, dataSize int64) ([48]byte, error) {
 // DeepHashMixed computes DeepHash for an array where one element is streamed
 // This is specifically for DataItem signing where most fields are small but data can be huge
 func DeepHashMixed(chunks [][]byte, streamReader io.Reader, streamSize int64) ([48]byte, error) {
+	return DeepHashMixedContext(context.Background(), chunks, streamReader, streamSize)
+}
+
+// DeepHashMixedContext is DeepHashMixed bound to ctx, see DeepHashStreamContext.
+func DeepHashMixedContext(ctx context.Context, chunks [][]byte, streamReader io.Reader, streamSize int64) ([48]byte, error) {
 	// Create list tag
 	totalItems := len(chunks) + 1 // +1 for the streamed data
 	tag := append([]byte("list"), []byte(fmt.Sprint(totalItems))...)
@@ -64,7 +78,7 @@ func DeepHashMixed(chunks [][]byte, streamReader io.Reader, streamSize int64) ([
 	}
 
 	// Process the streamed data
-	streamHash, err := DeepHashStream(streamReader, streamSize)
+	streamHash, err := DeepHashStreamContext(ctx, streamReader, streamSize)
 	if err != nil {
 		return [48]byte{}, err
 	}
@@ -74,6 +88,19 @@ func DeepHashMixed(chunks [][]byte, streamReader io.Reader, streamSize int64) ([
 	return finalHash, nil
 }
 
+// contextReader reads from r until ctx is done
+type contextReader struct {
+	ctx context.Context
+	r   io.Reader
+}
+
+func (c *contextReader) Read(p []byte) (int, error) {
+	if err := c.ctx.Err(); err != nil {
+		return 0, err
+	}
+	return c.r.Read(p)
+}
+
 func deepHashChunk(data []any, acc [48]byte) [48]byte {
 	if len(data) < 1 {
 		return acc
@@ -1,6 +1,8 @@
 package crypto
 
 import (
+	"bytes"
+	"context"
 	"encoding/hex"
 	"testing"
 
@@ -118,3 +120,16 @@ func TestDeepHash(t *testing.T) {
 
 	})
 }
+
+func TestDeepHashStreamContext(t *testing.T) {
+	data := bytes.Repeat([]byte{7}, 100_000)
+
+	h, err := DeepHashStreamContext(context.Background(), bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.Equal(t, DeepHash(data), h)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = DeepHashMixedContext(ctx, [][]byte{{1}}, bytes.NewReader(data), int64(len(data)))
+	assert.ErrorIs(t, err, context.Canceled)
+}
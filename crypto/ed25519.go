@@ -0,0 +1,33 @@
+package crypto
+
+import (
+	"crypto/ed25519"
+	"errors"
+)
+
+// VerifyED25519 validates an Ed25519 signature, as used by ANS-104 ED25519
+// and Solana data items.
+//
+// Verifications are bounded process-wide by SetVerifyConcurrency.
+//
+// Parameters:
+//   - message: The original message that was signed
+//   - signature: The 64-byte signature
+//   - publicKey: The 32-byte public key
+//
+// Returns nil if the signature is valid, or an error if verification fails.
+func VerifyED25519(message []byte, signature []byte, publicKey []byte) error {
+	if len(publicKey) != ed25519.PublicKeySize {
+		return errors.New("invalid public key length")
+	}
+	if len(signature) != ed25519.SignatureSize {
+		return errors.New("invalid signature length")
+	}
+
+	release := acquireVerifySlot()
+	defer release()
+	if !ed25519.Verify(ed25519.PublicKey(publicKey), message, signature) {
+		return errors.New("ed25519: invalid signature")
+	}
+	return nil
+}
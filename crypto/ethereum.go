@@ -0,0 +1,132 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+	"golang.org/x/crypto/sha3"
+)
+
+// Keccak256 computes the legacy Keccak-256 hash used by Ethereum.
+//
+// Parameters:
+//   - data: The raw binary data to hash
+//
+// Returns the Keccak-256 hash as a 32-byte slice.
+func Keccak256(data []byte) []byte {
+	h := sha3.NewLegacyKeccak256()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// GetEthereumAddress converts a secp256k1 public key to an Ethereum address.
+//
+// The address is the last 20 bytes of the Keccak-256 hash of the public key,
+// hex-encoded with the EIP-55 mixed-case checksum.
+//
+// Parameters:
+//   - publicKey: The uncompressed public key, either 65 bytes with the 0x04
+//     prefix or the 64 raw coordinate bytes
+//
+// Returns the 0x-prefixed address, or an error if the key is not an
+// uncompressed public key.
+//
+// Example:
+//
+//	address, err := GetEthereumAddress(rawOwner)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Ethereum address: %s\n", address)
+func GetEthereumAddress(publicKey []byte) (string, error) {
+	if len(publicKey) == 65 {
+		if publicKey[0] != 4 {
+			return "", errors.New("public key is not uncompressed")
+		}
+		publicKey = publicKey[1:]
+	}
+	if len(publicKey) != 64 {
+		return "", errors.New("invalid public key length")
+	}
+
+	address := []byte(hex.EncodeToString(Keccak256(publicKey)[12:]))
+	checksum := hex.EncodeToString(Keccak256(address))
+	for i, c := range address {
+		if c >= 'a' && checksum[i] >= '8' {
+			address[i] = c - 'a' + 'A'
+		}
+	}
+	return "0x" + string(address), nil
+}
+
+// EthereumMessageHash returns the EIP-191 hash of a personal message, as
+// signed by the personal_sign method of Ethereum wallets.
+//
+// The hash is the Keccak-256 of "\x19Ethereum Signed Message:\n", the
+// decimal length of message, and message.
+func EthereumMessageHash(message []byte) []byte {
+	prefix := fmt.Sprintf("\x19Ethereum Signed Message:\n%d", len(message))
+	return Keccak256(append([]byte(prefix), message...))
+}
+
+// SignEthereum signs message as an EIP-191 personal message with a secp256k1 key.
+//
+// This is the signature scheme of ANS-104 Ethereum data items, where the
+// message is the deep hash of the item.
+//
+// Parameters:
+//   - message: The message to sign
+//   - privateKey: The secp256k1 private key
+//
+// Returns the 65-byte signature r || s || v, with v 27 or 28.
+//
+// Example:
+//
+//	signature := SignEthereum(message, s.PrivateKey)
+func SignEthereum(message []byte, privateKey *secp256k1.PrivateKey) []byte {
+	// SignCompact returns v || r || s
+	compact := ecdsa.SignCompact(privateKey, EthereumMessageHash(message), false)
+	return append(compact[1:], compact[0])
+}
+
+// VerifyEthereum validates an EIP-191 personal message signature.
+//
+// The public key is recovered from the signature and compared to publicKey.
+// Verifications are bounded process-wide by SetVerifyConcurrency.
+//
+// Parameters:
+//   - message: The original message that was signed
+//   - signature: The 65-byte signature r || s || v, with v 0, 1, 27 or 28
+//   - publicKey: The uncompressed 65-byte secp256k1 public key
+//
+// Returns nil if the signature is valid, or an error if verification fails.
+func VerifyEthereum(message []byte, signature []byte, publicKey []byte) error {
+	if len(signature) != 65 {
+		return errors.New("invalid signature length")
+	}
+	expected, err := secp256k1.ParsePubKey(publicKey)
+	if err != nil {
+		return err
+	}
+	v := signature[64]
+	if v < 27 {
+		v += 27
+	}
+	if v != 27 && v != 28 {
+		return fmt.Errorf("invalid recovery id %d", signature[64])
+	}
+
+	release := acquireVerifySlot()
+	defer release()
+	recovered, _, err := ecdsa.RecoverCompact(append([]byte{v}, signature[:64]...), EthereumMessageHash(message))
+	if err != nil {
+		return err
+	}
+	if !recovered.IsEqual(expected) {
+		return errors.New("signature does not match public key")
+	}
+	return nil
+}
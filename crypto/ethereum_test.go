@@ -0,0 +1,73 @@
+package crypto
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// generatorPublicKey is the uncompressed secp256k1 public key of private key 1
+const generatorPublicKey = "0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8"
+
+// TestGetEthereumAddress verifies address derivation and EIP-55 checksums
+func TestGetEthereumAddress(t *testing.T) {
+	publicKey, err := hex.DecodeString(generatorPublicKey)
+	require.NoError(t, err)
+
+	address, err := GetEthereumAddress(publicKey)
+	require.NoError(t, err)
+	assert.Equal(t, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", address)
+
+	// The raw coordinates without prefix give the same address
+	address, err = GetEthereumAddress(publicKey[1:])
+	require.NoError(t, err)
+	assert.Equal(t, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", address)
+
+	_, err = GetEthereumAddress(publicKey[:33])
+	assert.Error(t, err)
+
+	compressed := append([]byte{2}, publicKey[1:]...)
+	_, err = GetEthereumAddress(compressed)
+	assert.Error(t, err)
+}
+
+// TestKeccak256 verifies the legacy Keccak-256 hash of the empty input
+func TestKeccak256(t *testing.T) {
+	assert.Equal(t, "c5d2460186f7233c927e7db2dcc703c0e500b653ca82273b7bfad8045d85a470", hex.EncodeToString(Keccak256(nil)))
+}
+
+// TestSignEthereum verifies personal message signatures against the web3.js accounts.sign example
+func TestSignEthereum(t *testing.T) {
+	key, err := hex.DecodeString("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	require.NoError(t, err)
+	privateKey := secp256k1.PrivKeyFromBytes(key)
+	publicKey := privateKey.PubKey().SerializeUncompressed()
+	message := []byte("Some data")
+
+	assert.Equal(t, "1da44b586eb0729ff70a73c326926f6ed5a25f5b056e7f47fbc6e58d86871655", hex.EncodeToString(EthereumMessageHash(message)))
+	signature := SignEthereum(message, privateKey)
+	assert.Equal(t, "b91467e570a6466aa9e9876cbcd013baba02900b8979d43fe208a4a4f339f5fd6007e74cd82e037b800186422fc2da167c747ef045e5d18a5f5d4300f8e1a0291c", hex.EncodeToString(signature))
+	address, err := GetEthereumAddress(publicKey)
+	require.NoError(t, err)
+	assert.Equal(t, "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", address)
+
+	assert.NoError(t, VerifyEthereum(message, signature, publicKey))
+
+	// v may also be given as a bare recovery id
+	bare := append([]byte(nil), signature...)
+	bare[64] -= 27
+	assert.NoError(t, VerifyEthereum(message, bare, publicKey))
+
+	assert.Error(t, VerifyEthereum([]byte("Other data"), signature, publicKey))
+	other, err := hex.DecodeString(generatorPublicKey)
+	require.NoError(t, err)
+	assert.Error(t, VerifyEthereum(message, signature, other))
+	assert.Error(t, VerifyEthereum(message, signature[:64], publicKey))
+	invalid := append([]byte(nil), signature...)
+	invalid[64] = 30
+	assert.Error(t, VerifyEthereum(message, invalid, publicKey))
+}
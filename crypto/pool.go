@@ -0,0 +1,99 @@
+package crypto
+
+import (
+	"context"
+	"runtime"
+	"sync"
+	"sync/atomic"
+)
+
+// verifySlots bounds the number of signature verifications running at once
+var verifySlots atomic.Pointer[chan struct{}]
+
+func init() {
+	SetVerifyConcurrency(0)
+}
+
+// SetVerifyConcurrency sets how many signature verifications may run at once across all goroutines.
+//
+// Every signature check goes through Verify, including DataItem.Verify,
+// Bundle.VerifyDeep and Transaction.Verify, so this bounds the CPU spent on
+// verification by a whole process regardless of how many requests call it.
+// Verifications already running complete under the previous limit.
+//
+// Parameters:
+//   - n: The maximum number of concurrent verifications, runtime.GOMAXPROCS(0) if n <= 0
+//
+// Example:
+//
+//	// Keep half of the CPUs for request handling
+//	crypto.SetVerifyConcurrency(runtime.GOMAXPROCS(0) / 2)
+func SetVerifyConcurrency(n int) {
+	if n <= 0 {
+		n = runtime.GOMAXPROCS(0)
+	}
+	slots := make(chan struct{}, n)
+	verifySlots.Store(&slots)
+}
+
+// VerifyConcurrency returns the maximum number of concurrent signature verifications.
+func VerifyConcurrency() int {
+	return cap(*verifySlots.Load())
+}
+
+// acquireVerifySlot blocks until a verification may start and returns the function releasing it
+func acquireVerifySlot() func() {
+	slots := *verifySlots.Load()
+	slots <- struct{}{}
+	return func() { <-slots }
+}
+
+// VerifyAll runs verify for every index from 0 to n-1 on a pool of VerifyConcurrency workers.
+//
+// Returns nil if every call succeeded, otherwise the error of the lowest
+// failing index. Remaining indexes are skipped once an error occurs.
+//
+// Example:
+//
+//	err := crypto.VerifyAll(len(items), func(i int) error {
+//		return items[i].Verify()
+//	})
+func VerifyAll(n int, verify func(i int) error) error {
+	return VerifyAllContext(context.Background(), n, verify)
+}
+
+// VerifyAllContext is VerifyAll bound to ctx.
+//
+// ctx is checked before each index is verified. When it is done, the
+// remaining indexes are skipped and ctx.Err() is returned unless a
+// verification failed first.
+func VerifyAllContext(ctx context.Context, n int, verify func(i int) error) error {
+	workers := min(VerifyConcurrency(), n)
+	errs := make([]error, n)
+	var next atomic.Int64
+	var failed atomic.Bool
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for !failed.Load() && ctx.Err() == nil {
+				i := int(next.Add(1) - 1)
+				if i >= n {
+					return
+				}
+				if errs[i] = verify(i); errs[i] != nil {
+					failed.Store(true)
+				}
+			}
+		}()
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return ctx.Err()
+}
@@ -0,0 +1,66 @@
+package crypto
+
+import (
+	"context"
+	"errors"
+	"runtime"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifyAll(t *testing.T) {
+	defer SetVerifyConcurrency(0)
+	assert.Equal(t, runtime.GOMAXPROCS(0), VerifyConcurrency())
+
+	t.Run("Concurrency", func(t *testing.T) {
+		SetVerifyConcurrency(3)
+		assert.Equal(t, 3, VerifyConcurrency())
+
+		var inFlight, peak atomic.Int32
+		var calls atomic.Int32
+		err := VerifyAll(20, func(i int) error {
+			calls.Add(1)
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(time.Millisecond)
+			return nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, int32(20), calls.Load())
+		assert.LessOrEqual(t, peak.Load(), int32(3))
+	})
+
+	t.Run("First error", func(t *testing.T) {
+		SetVerifyConcurrency(1)
+		err := VerifyAll(10, func(i int) error {
+			if i >= 4 {
+				return errors.New(string(rune('0' + i)))
+			}
+			return nil
+		})
+		assert.EqualError(t, err, "4")
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		assert.NoError(t, VerifyAll(0, func(i int) error { return errors.New("unreachable") }))
+	})
+	t.Run("Cancel", func(t *testing.T) {
+		SetVerifyConcurrency(1)
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+		var calls atomic.Int32
+		err := VerifyAllContext(ctx, 10, func(i int) error {
+			if calls.Add(1) == 3 {
+				cancel()
+			}
+			return nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, int32(3), calls.Load())
+	})
+}
@@ -22,6 +22,8 @@ import (
 //   - signature: The signature bytes to verify
 //   - publicKey: The RSA public key to verify against
 //
+// Verifications are bounded process-wide by SetVerifyConcurrency.
+//
 // Returns nil if the signature is valid, or an error if verification fails.
 //
 // Example:
@@ -42,6 +44,8 @@ import (
 func Verify(data []byte, signature []byte, publicKey *rsa.PublicKey) error {
 	hashed := sha256.Sum256(data)
 
+	release := acquireVerifySlot()
+	defer release()
 	return rsa.VerifyPSS(publicKey, crypto.SHA256, hashed[:], signature, &rsa.PSSOptions{
 		SaltLength: rsa.PSSSaltLengthAuto,
 		Hash:       crypto.SHA256,
@@ -0,0 +1,89 @@
+// Package dedup avoids uploading content that is already stored on Arweave.
+//
+// Gateways cannot search transactions by data root, so content is
+// addressed by a tag instead: Tag returns a Content-Digest tag holding the
+// SHA-256 of the data, which uploads carry, and Find looks the digest up
+// through the gateway's GraphQL endpoint before paying for a new upload.
+// wallet.UploadOnce combines both.
+//
+// Example usage:
+//
+//	id, err := dedup.Find(c, dedup.Digest(data), []string{address})
+//	if errors.Is(err, goar.ErrNotFound) {
+//		// Not archived yet, upload it with dedup.Tag(data)
+//	}
+package dedup
+
+import (
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/tag"
+)
+
+// TAG_NAME is the name of the tag holding the content digest
+const TAG_NAME = "Content-Digest"
+
+// DIGEST_PREFIX identifies the hash function of digests
+const DIGEST_PREFIX = "sha256:"
+
+const query = `query($digest: String!, $name: String!, $owners: [String!]) {
+  transactions(owners: $owners, tags: [{name: $name, values: [$digest]}], sort: HEIGHT_ASC, first: 1) {
+    edges { node { id } }
+  }
+}`
+
+type queryResult struct {
+	Transactions struct {
+		Edges []struct {
+			Node struct {
+				ID string `json:"id"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"transactions"`
+}
+
+// Digest returns the content digest of data, e.g. "sha256:47DEQpj8HBSa-_TImW-5JCeuQeRkm5NMpJWZG3hSuFU".
+func Digest(data []byte) string {
+	return DIGEST_PREFIX + crypto.Base64URLEncode(crypto.SHA256(data))
+}
+
+// Tag returns the Content-Digest tag of data, to add to the tags of its upload.
+func Tag(data []byte) tag.Tag {
+	return tag.Tag{Name: TAG_NAME, Value: Digest(data)}
+}
+
+// Key returns a key identifying data uploaded with tags, for local caches of uploads, e.g. "sha256:47DEQ.../mIYACtZX...".
+//
+// It is the Digest of data followed by the tag.Hash of tags, so uploads
+// of the same data with the same tags in any order share a key.
+func Key(data []byte, tags []tag.Tag) string {
+	return Digest(data) + "/" + tag.Hash(tags)
+}
+
+// Find returns the ID of the oldest transaction or data item tagged with digest.
+//
+// Parameters:
+//   - c: The client used to query the gateway
+//   - digest: The content digest, see Digest
+//   - owners: Addresses the upload must come from, nil to accept any owner
+//
+// Restricting owners is recommended: anyone can tag a transaction with any
+// digest, so matches from unknown owners should have their data verified.
+//
+// Returns an error with code goar.ErrNotFound if no upload carries the
+// digest, or an error if the query fails.
+func Find(c *client.Client, digest string, owners []string) (string, error) {
+	variables := map[string]any{"digest": digest, "name": TAG_NAME}
+	if len(owners) > 0 {
+		variables["owners"] = owners
+	}
+	var result queryResult
+	if err := c.GraphQL(query, variables, &result); err != nil {
+		return "", err
+	}
+	if len(result.Transactions.Edges) == 0 {
+		return "", goar.Errorf(goar.ErrNotFound, "no upload with digest %s", digest)
+	}
+	return result.Transactions.Edges[0].Node.ID, nil
+}
@@ -0,0 +1,66 @@
+package dedup
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const owner = "vLRHFqCw1uHu75xqB4fCDW-QxpkpJxBtFD9g4QYUbfw"
+
+func TestDigest(t *testing.T) {
+	assert.Equal(t, "sha256:47DEQpj8HBSa-_TImW-5JCeuQeRkm5NMpJWZG3hSuFU", Digest(nil))
+	assert.Equal(t, TAG_NAME, Tag([]byte("hello")).Name)
+	assert.NotEqual(t, Digest([]byte("hello")), Digest([]byte("world")))
+}
+
+func TestKey(t *testing.T) {
+	a := []tag.Tag{{Name: "App-Name", Value: "MyApp"}, {Name: "Content-Type", Value: "text/plain"}}
+	b := []tag.Tag{a[1], a[0]}
+	assert.Equal(t, Key([]byte("hello"), a), Key([]byte("hello"), b))
+	assert.NotEqual(t, Key([]byte("hello"), a), Key([]byte("hello"), a[:1]))
+	assert.NotEqual(t, Key([]byte("hello"), a), Key([]byte("world"), a))
+}
+
+func TestFind(t *testing.T) {
+	stored := Digest([]byte("hello"))
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				Digest string   `json:"digest"`
+				Name   string   `json:"name"`
+				Owners []string `json:"owners"`
+			} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, TAG_NAME, req.Variables.Name)
+		if req.Variables.Digest != stored || (req.Variables.Owners != nil && req.Variables.Owners[0] != owner) {
+			_, _ = w.Write([]byte(`{"data": {"transactions": {"edges": []}}}`))
+			return
+		}
+		_, _ = w.Write([]byte(`{"data": {"transactions": {"edges": [{"node": {"id": "existing"}}]}}}`))
+	}))
+	defer server.Close()
+	c := client.New(server.URL)
+
+	id, err := Find(c, stored, []string{owner})
+	require.NoError(t, err)
+	assert.Equal(t, "existing", id)
+
+	id, err = Find(c, stored, nil)
+	require.NoError(t, err)
+	assert.Equal(t, "existing", id)
+
+	_, err = Find(c, stored, []string{"someone-else"})
+	assert.ErrorIs(t, err, goar.ErrNotFound)
+
+	_, err = Find(c, Digest([]byte("world")), nil)
+	assert.ErrorIs(t, err, goar.ErrNotFound)
+}
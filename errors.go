@@ -0,0 +1,139 @@
+// Package goar defines the error codes and value types shared by every goar package.
+//
+// Errors returned by the client, uploader, transaction, data item and bundle
+// packages carry an ErrorCode, so consumers can branch on the kind of failure
+// without matching error strings. The original message is preserved.
+//
+// Example usage:
+//
+//	tx, err := c.GetTransactionByID(id)
+//	if errors.Is(err, goar.ErrNotFound) {
+//		// The transaction does not exist (yet)
+//	}
+//
+//	switch goar.CodeOf(err) {
+//	case goar.ErrRateLimited, goar.ErrNetwork:
+//		// Retry later
+//	}
+package goar
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrorCode classifies the errors returned by goar.
+//
+// ErrorCode implements error so that codes can be used directly as
+// errors.Is targets.
+type ErrorCode int
+
+// Error codes
+const (
+	ErrUnknown          ErrorCode = iota // The error was not classified
+	ErrNotFound                          // The requested resource does not exist on the gateway
+	ErrAnchorExpired                     // The transaction anchor (last_tx) is invalid or too old
+	ErrChunkRejected                     // The gateway rejected a chunk permanently
+	ErrInvalidSignature                  // A signature or ID does not verify
+	ErrDecode                            // Binary or JSON data could not be decoded
+	ErrNetwork                           // The request did not reach the gateway
+	ErrRateLimited                       // The gateway throttled the request (HTTP 429)
+	ErrGateway                           // The gateway failed to serve the request (HTTP 5xx)
+	ErrBadRequest                        // The gateway rejected the request (other HTTP 4xx)
+	ErrInvalidInput                      // The caller supplied invalid data
+	ErrSigningDenied                     // An approval hook refused to sign
+	ErrItemTooLarge                      // A data item exceeds the configured maximum size
+	ErrDial                              // The connection to the gateway could not be established (DNS or TCP); also an ErrNetwork
+	ErrCostExceeded                      // An upload would cost more than the configured maximum
+)
+
+var errorCodeNames = map[ErrorCode]string{
+	ErrUnknown:          "unknown",
+	ErrNotFound:         "not found",
+	ErrAnchorExpired:    "anchor expired",
+	ErrChunkRejected:    "chunk rejected",
+	ErrInvalidSignature: "invalid signature",
+	ErrDecode:           "decode error",
+	ErrNetwork:          "network error",
+	ErrRateLimited:      "rate limited",
+	ErrGateway:          "gateway error",
+	ErrBadRequest:       "bad request",
+	ErrInvalidInput:     "invalid input",
+	ErrSigningDenied:    "signing denied",
+	ErrItemTooLarge:     "item too large",
+	ErrDial:             "dial error",
+	ErrCostExceeded:     "cost exceeded",
+}
+
+// errorCodeParents maps codes that refine a more general code to it
+var errorCodeParents = map[ErrorCode]ErrorCode{
+	ErrDial: ErrNetwork,
+}
+
+// Error returns the name of the error code.
+func (c ErrorCode) Error() string {
+	if name, ok := errorCodeNames[c]; ok {
+		return name
+	}
+	return "unknown"
+}
+
+// Error is an error annotated with an ErrorCode.
+type Error struct {
+	Code ErrorCode // Classification of the error
+	Err  error     // The underlying error
+}
+
+// Error returns the message of the underlying error.
+func (e *Error) Error() string {
+	return e.Err.Error()
+}
+
+// Unwrap returns the underlying error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the error code of e, or a more general code it refines.
+// For example, errors with code ErrDial are also ErrNetwork errors.
+func (e *Error) Is(target error) bool {
+	want, ok := target.(ErrorCode)
+	if !ok {
+		return false
+	}
+	for code := e.Code; code != ErrUnknown; code = errorCodeParents[code] {
+		if code == want {
+			return true
+		}
+	}
+	return false
+}
+
+// Wrap annotates err with the given code.
+//
+// Returns nil if err is nil. An error that already carries a code other
+// than ErrUnknown keeps it, so wrapping at several layers is safe.
+func Wrap(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	if CodeOf(err) != ErrUnknown {
+		return err
+	}
+	return &Error{Code: code, Err: err}
+}
+
+// Errorf creates a new error with the given code and a message formatted
+// according to format, as fmt.Errorf does.
+func Errorf(code ErrorCode, format string, args ...any) error {
+	return &Error{Code: code, Err: fmt.Errorf(format, args...)}
+}
+
+// CodeOf returns the code attached to err, or ErrUnknown if there is none.
+func CodeOf(err error) ErrorCode {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ErrUnknown
+}
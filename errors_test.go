@@ -0,0 +1,54 @@
+package goar
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWrap(t *testing.T) {
+	t.Run("Preserves message", func(t *testing.T) {
+		err := Wrap(ErrNotFound, errors.New("404: Not Found"))
+		assert.EqualError(t, err, "404: Not Found")
+		assert.ErrorIs(t, err, ErrNotFound)
+		assert.NotErrorIs(t, err, ErrDecode)
+		assert.Equal(t, ErrNotFound, CodeOf(err))
+	})
+
+	t.Run("Nil", func(t *testing.T) {
+		assert.NoError(t, Wrap(ErrDecode, nil))
+	})
+
+	t.Run("Keeps the innermost code", func(t *testing.T) {
+		err := Wrap(ErrDecode, Wrap(ErrInvalidSignature, errors.New("bad")))
+		assert.Equal(t, ErrInvalidSignature, CodeOf(err))
+	})
+
+	t.Run("Survives fmt wrapping", func(t *testing.T) {
+		err := fmt.Errorf("context: %w", Errorf(ErrChunkRejected, "invalid_proof"))
+		assert.ErrorIs(t, err, ErrChunkRejected)
+		assert.Equal(t, ErrChunkRejected, CodeOf(err))
+	})
+
+	t.Run("Unclassified", func(t *testing.T) {
+		assert.Equal(t, ErrUnknown, CodeOf(errors.New("plain")))
+		assert.Equal(t, ErrUnknown, CodeOf(nil))
+	})
+}
+
+func TestErrorCodeNames(t *testing.T) {
+	assert.Equal(t, "not found", ErrNotFound.Error())
+	assert.Equal(t, "unknown", ErrorCode(1000).Error())
+}
+
+func TestErrorCodeParents(t *testing.T) {
+	err := Wrap(ErrDial, errors.New("dial tcp: connection refused"))
+	assert.ErrorIs(t, err, ErrDial)
+	assert.ErrorIs(t, err, ErrNetwork)
+	assert.NotErrorIs(t, err, ErrGateway)
+	assert.Equal(t, ErrDial, CodeOf(err))
+
+	assert.NotErrorIs(t, Wrap(ErrNetwork, errors.New("reset")), ErrDial)
+}
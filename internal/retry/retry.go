@@ -0,0 +1,123 @@
+// Package retry holds the backoff and polling logic shared by the client,
+// uploader and bundler packages.
+//
+// Delays are computed by Backoff and waited for on a Clock, which tests
+// replace with a fake to run retry loops without sleeping.
+package retry
+
+import (
+	"context"
+	"errors"
+	"math"
+	"math/rand"
+	"time"
+)
+
+// ErrTimeout is returned by Poll when the timeout elapses first.
+var ErrTimeout = errors.New("timed out")
+
+// Clock is the source of time of retry loops.
+type Clock interface {
+	Now() time.Time
+	After(d time.Duration) <-chan time.Time
+}
+
+// SystemClock is the Clock of the time package.
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time                         { return time.Now() }
+func (systemClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// Backoff computes the delay before each retry.
+//
+// The delay of attempt n (1-based) is Base*n when Exponential is false and
+// Base*2^(n-1) otherwise, capped at Max. Jitter then removes a random
+// fraction of up to Jitter of the delay, so that clients failing together
+// do not retry together.
+type Backoff struct {
+	Base        time.Duration  // Delay of the first retry
+	Max         time.Duration  // Upper bound of the delay before jitter, 0 for no bound
+	Exponential bool           // Double the delay on each attempt instead of growing it linearly
+	Jitter      float64        // Maximum fraction of the delay removed at random, between 0 and 1
+	Rand        func() float64 // Source of randomness in [0, 1), rand.Float64 if nil
+}
+
+// Delay returns the delay before retry attempt n, starting at 1.
+func (b Backoff) Delay(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	var d float64
+	if b.Exponential {
+		d = float64(b.Base) * math.Pow(2, float64(attempt-1))
+	} else {
+		d = float64(b.Base) * float64(attempt)
+	}
+	if b.Max > 0 && d > float64(b.Max) {
+		d = float64(b.Max)
+	}
+	return b.jitter(time.Duration(d))
+}
+
+// jitter removes a random fraction of up to b.Jitter from d
+func (b Backoff) jitter(d time.Duration) time.Duration {
+	if b.Jitter <= 0 || d <= 0 {
+		return d
+	}
+	random := b.Rand
+	if random == nil {
+		random = rand.Float64
+	}
+	return d - time.Duration(float64(d)*min(b.Jitter, 1)*random())
+}
+
+// Jitter removes a random fraction of up to fraction from d, using rand.Float64.
+func Jitter(d time.Duration, fraction float64) time.Duration {
+	return Backoff{Jitter: fraction}.jitter(d)
+}
+
+// Sleep waits for d on clock and returns true, or returns false early when done is closed.
+// A nil done channel never closes.
+func Sleep(clock Clock, d time.Duration, done <-chan struct{}) bool {
+	if d <= 0 {
+		return true
+	}
+	select {
+	case <-clock.After(d):
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// Poll calls check until it reports done or returns an error, waiting b.Delay(attempt) on clock between calls.
+//
+// Returns nil once check is done, the error of check, or ErrTimeout if
+// the next wait would end after timeout from the first call. A timeout of
+// 0 or less never expires.
+func Poll(clock Clock, b Backoff, timeout time.Duration, check func() (done bool, err error)) error {
+	return PollContext(context.Background(), clock, b, timeout, check)
+}
+
+// PollContext is Poll stopping early with the error of ctx once ctx is done.
+func PollContext(ctx context.Context, clock Clock, b Backoff, timeout time.Duration, check func() (done bool, err error)) error {
+	deadline := clock.Now().Add(timeout)
+	for attempt := 1; ; attempt++ {
+		done, err := check()
+		if err != nil {
+			return err
+		}
+		if done {
+			return nil
+		}
+		d := b.Delay(attempt)
+		if timeout > 0 && clock.Now().Add(d).After(deadline) {
+			return ErrTimeout
+		}
+		if !Sleep(clock, d, ctx.Done()) {
+			return ctx.Err()
+		}
+	}
+}
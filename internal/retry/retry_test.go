@@ -0,0 +1,106 @@
+package retry
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBackoff(t *testing.T) {
+	t.Run("Linear", func(t *testing.T) {
+		b := Backoff{Base: time.Second}
+		assert.Equal(t, time.Second, b.Delay(0))
+		assert.Equal(t, time.Second, b.Delay(1))
+		assert.Equal(t, 3*time.Second, b.Delay(3))
+	})
+
+	t.Run("Exponential", func(t *testing.T) {
+		b := Backoff{Base: time.Second, Max: 10 * time.Second, Exponential: true}
+		var delays []time.Duration
+		for attempt := 1; attempt <= 6; attempt++ {
+			delays = append(delays, b.Delay(attempt))
+		}
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second, 10 * time.Second}, delays)
+
+		// Large attempts do not overflow past the cap
+		assert.Equal(t, 10*time.Second, b.Delay(200))
+	})
+
+	t.Run("Jitter", func(t *testing.T) {
+		b := Backoff{Base: 10 * time.Second, Jitter: 0.3, Rand: func() float64 { return 0.5 }}
+		assert.Equal(t, 8500*time.Millisecond, b.Delay(1))
+
+		b.Rand = func() float64 { return 0 }
+		assert.Equal(t, 10*time.Second, b.Delay(1))
+
+		for i := 0; i < 100; i++ {
+			d := Jitter(time.Second, 0.3)
+			assert.True(t, d > 700*time.Millisecond && d <= time.Second, d)
+		}
+	})
+}
+
+func TestSleep(t *testing.T) {
+	clock := NewFakeClock(time.Unix(0, 0))
+	assert.True(t, Sleep(clock, time.Minute, nil))
+	assert.True(t, Sleep(clock, 0, nil))
+	assert.Equal(t, []time.Duration{time.Minute}, clock.Sleeps())
+	assert.Equal(t, time.Unix(60, 0), clock.Now())
+
+	done := make(chan struct{})
+	close(done)
+	assert.False(t, Sleep(blockingClock{}, time.Minute, done))
+}
+
+// blockingClock never fires
+type blockingClock struct{}
+
+func (blockingClock) Now() time.Time                       { return time.Unix(0, 0) }
+func (blockingClock) After(time.Duration) <-chan time.Time { return nil }
+
+func TestPoll(t *testing.T) {
+	b := Backoff{Base: time.Second, Max: 4 * time.Second, Exponential: true}
+
+	t.Run("Done", func(t *testing.T) {
+		clock := NewFakeClock(time.Unix(0, 0))
+		calls := 0
+		err := Poll(clock, b, 0, func() (bool, error) {
+			calls++
+			return calls == 5, nil
+		})
+		assert.NoError(t, err)
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second}, clock.Sleeps())
+	})
+
+	t.Run("Error", func(t *testing.T) {
+		failure := errors.New("failure")
+		err := Poll(NewFakeClock(time.Unix(0, 0)), b, 0, func() (bool, error) {
+			return false, failure
+		})
+		assert.ErrorIs(t, err, failure)
+	})
+
+	t.Run("Timeout", func(t *testing.T) {
+		clock := NewFakeClock(time.Unix(0, 0))
+		err := Poll(clock, b, 10*time.Second, func() (bool, error) {
+			return false, nil
+		})
+		assert.ErrorIs(t, err, ErrTimeout)
+		// 1+2+4 seconds fit in the timeout, another 4 would not
+		assert.Equal(t, []time.Duration{time.Second, 2 * time.Second, 4 * time.Second}, clock.Sleeps())
+	})
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		calls := 0
+		err := PollContext(ctx, blockingClock{}, b, 0, func() (bool, error) {
+			calls++
+			cancel()
+			return false, nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Equal(t, 1, calls)
+	})
+}
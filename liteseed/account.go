@@ -0,0 +1,99 @@
+package liteseed
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+	"github.com/liteseed/goar/tag"
+)
+
+// PAYMENT_ACTION_FUND is the Action tag value of transfers funding an account
+const PAYMENT_ACTION_FUND = "Fund"
+
+// Info describes a bundler.
+type Info struct {
+	Address string `json:"address"` // Arweave address payments and account funding are sent to
+	Version string `json:"version"` // Version of the bundler software
+}
+
+// Balance is the prepaid upload credit of an address on a bundler.
+type Balance struct {
+	Address string `json:"address"` // Arweave address of the account
+	Balance string `json:"balance"` // Credit in winston
+}
+
+// GetInfo returns the bundler's address and version.
+func (c *Client) GetInfo() (*Info, error) {
+	var info Info
+	if err := c.request(http.MethodGet, "", nil, &info); err != nil {
+		return nil, err
+	}
+	if info.Address == "" {
+		return nil, goar.Errorf(goar.ErrDecode, "bundler info has no address")
+	}
+	return &info, nil
+}
+
+// GetBalance returns the prepaid upload credit of address.
+//
+// Accounts that were never funded have a zero balance.
+func (c *Client) GetBalance(address string) (*Balance, error) {
+	var balance Balance
+	if err := c.request(http.MethodGet, "account/"+address+"/balance", nil, &balance); err != nil {
+		return nil, err
+	}
+	if _, ok := new(big.Int).SetString(balance.Balance, 10); !ok {
+		return nil, goar.Errorf(goar.ErrDecode, "invalid balance %q", balance.Balance)
+	}
+	return &balance, nil
+}
+
+// Fund credits the account of the sender of the AR transfer paymentID.
+//
+// The transfer must send AR to the address returned by GetInfo. The
+// bundler credits the account once it has verified the transfer, which may
+// take until the transfer is confirmed; use WaitForBalance to wait for it.
+func (c *Client) Fund(paymentID string) (*Balance, error) {
+	var balance Balance
+	if err := c.request(http.MethodPost, "account/fund/"+paymentID, nil, &balance); err != nil {
+		return nil, err
+	}
+	return &balance, nil
+}
+
+// WaitForBalance polls the balance of address every PollInterval until it reaches minimum winston.
+//
+// Returns the last balance, with an error if timeout elapsed first.
+func (c *Client) WaitForBalance(address string, minimum *big.Int, timeout time.Duration) (*Balance, error) {
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = DEFAULT_POLL_INTERVAL
+	}
+	var balance *Balance
+	err := retry.Poll(c.getClock(), retry.Backoff{Base: interval, Max: interval}, timeout, func() (bool, error) {
+		var err error
+		if balance, err = c.GetBalance(address); err != nil {
+			return false, err
+		}
+		current, _ := new(big.Int).SetString(balance.Balance, 10)
+		return current.Cmp(minimum) >= 0, nil
+	})
+	if errors.Is(err, retry.ErrTimeout) {
+		return balance, fmt.Errorf("timed out waiting for a balance of %s winston on %s", minimum, address)
+	}
+	return balance, err
+}
+
+// FundTags returns the tags of the AR transfer funding the account of address.
+func FundTags(address string) []tag.Tag {
+	return []tag.Tag{
+		{Name: "App-Name", Value: PAYMENT_APP_NAME},
+		{Name: "Action", Value: PAYMENT_ACTION_FUND},
+		{Name: "Account", Value: address},
+	}
+}
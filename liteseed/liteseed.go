@@ -0,0 +1,277 @@
+// Package liteseed implements a client for the Liteseed bundler API.
+//
+// Liteseed bundlers accept signed ANS-104 data items, bundle them and post
+// the bundles to Arweave. Uploads are paid with a plain AR transfer to the
+// bundler: the data items are posted first, then the payment transaction ID
+// is attached to each of them, and the bundler includes them once the
+// payment is confirmed. wallet.SendDataItems orchestrates the whole flow.
+//
+// Bundlers also keep prepaid accounts: an AR transfer registered with Fund
+// credits the sender, see wallet.FundBundlerAccount.
+//
+// Example usage:
+//
+//	b := liteseed.New(liteseed.DEFAULT_URL)
+//	quote, err := b.GetPrice(size)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%s winston to %s\n", quote.Price, quote.Address)
+package liteseed
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// Default Client settings
+const (
+	DEFAULT_URL           = "https://api.liteseed.xyz" // URL of the public Liteseed bundler
+	DEFAULT_POLL_INTERVAL = 10 * time.Second           // Interval between two status requests of WaitForStatus
+)
+
+// Data item statuses reported by the bundler
+const (
+	StatusQueued    = "queued"    // Waiting for payment or bundling
+	StatusSent      = "sent"      // Included in a bundle posted to Arweave
+	StatusPermanent = "permanent" // The bundle is confirmed on Arweave
+	StatusFailed    = "failed"    // The bundler gave up on the item, e.g. the payment was insufficient
+)
+
+// Tag names and values of payment transactions
+const (
+	PAYMENT_APP_NAME     = "Liteseed"
+	PAYMENT_ACTION       = "Payment"
+	PAYMENT_DATA_ITEM_ID = "Data-Item-Id"
+)
+
+// Client represents an HTTP client for a Liteseed bundler.
+type Client struct {
+	Client       *http.Client  // HTTP client with configured timeout
+	URL          string        // Base URL of the bundler
+	PollInterval time.Duration // Interval between two status requests of WaitForStatus
+
+	clock retry.Clock // Time source of WaitForStatus, retry.SystemClock if nil
+}
+
+// Quote is the fee a bundler charges for storing data.
+type Quote struct {
+	Price   string `json:"price"`   // Fee in winston
+	Address string `json:"address"` // Arweave address the fee is paid to
+}
+
+// Receipt is the bundler's record of a data item.
+type Receipt struct {
+	ID        string `json:"id"`                   // Data item ID
+	Owner     string `json:"owner,omitempty"`      // Address of the data item owner
+	PaymentID string `json:"payment_id,omitempty"` // ID of the AR transfer paying for the item, empty until paid
+	Status    string `json:"status,omitempty"`     // One of the Status constants
+	BundleID  string `json:"bundle_id,omitempty"`  // ID of the bundle transaction the item is included in, empty until sent
+}
+
+// New creates a bundler client with a 30-second request timeout and DEFAULT_POLL_INTERVAL.
+//
+// Example:
+//
+//	b := liteseed.New(liteseed.DEFAULT_URL)
+func New(url string) *Client {
+	return &Client{
+		Client:       &http.Client{Timeout: 30 * time.Second},
+		URL:          url,
+		PollInterval: DEFAULT_POLL_INTERVAL,
+	}
+}
+
+// Close closes the idle connections of the client's HTTP client.
+func (c *Client) Close() error {
+	c.Client.CloseIdleConnections()
+	return nil
+}
+
+// GetPrice returns the fee the bundler charges for size bytes of data items, and the address to pay it to.
+//
+// Example:
+//
+//	quote, err := b.GetPrice(int(item.RawSize()))
+func (c *Client) GetPrice(size int) (*Quote, error) {
+	var quote Quote
+	if err := c.request(http.MethodGet, "price/"+strconv.Itoa(size), nil, &quote); err != nil {
+		return nil, err
+	}
+	if quote.Price == "" || quote.Address == "" {
+		return nil, goar.Errorf(goar.ErrDecode, "incomplete price quote")
+	}
+	return &quote, nil
+}
+
+// PostDataItem uploads a signed data item to the bundler.
+//
+// Returns the bundler's receipt, or an error if the item is not signed or
+// the bundler rejects it.
+//
+// Example:
+//
+//	receipt, err := b.PostDataItem(signedItem)
+func (c *Client) PostDataItem(item *data_item.DataItem) (*Receipt, error) {
+	if item.ID == "" || item.Signature == "" {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "data item not signed")
+	}
+	var raw bytes.Buffer
+	raw.Grow(int(item.RawSize()))
+	if err := item.WriteRawTo(&raw); err != nil {
+		return nil, err
+	}
+
+	var receipt Receipt
+	if err := c.request(http.MethodPost, "tx", &raw, &receipt); err != nil {
+		return nil, err
+	}
+	if receipt.ID != item.ID {
+		return nil, goar.Errorf(goar.ErrDecode, "bundler returned receipt for %s instead of %s", receipt.ID, item.ID)
+	}
+	return &receipt, nil
+}
+
+// SetPayment attaches the AR transfer paymentID to data item id.
+//
+// The bundler verifies the transfer before bundling the item.
+func (c *Client) SetPayment(id string, paymentID string) (*Receipt, error) {
+	var receipt Receipt
+	if err := c.request(http.MethodPut, "tx/"+id+"/"+paymentID, nil, &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// GetStatus returns the bundler's current receipt for data item id.
+//
+// Returns an error with code goar.ErrNotFound if the bundler does not know the item.
+func (c *Client) GetStatus(id string) (*Receipt, error) {
+	var receipt Receipt
+	if err := c.request(http.MethodGet, "tx/"+id+"/status", nil, &receipt); err != nil {
+		return nil, err
+	}
+	return &receipt, nil
+}
+
+// WaitForStatus polls the receipt of data item id every PollInterval until the item reaches status or fails.
+//
+// Statuses are ordered: waiting for StatusSent also returns once the item
+// is StatusPermanent.
+//
+// Returns the last receipt, with an error if the item failed or timeout
+// elapsed first.
+func (c *Client) WaitForStatus(id string, status string, timeout time.Duration) (*Receipt, error) {
+	interval := c.PollInterval
+	if interval <= 0 {
+		interval = DEFAULT_POLL_INTERVAL
+	}
+	var receipt *Receipt
+	err := retry.Poll(c.getClock(), retry.Backoff{Base: interval, Max: interval}, timeout, func() (bool, error) {
+		var err error
+		receipt, err = c.GetStatus(id)
+		if errors.Is(err, goar.ErrNotFound) {
+			return false, nil
+		}
+		if err != nil {
+			return false, err
+		}
+		if receipt.Status == StatusFailed {
+			return false, fmt.Errorf("data item %s failed", id)
+		}
+		return rank(receipt.Status) >= rank(status), nil
+	})
+	if errors.Is(err, retry.ErrTimeout) {
+		return receipt, fmt.Errorf("timed out waiting for data item %s to be %s", id, status)
+	}
+	if err != nil && receipt == nil {
+		return nil, err
+	}
+	return receipt, err
+}
+
+// PaymentTags returns the tags of the AR transfer paying for the data items ids.
+func PaymentTags(ids []string) []tag.Tag {
+	tags := make([]tag.Tag, 0, len(ids)+2)
+	tags = append(tags,
+		tag.Tag{Name: "App-Name", Value: PAYMENT_APP_NAME},
+		tag.Tag{Name: "Action", Value: PAYMENT_ACTION},
+	)
+	for _, id := range ids {
+		tags = append(tags, tag.Tag{Name: PAYMENT_DATA_ITEM_ID, Value: id})
+	}
+	return tags
+}
+
+// getClock returns the clock of the client
+func (c *Client) getClock() retry.Clock {
+	if c.clock == nil {
+		return retry.SystemClock
+	}
+	return c.clock
+}
+
+// rank orders the statuses of a data item, unknown statuses first
+func rank(status string) int {
+	switch status {
+	case StatusQueued:
+		return 1
+	case StatusSent:
+		return 2
+	case StatusPermanent:
+		return 3
+	}
+	return 0
+}
+
+// request sends body to route and decodes the JSON response into v
+func (c *Client) request(method string, route string, body io.Reader, v any) error {
+	req, err := http.NewRequest(method, c.URL+"/"+route, body)
+	if err != nil {
+		return err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/octet-stream")
+	}
+
+	resp, err := c.Client.Do(req)
+	if err != nil {
+		return goar.Wrap(goar.ErrNetwork, err)
+	}
+	defer resp.Body.Close()
+
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return goar.Wrap(goar.ErrNetwork, err)
+	}
+	if resp.StatusCode >= 400 {
+		return statusError(resp.StatusCode, b)
+	}
+	return goar.Wrap(goar.ErrDecode, json.Unmarshal(b, v))
+}
+
+// statusError classifies a failed bundler response
+func statusError(code int, body []byte) error {
+	err := fmt.Errorf("%d: %s", code, string(body))
+	switch {
+	case code == http.StatusNotFound:
+		return goar.Wrap(goar.ErrNotFound, err)
+	case code == http.StatusTooManyRequests:
+		return goar.Wrap(goar.ErrRateLimited, err)
+	case code >= 500:
+		return goar.Wrap(goar.ErrGateway, err)
+	default:
+		return goar.Wrap(goar.ErrBadRequest, err)
+	}
+}
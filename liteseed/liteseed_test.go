@@ -0,0 +1,186 @@
+package liteseed
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/internal/retry"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// bundler is a minimal in-memory Liteseed bundler
+type bundler struct {
+	mu       sync.Mutex
+	receipts map[string]*Receipt
+	polls    int
+	balances map[string]int64
+	pending  map[string]int64
+}
+
+func newBundler(t *testing.T) *httptest.Server {
+	b := &bundler{receipts: map[string]*Receipt{}, balances: map[string]int64{}, pending: map[string]int64{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Info{Address: "bundler", Version: "test"})
+	})
+	mux.HandleFunc("GET /account/{address}/balance", func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		address := r.PathValue("address")
+		// Funding is credited on the first balance query after it was registered
+		b.balances[address] += b.pending[address]
+		delete(b.pending, address)
+		_ = json.NewEncoder(w).Encode(Balance{Address: address, Balance: strconv.FormatInt(b.balances[address], 10)})
+	})
+	mux.HandleFunc("POST /account/fund/{payment}", func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		// Every payment of the fake is worth 1000 winston from "owner"
+		b.pending["owner"] += 1000
+		_ = json.NewEncoder(w).Encode(Balance{Address: "owner", Balance: strconv.FormatInt(b.balances["owner"], 10)})
+	})
+	mux.HandleFunc("GET /price/{size}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(Quote{Price: r.PathValue("size") + "0", Address: "bundler"})
+	})
+	mux.HandleFunc("POST /tx", func(w http.ResponseWriter, r *http.Request) {
+		raw, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		item, err := data_item.Decode(raw)
+		if err != nil || item.Verify() != nil {
+			http.Error(w, "invalid data item", http.StatusBadRequest)
+			return
+		}
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.receipts[item.ID] = &Receipt{ID: item.ID, Owner: item.GetOwnerAddress(), Status: StatusQueued}
+		_ = json.NewEncoder(w).Encode(b.receipts[item.ID])
+	})
+	mux.HandleFunc("PUT /tx/{id}/{payment}", func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		receipt, ok := b.receipts[r.PathValue("id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		receipt.PaymentID = r.PathValue("payment")
+		_ = json.NewEncoder(w).Encode(receipt)
+	})
+	mux.HandleFunc("GET /tx/{id}/status", func(w http.ResponseWriter, r *http.Request) {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		receipt, ok := b.receipts[r.PathValue("id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		// Items are bundled on the second poll after payment
+		if receipt.PaymentID != "" {
+			if b.polls++; b.polls >= 2 {
+				receipt.Status = StatusSent
+				receipt.BundleID = "bundle"
+			}
+		}
+		_ = json.NewEncoder(w).Encode(receipt)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+func signedItem(t *testing.T) *data_item.DataItem {
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+	item := data_item.New([]byte("hello"), "", "", nil)
+	require.NoError(t, item.Sign(s))
+	return item
+}
+
+func TestClient(t *testing.T) {
+	b := New(newBundler(t).URL)
+	b.clock = retry.NewFakeClock(time.Unix(0, 0))
+
+	t.Run("GetPrice", func(t *testing.T) {
+		quote, err := b.GetPrice(1024)
+		require.NoError(t, err)
+		assert.Equal(t, &Quote{Price: "10240", Address: "bundler"}, quote)
+	})
+
+	t.Run("PostDataItem", func(t *testing.T) {
+		item := signedItem(t)
+		receipt, err := b.PostDataItem(item)
+		require.NoError(t, err)
+		assert.Equal(t, item.ID, receipt.ID)
+		assert.Equal(t, StatusQueued, receipt.Status)
+
+		_, err = b.PostDataItem(data_item.New([]byte("hello"), "", "", nil))
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+	})
+
+	t.Run("WaitForStatus", func(t *testing.T) {
+		item := signedItem(t)
+		_, err := b.PostDataItem(item)
+		require.NoError(t, err)
+
+		// Unpaid items are never bundled
+		receipt, err := b.WaitForStatus(item.ID, StatusSent, time.Minute)
+		assert.Error(t, err)
+		assert.Equal(t, StatusQueued, receipt.Status)
+
+		receipt, err = b.SetPayment(item.ID, "payment")
+		require.NoError(t, err)
+		assert.Equal(t, "payment", receipt.PaymentID)
+
+		receipt, err = b.WaitForStatus(item.ID, StatusSent, time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, "bundle", receipt.BundleID)
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		_, err := b.GetStatus("missing")
+		assert.ErrorIs(t, err, goar.ErrNotFound)
+	})
+}
+
+func TestAccount(t *testing.T) {
+	b := New(newBundler(t).URL)
+	b.clock = retry.NewFakeClock(time.Unix(0, 0))
+
+	info, err := b.GetInfo()
+	require.NoError(t, err)
+	assert.Equal(t, "bundler", info.Address)
+
+	balance, err := b.GetBalance("owner")
+	require.NoError(t, err)
+	assert.Equal(t, "0", balance.Balance)
+
+	balance, err = b.Fund("payment")
+	require.NoError(t, err)
+	assert.Equal(t, "0", balance.Balance)
+
+	balance, err = b.WaitForBalance("owner", big.NewInt(1000), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, "1000", balance.Balance)
+
+	balance, err = b.WaitForBalance("owner", big.NewInt(2000), time.Minute)
+	assert.ErrorContains(t, err, "timed out")
+	assert.Equal(t, "1000", balance.Balance)
+}
+
+func TestPaymentTags(t *testing.T) {
+	tags := PaymentTags([]string{"a", "b"})
+	assert.Len(t, tags, 4)
+	assert.Equal(t, PAYMENT_DATA_ITEM_ID, tags[3].Name)
+	assert.Equal(t, "b", tags[3].Value)
+}
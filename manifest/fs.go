@@ -0,0 +1,264 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"io/fs"
+	"path"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/liteseed/goar/client"
+)
+
+// FS is a read-only fs.FS over the paths of a manifest.
+//
+// Each path of the manifest is a file holding the data of its transaction
+// or data item, and the directories are those implied by the paths. Paths
+// that are not valid fs.FS names, e.g. with a leading or trailing slash,
+// are left out. Unlike a gateway, FS only opens exact paths: the index and
+// fallback of the manifest are not served for other names, see
+// Manifest.Resolve.
+//
+// Opening a file fetches its data with Fetch and checks it against its
+// signature, so a file only ever reads verified content. The data is held
+// in memory until the file is closed; files are seekable and implement
+// io.ReaderAt. The Sys method of their fs.FileInfo returns the
+// *client.TransactionNode of the file. Stat and the Info method of
+// directory entries only query GraphQL, without downloading the data.
+//
+// Example:
+//
+//	fsys, err := manifest.Mount(c, "MANIFEST_ID")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	tmpl, err := template.ParseFS(fsys, "templates/*.html")
+type FS struct {
+	Manifest *Manifest
+
+	client *client.Client
+	ctx    context.Context
+	files  map[string]string              // ID of the content of each file
+	dirs   map[string]map[string]struct{} // Names of the entries of each directory, "." for the root
+}
+
+var (
+	_ fs.StatFS    = (*FS)(nil)
+	_ fs.ReadDirFS = (*FS)(nil)
+)
+
+// Mount fetches the manifest id with Fetch and returns the file system of its paths.
+//
+// Returns an error with code goar.ErrDecode if id is not a manifest, or
+// the error of Fetch.
+func Mount(c *client.Client, id string) (*FS, error) {
+	return MountContext(context.Background(), c, id)
+}
+
+// MountContext is Mount bound to ctx, which also bounds the requests of the returned file system.
+func MountContext(ctx context.Context, c *client.Client, id string) (*FS, error) {
+	data, _, err := Fetch(ctx, c, id)
+	if err != nil {
+		return nil, err
+	}
+	m, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	return NewFS(c, m).WithContext(ctx), nil
+}
+
+// NewFS returns the file system of the paths of m, whose content is fetched from c.
+func NewFS(c *client.Client, m *Manifest) *FS {
+	f := &FS{
+		Manifest: m,
+		client:   c,
+		ctx:      context.Background(),
+		files:    map[string]string{},
+		dirs:     map[string]map[string]struct{}{".": {}},
+	}
+	for name, p := range m.Paths {
+		if !fs.ValidPath(name) || name == "." {
+			continue
+		}
+		f.files[name] = p.ID
+		for name != "." {
+			dir, base := path.Split(name)
+			dir = path.Clean(dir)
+			if f.dirs[dir] == nil {
+				f.dirs[dir] = map[string]struct{}{}
+			}
+			f.dirs[dir][base] = struct{}{}
+			name = dir
+		}
+	}
+	return f
+}
+
+// WithContext returns a copy of the file system whose requests are bound to ctx.
+func (f *FS) WithContext(ctx context.Context) *FS {
+	g := *f
+	g.ctx = ctx
+	return &g
+}
+
+// Open opens the file or directory name, fetching and verifying the content of files.
+//
+// The error is a *fs.PathError wrapping fs.ErrNotExist for names that are
+// not in the manifest, or the error of Fetch.
+func (f *FS) Open(name string) (fs.File, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrInvalid}
+	}
+	if id, ok := f.files[name]; ok {
+		data, node, err := Fetch(f.ctx, f.client, id)
+		if err != nil {
+			return nil, &fs.PathError{Op: "open", Path: name, Err: err}
+		}
+		info := newFileInfo(name, node)
+		info.size = int64(len(data))
+		return &file{Reader: bytes.NewReader(data), info: info}, nil
+	}
+	if _, ok := f.dirs[name]; ok {
+		entries, _ := f.ReadDir(name)
+		return &dir{info: &fileInfo{name: path.Base(name), dir: true}, entries: entries}, nil
+	}
+	return nil, &fs.PathError{Op: "open", Path: name, Err: fs.ErrNotExist}
+}
+
+// Stat returns the fs.FileInfo of name, querying GraphQL for files.
+func (f *FS) Stat(name string) (fs.FileInfo, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrInvalid}
+	}
+	if id, ok := f.files[name]; ok {
+		node, err := lookup(f.ctx, f.client, id)
+		if err != nil {
+			return nil, &fs.PathError{Op: "stat", Path: name, Err: err}
+		}
+		return newFileInfo(name, node), nil
+	}
+	if _, ok := f.dirs[name]; ok {
+		return &fileInfo{name: path.Base(name), dir: true}, nil
+	}
+	return nil, &fs.PathError{Op: "stat", Path: name, Err: fs.ErrNotExist}
+}
+
+// ReadDir returns the entries of the directory name sorted by name.
+func (f *FS) ReadDir(name string) ([]fs.DirEntry, error) {
+	if !fs.ValidPath(name) {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrInvalid}
+	}
+	children, ok := f.dirs[name]
+	if !ok {
+		return nil, &fs.PathError{Op: "readdir", Path: name, Err: fs.ErrNotExist}
+	}
+	entries := make([]fs.DirEntry, 0, len(children))
+	for child := range children {
+		p := path.Join(name, child)
+		_, isFile := f.files[p]
+		entries = append(entries, &dirEntry{fsys: f, path: p, dir: !isFile})
+	}
+	slices.SortFunc(entries, func(a, b fs.DirEntry) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+	return entries, nil
+}
+
+// fileInfo describes a file or directory of an FS
+type fileInfo struct {
+	name    string
+	size    int64
+	modTime time.Time
+	dir     bool
+	node    *client.TransactionNode
+}
+
+// newFileInfo describes the file name holding the content of node
+func newFileInfo(name string, node *client.TransactionNode) *fileInfo {
+	info := &fileInfo{name: path.Base(name), node: node}
+	info.size, _ = strconv.ParseInt(node.Data.Size, 10, 64)
+	if node.Block != nil {
+		info.modTime = time.Unix(node.Block.Timestamp, 0)
+	}
+	return info
+}
+
+func (i *fileInfo) Name() string       { return i.name }
+func (i *fileInfo) Size() int64        { return i.size }
+func (i *fileInfo) ModTime() time.Time { return i.modTime }
+func (i *fileInfo) IsDir() bool        { return i.dir }
+
+func (i *fileInfo) Mode() fs.FileMode {
+	if i.dir {
+		return fs.ModeDir | 0o555
+	}
+	return 0o444
+}
+
+func (i *fileInfo) Sys() any {
+	if i.node == nil {
+		return nil
+	}
+	return i.node
+}
+
+// file is an open file of an FS, holding its verified content
+type file struct {
+	*bytes.Reader
+	info *fileInfo
+}
+
+func (f *file) Stat() (fs.FileInfo, error) { return f.info, nil }
+func (f *file) Close() error               { return nil }
+
+// dir is an open directory of an FS
+type dir struct {
+	info    *fileInfo
+	entries []fs.DirEntry
+	offset  int
+}
+
+func (d *dir) Stat() (fs.FileInfo, error) { return d.info, nil }
+func (d *dir) Close() error               { return nil }
+
+func (d *dir) Read([]byte) (int, error) {
+	return 0, &fs.PathError{Op: "read", Path: d.info.name, Err: errors.New("is a directory")}
+}
+
+func (d *dir) ReadDir(n int) ([]fs.DirEntry, error) {
+	rest := d.entries[d.offset:]
+	if n <= 0 {
+		d.offset = len(d.entries)
+		return rest, nil
+	}
+	if len(rest) == 0 {
+		return nil, io.EOF
+	}
+	rest = rest[:min(n, len(rest))]
+	d.offset += len(rest)
+	return rest, nil
+}
+
+// dirEntry is an entry of a directory of an FS, whose Info is only queried on demand
+type dirEntry struct {
+	fsys *FS
+	path string
+	dir  bool
+}
+
+func (e *dirEntry) Name() string               { return path.Base(e.path) }
+func (e *dirEntry) IsDir() bool                { return e.dir }
+func (e *dirEntry) Info() (fs.FileInfo, error) { return e.fsys.Stat(e.path) }
+
+func (e *dirEntry) Type() fs.FileMode {
+	if e.dir {
+		return fs.ModeDir
+	}
+	return 0
+}
@@ -0,0 +1,199 @@
+package manifest
+
+import (
+	"encoding/json"
+	"io/fs"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// gateway serves transactions and data items from /graphql, /tx/{id} and /raw/{id}
+type gateway struct {
+	t     *testing.T
+	nodes map[string]client.TransactionNode
+	txs   map[string]*transaction.Transaction
+	data  map[string][]byte
+}
+
+func newGateway(t *testing.T) (*gateway, *client.Client) {
+	g := &gateway{t: t, nodes: map[string]client.TransactionNode{}, txs: map[string]*transaction.Transaction{}, data: map[string][]byte{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /graphql", func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			Variables struct {
+				IDs []string `json:"ids"`
+			} `json:"variables"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		edges := []map[string]any{}
+		for _, id := range req.Variables.IDs {
+			if node, ok := g.nodes[id]; ok {
+				edges = append(edges, map[string]any{"cursor": id, "node": node})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]any{"data": map[string]any{"transactions": map[string]any{"edges": edges}}})
+	})
+	mux.HandleFunc("GET /tx/{id}", func(w http.ResponseWriter, r *http.Request) {
+		tx, ok := g.txs[r.PathValue("id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		header := *tx
+		header.Data = ""
+		header.ChunkData = nil
+		json.NewEncoder(w).Encode(&header)
+	})
+	mux.HandleFunc("GET /raw/{id}", func(w http.ResponseWriter, r *http.Request) {
+		data, ok := g.data[r.PathValue("id")]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write(data)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return g, client.New(server.URL)
+}
+
+// addItem signs a data item holding data and serves it
+func (g *gateway) addItem(s *signer.Signer, data []byte, tags []tag.Tag) string {
+	anchor := strings.Repeat("a", 32)
+	d := data_item.New(data, "", anchor, &tags)
+	require.NoError(g.t, d.Sign(s))
+	node := client.TransactionNode{ID: d.ID, Anchor: crypto.Base64URLEncode([]byte(anchor)), Signature: d.Signature, Tags: tags}
+	node.Owner.Key = d.Owner
+	node.Data.Size = strconv.Itoa(len(data))
+	node.Block = &client.BlockRef{Timestamp: 1700000000}
+	node.BundledIn = &struct {
+		ID string `json:"id"`
+	}{ID: "bundle"}
+	g.nodes[d.ID] = node
+	g.data[d.ID] = data
+	return d.ID
+}
+
+// addTransaction signs a transaction holding data and serves it
+func (g *gateway) addTransaction(s *signer.Signer, data []byte) string {
+	tx := transaction.New(data, "", "0", nil)
+	tx.Owner = s.Owner()
+	tx.Reward = "1"
+	require.NoError(g.t, tx.Sign(s))
+	node := client.TransactionNode{ID: tx.ID, Signature: tx.Signature}
+	node.Owner.Key = tx.Owner
+	node.Data.Size = tx.DataSize
+	g.nodes[tx.ID] = node
+	g.txs[tx.ID] = tx
+	g.data[tx.ID] = data
+	return tx.ID
+}
+
+func TestFS(t *testing.T) {
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+	g, c := newGateway(t)
+
+	index := g.addItem(s, []byte("<h1>Hello</h1>"), []tag.Tag{{Name: "Content-Type", Value: "text/html"}})
+	style := g.addItem(s, []byte("h1 { color: red }"), nil)
+	text := g.addTransaction(s, []byte("stored in a transaction"))
+	m := New(map[string]string{"index.html": index, "css/style.css": style, "text.txt": text}, "index.html")
+	b, err := json.Marshal(m)
+	require.NoError(t, err)
+	id := g.addItem(s, b, []tag.Tag{{Name: "Content-Type", Value: CONTENT_TYPE}})
+
+	fsys, err := Mount(c, id)
+	require.NoError(t, err)
+	assert.Equal(t, m, fsys.Manifest)
+
+	t.Run("fs.FS", func(t *testing.T) {
+		require.NoError(t, fstest.TestFS(fsys, "index.html", "css/style.css", "text.txt"))
+	})
+
+	t.Run("Content", func(t *testing.T) {
+		data, err := fs.ReadFile(fsys, "css/style.css")
+		require.NoError(t, err)
+		assert.Equal(t, "h1 { color: red }", string(data))
+		data, err = fs.ReadFile(fsys, "text.txt")
+		require.NoError(t, err)
+		assert.Equal(t, "stored in a transaction", string(data))
+
+		info, err := fs.Stat(fsys, "index.html")
+		require.NoError(t, err)
+		assert.Equal(t, int64(14), info.Size())
+		assert.Equal(t, int64(1700000000), info.ModTime().Unix())
+		assert.Equal(t, "text/html", tag.Tags(info.Sys().(*client.TransactionNode).Tags).Get("Content-Type"))
+
+		entries, err := fs.ReadDir(fsys, ".")
+		require.NoError(t, err)
+		require.Len(t, entries, 3)
+		assert.Equal(t, "css", entries[0].Name())
+		assert.True(t, entries[0].IsDir())
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		_, err := fsys.Open("missing.html")
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		_, err = fsys.Open("/index.html")
+		assert.ErrorIs(t, err, fs.ErrInvalid)
+
+		delete(g.nodes, style)
+		_, err = fsys.Open("css/style.css")
+		assert.ErrorIs(t, err, goar.ErrNotFound)
+	})
+
+	t.Run("Tampered", func(t *testing.T) {
+		g.data[index] = []byte("<h1>Phishing</h1>")
+		_, err := fsys.Open("index.html")
+		assert.ErrorIs(t, err, goar.ErrInvalidSignature)
+
+		g.data[text] = []byte("stored in a transactioN")
+		_, err = fsys.Open("text.txt")
+		assert.ErrorIs(t, err, goar.ErrInvalidSignature)
+
+		_, err = Mount(c, index)
+		assert.ErrorIs(t, err, goar.ErrInvalidSignature)
+	})
+
+	t.Run("Not a manifest", func(t *testing.T) {
+		_, err := Mount(c, g.addItem(s, []byte(`{"paths": {}}`), nil))
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
+
+func TestResolve(t *testing.T) {
+	m := New(map[string]string{"index.html": "index", "about.html": "about"}, "index.html")
+	for path, want := range map[string]string{"": "index", "/": "index", "about.html": "about", "/about.html": "about", "missing": ""} {
+		id, ok := m.Resolve(path)
+		assert.Equal(t, want, id, path)
+		assert.Equal(t, want != "", ok, path)
+	}
+
+	m.Index = &Index{ID: "root"}
+	m.Fallback = &Path{ID: "404"}
+	id, _ := m.Resolve("")
+	assert.Equal(t, "root", id)
+	id, _ = m.Resolve("missing")
+	assert.Equal(t, "404", id)
+
+	_, err := Parse([]byte("not json"))
+	assert.ErrorIs(t, err, goar.ErrDecode)
+	m, err = Parse([]byte(`{"manifest": "arweave/paths", "version": "0.1.0", "index": {"path": "a"}, "paths": {"a": {"id": "x"}}}`))
+	require.NoError(t, err)
+	id, _ = m.Resolve("/")
+	assert.Equal(t, "x", id)
+}
@@ -0,0 +1,109 @@
+// Package manifest reads Arweave path manifests and serves them as an io/fs file system.
+//
+// A manifest is a JSON document mapping paths to transactions or data
+// items, which gateways use to serve a whole site from one ID. FS exposes
+// the paths of a manifest through fs.FS, so that code consuming fs.FS,
+// such as html/template or http.FileServer, serves permaweb content
+// directly. The content of every file is checked against its signature
+// before it is read.
+//
+// Example usage:
+//
+//	fsys, err := manifest.Mount(client.New("https://arweave.net"), "MANIFEST_ID")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	http.Handle("/", http.FileServerFS(fsys))
+//
+// Learn more: https://github.com/ArweaveTeam/arweave/wiki/Path-Manifests
+package manifest
+
+import (
+	"encoding/json"
+
+	"github.com/liteseed/goar"
+)
+
+// Values of Manifest.Manifest and Manifest.Version written by New
+const (
+	MANIFEST_TYPE    = "arweave/paths"
+	MANIFEST_VERSION = "0.2.0"
+)
+
+// CONTENT_TYPE is the Content-Type tag of manifest uploads, which tells gateways to resolve their paths
+const CONTENT_TYPE = "application/x.arweave-manifest+json"
+
+// Manifest is an Arweave path manifest.
+type Manifest struct {
+	Manifest string          `json:"manifest"`           // Always MANIFEST_TYPE
+	Version  string          `json:"version"`            // Version of the format, e.g. "0.1.0" or "0.2.0"
+	Index    *Index          `json:"index,omitempty"`    // Content served for the root path
+	Fallback *Path           `json:"fallback,omitempty"` // Content served for unknown paths, since 0.2.0
+	Paths    map[string]Path `json:"paths"`              // Content of each path, relative to the root without a leading slash
+}
+
+// Index is the content of the root path of a manifest, given by a path or, since 0.2.0, an ID.
+type Index struct {
+	Path string `json:"path,omitempty"`
+	ID   string `json:"id,omitempty"`
+}
+
+// Path is the transaction or data item holding the content of a path.
+type Path struct {
+	ID string `json:"id"`
+}
+
+// New returns a manifest of paths, served with index for the root path if it is not empty.
+func New(paths map[string]string, index string) *Manifest {
+	m := &Manifest{Manifest: MANIFEST_TYPE, Version: MANIFEST_VERSION, Paths: map[string]Path{}}
+	for path, id := range paths {
+		m.Paths[path] = Path{ID: id}
+	}
+	if index != "" {
+		m.Index = &Index{Path: index}
+	}
+	return m
+}
+
+// Parse decodes a manifest.
+//
+// Returns an error with code goar.ErrDecode if b is not JSON or not a
+// path manifest.
+func Parse(b []byte) (*Manifest, error) {
+	m := &Manifest{}
+	if err := json.Unmarshal(b, m); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	if m.Manifest != MANIFEST_TYPE {
+		return nil, goar.Errorf(goar.ErrDecode, "not a path manifest: %q", m.Manifest)
+	}
+	if m.Paths == nil {
+		m.Paths = map[string]Path{}
+	}
+	return m, nil
+}
+
+// Resolve returns the ID of the content a gateway serves for path, and whether there is any.
+//
+// The root path, "" or "/", resolves to the index. Other paths resolve to
+// their exact entry, then to the fallback.
+func (m *Manifest) Resolve(path string) (string, bool) {
+	if len(path) > 0 && path[0] == '/' {
+		path = path[1:]
+	}
+	if path == "" && m.Index != nil {
+		if m.Index.ID != "" {
+			return m.Index.ID, true
+		}
+		if p, ok := m.Paths[m.Index.Path]; ok {
+			return p.ID, true
+		}
+	}
+	if p, ok := m.Paths[path]; ok && path != "" {
+		return p.ID, true
+	}
+	if m.Fallback != nil && m.Fallback.ID != "" {
+		return m.Fallback.ID, true
+	}
+	return "", false
+}
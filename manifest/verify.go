@@ -0,0 +1,136 @@
+package manifest
+
+import (
+	"bytes"
+	"context"
+	"slices"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// Fetch downloads the data of a transaction or data item and checks it against its signature.
+//
+// The signed fields are looked up through the gateway's GraphQL endpoint,
+// and for transactions also from /tx/<id>, and the data is downloaded from
+// /raw/<id>. Data items are verified like DataItem.Verify, transactions
+// with Transaction.VerifyData and Transaction.Verify, and in both cases
+// the ID must derive from the signature. A gateway can therefore not serve
+// other data than was signed for id.
+//
+// Returns the data and the GraphQL node of id. The error has code
+// goar.ErrNotFound if the gateway does not know id,
+// goar.ErrInvalidSignature if the data does not match its signature, or
+// the code of the failed request.
+//
+// Example:
+//
+//	data, node, err := manifest.Fetch(ctx, c, "ABC123...")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(node.Data.Type, len(data))
+func Fetch(ctx context.Context, c *client.Client, id string) ([]byte, *client.TransactionNode, error) {
+	node, err := lookup(ctx, c, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	data, err := c.GetRawDataContext(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if node.BundledIn == nil {
+		err = verifyTransaction(ctx, c, id, data)
+	} else {
+		err = verifyDataItem(node, data)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	return data, node, nil
+}
+
+// lookup returns the GraphQL node of id, or an error with code goar.ErrNotFound if there is none
+func lookup(ctx context.Context, c *client.Client, id string) (*client.TransactionNode, error) {
+	page, err := c.SearchTransactions(ctx, &client.TransactionQuery{IDs: []string{id}, First: 1}, "")
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Transactions) == 0 || page.Transactions[0].ID != id {
+		return nil, goar.Errorf(goar.ErrNotFound, "transaction %s not found", id)
+	}
+	return &page.Transactions[0], nil
+}
+
+// verifyTransaction checks that data is the data signed by the transaction id
+func verifyTransaction(ctx context.Context, c *client.Client, id string, data []byte) error {
+	tx, err := c.GetTransactionByIDContext(ctx, id)
+	if err != nil {
+		return err
+	}
+	if err := tx.VerifyDataContext(ctx, data); err != nil {
+		return err
+	}
+	// Verify rebuilds the data root from the data, which now matches the header
+	tx.Data = crypto.Base64URLEncode(data)
+	tx.ChunkData = nil
+	if err := tx.Verify(); err != nil {
+		return goar.Wrap(goar.ErrInvalidSignature, err)
+	}
+	return checkID(id, tx.Signature)
+}
+
+// verifyDataItem checks that data is the data signed by the data item of node
+func verifyDataItem(node *client.TransactionNode, data []byte) error {
+	anchor, err := crypto.Base64URLDecode(node.Anchor)
+	if err != nil {
+		return goar.Wrap(goar.ErrDecode, err)
+	}
+	owner, err := crypto.Base64URLDecode(node.Owner.Key)
+	if err != nil {
+		return goar.Wrap(goar.ErrDecode, err)
+	}
+	tags := append([]tag.Tag{}, node.Tags...)
+
+	// GraphQL does not report the signature type, which keys of the same size share
+	types := make([]int, 0, len(data_item.SignatureConfig))
+	for t, meta := range data_item.SignatureConfig {
+		if meta.PublicKeyLength == len(owner) {
+			types = append(types, t)
+		}
+	}
+	slices.Sort(types)
+	err = goar.Errorf(goar.ErrInvalidSignature, "data item %s has an owner of unknown type", node.ID)
+	for _, t := range types {
+		d := &data_item.DataItem{
+			ID:            node.ID,
+			Signature:     node.Signature,
+			SignatureType: t,
+			Owner:         node.Owner.Key,
+			Target:        node.Recipient,
+			Anchor:        string(anchor),
+			Tags:          &tags,
+			DataReader:    bytes.NewReader(data),
+			DataSize:      int64(len(data)),
+		}
+		if err = d.Verify(); err == nil {
+			return nil
+		}
+	}
+	return goar.Wrap(goar.ErrInvalidSignature, err)
+}
+
+// checkID returns an error with code goar.ErrInvalidSignature unless id derives from signature
+func checkID(id string, signature string) error {
+	rawSignature, err := crypto.Base64URLDecode(signature)
+	if err != nil {
+		return goar.Wrap(goar.ErrDecode, err)
+	}
+	if crypto.Base64URLEncode(crypto.SHA256(rawSignature)) != id {
+		return goar.Errorf(goar.ErrInvalidSignature, "signature does not match ID %s", id)
+	}
+	return nil
+}
@@ -0,0 +1,128 @@
+package pricing
+
+import (
+	"sync"
+	"time"
+
+	"github.com/liteseed/goar/client"
+)
+
+// Default PriceCache settings
+const (
+	DEFAULT_BUCKET_SIZE  = 256 * 1024       // Arweave charges per started 256KB chunk
+	DEFAULT_HEIGHT_CHECK = 30 * time.Second // Block time is two minutes on average
+)
+
+// Pricer returns the fee in winston to store size bytes. *client.Client and *PriceCache implement it.
+type Pricer interface {
+	GetTransactionPrice(size int, target string) (string, error)
+}
+
+// PriceCache caches fees by size bucket until a new block is mined.
+//
+// Sizes are rounded up to a multiple of BucketSize, and the fee of the
+// rounded size is requested once per bucket and target. The network height
+// is checked through /info at most once every HeightCheck; when it changed
+// the cache is emptied, since fees are only updated with new blocks. This
+// turns the per-item price calls of a mass estimation into a handful.
+//
+// With the default bucket of one chunk the fees are exact for layer 1
+// transactions; larger buckets trade accuracy for fewer requests, as fees
+// are then quoted for the upper bound of each bucket.
+//
+// A PriceCache is safe for concurrent use.
+//
+// Example:
+//
+//	cache := pricing.NewPriceCache(client.New("https://arweave.net"))
+//	for _, item := range items {
+//		fee, err := cache.GetTransactionPrice(len(item), "")
+//		...
+//	}
+type PriceCache struct {
+	Client      *client.Client // Client used for /price and /info requests
+	BucketSize  int            // Size granularity of the cache in bytes
+	HeightCheck time.Duration  // Minimum interval between two network height checks
+
+	mu      sync.Mutex
+	height  int64
+	checked time.Time
+	prices  map[priceKey]string
+}
+
+type priceKey struct {
+	bucket int
+	target string
+}
+
+// NewPriceCache creates a PriceCache with DEFAULT_BUCKET_SIZE and DEFAULT_HEIGHT_CHECK.
+func NewPriceCache(c *client.Client) *PriceCache {
+	return &PriceCache{
+		Client:      c,
+		BucketSize:  DEFAULT_BUCKET_SIZE,
+		HeightCheck: DEFAULT_HEIGHT_CHECK,
+	}
+}
+
+// GetTransactionPrice returns the fee in winston for size bytes sent to target, from the cache when possible.
+//
+// Returns the fee of the size rounded up to the bucket, or an error if the
+// network height or the price cannot be retrieved.
+func (p *PriceCache) GetTransactionPrice(size int, target string) (string, error) {
+	if err := p.invalidate(); err != nil {
+		return "", err
+	}
+
+	bucketSize := p.BucketSize
+	if bucketSize <= 0 {
+		bucketSize = DEFAULT_BUCKET_SIZE
+	}
+	key := priceKey{bucket: (size + bucketSize - 1) / bucketSize, target: target}
+
+	p.mu.Lock()
+	fee, ok := p.prices[key]
+	p.mu.Unlock()
+	if ok {
+		return fee, nil
+	}
+
+	fee, err := p.Client.GetTransactionPrice(key.bucket*bucketSize, target)
+	if err != nil {
+		return "", err
+	}
+	p.mu.Lock()
+	p.prices[key] = fee
+	p.mu.Unlock()
+	return fee, nil
+}
+
+// Height returns the network height the cached fees were quoted at, 0 before the first request.
+func (p *PriceCache) Height() int64 {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.height
+}
+
+// invalidate empties the cache if the network height changed since the last check
+func (p *PriceCache) invalidate() error {
+	p.mu.Lock()
+	due := p.prices == nil || time.Since(p.checked) >= p.HeightCheck
+	p.mu.Unlock()
+	if !due {
+		return nil
+	}
+
+	info, err := p.Client.GetNetworkInfo()
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.prices == nil || info.Height != p.height {
+		p.prices = map[priceKey]string{}
+		p.height = info.Height
+	}
+	p.checked = time.Now()
+	return nil
+}
@@ -0,0 +1,141 @@
+package pricing
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+)
+
+// BlockSample is the fee-related state of the network recorded in a block.
+type BlockSample struct {
+	Height     uint64    // Block height
+	Hash       string    // Independent hash of the block
+	Time       time.Time // When the block was mined
+	RewardPool Winston   // Size of the mining reward pool, funded by the storage fees
+	WeaveSize  uint64    // Total size of the data stored in bytes
+	BlockSize  uint64    // Size of the data added by the block in bytes
+	Diff       *big.Int  // Mining difficulty
+	Txs        int       // Number of transactions in the block
+}
+
+// History is a series of consecutive blocks, oldest first, for fee trend analysis.
+type History struct {
+	Samples []BlockSample
+}
+
+// BlockHistory walks the chain back from the current block and records its last blocks.
+//
+// The blocks are fetched one after the other by hash, following their
+// previous_block, so the series is consistent even if the chain is
+// reorganized while it is read.
+//
+// Parameters:
+//   - c: The client used to query the gateway
+//   - blocks: The number of blocks to record, at least 1
+//
+// Returns the history, which holds fewer blocks when the chain is shorter,
+// or an error if a block cannot be retrieved.
+//
+// Example:
+//
+//	h, err := pricing.BlockHistory(client.New("https://arweave.net"), 50)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("The weave grows by %.0f bytes per second\n", h.WeaveGrowth())
+func BlockHistory(c *client.Client, blocks int) (*History, error) {
+	return BlockHistoryContext(context.Background(), c, blocks)
+}
+
+// BlockHistoryContext is BlockHistory bound to ctx.
+func BlockHistoryContext(ctx context.Context, c *client.Client, blocks int) (*History, error) {
+	info, err := c.GetNetworkInfoContext(ctx)
+	if err != nil {
+		return nil, err
+	}
+	samples := make([]BlockSample, 0, max(blocks, 1))
+	for hash := info.Current; hash != "" && len(samples) < max(blocks, 1); {
+		b, err := c.GetBlockByIDContext(ctx, hash)
+		if err != nil {
+			return nil, err
+		}
+		sample, err := newBlockSample(b)
+		if err != nil {
+			return nil, err
+		}
+		samples = append(samples, sample)
+		if b.Height == 0 {
+			break
+		}
+		hash = b.PreviousBlock
+	}
+	for i, j := 0, len(samples)-1; i < j; i, j = i+1, j-1 {
+		samples[i], samples[j] = samples[j], samples[i]
+	}
+	return &History{Samples: samples}, nil
+}
+
+// newBlockSample returns the sample of b, or an error with code goar.ErrDecode if its difficulty is not a number
+func newBlockSample(b *client.Block) (BlockSample, error) {
+	diff, ok := new(big.Int).SetString(b.Diff, 10)
+	if !ok {
+		return BlockSample{}, goar.Errorf(goar.ErrDecode, "block %s has invalid difficulty %q", b.IndepHash, b.Diff)
+	}
+	return BlockSample{
+		Height:     b.Height,
+		Hash:       b.IndepHash,
+		Time:       time.Unix(int64(b.Timestamp), 0),
+		RewardPool: WinstonFromBig(new(big.Int).SetUint64(b.RewardPool)),
+		WeaveSize:  b.WeaveSize,
+		BlockSize:  b.BlockSize,
+		Diff:       diff,
+		Txs:        len(b.Txs),
+	}, nil
+}
+
+// Span returns the time between the first and the last block, 0 for less than two blocks.
+func (h *History) Span() time.Duration {
+	if len(h.Samples) < 2 {
+		return 0
+	}
+	return h.Samples[len(h.Samples)-1].Time.Sub(h.Samples[0].Time)
+}
+
+// BlockTime returns the average time between two blocks, 0 for less than two blocks.
+func (h *History) BlockTime() time.Duration {
+	if len(h.Samples) < 2 {
+		return 0
+	}
+	return h.Span() / time.Duration(len(h.Samples)-1)
+}
+
+// WeaveGrowth returns the average number of bytes added to the weave per second, 0 for less than two blocks.
+func (h *History) WeaveGrowth() float64 {
+	span := h.Span()
+	if span <= 0 {
+		return 0
+	}
+	first, last := h.Samples[0], h.Samples[len(h.Samples)-1]
+	return (float64(last.WeaveSize) - float64(first.WeaveSize)) / span.Seconds()
+}
+
+// RewardPoolChange returns how much the reward pool grew from the first to the last block, negative when it shrank.
+func (h *History) RewardPoolChange() Winston {
+	if len(h.Samples) < 2 {
+		return Winston{}
+	}
+	return h.Samples[len(h.Samples)-1].RewardPool.Sub(h.Samples[0].RewardPool)
+}
+
+// DiffChange returns the relative change of the difficulty from the first to the last block, e.g. 0.02 for 2% harder.
+func (h *History) DiffChange() float64 {
+	if len(h.Samples) < 2 || h.Samples[0].Diff.Sign() == 0 {
+		return 0
+	}
+	first, last := h.Samples[0].Diff, h.Samples[len(h.Samples)-1].Diff
+	change, _ := new(big.Rat).SetFrac(new(big.Int).Sub(last, first), first).Float64()
+	return change
+}
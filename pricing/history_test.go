@@ -0,0 +1,74 @@
+package pricing
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBlockHistory(t *testing.T) {
+	// Blocks 0 to 4, two minutes apart, each adding 1MB and 1000 winston to the pool
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /info", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"height":4,"current":"b4"}`))
+	})
+	mux.HandleFunc("GET /block/hash/{hash}", func(w http.ResponseWriter, r *http.Request) {
+		var height int
+		_, err := fmt.Sscanf(r.PathValue("hash"), "b%d", &height)
+		require.NoError(t, err)
+		diff := 1000 + 10*height
+		_, _ = fmt.Fprintf(w, `{"height":%d,"indep_hash":"b%d","previous_block":"b%d","timestamp":%d,"reward_pool":%d,"weave_size":%d,"block_size":1048576,"diff":"%d","txs":["a","b"]}`,
+			height, height, height-1, 1_700_000_000+120*height, 5000+1000*height, 1048576*(height+1), diff)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	c := client.New(server.URL)
+
+	h, err := BlockHistory(c, 3)
+	require.NoError(t, err)
+	require.Len(t, h.Samples, 3)
+	assert.Equal(t, uint64(2), h.Samples[0].Height)
+	assert.Equal(t, "b4", h.Samples[2].Hash)
+	assert.Equal(t, "7000", h.Samples[0].RewardPool.String())
+	assert.Equal(t, 2, h.Samples[0].Txs)
+
+	assert.Equal(t, 4*time.Minute, h.Span())
+	assert.Equal(t, 2*time.Minute, h.BlockTime())
+	assert.InDelta(t, 2*1048576/240.0, h.WeaveGrowth(), 0.001)
+	assert.Equal(t, "2000", h.RewardPoolChange().String())
+	assert.InDelta(t, 20/1020.0, h.DiffChange(), 1e-9)
+
+	t.Run("Whole chain", func(t *testing.T) {
+		h, err := BlockHistory(c, 10)
+		require.NoError(t, err)
+		assert.Len(t, h.Samples, 5)
+		assert.Equal(t, uint64(0), h.Samples[0].Height)
+	})
+
+	t.Run("Single block", func(t *testing.T) {
+		h, err := BlockHistory(c, 0)
+		require.NoError(t, err)
+		assert.Len(t, h.Samples, 1)
+		assert.Zero(t, h.Span())
+		assert.Zero(t, h.WeaveGrowth())
+		assert.True(t, h.RewardPoolChange().IsZero())
+	})
+
+	t.Run("Invalid difficulty", func(t *testing.T) {
+		mux.HandleFunc("GET /broken/info", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"height":4,"current":"b4"}`))
+		})
+		mux.HandleFunc("GET /broken/block/hash/{hash}", func(w http.ResponseWriter, r *http.Request) {
+			_, _ = w.Write([]byte(`{"height":4,"indep_hash":"b4","diff":"many"}`))
+		})
+		_, err := BlockHistory(client.New(server.URL+"/broken"), 2)
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
@@ -0,0 +1,145 @@
+package pricing
+
+import (
+	"encoding/json"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+)
+
+// COINGECKO_URL is the base URL of the public CoinGecko API
+const COINGECKO_URL = "https://api.coingecko.com/api/v3"
+
+// RateSource returns the price of one AR in a currency such as "usd".
+type RateSource interface {
+	Rate(currency string) (float64, error)
+}
+
+// CoinGecko is a RateSource reading AR exchange rates from the CoinGecko API.
+type CoinGecko struct {
+	Client *http.Client // HTTP client with configured timeout
+	URL    string       // Base URL of the API
+}
+
+// NewCoinGecko creates a CoinGecko rate source for COINGECKO_URL with a 30-second request timeout.
+func NewCoinGecko() *CoinGecko {
+	return &CoinGecko{Client: &http.Client{Timeout: 30 * time.Second}, URL: COINGECKO_URL}
+}
+
+// Rate returns the price of one AR in currency.
+//
+// Returns an error with code goar.ErrDecode if the API has no rate for
+// currency, or the error code of the failed request.
+func (g *CoinGecko) Rate(currency string) (float64, error) {
+	currency = strings.ToLower(currency)
+	resp, err := g.Client.Get(g.URL + "/simple/price?ids=arweave&vs_currencies=" + url.QueryEscape(currency))
+	if err != nil {
+		return 0, goar.Wrap(goar.ErrNetwork, err)
+	}
+	defer resp.Body.Close()
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return 0, goar.Wrap(goar.ErrNetwork, err)
+	}
+	switch {
+	case resp.StatusCode == http.StatusTooManyRequests:
+		return 0, goar.Errorf(goar.ErrRateLimited, "%d: %s", resp.StatusCode, b)
+	case resp.StatusCode >= 500:
+		return 0, goar.Errorf(goar.ErrGateway, "%d: %s", resp.StatusCode, b)
+	case resp.StatusCode >= 400:
+		return 0, goar.Errorf(goar.ErrBadRequest, "%d: %s", resp.StatusCode, b)
+	}
+
+	var prices map[string]map[string]float64
+	if err := json.Unmarshal(b, &prices); err != nil {
+		return 0, goar.Wrap(goar.ErrDecode, err)
+	}
+	rate, ok := prices["arweave"][currency]
+	if !ok || rate <= 0 {
+		return 0, goar.Errorf(goar.ErrDecode, "no AR rate for %s", currency)
+	}
+	return rate, nil
+}
+
+// Estimate is the fee of storing data, with its value in a currency when a rate is known.
+type Estimate struct {
+	Size     int     // Size of the data in bytes
+	Fee      Winston // Fee to store the data
+	Currency string  // Currency of Rate and Value, empty without a rate
+	Rate     float64 // Price of one AR in Currency
+	Value    float64 // Fee in Currency
+}
+
+// PerByte returns the fee per byte in winston, 0 for empty data.
+func (e *Estimate) PerByte() float64 {
+	if e.Size == 0 {
+		return 0
+	}
+	perByte, _ := new(big.Rat).SetFrac(e.Fee.value(), big.NewInt(int64(e.Size))).Float64()
+	return perByte
+}
+
+// Oracle estimates fees in winston and, with a RateSource, in other currencies.
+//
+// Example:
+//
+//	oracle := pricing.NewOracle(client.New("https://arweave.net"))
+//	oracle.Rates = pricing.NewCoinGecko()
+//	estimate, err := oracle.Estimate(1024*1024, "usd")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("%s AR ($%.4f)\n", estimate.Fee.AR(), estimate.Value)
+type Oracle struct {
+	Pricer Pricer     // Source of fees, e.g. a *client.Client or a *PriceCache
+	Rates  RateSource // Optional source of exchange rates
+}
+
+// NewOracle creates an Oracle quoting fees through a PriceCache of c, without exchange rates.
+func NewOracle(c *client.Client) *Oracle {
+	return &Oracle{Pricer: NewPriceCache(c)}
+}
+
+// Fee returns the fee to store size bytes, see Pricer.
+//
+// Returns an error with code goar.ErrDecode if the fee is not a winston
+// amount, or the error of the price request.
+func (o *Oracle) Fee(size int) (Winston, error) {
+	fee, err := o.Pricer.GetTransactionPrice(size, "")
+	if err != nil {
+		return Winston{}, err
+	}
+	w, err := ParseWinston(fee)
+	if err != nil {
+		return Winston{}, goar.Errorf(goar.ErrDecode, "invalid fee %q", fee)
+	}
+	return w, nil
+}
+
+// Estimate returns the fee to store size bytes, valued in currency if it is set and Rates is.
+//
+// Returns an error if the fee or the exchange rate cannot be retrieved.
+func (o *Oracle) Estimate(size int, currency string) (*Estimate, error) {
+	fee, err := o.Fee(size)
+	if err != nil {
+		return nil, err
+	}
+	estimate := &Estimate{Size: size, Fee: fee}
+	if currency == "" || o.Rates == nil {
+		return estimate, nil
+	}
+	rate, err := o.Rates.Rate(currency)
+	if err != nil {
+		return nil, err
+	}
+	estimate.Currency = currency
+	estimate.Rate = rate
+	estimate.Value = fee.Float64() * rate
+	return estimate, nil
+}
@@ -0,0 +1,80 @@
+package pricing
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// pricerFunc is a Pricer answering with a function
+type pricerFunc func(size int, target string) (string, error)
+
+func (f pricerFunc) GetTransactionPrice(size int, target string) (string, error) {
+	return f(size, target)
+}
+
+func newCoinGecko(t *testing.T) *CoinGecko {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "/simple/price", r.URL.Path)
+		assert.Equal(t, "arweave", r.URL.Query().Get("ids"))
+		switch r.URL.Query().Get("vs_currencies") {
+		case "usd":
+			_, _ = w.Write([]byte(`{"arweave":{"usd":8}}`))
+		case "busy":
+			w.WriteHeader(http.StatusTooManyRequests)
+		default:
+			_, _ = w.Write([]byte(`{"arweave":{}}`))
+		}
+	}))
+	t.Cleanup(server.Close)
+	g := NewCoinGecko()
+	g.URL = server.URL
+	return g
+}
+
+func TestCoinGecko(t *testing.T) {
+	g := newCoinGecko(t)
+
+	rate, err := g.Rate("USD")
+	require.NoError(t, err)
+	assert.Equal(t, 8.0, rate)
+
+	_, err = g.Rate("xyz")
+	assert.ErrorIs(t, err, goar.ErrDecode)
+
+	_, err = g.Rate("busy")
+	assert.ErrorIs(t, err, goar.ErrRateLimited)
+}
+
+func TestOracle(t *testing.T) {
+	oracle := &Oracle{Pricer: pricerFunc(func(size int, target string) (string, error) {
+		assert.Empty(t, target)
+		return "250000000000", nil
+	})}
+
+	estimate, err := oracle.Estimate(1000, "usd")
+	require.NoError(t, err)
+	assert.Equal(t, "0.25", estimate.Fee.AR())
+	assert.Empty(t, estimate.Currency)
+	assert.Equal(t, 250000000.0, estimate.PerByte())
+
+	oracle.Rates = newCoinGecko(t)
+	estimate, err = oracle.Estimate(1000, "usd")
+	require.NoError(t, err)
+	assert.Equal(t, "usd", estimate.Currency)
+	assert.Equal(t, 8.0, estimate.Rate)
+	assert.InDelta(t, 2.0, estimate.Value, 1e-9)
+
+	_, err = oracle.Estimate(1000, "xyz")
+	assert.ErrorIs(t, err, goar.ErrDecode)
+
+	t.Run("Invalid fee", func(t *testing.T) {
+		oracle := &Oracle{Pricer: pricerFunc(func(int, string) (string, error) { return "1e12", nil })}
+		_, err := oracle.Fee(1)
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
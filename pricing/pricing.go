@@ -0,0 +1,153 @@
+// Package pricing estimates the cost of storing data on Arweave.
+//
+// Fees are quoted by the gateway per byte uploaded, so the size that
+// matters is the size of the payload as sent. When data is compressed
+// before upload, or arrives already compressed, both the raw and the
+// compressed sizes are accounted for, and the quote warns when
+// compression does not pay off. A PriceCache reuses fees across many
+// estimates until the network moves to a new block.
+//
+// Amounts are Winston values rather than strings, converted to and from AR
+// with ParseAR and AR. An Oracle values fees in other currencies with the
+// exchange rates of a RateSource such as CoinGecko. BlockHistory records
+// the reward pool, weave size and difficulty of recent blocks for fee
+// trend analysis.
+//
+// Example usage:
+//
+//	quote, err := pricing.GetQuote(client.New("https://arweave.net"), data, "")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, w := range quote.Warnings {
+//		log.Println(w)
+//	}
+//	fmt.Printf("Raw: %s winston, compressed: %s winston\n", quote.RawFee, quote.CompressedFee)
+package pricing
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// MIN_COMPRESSION_SAVING is the fraction of the raw size compression must save to be worthwhile (5%)
+const MIN_COMPRESSION_SAVING = 0.05
+
+// Sizes accounts for the raw and gzip-compressed sizes of a payload.
+type Sizes struct {
+	Raw           int64 // Size of the uncompressed data in bytes
+	Compressed    int64 // Size of the gzip-compressed data in bytes
+	PreCompressed bool  // Whether the input was already gzip-compressed
+}
+
+// Measure returns the raw and compressed sizes of data.
+//
+// If data is already gzip-compressed it is decompressed to measure the raw
+// size; otherwise it is compressed to measure the compressed size.
+//
+// Returns the sizes, or an error if gzip data is corrupted.
+func Measure(data []byte) (*Sizes, error) {
+	if IsGzip(data) {
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		raw, err := io.Copy(io.Discard, r)
+		if err != nil {
+			return nil, err
+		}
+		return &Sizes{Raw: raw, Compressed: int64(len(data)), PreCompressed: true}, nil
+	}
+
+	compressed, err := Compress(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Sizes{Raw: int64(len(data)), Compressed: int64(len(compressed))}, nil
+}
+
+// Saving returns the fraction of the raw size saved by compression.
+// It is negative when compression increases the size.
+func (s *Sizes) Saving() float64 {
+	if s.Raw == 0 {
+		return 0
+	}
+	return 1 - float64(s.Compressed)/float64(s.Raw)
+}
+
+// Worthwhile reports whether compression saves at least MIN_COMPRESSION_SAVING of the raw size.
+func (s *Sizes) Worthwhile() bool {
+	return s.Saving() >= MIN_COMPRESSION_SAVING
+}
+
+// Warnings describes size accounting issues worth surfacing to the user.
+func (s *Sizes) Warnings() []string {
+	warnings := []string{}
+	switch {
+	case s.Compressed > s.Raw && s.PreCompressed:
+		warnings = append(warnings, fmt.Sprintf("data is gzip-compressed but %d bytes larger than uncompressed; upload it uncompressed", s.Compressed-s.Raw))
+	case s.Compressed > s.Raw:
+		warnings = append(warnings, fmt.Sprintf("compression increases size by %d bytes", s.Compressed-s.Raw))
+	case !s.Worthwhile() && s.Raw > 0:
+		warnings = append(warnings, fmt.Sprintf("compression saves only %.1f%%", 100*s.Saving()))
+	}
+	return warnings
+}
+
+// Quote is a fee estimate for both the raw and the compressed payload.
+type Quote struct {
+	Sizes
+	RawFee        string   // Fee in winston to store the raw data
+	CompressedFee string   // Fee in winston to store the compressed data
+	Warnings      []string // Size accounting warnings, see Sizes.Warnings
+}
+
+// GetQuote measures data and asks the gateway for the fee of both sizes.
+//
+// Parameters:
+//   - c: The client or PriceCache used to query prices
+//   - data: The payload, compressed or not
+//   - target: Optional target address (use empty string if not applicable)
+//
+// Returns the quote, or an error if the data cannot be measured or a price
+// request fails.
+func GetQuote(c Pricer, data []byte, target string) (*Quote, error) {
+	sizes, err := Measure(data)
+	if err != nil {
+		return nil, err
+	}
+	rawFee, err := c.GetTransactionPrice(int(sizes.Raw), target)
+	if err != nil {
+		return nil, err
+	}
+	compressedFee, err := c.GetTransactionPrice(int(sizes.Compressed), target)
+	if err != nil {
+		return nil, err
+	}
+	return &Quote{
+		Sizes:         *sizes,
+		RawFee:        rawFee,
+		CompressedFee: compressedFee,
+		Warnings:      sizes.Warnings(),
+	}, nil
+}
+
+// Compress gzip-compresses data at the default compression level.
+func Compress(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	w := gzip.NewWriter(&buf)
+	if _, err := w.Write(data); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// IsGzip reports whether data starts with the gzip magic number.
+func IsGzip(data []byte) bool {
+	return len(data) >= 2 && data[0] == 0x1f && data[1] == 0x8b
+}
@@ -0,0 +1,145 @@
+package pricing
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestMeasure verifies raw and compressed size accounting
+func TestMeasure(t *testing.T) {
+	text := bytes.Repeat([]byte("arweave "), 1000)
+
+	t.Run("Compressible", func(t *testing.T) {
+		sizes, err := Measure(text)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(text)), sizes.Raw)
+		assert.Less(t, sizes.Compressed, sizes.Raw)
+		assert.False(t, sizes.PreCompressed)
+		assert.True(t, sizes.Worthwhile())
+		assert.Empty(t, sizes.Warnings())
+	})
+
+	t.Run("Pre-compressed", func(t *testing.T) {
+		compressed, err := Compress(text)
+		require.NoError(t, err)
+		sizes, err := Measure(compressed)
+		require.NoError(t, err)
+		assert.True(t, sizes.PreCompressed)
+		assert.Equal(t, int64(len(text)), sizes.Raw)
+		assert.Equal(t, int64(len(compressed)), sizes.Compressed)
+	})
+
+	t.Run("Incompressible", func(t *testing.T) {
+		random := make([]byte, 4096)
+		_, err := rand.Read(random)
+		require.NoError(t, err)
+		sizes, err := Measure(random)
+		require.NoError(t, err)
+		assert.Greater(t, sizes.Compressed, sizes.Raw)
+		assert.False(t, sizes.Worthwhile())
+		require.Len(t, sizes.Warnings(), 1)
+		assert.Contains(t, sizes.Warnings()[0], "compression increases size")
+
+		compressed, err := Compress(random)
+		require.NoError(t, err)
+		sizes, err = Measure(compressed)
+		require.NoError(t, err)
+		assert.Contains(t, sizes.Warnings()[0], "upload it uncompressed")
+	})
+
+	t.Run("Corrupted gzip", func(t *testing.T) {
+		_, err := Measure([]byte{0x1f, 0x8b, 0, 0})
+		assert.Error(t, err)
+	})
+
+	t.Run("Empty", func(t *testing.T) {
+		sizes, err := Measure(nil)
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, sizes.Saving())
+	})
+}
+
+// TestGetQuote verifies that fees are quoted for both sizes
+func TestGetQuote(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		size, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/price/"))
+		require.NoError(t, err)
+		fmt.Fprint(w, size*10)
+	}))
+	defer server.Close()
+
+	text := bytes.Repeat([]byte("arweave "), 1000)
+	quote, err := GetQuote(client.New(server.URL), text, "")
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(len(text)*10), quote.RawFee)
+	assert.Equal(t, strconv.FormatInt(quote.Compressed*10, 10), quote.CompressedFee)
+	assert.Empty(t, quote.Warnings)
+}
+
+// TestPriceCache verifies bucketing and invalidation on new blocks
+func TestPriceCache(t *testing.T) {
+	var height, prices atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/info" {
+			fmt.Fprintf(w, `{"height": %d}`, height.Load())
+			return
+		}
+		prices.Add(1)
+		size, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/price/"))
+		require.NoError(t, err)
+		fmt.Fprint(w, size*10+int(height.Load()))
+	}))
+	defer server.Close()
+
+	cache := NewPriceCache(client.New(server.URL))
+	cache.HeightCheck = 0
+	height.Store(1)
+
+	fee, err := cache.GetTransactionPrice(1, "")
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(DEFAULT_BUCKET_SIZE*10+1), fee)
+	assert.Equal(t, int64(1), cache.Height())
+
+	// Same bucket
+	fee, err = cache.GetTransactionPrice(DEFAULT_BUCKET_SIZE, "")
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(DEFAULT_BUCKET_SIZE*10+1), fee)
+	assert.Equal(t, int32(1), prices.Load())
+
+	// Next bucket
+	_, err = cache.GetTransactionPrice(DEFAULT_BUCKET_SIZE+1, "")
+	require.NoError(t, err)
+	assert.Equal(t, int32(2), prices.Load())
+
+	// New block
+	height.Store(2)
+	fee, err = cache.GetTransactionPrice(10, "")
+	require.NoError(t, err)
+	assert.Equal(t, strconv.Itoa(DEFAULT_BUCKET_SIZE*10+2), fee)
+	assert.Equal(t, int32(3), prices.Load())
+
+	// The height is not checked again before HeightCheck
+	cache.HeightCheck = time.Hour
+	height.Store(3)
+	_, err = cache.GetTransactionPrice(10, "")
+	require.NoError(t, err)
+	assert.Equal(t, int32(3), prices.Load())
+	assert.Equal(t, int64(2), cache.Height())
+
+	// GetQuote accepts the cache
+	quote, err := GetQuote(cache, []byte("arweave"), "")
+	require.NoError(t, err)
+	assert.Equal(t, quote.RawFee, quote.CompressedFee)
+}
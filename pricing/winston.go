@@ -0,0 +1,168 @@
+package pricing
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/transaction"
+)
+
+// WINSTON_PER_AR is the number of winston in one AR
+const WINSTON_PER_AR = 1_000_000_000_000
+
+// AR_DECIMALS is the number of decimal places of AR amounts
+const AR_DECIMALS = 12
+
+var winstonPerAR = big.NewInt(WINSTON_PER_AR)
+
+// Winston is an amount of winston, the smallest unit of AR.
+//
+// Winston values are immutable: arithmetic returns new values, so they can
+// be copied and shared freely. The zero value is 0 winston. Amounts
+// marshal to and from JSON and text as winston strings, the format of
+// transaction quantities and rewards.
+type Winston struct {
+	v *big.Int
+}
+
+// NewWinston returns n winston.
+func NewWinston(n int64) Winston {
+	return Winston{big.NewInt(n)}
+}
+
+// WinstonFromBig returns the amount of winston in n, which is copied.
+func WinstonFromBig(n *big.Int) Winston {
+	return Winston{new(big.Int).Set(n)}
+}
+
+// ParseWinston parses an amount of winston such as a transaction reward, see transaction.ParseWinston.
+//
+// Returns an error with code goar.ErrInvalidInput if s is not a whole
+// non-negative number in canonical form.
+func ParseWinston(s string) (Winston, error) {
+	n, err := transaction.ParseWinston(s)
+	if err != nil {
+		return Winston{}, err
+	}
+	return Winston{n}, nil
+}
+
+// ParseAR parses an amount of AR with up to AR_DECIMALS decimal places, such as "1.5" or "0.000000000001".
+//
+// Returns an error with code goar.ErrInvalidInput if s is negative, has
+// more than AR_DECIMALS decimal places, or is not a plain decimal number,
+// e.g. uses an exponent.
+//
+// Example:
+//
+//	fee, err := pricing.ParseAR("0.25")
+//	fmt.Println(fee) // 250000000000
+func ParseAR(s string) (Winston, error) {
+	whole, fraction, _ := strings.Cut(s, ".")
+	if len(fraction) > AR_DECIMALS || (whole == "" && fraction == "") || !isDigits(whole) || !isDigits(fraction) {
+		return Winston{}, goar.Errorf(goar.ErrInvalidInput, "invalid AR amount %q", s)
+	}
+	digits := strings.TrimLeft(whole+fraction+strings.Repeat("0", AR_DECIMALS-len(fraction)), "0")
+	n, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		n = new(big.Int)
+	}
+	return Winston{n}, nil
+}
+
+// value returns the amount, never nil
+func (w Winston) value() *big.Int {
+	if w.v == nil {
+		return new(big.Int)
+	}
+	return w.v
+}
+
+// Int returns a copy of the amount as a big.Int.
+func (w Winston) Int() *big.Int {
+	return new(big.Int).Set(w.value())
+}
+
+// String returns the amount in winston, e.g. "1500000000000".
+func (w Winston) String() string {
+	return w.value().String()
+}
+
+// AR returns the amount in AR with the decimal places it needs, e.g. "1.5" or "0.000000000001".
+func (w Winston) AR() string {
+	n := w.value()
+	sign := ""
+	if n.Sign() < 0 {
+		sign = "-"
+		n = new(big.Int).Neg(n)
+	}
+	whole, fraction := new(big.Int).QuoRem(n, winstonPerAR, new(big.Int))
+	if fraction.Sign() == 0 {
+		return sign + whole.String()
+	}
+	decimals := strings.TrimRight(fmt.Sprintf("%0*s", AR_DECIMALS, fraction.String()), "0")
+	return sign + whole.String() + "." + decimals
+}
+
+// Float64 returns the amount in AR as a float64, for display and exchange rate conversions.
+func (w Winston) Float64() float64 {
+	f, _ := new(big.Rat).SetFrac(w.value(), winstonPerAR).Float64()
+	return f
+}
+
+// Add returns w + o.
+func (w Winston) Add(o Winston) Winston {
+	return Winston{new(big.Int).Add(w.value(), o.value())}
+}
+
+// Sub returns w - o, which is negative if o is larger.
+func (w Winston) Sub(o Winston) Winston {
+	return Winston{new(big.Int).Sub(w.value(), o.value())}
+}
+
+// Mul returns w * n, e.g. the fee of n items of the same size.
+func (w Winston) Mul(n int64) Winston {
+	return Winston{new(big.Int).Mul(w.value(), big.NewInt(n))}
+}
+
+// Cmp returns -1, 0 or +1 as w is less than, equal to or greater than o.
+func (w Winston) Cmp(o Winston) int {
+	return w.value().Cmp(o.value())
+}
+
+// Sign returns -1, 0 or +1 as w is negative, zero or positive.
+func (w Winston) Sign() int {
+	return w.value().Sign()
+}
+
+// IsZero reports whether the amount is 0.
+func (w Winston) IsZero() bool {
+	return w.Sign() == 0
+}
+
+// MarshalText returns the amount in winston.
+func (w Winston) MarshalText() ([]byte, error) {
+	return []byte(w.String()), nil
+}
+
+// UnmarshalText parses an amount in winston, see ParseWinston.
+func (w *Winston) UnmarshalText(text []byte) error {
+	parsed, err := ParseWinston(string(text))
+	if err != nil {
+		return err
+	}
+	*w = parsed
+	return nil
+}
+
+// isDigits reports whether s only holds decimal digits
+func isDigits(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,88 @@
+package pricing
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseAR(t *testing.T) {
+	for s, expected := range map[string]string{
+		"1":              "1000000000000",
+		"1.5":            "1500000000000",
+		"0.25":           "250000000000",
+		".5":             "500000000000",
+		"2.":             "2000000000000",
+		"0":              "0",
+		"0.000000000001": "1",
+		"007.0100":       "7010000000000",
+	} {
+		w, err := ParseAR(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, w.String(), s)
+	}
+	for _, s := range []string{"", ".", "-1", "+1", "1e3", "1.0000000000001", "1,5", "1.2.3", " 1"} {
+		_, err := ParseAR(s)
+		assert.ErrorIs(t, err, goar.ErrInvalidInput, s)
+	}
+}
+
+func TestWinston(t *testing.T) {
+	t.Run("AR", func(t *testing.T) {
+		for winston, ar := range map[string]string{
+			"0":                "0",
+			"1":                "0.000000000001",
+			"1500000000000":    "1.5",
+			"1000000000000":    "1",
+			"1234567890123456": "1234.567890123456",
+		} {
+			w, err := ParseWinston(winston)
+			require.NoError(t, err)
+			assert.Equal(t, ar, w.AR())
+			parsed, err := ParseAR(ar)
+			require.NoError(t, err)
+			assert.Equal(t, w, parsed)
+		}
+		assert.Equal(t, "-0.5", NewWinston(0).Sub(NewWinston(500000000000)).AR())
+		assert.InDelta(t, 1.5, NewWinston(1500000000000).Float64(), 1e-12)
+	})
+
+	t.Run("Arithmetic", func(t *testing.T) {
+		var zero Winston
+		assert.True(t, zero.IsZero())
+		assert.Equal(t, "0", zero.String())
+
+		a, b := NewWinston(700), NewWinston(300)
+		assert.Equal(t, "1000", a.Add(b).String())
+		assert.Equal(t, "400", a.Sub(b).String())
+		assert.Equal(t, -1, b.Sub(a).Sign())
+		assert.Equal(t, "2100", a.Mul(3).String())
+		assert.Equal(t, 1, a.Cmp(b))
+		assert.Equal(t, 0, a.Cmp(a.Add(zero)))
+		assert.Equal(t, "700", a.String(), "operands are not modified")
+
+		n := big.NewInt(5)
+		w := WinstonFromBig(n)
+		n.SetInt64(6)
+		assert.Equal(t, "5", w.String())
+		w.Int().SetInt64(7)
+		assert.Equal(t, "5", w.String())
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		var v struct {
+			Fee Winston `json:"fee"`
+		}
+		require.NoError(t, json.Unmarshal([]byte(`{"fee":"1500"}`), &v))
+		assert.Equal(t, "1500", v.Fee.String())
+		b, err := json.Marshal(v)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"fee":"1500"}`, string(b))
+
+		assert.ErrorIs(t, json.Unmarshal([]byte(`{"fee":"1e3"}`), &v), goar.ErrInvalidInput)
+	})
+}
@@ -0,0 +1,122 @@
+// Package profile resolves the account profiles attached to Arweave addresses.
+//
+// Profiles follow the Account protocol used by ArProfile: the owner of an
+// address uploads a JSON document tagged with Protocol-Name: Account-0.3,
+// and the most recent one is the current profile. Get finds it through the
+// gateway's GraphQL endpoint and fetches its data.
+//
+// Example usage:
+//
+//	c := client.New("https://arweave.net")
+//	p, err := profile.Get(c, address)
+//	if errors.Is(err, goar.ErrNotFound) {
+//		// The address has no profile
+//	}
+//	fmt.Printf("%s (@%s) %s\n", p.Name, p.Handle, p.AvatarURL(c.Gateway))
+package profile
+
+import (
+	"encoding/json"
+	"strings"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+)
+
+// PROTOCOL_NAME is the Protocol-Name tag value of profile documents
+const PROTOCOL_NAME = "Account-0.3"
+
+// Profile is the account data published by an address.
+type Profile struct {
+	ID      string            `json:"-"`      // ID of the transaction or data item holding the profile
+	Address string            `json:"-"`      // Address the profile belongs to
+	Handle  string            `json:"handle"` // Unique user handle, without the @
+	Name    string            `json:"name"`   // Display name
+	Bio     string            `json:"bio"`    // Free-form description
+	Avatar  string            `json:"avatar"` // Avatar image as an ar:// URL or transaction ID, empty if unset
+	Banner  string            `json:"banner"` // Banner image as an ar:// URL or transaction ID, empty if unset
+	Links   map[string]string `json:"links"`  // Links by service name, e.g. "twitter" or "github"
+}
+
+const query = `query($owner: String!, $protocol: String!) {
+  transactions(owners: [$owner], tags: [{name: "Protocol-Name", values: [$protocol]}], sort: HEIGHT_DESC, first: 1) {
+    edges { node { id } }
+  }
+}`
+
+type queryResult struct {
+	Transactions struct {
+		Edges []struct {
+			Node struct {
+				ID string `json:"id"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"transactions"`
+}
+
+// Get retrieves the current profile of address.
+//
+// Parameters:
+//   - c: The client used to query the gateway
+//   - address: The Arweave address of the account
+//
+// Returns the profile, an error with code goar.ErrNotFound if the address
+// has not published one, or an error if a request fails or the profile
+// data is malformed.
+func Get(c *client.Client, address string) (*Profile, error) {
+	var result queryResult
+	err := c.GraphQL(query, map[string]any{"owner": address, "protocol": PROTOCOL_NAME}, &result)
+	if err != nil {
+		return nil, err
+	}
+	if len(result.Transactions.Edges) == 0 {
+		return nil, goar.Errorf(goar.ErrNotFound, "no profile for %s", address)
+	}
+
+	id := result.Transactions.Edges[0].Node.ID
+	data, err := c.GetTransactionData(id)
+	if err != nil {
+		return nil, err
+	}
+	p, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	p.ID = id
+	p.Address = address
+	return p, nil
+}
+
+// Parse decodes a profile document.
+//
+// Returns the profile, or an error with code goar.ErrDecode if data is not
+// a JSON object.
+func Parse(data []byte) (*Profile, error) {
+	p := &Profile{}
+	if err := json.Unmarshal(data, p); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	return p, nil
+}
+
+// AvatarURL returns the URL of the avatar image on gateway, or an empty string if there is none.
+func (p *Profile) AvatarURL(gateway string) string {
+	return resolve(gateway, p.Avatar)
+}
+
+// BannerURL returns the URL of the banner image on gateway, or an empty string if there is none.
+func (p *Profile) BannerURL(gateway string) string {
+	return resolve(gateway, p.Banner)
+}
+
+// resolve turns an ar:// URL or a bare transaction ID into a gateway URL
+func resolve(gateway string, ref string) string {
+	id := strings.TrimPrefix(ref, "ar://")
+	if id == "" {
+		return ""
+	}
+	if strings.Contains(id, "://") {
+		return ref
+	}
+	return strings.TrimSuffix(gateway, "/") + "/" + id
+}
@@ -0,0 +1,84 @@
+package profile
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	address   = "vLRHFqCw1uHu75xqB4fCDW-QxpkpJxBtFD9g4QYUbfw"
+	profileID = "Rt2qFRMzuZoAfY5bGB4W5fk8DkxV6Omfb3ZV2QxOtTQ"
+)
+
+// newGateway serves one profile for address through /graphql and its data by ID
+func newGateway(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/graphql":
+			body, err := io.ReadAll(r.Body)
+			require.NoError(t, err)
+			var req struct {
+				Variables map[string]string `json:"variables"`
+			}
+			require.NoError(t, json.Unmarshal(body, &req))
+			assert.Equal(t, PROTOCOL_NAME, req.Variables["protocol"])
+			if req.Variables["owner"] != address {
+				_, _ = w.Write([]byte(`{"data": {"transactions": {"edges": []}}}`))
+				return
+			}
+			_, _ = w.Write([]byte(`{"data": {"transactions": {"edges": [{"node": {"id": "` + profileID + `"}}]}}}`))
+		case "/" + profileID:
+			_, _ = w.Write([]byte(`{
+				"handle": "goar",
+				"name": "Goar",
+				"bio": "Arweave in Go",
+				"avatar": "ar://7Lh8lqTy2o8hMQ2YwIGT6Ef9oBD0lmOizVNT5IjIoHA",
+				"banner": "ar://",
+				"links": {"github": "liteseed"}
+			}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestGet(t *testing.T) {
+	server := newGateway(t)
+	c := client.New(server.URL)
+
+	t.Run("Found", func(t *testing.T) {
+		p, err := Get(c, address)
+		require.NoError(t, err)
+		assert.Equal(t, profileID, p.ID)
+		assert.Equal(t, address, p.Address)
+		assert.Equal(t, "goar", p.Handle)
+		assert.Equal(t, "Goar", p.Name)
+		assert.Equal(t, "liteseed", p.Links["github"])
+		assert.Equal(t, server.URL+"/7Lh8lqTy2o8hMQ2YwIGT6Ef9oBD0lmOizVNT5IjIoHA", p.AvatarURL(server.URL))
+		assert.Empty(t, p.BannerURL(server.URL))
+	})
+
+	t.Run("Not found", func(t *testing.T) {
+		_, err := Get(c, "unknown")
+		assert.ErrorIs(t, err, goar.ErrNotFound)
+	})
+}
+
+func TestParse(t *testing.T) {
+	_, err := Parse([]byte("not json"))
+	assert.ErrorIs(t, err, goar.ErrDecode)
+
+	p, err := Parse([]byte(`{"handle": "goar", "avatar": "https://example.com/a.png"}`))
+	require.NoError(t, err)
+	assert.Equal(t, "https://example.com/a.png", p.AvatarURL("https://arweave.net"))
+}
@@ -0,0 +1,208 @@
+// Package sampler estimates the availability of transaction data on the network.
+//
+// Instead of downloading and auditing a whole dataset, the sampler picks K
+// random byte offsets of a transaction, fetches the chunks covering them from
+// several peers, and validates each chunk against the transaction's data_root.
+// The fraction of offsets that could be retrieved and verified is a cheap,
+// statistical availability score suitable for monitoring many large datasets.
+//
+// Example usage:
+//
+//	s := sampler.New([]*client.Client{
+//		client.New("https://arweave.net"),
+//		client.New("https://ar-io.net"),
+//	}, 16)
+//
+//	report, err := s.Sample("txid...")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Availability: %.2f\n", report.Score)
+package sampler
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"strconv"
+	"time"
+
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction"
+)
+
+// DEFAULT_SAMPLES is the number of offsets sampled when none is configured
+const DEFAULT_SAMPLES = 16
+
+// Sampler fetches and validates random chunks of a transaction from a set of peers.
+type Sampler struct {
+	Peers   []*client.Client // Peers the chunks are fetched from
+	Samples int              // Number of random offsets (K) to sample per transaction
+	Rand    *rand.Rand       // Source of randomness for picking offsets
+}
+
+// Result is the outcome of fetching one sampled offset from one peer.
+type Result struct {
+	Offset int64  // Sampled offset, relative to the start of the transaction data
+	Peer   string // Gateway the chunk was requested from
+	Valid  bool   // Whether the chunk was retrieved and validated
+	Error  string // Why the chunk could not be retrieved or validated
+}
+
+// Report summarizes the availability of a transaction's data.
+type Report struct {
+	ID         string             // Transaction ID
+	DataSize   int64              // Size of the transaction data in bytes
+	Offsets    []int64            // Sampled offsets, relative to the start of the data
+	Results    []Result           // One result per sampled offset and peer
+	Available  int                // Number of offsets served and validated by at least one peer
+	Score      float64            // Available / len(Offsets)
+	PeerScores map[string]float64 // Fraction of offsets each peer served and validated
+}
+
+// New creates a Sampler over the given peers, sampling k offsets per transaction.
+//
+// If k is not positive, DEFAULT_SAMPLES is used.
+func New(peers []*client.Client, k int) *Sampler {
+	if k <= 0 {
+		k = DEFAULT_SAMPLES
+	}
+	return &Sampler{
+		Peers:   peers,
+		Samples: k,
+		Rand:    rand.New(rand.NewSource(time.Now().UnixNano())),
+	}
+}
+
+// Sample checks the availability of the data of the transaction with the given ID.
+//
+// The transaction header and its weave offset are taken from the first peer
+// that can serve them. Every sampled offset is then requested from every
+// peer, and each returned chunk is validated against the data_root.
+//
+// Returns a Report, or an error if no peer knows the transaction.
+func (s *Sampler) Sample(id string) (*Report, error) {
+	if len(s.Peers) == 0 {
+		return nil, errors.New("no peers configured")
+	}
+
+	tx, txOffset, err := s.locate(id)
+	if err != nil {
+		return nil, err
+	}
+	dataRoot, err := crypto.Base64URLDecode(tx.DataRoot)
+	if err != nil {
+		return nil, err
+	}
+	dataSize, err := strconv.ParseInt(tx.DataSize, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	if dataSize <= 0 {
+		return nil, fmt.Errorf("transaction %s has no data", id)
+	}
+
+	report := &Report{
+		ID:         id,
+		DataSize:   dataSize,
+		Offsets:    s.pickOffsets(dataSize),
+		PeerScores: map[string]float64{},
+	}
+	start := txOffset.Offset - txOffset.Size + 1
+
+	for _, offset := range report.Offsets {
+		available := false
+		for _, peer := range s.Peers {
+			result := Result{Offset: offset, Peer: peer.Gateway}
+			if err := fetchAndValidate(peer, start+offset, offset, dataRoot, dataSize); err != nil {
+				result.Error = err.Error()
+			} else {
+				result.Valid = true
+				available = true
+				report.PeerScores[peer.Gateway]++
+			}
+			report.Results = append(report.Results, result)
+		}
+		if available {
+			report.Available++
+		}
+	}
+
+	for _, peer := range s.Peers {
+		report.PeerScores[peer.Gateway] /= float64(len(report.Offsets))
+	}
+	report.Score = float64(report.Available) / float64(len(report.Offsets))
+	return report, nil
+}
+
+// Confidence returns the probability that this report would have detected
+// a loss of at least the given fraction of the data.
+//
+// With n independently sampled offsets, a dataset missing a fraction f of
+// its bytes passes every sample with probability (1-f)^n.
+//
+// Example:
+//
+//	// How sure are we that less than 1% of the data is missing?
+//	fmt.Printf("%.4f\n", report.Confidence(0.01))
+func (r *Report) Confidence(missingFraction float64) float64 {
+	return 1 - math.Pow(1-missingFraction, float64(len(r.Offsets)))
+}
+
+// locate finds the transaction header and its weave offset on the first peer that has them.
+func (s *Sampler) locate(id string) (*transaction.Transaction, *transaction.TransactionOffset, error) {
+	var errs []error
+	for _, peer := range s.Peers {
+		tx, err := peer.GetTransactionByID(id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		offset, err := peer.GetTransactionOffset(id)
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		return tx, offset, nil
+	}
+	return nil, nil, fmt.Errorf("unable to locate transaction %s: %w", id, errors.Join(errs...))
+}
+
+// pickOffsets selects distinct random offsets within the data.
+func (s *Sampler) pickOffsets(dataSize int64) []int64 {
+	k := int64(s.Samples)
+	if k > dataSize {
+		k = dataSize
+	}
+	seen := make(map[int64]bool, k)
+	offsets := make([]int64, 0, k)
+	for int64(len(offsets)) < k {
+		offset := s.Rand.Int63n(dataSize)
+		if seen[offset] {
+			continue
+		}
+		seen[offset] = true
+		offsets = append(offsets, offset)
+	}
+	return offsets
+}
+
+// fetchAndValidate downloads the chunk at an absolute weave offset and checks it against the data root.
+func fetchAndValidate(peer *client.Client, absolute int64, relative int64, dataRoot []byte, dataSize int64) error {
+	chunk, err := peer.GetChunk(absolute)
+	if err != nil {
+		return err
+	}
+	rawChunk, err := crypto.Base64URLDecode(chunk.Chunk)
+	if err != nil {
+		return err
+	}
+	dataPath, err := crypto.Base64URLDecode(chunk.DataPath)
+	if err != nil {
+		return err
+	}
+	_, err = transaction.ValidateChunk(dataRoot, int(relative), int(dataSize), rawChunk, dataPath)
+	return err
+}
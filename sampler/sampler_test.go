@@ -0,0 +1,126 @@
+package sampler
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/rand"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const weaveStart = 5000000
+
+// newPeer serves the chunks of tx from a fake node. Chunks listed in missing are answered with 404.
+func newPeer(t *testing.T, tx *transaction.Transaction, data []byte, missing map[int]bool) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/tx/"+tx.ID:
+			_ = json.NewEncoder(w).Encode(tx)
+		case r.URL.Path == "/tx/"+tx.ID+"/offset":
+			fmt.Fprintf(w, `{"size":"%d","offset":"%d"}`, len(data), weaveStart+len(data)-1)
+		case strings.HasPrefix(r.URL.Path, "/chunk/"):
+			absolute, err := strconv.Atoi(strings.TrimPrefix(r.URL.Path, "/chunk/"))
+			require.NoError(t, err)
+			relative := absolute - weaveStart
+			for i, c := range tx.ChunkData.Chunks {
+				if relative >= c.MinByteRange && relative < c.MaxByteRange {
+					if missing[i] {
+						break
+					}
+					_ = json.NewEncoder(w).Encode(transaction.TransactionChunk{
+						Chunk:    crypto.Base64URLEncode(data[c.MinByteRange:c.MaxByteRange]),
+						DataPath: crypto.Base64URLEncode(tx.ChunkData.Proofs[i].Proof),
+					})
+					return
+				}
+			}
+			http.NotFound(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+func TestSample(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	tx := transaction.New(data, "", "0", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	tx.ID = "sampled"
+
+	t.Run("Fully available", func(t *testing.T) {
+		peer := newPeer(t, tx, data, nil)
+		defer peer.Close()
+
+		s := New([]*client.Client{client.New(peer.URL)}, 8)
+		report, err := s.Sample(tx.ID)
+		require.NoError(t, err)
+
+		assert.Equal(t, int64(len(data)), report.DataSize)
+		assert.Len(t, report.Offsets, 8)
+		assert.Len(t, report.Results, 8)
+		assert.Equal(t, 8, report.Available)
+		assert.Equal(t, 1.0, report.Score)
+		assert.Equal(t, 1.0, report.PeerScores[peer.URL])
+	})
+
+	t.Run("Partially available across peers", func(t *testing.T) {
+		healthy := newPeer(t, tx, data, nil)
+		defer healthy.Close()
+		broken := newPeer(t, tx, data, map[int]bool{0: true, 1: true, 2: true, 3: true})
+		defer broken.Close()
+
+		s := New([]*client.Client{client.New(broken.URL), client.New(healthy.URL)}, 8)
+		s.Rand = rand.New(rand.NewSource(1))
+		report, err := s.Sample(tx.ID)
+		require.NoError(t, err)
+
+		assert.Len(t, report.Results, 16)
+		assert.Equal(t, 1.0, report.Score)
+		assert.Equal(t, 1.0, report.PeerScores[healthy.URL])
+		assert.Less(t, report.PeerScores[broken.URL], 1.0)
+	})
+
+	t.Run("Unavailable", func(t *testing.T) {
+		peer := newPeer(t, tx, data, map[int]bool{0: true, 1: true, 2: true, 3: true})
+		defer peer.Close()
+
+		report, err := New([]*client.Client{client.New(peer.URL)}, 4).Sample(tx.ID)
+		require.NoError(t, err)
+		assert.Equal(t, 0.0, report.Score)
+		for _, r := range report.Results {
+			assert.False(t, r.Valid)
+			assert.NotEmpty(t, r.Error)
+		}
+	})
+
+	t.Run("Unknown transaction", func(t *testing.T) {
+		peer := newPeer(t, tx, data, nil)
+		defer peer.Close()
+
+		_, err := New([]*client.Client{client.New(peer.URL)}, 4).Sample("unknown")
+		assert.Error(t, err)
+	})
+
+	t.Run("No peers", func(t *testing.T) {
+		_, err := New(nil, 4).Sample(tx.ID)
+		assert.Error(t, err)
+	})
+}
+
+func TestConfidence(t *testing.T) {
+	r := &Report{Offsets: make([]int64, 10)}
+	assert.InDelta(t, 1-0.9*0.9*0.9*0.9*0.9*0.9*0.9*0.9*0.9*0.9, r.Confidence(0.1), 1e-9)
+	assert.Equal(t, 0.0, r.Confidence(0))
+}
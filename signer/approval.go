@@ -0,0 +1,79 @@
+package signer
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/tag"
+)
+
+// Kinds of payloads submitted for approval
+const (
+	KindTransaction = "transaction" // A layer 1 Arweave transaction
+	KindDataItem    = "data_item"   // An ANS-104 data item
+	KindMultisig    = "multisig"    // An approval of a multisig envelope, see transaction/multisig
+)
+
+// SigningRequest summarizes a payload that is about to be signed.
+//
+// It is passed to the signer's Approver before any signature is produced,
+// so that a human or a policy engine can review outgoing payloads.
+type SigningRequest struct {
+	Kind     string    // KindTransaction, KindDataItem or KindMultisig
+	Address  string    // Address of the signing wallet
+	Target   string    // Recipient address, empty if none
+	Quantity string    // Amount of AR transferred in Winston units ("0" for data items)
+	Fee      string    // Transaction reward in Winston units ("0" for data items)
+	DataSize int64     // Size of the data in bytes
+	Tags     []tag.Tag // Decoded tags
+}
+
+// Approver reviews signing requests before they are signed.
+//
+// Approve may block, for example while waiting for a human decision. It
+// returns nil to allow the signature, or an error to deny it.
+type Approver interface {
+	Approve(req *SigningRequest) error
+}
+
+// ApproverFunc adapts a function to the Approver interface.
+type ApproverFunc func(req *SigningRequest) error
+
+// Approve calls f(req).
+func (f ApproverFunc) Approve(req *SigningRequest) error {
+	return f(req)
+}
+
+// Approve submits req to the signer's Approver.
+//
+// Sign operations in the transaction and data item packages call Approve
+// before signing. It returns nil when the signer has no Approver. A denial
+// is returned as an error carrying goar.ErrSigningDenied.
+//
+// Example:
+//
+//	s.Approver = signer.ApproverFunc(func(req *signer.SigningRequest) error {
+//		if req.Quantity != "0" {
+//			return errors.New("transfers are not allowed")
+//		}
+//		return nil
+//	})
+func (s *Signer) Approve(req *SigningRequest) error {
+	return approve(s.Approver, s.Address, req)
+}
+
+// approve submits req from address to a, which may be nil
+func approve(a Approver, address string, req *SigningRequest) error {
+	if a == nil {
+		return nil
+	}
+	req.Address = address
+	if err := a.Approve(req); err != nil {
+		if errors.Is(err, goar.ErrSigningDenied) {
+			return err
+		}
+		return &goar.Error{Code: goar.ErrSigningDenied, Err: fmt.Errorf("signing denied: %w", err)}
+	}
+	return nil
+}
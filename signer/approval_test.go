@@ -0,0 +1,40 @@
+package signer
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestApprove verifies that signing requests are submitted to the approval hook
+func TestApprove(t *testing.T) {
+	s, err := FromPath("../test/signer.json")
+	require.NoError(t, err)
+
+	t.Run("No approver", func(t *testing.T) {
+		assert.NoError(t, s.Approve(&SigningRequest{Kind: KindTransaction}))
+	})
+
+	t.Run("Approved", func(t *testing.T) {
+		var got *SigningRequest
+		s.Approver = ApproverFunc(func(req *SigningRequest) error {
+			got = req
+			return nil
+		})
+		require.NoError(t, s.Approve(&SigningRequest{Kind: KindDataItem, DataSize: 10}))
+		assert.Equal(t, s.Address, got.Address)
+		assert.Equal(t, int64(10), got.DataSize)
+	})
+
+	t.Run("Denied", func(t *testing.T) {
+		s.Approver = ApproverFunc(func(req *SigningRequest) error {
+			return errors.New("transfers are not allowed")
+		})
+		err := s.Approve(&SigningRequest{Kind: KindTransaction, Quantity: "1"})
+		assert.ErrorIs(t, err, goar.ErrSigningDenied)
+		assert.EqualError(t, err, "signing denied: transfers are not allowed")
+	})
+}
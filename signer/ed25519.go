@@ -0,0 +1,136 @@
+package signer
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/json"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+)
+
+// ED25519Signer signs ANS-104 data items with an Ed25519 key.
+//
+// Data items have signature type 2 (ED25519), or type 4 (Solana) when
+// Solana is set. Both sign the deep hash of the item directly and store the
+// 32-byte public key as owner; the type only tells bundlers which chain the
+// key belongs to. Layer 1 Arweave transactions can only be signed by a Signer.
+type ED25519Signer struct {
+	Address    string             // The base58-encoded public key, which is also the Solana address
+	PrivateKey ed25519.PrivateKey // Ed25519 private key for signing operations
+	Solana     bool               // Sign Solana (type 4) rather than ED25519 (type 2) data items
+	Approver   Approver           // Optional hook that must approve every payload before it is signed
+}
+
+// NewED25519 creates an ED25519Signer with a randomly generated key.
+//
+// Example:
+//
+//	s, err := signer.NewED25519()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Generated ED25519 signer: %s\n", s.Address)
+func NewED25519() (*ED25519Signer, error) {
+	_, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+	return ED25519FromPrivateKey(key), nil
+}
+
+// ED25519FromPrivateKey creates an ED25519Signer from an existing Ed25519 private key.
+func ED25519FromPrivateKey(privateKey ed25519.PrivateKey) *ED25519Signer {
+	publicKey := privateKey.Public().(ed25519.PublicKey)
+	return &ED25519Signer{Address: crypto.Base58Encode(publicKey), PrivateKey: privateKey}
+}
+
+// SolanaFromBase58 creates a Solana signer from a base58-encoded secret key,
+// as exported by Solana wallets: the 64-byte keypair or the 32-byte seed.
+//
+// Returns an error with code goar.ErrInvalidInput if the key is not valid
+// base58 of either length, or if a keypair's public half does not match its seed.
+//
+// Example:
+//
+//	s, err := signer.SolanaFromBase58(os.Getenv("SOLANA_PRIVATE_KEY"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func SolanaFromBase58(key string) (*ED25519Signer, error) {
+	b, err := crypto.Base58Decode(key)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	return solanaFromBytes(b)
+}
+
+// SolanaFromKeypair creates a Solana signer from a keypair file written by
+// solana-keygen, a JSON array of the 64 keypair bytes.
+//
+// Returns an error with code goar.ErrInvalidInput if the file is not a valid keypair.
+//
+// Example:
+//
+//	b, err := os.ReadFile("id.json")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	s, err := signer.SolanaFromKeypair(b)
+func SolanaFromKeypair(b []byte) (*ED25519Signer, error) {
+	var keypair []byte
+	var numbers []int
+	if err := json.Unmarshal(b, &numbers); err != nil {
+		return nil, goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	for _, n := range numbers {
+		if n < 0 || n > 255 {
+			return nil, goar.Errorf(goar.ErrInvalidInput, "invalid keypair byte %d", n)
+		}
+		keypair = append(keypair, byte(n))
+	}
+	return solanaFromBytes(keypair)
+}
+
+// solanaFromBytes creates a Solana signer from a 64-byte keypair or a 32-byte seed
+func solanaFromBytes(b []byte) (*ED25519Signer, error) {
+	var privateKey ed25519.PrivateKey
+	switch len(b) {
+	case ed25519.SeedSize:
+		privateKey = ed25519.NewKeyFromSeed(b)
+	case ed25519.PrivateKeySize:
+		privateKey = ed25519.NewKeyFromSeed(b[:ed25519.SeedSize])
+		if !privateKey.Equal(ed25519.PrivateKey(b)) {
+			return nil, goar.Errorf(goar.ErrInvalidInput, "keypair public key does not match its secret key")
+		}
+	default:
+		return nil, goar.Errorf(goar.ErrInvalidInput, "invalid secret key length %d", len(b))
+	}
+	s := ED25519FromPrivateKey(privateKey)
+	s.Solana = true
+	return s, nil
+}
+
+// SignatureType returns 4, the ANS-104 signature type of Solana keys, if
+// Solana is set, and 2, the type of ED25519 keys, otherwise.
+func (s *ED25519Signer) SignatureType() int {
+	if s.Solana {
+		return 4
+	}
+	return 2
+}
+
+// Owner returns the base64url-encoded 32-byte public key.
+func (s *ED25519Signer) Owner() string {
+	return crypto.Base64URLEncode(s.PrivateKey.Public().(ed25519.PublicKey))
+}
+
+// SignMessage signs message with Ed25519.
+func (s *ED25519Signer) SignMessage(message []byte) ([]byte, error) {
+	return ed25519.Sign(s.PrivateKey, message), nil
+}
+
+// Approve submits req to the signer's Approver, see Signer.Approve.
+func (s *ED25519Signer) Approve(req *SigningRequest) error {
+	return approve(s.Approver, s.Address, req)
+}
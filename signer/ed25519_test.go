@@ -0,0 +1,67 @@
+package signer
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"encoding/json"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestED25519Signer verifies Ed25519 and Solana keys are loaded and sign messages
+func TestED25519Signer(t *testing.T) {
+	// RFC 8032 test 1
+	seed, err := hex.DecodeString("9d61b19deffd5a60ba844af492ec2cc44449c5697b326919703bac031cae7f60")
+	require.NoError(t, err)
+	publicKey, err := hex.DecodeString("d75a980182b10ab7d54bfed3c964073a0ee172f3daa62325af021a68f707511a")
+	require.NoError(t, err)
+	keypair := ed25519.NewKeyFromSeed(seed)
+
+	s := ED25519FromPrivateKey(keypair)
+	assert.Equal(t, 2, s.SignatureType())
+	assert.Equal(t, crypto.Base58Encode(publicKey), s.Address)
+	assert.Equal(t, crypto.Base64URLEncode(publicKey), s.Owner())
+	signature, err := s.SignMessage(nil)
+	require.NoError(t, err)
+	assert.Equal(t, "e5564300c360ac729086e2cc806e828a84877f1eb8e5d974d873e065224901555fb8821590a33bacc61e39701cf9b46bd25bf5f0595bbe24655141438e7a100b", hex.EncodeToString(signature))
+	assert.NoError(t, crypto.VerifyED25519(nil, signature, publicKey))
+
+	t.Run("Solana", func(t *testing.T) {
+		fromKeypair, err := SolanaFromBase58(crypto.Base58Encode(keypair))
+		require.NoError(t, err)
+		assert.Equal(t, 4, fromKeypair.SignatureType())
+		assert.Equal(t, s.Address, fromKeypair.Address)
+
+		fromSeed, err := SolanaFromBase58(crypto.Base58Encode(seed))
+		require.NoError(t, err)
+		assert.Equal(t, s.Address, fromSeed.Address)
+
+		numbers := make([]int, len(keypair))
+		for i, b := range keypair {
+			numbers[i] = int(b)
+		}
+		file, err := json.Marshal(numbers)
+		require.NoError(t, err)
+		fromFile, err := SolanaFromKeypair(file)
+		require.NoError(t, err)
+		assert.Equal(t, s.Address, fromFile.Address)
+
+		mismatched := append(append([]byte(nil), seed...), make([]byte, 32)...)
+		for _, key := range []string{"0OIl", crypto.Base58Encode(seed[:16]), crypto.Base58Encode(mismatched)} {
+			_, err := SolanaFromBase58(key)
+			assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err), key)
+		}
+		for _, file := range []string{`{}`, `[256]`, `[1, 2]`} {
+			_, err := SolanaFromKeypair([]byte(file))
+			assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err), file)
+		}
+	})
+
+	generated, err := NewED25519()
+	require.NoError(t, err)
+	assert.NotEqual(t, s.Address, generated.Address)
+}
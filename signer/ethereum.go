@@ -0,0 +1,88 @@
+package signer
+
+import (
+	"encoding/hex"
+	"strings"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+)
+
+// EthereumSigner signs ANS-104 data items with an Ethereum secp256k1 key.
+//
+// Data items signed this way have signature type 3: the deep hash of the
+// item is signed as an EIP-191 personal message, and the owner is the
+// uncompressed public key. Bundlers credit them to the Ethereum address of
+// the key. Layer 1 Arweave transactions can only be signed by a Signer.
+type EthereumSigner struct {
+	Address    string                // The EIP-55 checksummed Ethereum address of the key
+	PrivateKey *secp256k1.PrivateKey // secp256k1 private key for signing operations
+	Approver   Approver              // Optional hook that must approve every payload before it is signed
+}
+
+// NewEthereum creates an EthereumSigner with a randomly generated key.
+//
+// Example:
+//
+//	s, err := signer.NewEthereum()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Generated Ethereum signer: %s\n", s.Address)
+func NewEthereum() (*EthereumSigner, error) {
+	key, err := secp256k1.GeneratePrivateKey()
+	if err != nil {
+		return nil, err
+	}
+	return EthereumFromPrivateKey(key), nil
+}
+
+// EthereumFromHex creates an EthereumSigner from a hex-encoded private key,
+// as exported by Ethereum wallets, with or without the 0x prefix.
+//
+// Returns an error with code goar.ErrInvalidInput if the key is not 32
+// hex-encoded bytes.
+//
+// Example:
+//
+//	s, err := signer.EthereumFromHex(os.Getenv("ETH_PRIVATE_KEY"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func EthereumFromHex(key string) (*EthereumSigner, error) {
+	b, err := hex.DecodeString(strings.TrimPrefix(key, "0x"))
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	if len(b) != 32 {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "invalid private key length %d", len(b))
+	}
+	return EthereumFromPrivateKey(secp256k1.PrivKeyFromBytes(b)), nil
+}
+
+// EthereumFromPrivateKey creates an EthereumSigner from an existing secp256k1 private key.
+func EthereumFromPrivateKey(privateKey *secp256k1.PrivateKey) *EthereumSigner {
+	address, _ := crypto.GetEthereumAddress(privateKey.PubKey().SerializeUncompressed())
+	return &EthereumSigner{Address: address, PrivateKey: privateKey}
+}
+
+// SignatureType returns 3, the ANS-104 signature type of Ethereum keys.
+func (s *EthereumSigner) SignatureType() int {
+	return 3
+}
+
+// Owner returns the base64url-encoded 65-byte uncompressed public key.
+func (s *EthereumSigner) Owner() string {
+	return crypto.Base64URLEncode(s.PrivateKey.PubKey().SerializeUncompressed())
+}
+
+// SignMessage signs message as an EIP-191 personal message, see crypto.SignEthereum.
+func (s *EthereumSigner) SignMessage(message []byte) ([]byte, error) {
+	return crypto.SignEthereum(message, s.PrivateKey), nil
+}
+
+// Approve submits req to the signer's Approver, see Signer.Approve.
+func (s *EthereumSigner) Approve(req *SigningRequest) error {
+	return approve(s.Approver, s.Address, req)
+}
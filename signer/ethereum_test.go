@@ -0,0 +1,43 @@
+package signer
+
+import (
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestEthereumSigner verifies Ethereum keys are loaded, addressed and sign personal messages
+func TestEthereumSigner(t *testing.T) {
+	s, err := EthereumFromHex("0x4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	require.NoError(t, err)
+	assert.Equal(t, "0x2c7536E3605D9C16a7a3D7b1898e529396a65c23", s.Address)
+	assert.Equal(t, 3, s.SignatureType())
+
+	owner, err := crypto.Base64URLDecode(s.Owner())
+	require.NoError(t, err)
+	assert.Len(t, owner, 65)
+	signature, err := s.SignMessage([]byte("message"))
+	require.NoError(t, err)
+	assert.NoError(t, crypto.VerifyEthereum([]byte("message"), signature, owner))
+
+	generated, err := NewEthereum()
+	require.NoError(t, err)
+	assert.NotEqual(t, s.Address, generated.Address)
+
+	for _, key := range []string{"zz", "0x0102"} {
+		_, err := EthereumFromHex(key)
+		assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err), key)
+	}
+
+	var kind string
+	s.Approver = ApproverFunc(func(req *SigningRequest) error {
+		kind = req.Kind
+		assert.Equal(t, s.Address, req.Address)
+		return nil
+	})
+	require.NoError(t, s.Approve(&SigningRequest{Kind: KindDataItem}))
+	assert.Equal(t, KindDataItem, kind)
+}
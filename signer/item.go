@@ -0,0 +1,29 @@
+package signer
+
+import "github.com/liteseed/goar/crypto"
+
+// ItemSigner signs ANS-104 data items with a key of any supported signature type.
+//
+// Signer signs with an Arweave RSA key, EthereumSigner with an Ethereum
+// secp256k1 key and ED25519Signer with an Ed25519 or Solana key. Data items record the signature type, so verifiers pick
+// the matching scheme.
+type ItemSigner interface {
+	// SignatureType returns the ANS-104 signature type of the key, e.g. 1 for Arweave.
+	SignatureType() int
+	// Owner returns the base64url-encoded public key stored in the data item.
+	Owner() string
+	// Approve submits a signing request to the signer's Approver.
+	Approve(req *SigningRequest) error
+	// SignMessage signs the deep hash of a data item.
+	SignMessage(message []byte) ([]byte, error)
+}
+
+// SignatureType returns 1, the ANS-104 signature type of Arweave RSA-PSS keys.
+func (s *Signer) SignatureType() int {
+	return 1
+}
+
+// SignMessage signs message with RSA-PSS and SHA-256, see crypto.Sign.
+func (s *Signer) SignMessage(message []byte) ([]byte, error) {
+	return crypto.Sign(message, s.PrivateKey)
+}
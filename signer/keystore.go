@@ -0,0 +1,202 @@
+package signer
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"os"
+
+	"github.com/everFinance/gojwk"
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"golang.org/x/crypto/scrypt"
+)
+
+// KEYSTORE_VERSION is the version of the encrypted keystore format written by EncryptKeystore
+const KEYSTORE_VERSION = 1
+
+// Default scrypt parameters of EncryptKeystore, taking about 100ms and 32MB per key derivation
+const (
+	SCRYPT_N = 1 << 15 // CPU and memory cost
+	SCRYPT_R = 8       // Block size
+	SCRYPT_P = 1       // Parallelization
+)
+
+// keystore is the JSON form of an encrypted keystore
+type keystore struct {
+	Version    int          `json:"version"`
+	Address    string       `json:"address"` // Address of the key, readable without the passphrase and authenticated by the cipher
+	KDF        string       `json:"kdf"`
+	KDFParams  scryptParams `json:"kdfparams"`
+	Cipher     string       `json:"cipher"`
+	Nonce      string       `json:"nonce"`      // base64url AES-GCM nonce
+	Ciphertext string       `json:"ciphertext"` // base64url AES-GCM encryption of the JWK
+}
+
+type scryptParams struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt string `json:"salt"` // base64url
+}
+
+// ToJWK returns the private key of the signer in JWK format, as read by FromJWK.
+//
+// The result holds the private key in plain text: store it with care, or
+// use EncryptKeystore instead.
+func (s *Signer) ToJWK() ([]byte, error) {
+	jwk, err := gojwk.PrivateKey(s.PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+	return gojwk.Marshal(jwk)
+}
+
+// Save writes the private key of the signer in JWK format to a new file at path, readable by its owner only.
+//
+// Returns an error if path already exists, so that a wallet is never
+// overwritten.
+//
+// Example:
+//
+//	s, err := signer.New()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	err = s.Save("wallet.json")
+func (s *Signer) Save(path string) error {
+	b, err := s.ToJWK()
+	if err != nil {
+		return err
+	}
+	return writeNew(path, b)
+}
+
+// EncryptKeystore returns the private key of the signer encrypted with passphrase.
+//
+// The key is derived from passphrase with scrypt, using SCRYPT_N, SCRYPT_R
+// and SCRYPT_P and a random salt, and the JWK is sealed with AES-256-GCM.
+// The keystore is JSON holding the address of the key in clear, so that
+// keystores can be told apart without their passphrase.
+//
+// Example:
+//
+//	b, err := s.EncryptKeystore(os.Getenv("WALLET_PASSPHRASE"))
+func (s *Signer) EncryptKeystore(passphrase string) ([]byte, error) {
+	jwk, err := s.ToJWK()
+	if err != nil {
+		return nil, err
+	}
+	ks := keystore{
+		Version:   KEYSTORE_VERSION,
+		Address:   s.Address,
+		KDF:       "scrypt",
+		KDFParams: scryptParams{N: SCRYPT_N, R: SCRYPT_R, P: SCRYPT_P},
+		Cipher:    "aes-256-gcm",
+	}
+	salt := make([]byte, 32)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	ks.KDFParams.Salt = crypto.Base64URLEncode(salt)
+	aead, err := ks.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	ks.Nonce = crypto.Base64URLEncode(nonce)
+	ks.Ciphertext = crypto.Base64URLEncode(aead.Seal(nil, nonce, jwk, []byte(ks.Address)))
+	return json.MarshalIndent(ks, "", "  ")
+}
+
+// SaveEncrypted writes the keystore of the signer encrypted with passphrase to a new file at path, see EncryptKeystore and Save.
+func (s *Signer) SaveEncrypted(path string, passphrase string) error {
+	b, err := s.EncryptKeystore(passphrase)
+	if err != nil {
+		return err
+	}
+	return writeNew(path, b)
+}
+
+// FromEncryptedKeystore creates a Signer from a keystore file written by SaveEncrypted.
+//
+// Returns an error with code goar.ErrInvalidInput if passphrase is wrong
+// or the keystore was altered, goar.ErrDecode if the file is not a
+// keystore, or the error of reading the file.
+//
+// Example:
+//
+//	s, err := signer.FromEncryptedKeystore("wallet.keystore.json", os.Getenv("WALLET_PASSPHRASE"))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func FromEncryptedKeystore(path string, passphrase string) (*Signer, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return DecryptKeystore(b, passphrase)
+}
+
+// DecryptKeystore creates a Signer from a keystore returned by EncryptKeystore, see FromEncryptedKeystore.
+func DecryptKeystore(b []byte, passphrase string) (*Signer, error) {
+	var ks keystore
+	if err := json.Unmarshal(b, &ks); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	if ks.Version != KEYSTORE_VERSION || ks.KDF != "scrypt" || ks.Cipher != "aes-256-gcm" {
+		return nil, goar.Errorf(goar.ErrDecode, "unsupported keystore version %d with %s and %s", ks.Version, ks.KDF, ks.Cipher)
+	}
+	aead, err := ks.aead(passphrase)
+	if err != nil {
+		return nil, err
+	}
+	nonce, err := crypto.Base64URLDecode(ks.Nonce)
+	if err != nil || len(nonce) != aead.NonceSize() {
+		return nil, goar.Errorf(goar.ErrDecode, "invalid keystore nonce")
+	}
+	ciphertext, err := crypto.Base64URLDecode(ks.Ciphertext)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	jwk, err := aead.Open(nil, nonce, ciphertext, []byte(ks.Address))
+	if err != nil {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "wrong passphrase or altered keystore")
+	}
+	return FromJWK(jwk)
+}
+
+// aead derives the key of the keystore from passphrase and returns its cipher
+func (ks *keystore) aead(passphrase string) (cipher.AEAD, error) {
+	salt, err := crypto.Base64URLDecode(ks.KDFParams.Salt)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, ks.KDFParams.N, ks.KDFParams.R, ks.KDFParams.P, 32)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// writeNew writes b to a new file at path readable by its owner only
+func writeNew(path string, b []byte) error {
+	f, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0o600)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(b); err != nil {
+		f.Close()
+		os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
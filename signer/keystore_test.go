@@ -0,0 +1,67 @@
+package signer
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeystore(t *testing.T) {
+	s, err := FromPath("../test/signer.json")
+	require.NoError(t, err)
+	dir := t.TempDir()
+
+	t.Run("JWK", func(t *testing.T) {
+		b, err := s.ToJWK()
+		require.NoError(t, err)
+		loaded, err := FromJWK(b)
+		require.NoError(t, err)
+		assert.Equal(t, s.Address, loaded.Address)
+		assert.True(t, s.PrivateKey.Equal(loaded.PrivateKey))
+
+		path := filepath.Join(dir, "wallet.json")
+		require.NoError(t, s.Save(path))
+		info, err := os.Stat(path)
+		require.NoError(t, err)
+		assert.Equal(t, os.FileMode(0o600), info.Mode().Perm())
+		loaded, err = FromPath(path)
+		require.NoError(t, err)
+		assert.Equal(t, s.Address, loaded.Address)
+
+		assert.ErrorIs(t, s.Save(path), os.ErrExist)
+	})
+
+	t.Run("Encrypted", func(t *testing.T) {
+		path := filepath.Join(dir, "wallet.keystore.json")
+		require.NoError(t, s.SaveEncrypted(path, "correct horse"))
+		b, err := os.ReadFile(path)
+		require.NoError(t, err)
+		assert.NotContains(t, string(b), crypto.Base64URLEncode(s.PrivateKey.D.Bytes()))
+
+		loaded, err := FromEncryptedKeystore(path, "correct horse")
+		require.NoError(t, err)
+		assert.Equal(t, s.Address, loaded.Address)
+		assert.True(t, s.PrivateKey.Equal(loaded.PrivateKey))
+
+		_, err = FromEncryptedKeystore(path, "wrong horse")
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+
+		var ks map[string]any
+		require.NoError(t, json.Unmarshal(b, &ks))
+		assert.Equal(t, s.Address, ks["address"])
+		ks["address"] = "another"
+		altered, err := json.Marshal(ks)
+		require.NoError(t, err)
+		_, err = DecryptKeystore(altered, "correct horse")
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+
+		_, err = DecryptKeystore([]byte(`{"version":2}`), "correct horse")
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
@@ -3,6 +3,9 @@
 // This package handles RSA key management and transaction signing operations
 // used in the Arweave protocol. It supports loading keys from JWK format,
 // generating new keys, and creating signatures for transactions.
+// Keys can be saved back to JWK files, or to keystores encrypted with a
+// passphrase, see EncryptKeystore. EthereumSigner and ED25519Signer sign
+// ANS-104 data items with Ethereum secp256k1 keys and Ed25519 or Solana keys.
 //
 // Example usage:
 //
@@ -42,6 +45,7 @@ type Signer struct {
 	Address    string          // The Arweave wallet address derived from the public key
 	PublicKey  *rsa.PublicKey  // RSA public key for verification operations
 	PrivateKey *rsa.PrivateKey // RSA private key for signing operations
+	Approver   Approver        // Optional hook that must approve every payload before it is signed
 }
 
 // New creates a new Signer with a randomly generated RSA key pair.
@@ -0,0 +1,213 @@
+// Package split stores data larger than a per-transaction cap as several transactions.
+//
+// Split cuts the data into parts of at most the given size and creates one
+// layer 1 transaction per part. Once the parts are signed, NewIndex creates
+// an index transaction whose data lists the parts in order. On the download
+// side, NewReader streams the original data back by fetching the parts
+// listed in the index one after the other.
+//
+// Example usage:
+//
+//	parts, err := split.Split(data, split.DEFAULT_PART_SIZE, &tags)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, part := range parts {
+//		if _, err := w.SignTransaction(part); err != nil {
+//			log.Fatal(err)
+//		}
+//		// Upload each part ...
+//	}
+//
+//	index, err := split.NewIndex(parts, &tags)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	// Sign and upload the index, then share index.ID
+//
+//	r, err := split.NewReader(client.New("https://arweave.net"), index.ID)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	_, err = io.Copy(os.Stdout, r)
+package split
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/liteseed/goar/canonical"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction"
+)
+
+// DEFAULT_PART_SIZE is the per-transaction cap used when none is configured (1GB)
+const DEFAULT_PART_SIZE = 1024 * 1024 * 1024
+
+// INDEX_VERSION is the version of the index format written by NewIndex
+const INDEX_VERSION = "1"
+
+// Tags added to parts and indexes
+const (
+	TAG_PART_NUMBER  = "Part-Number"                         // Zero-based position of a part
+	TAG_PART_COUNT   = "Part-Count"                          // Total number of parts
+	TAG_CONTENT_TYPE = "Content-Type"                        // Content type of the index
+	INDEX_MIME_TYPE  = "application/x.goar-split-index+json" // Content type identifying an index
+)
+
+// Part describes one transaction of a split upload.
+type Part struct {
+	ID   string `json:"id"`   // Transaction ID of the part
+	Size int64  `json:"size"` // Size of the part's data in bytes
+}
+
+// Index is the record linking the parts of a split upload in order.
+type Index struct {
+	Version string `json:"version"` // Index format version
+	Size    int64  `json:"size"`    // Total size of the original data in bytes
+	Parts   []Part `json:"parts"`   // Parts in the order their data must be concatenated
+}
+
+// Split cuts data into parts of at most partSize bytes and creates one transaction per part.
+//
+// Every part carries the given tags plus Part-Number and Part-Count tags.
+// The returned transactions are not signed.
+//
+// Parameters:
+//   - data: The data to store
+//   - partSize: The maximum data size of a single transaction
+//   - tags: Optional tags added to every part (can be nil)
+//
+// Returns the part transactions in order, or an error if partSize is not positive.
+func Split(data []byte, partSize int, tags *[]tag.Tag) ([]*transaction.Transaction, error) {
+	if partSize <= 0 {
+		return nil, errors.New("part size must be positive")
+	}
+	count := max(1, (len(data)+partSize-1)/partSize)
+
+	parts := make([]*transaction.Transaction, 0, count)
+	for i := 0; i < count; i++ {
+		end := min(len(data), (i+1)*partSize)
+		partTags := withTags(tags,
+			tag.Tag{Name: TAG_PART_NUMBER, Value: strconv.Itoa(i)},
+			tag.Tag{Name: TAG_PART_COUNT, Value: strconv.Itoa(count)},
+		)
+		parts = append(parts, transaction.New(data[i*partSize:end], "", "0", partTags))
+	}
+	return parts, nil
+}
+
+// NewIndex creates the index transaction for signed parts.
+//
+// The index lists the ID and size of every part in order as canonical JSON,
+// so its data is reproducible, and is tagged with INDEX_MIME_TYPE. The returned transaction is not signed.
+//
+// Parameters:
+//   - parts: The signed part transactions, in order
+//   - tags: Optional tags added to the index (can be nil)
+//
+// Returns the index transaction, or an error if a part has not been signed.
+func NewIndex(parts []*transaction.Transaction, tags *[]tag.Tag) (*transaction.Transaction, error) {
+	index := Index{Version: INDEX_VERSION, Parts: make([]Part, 0, len(parts))}
+	for i, p := range parts {
+		if p.ID == "" {
+			return nil, fmt.Errorf("part %d is not signed", i)
+		}
+		size, err := strconv.ParseInt(p.DataSize, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("part %d: invalid data size: %w", i, err)
+		}
+		index.Parts = append(index.Parts, Part{ID: p.ID, Size: size})
+		index.Size += size
+	}
+
+	b, err := canonical.Marshal(index)
+	if err != nil {
+		return nil, err
+	}
+	return transaction.New(b, "", "0", withTags(tags, tag.Tag{Name: TAG_CONTENT_TYPE, Value: INDEX_MIME_TYPE})), nil
+}
+
+// ParseIndex decodes the data of an index transaction.
+func ParseIndex(data []byte) (*Index, error) {
+	index := &Index{}
+	if err := json.Unmarshal(data, index); err != nil {
+		return nil, err
+	}
+	if index.Version != INDEX_VERSION {
+		return nil, fmt.Errorf("unsupported index version %q", index.Version)
+	}
+	var size int64
+	for _, p := range index.Parts {
+		size += p.Size
+	}
+	if size != index.Size {
+		return nil, errors.New("index size does not match its parts")
+	}
+	return index, nil
+}
+
+// Reader streams the original data of a split upload.
+type Reader struct {
+	client *client.Client
+	index  *Index
+	next   int    // Index of the next part to fetch
+	buf    []byte // Unread data of the current part
+}
+
+// NewReader fetches the index indexID and returns a Reader over the reassembled data.
+//
+// Parts are fetched lazily, one at a time, as the data is read. Each part
+// must have the size recorded in the index.
+func NewReader(c *client.Client, indexID string) (*Reader, error) {
+	data, err := c.GetTransactionData(indexID)
+	if err != nil {
+		return nil, err
+	}
+	index, err := ParseIndex(data)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{client: c, index: index}, nil
+}
+
+// Index returns the index the reader is reassembling.
+func (r *Reader) Index() *Index {
+	return r.index
+}
+
+// Read reads the reassembled data, fetching the next part when the current one is exhausted.
+func (r *Reader) Read(p []byte) (int, error) {
+	for len(r.buf) == 0 {
+		if r.next >= len(r.index.Parts) {
+			return 0, io.EOF
+		}
+		part := r.index.Parts[r.next]
+		data, err := r.client.GetTransactionData(part.ID)
+		if err != nil {
+			return 0, fmt.Errorf("part %d (%s): %w", r.next, part.ID, err)
+		}
+		if int64(len(data)) != part.Size {
+			return 0, fmt.Errorf("part %d (%s): expected %d bytes, got %d", r.next, part.ID, part.Size, len(data))
+		}
+		r.buf = data
+		r.next++
+	}
+	n := copy(p, r.buf)
+	r.buf = r.buf[n:]
+	return n, nil
+}
+
+// withTags returns a copy of tags followed by extra.
+func withTags(tags *[]tag.Tag, extra ...tag.Tag) *[]tag.Tag {
+	out := []tag.Tag{}
+	if tags != nil {
+		out = append(out, *tags...)
+	}
+	out = append(out, extra...)
+	return &out
+}
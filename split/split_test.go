@@ -0,0 +1,124 @@
+package split
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// sign signs tx with s using placeholder network fields
+func sign(t *testing.T, s *signer.Signer, tx *transaction.Transaction) {
+	tx.Owner = s.Owner()
+	tx.LastTx = "lqsw6xgaaunfs8h3d6n54ci1lgm2tmtqvz3wke9v9ygq64q8s68yz2jfq5xy4nec"
+	tx.Reward = "1000"
+	require.NoError(t, tx.Sign(s))
+}
+
+// newGateway serves the data of the given transactions by ID
+func newGateway(t *testing.T, txs []*transaction.Transaction) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/")
+		for _, tx := range txs {
+			if tx.ID == id {
+				data, err := crypto.Base64URLDecode(tx.Data)
+				require.NoError(t, err)
+				_, _ = w.Write(data)
+				return
+			}
+		}
+		http.NotFound(w, r)
+	}))
+}
+
+func TestSplit(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+
+	tags := &[]tag.Tag{{Name: "App-Name", Value: "goar"}}
+	parts, err := Split(data, 400*1024, tags)
+	require.NoError(t, err)
+	require.Len(t, parts, 3)
+
+	for i, part := range parts {
+		sign(t, s, part)
+		assert.NoError(t, part.Verify())
+		assert.Equal(t, crypto.Base64URLEncode([]byte(TAG_PART_NUMBER)), (*part.Tags)[1].Name)
+		assert.Equal(t, crypto.Base64URLEncode([]byte(string(rune('0'+i)))), (*part.Tags)[1].Value)
+	}
+	assert.Equal(t, "409600", parts[0].DataSize)
+	assert.Equal(t, "229376", parts[2].DataSize)
+
+	index, err := NewIndex(parts, tags)
+	require.NoError(t, err)
+	sign(t, s, index)
+
+	gateway := newGateway(t, append(parts, index))
+	defer gateway.Close()
+
+	t.Run("Reassemble", func(t *testing.T) {
+		r, err := NewReader(client.New(gateway.URL), index.ID)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(data)), r.Index().Size)
+		assert.Len(t, r.Index().Parts, 3)
+
+		got, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, data, got)
+	})
+
+	t.Run("Missing part", func(t *testing.T) {
+		gateway := newGateway(t, []*transaction.Transaction{parts[0], index})
+		defer gateway.Close()
+
+		r, err := NewReader(client.New(gateway.URL), index.ID)
+		require.NoError(t, err)
+		_, err = io.ReadAll(r)
+		assert.ErrorContains(t, err, "part 1")
+	})
+
+	t.Run("Unsigned part", func(t *testing.T) {
+		unsigned, err := Split(data, 400*1024, nil)
+		require.NoError(t, err)
+		_, err = NewIndex(unsigned, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Invalid part size", func(t *testing.T) {
+		_, err := Split(data, 0, nil)
+		assert.Error(t, err)
+	})
+
+	t.Run("Empty data", func(t *testing.T) {
+		parts, err := Split(nil, 10, nil)
+		require.NoError(t, err)
+		assert.Len(t, parts, 1)
+	})
+}
+
+func TestParseIndex(t *testing.T) {
+	index, err := ParseIndex([]byte(`{"version":"1","size":3,"parts":[{"id":"a","size":1},{"id":"b","size":2}]}`))
+	require.NoError(t, err)
+	assert.Equal(t, []Part{{ID: "a", Size: 1}, {ID: "b", Size: 2}}, index.Parts)
+
+	_, err = ParseIndex([]byte(`{"version":"1","size":4,"parts":[{"id":"a","size":1}]}`))
+	assert.Error(t, err)
+
+	_, err = ParseIndex([]byte(`{"version":"2","size":0,"parts":[]}`))
+	assert.Error(t, err)
+
+	_, err = ParseIndex([]byte(`not json`))
+	assert.Error(t, err)
+}
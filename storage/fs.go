@@ -0,0 +1,91 @@
+package storage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/liteseed/goar"
+)
+
+// FS is a Backend storing data as files in a directory.
+//
+// Files are spread over subdirectories named after the first two
+// characters of their ID, so that no directory grows too large. Put writes
+// to a temporary file first, so readers never see partial data.
+type FS struct {
+	Dir string // Root directory
+}
+
+// NewFS creates a filesystem backend rooted at dir, creating it if needed.
+func NewFS(dir string) (*FS, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &FS{Dir: dir}, nil
+}
+
+// path returns the file holding the data of id
+func (f *FS) path(id string) string {
+	return filepath.Join(f.Dir, id[:min(2, len(id))], id)
+}
+
+// Put stores size bytes read from r under id.
+//
+// Returns an error if r holds fewer than size bytes.
+func (f *FS) Put(ctx context.Context, id string, r io.Reader, size int64) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	path := f.path(id)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), id+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, io.LimitReader(r, size))
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+	if n != size {
+		return fmt.Errorf("expected %d bytes for %s, got %d", size, id, n)
+	}
+	return os.Rename(tmp.Name(), path)
+}
+
+// Get opens the data of id.
+func (f *FS) Get(ctx context.Context, id string) (io.ReadCloser, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+	file, err := os.Open(f.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, goar.Wrap(goar.ErrNotFound, err)
+	}
+	return file, err
+}
+
+// Exists reports whether data is stored under id.
+func (f *FS) Exists(ctx context.Context, id string) (bool, error) {
+	if err := checkID(id); err != nil {
+		return false, err
+	}
+	_, err := os.Stat(f.path(id))
+	if errors.Is(err, os.ErrNotExist) {
+		return false, nil
+	}
+	return err == nil, err
+}
@@ -0,0 +1,190 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/liteseed/goar"
+)
+
+// UNSIGNED_PAYLOAD is the payload hash of streamed S3 requests, whose body is not part of the signature
+const UNSIGNED_PAYLOAD = "UNSIGNED-PAYLOAD"
+
+// EMPTY_PAYLOAD_HASH is the SHA-256 of an empty body
+const EMPTY_PAYLOAD_HASH = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+// S3 is a Backend storing data as objects of an S3-compatible bucket, such
+// as AWS S3, MinIO, Cloudflare R2 or Backblaze B2.
+//
+// Requests are signed with AWS Signature Version 4. Objects are addressed
+// path-style, as Endpoint/Bucket/Prefix+ID, which every S3-compatible store
+// supports.
+type S3 struct {
+	Endpoint  string       // Base URL of the object store, e.g. "https://s3.us-east-1.amazonaws.com"
+	Region    string       // Signing region, e.g. "us-east-1"; "auto" for R2
+	Bucket    string       // Bucket holding the objects
+	Prefix    string       // Optional key prefix, e.g. "arweave/"
+	AccessKey string       // Access key ID
+	SecretKey string       // Secret access key
+	Client    *http.Client // HTTP client, http.DefaultClient if nil
+
+	now func() time.Time // Signing time, time.Now if nil
+}
+
+// Put uploads size bytes read from r as the object of id.
+func (s *S3) Put(ctx context.Context, id string, r io.Reader, size int64) error {
+	if err := checkID(id); err != nil {
+		return err
+	}
+	req, err := s.request(ctx, http.MethodPut, id, r)
+	if err != nil {
+		return err
+	}
+	req.ContentLength = size
+	req.Header.Set("Content-Type", "application/octet-stream")
+	resp, err := s.do(req, UNSIGNED_PAYLOAD)
+	if err != nil {
+		return err
+	}
+	return resp.Body.Close()
+}
+
+// Get downloads the object of id.
+func (s *S3) Get(ctx context.Context, id string) (io.ReadCloser, error) {
+	if err := checkID(id); err != nil {
+		return nil, err
+	}
+	req, err := s.request(ctx, http.MethodGet, id, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := s.do(req, EMPTY_PAYLOAD_HASH)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Body, nil
+}
+
+// Exists reports whether the object of id exists.
+func (s *S3) Exists(ctx context.Context, id string) (bool, error) {
+	if err := checkID(id); err != nil {
+		return false, err
+	}
+	req, err := s.request(ctx, http.MethodHead, id, nil)
+	if err != nil {
+		return false, err
+	}
+	resp, err := s.do(req, EMPTY_PAYLOAD_HASH)
+	if goar.CodeOf(err) == goar.ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, resp.Body.Close()
+}
+
+// request creates a request for the object of id
+func (s *S3) request(ctx context.Context, method string, id string, body io.Reader) (*http.Request, error) {
+	u, err := url.Parse(s.Endpoint)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.Bucket + "/" + s.Prefix + id
+	return http.NewRequestWithContext(ctx, method, u.String(), body)
+}
+
+// do signs and sends req, returning an error classified by status code for non-2xx responses
+func (s *S3) do(req *http.Request, payloadHash string) (*http.Response, error) {
+	now := time.Now
+	if s.now != nil {
+		now = s.now
+	}
+	req.Header.Set("x-amz-content-sha256", payloadHash)
+	s.sign(req, payloadHash, now().UTC())
+
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrNetwork, err)
+	}
+	if resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		resp.Body.Close()
+		err := fmt.Errorf("s3 %s %s: %d: %s", req.Method, req.URL.Path, resp.StatusCode, body)
+		switch {
+		case resp.StatusCode == http.StatusNotFound:
+			return nil, goar.Wrap(goar.ErrNotFound, err)
+		case resp.StatusCode >= 500:
+			return nil, goar.Wrap(goar.ErrGateway, err)
+		default:
+			return nil, goar.Wrap(goar.ErrBadRequest, err)
+		}
+	}
+	return resp, nil
+}
+
+// sign adds the AWS Signature Version 4 Authorization header to req, signing
+// the host and every header already set
+func (s *S3) sign(req *http.Request, payloadHash string, t time.Time) {
+	amzDate := t.Format("20060102T150405Z")
+	date := t.Format("20060102")
+	req.Header.Set("x-amz-date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		req.URL.EscapedPath(),
+		req.URL.Query().Encode(),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+	scope := date + "/" + s.Region + "/s3/aws4_request"
+	stringToSign := strings.Join([]string{"AWS4-HMAC-SHA256", amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+
+	key := hmacSHA256([]byte("AWS4"+s.SecretKey), date)
+	key = hmacSHA256(key, s.Region)
+	key = hmacSHA256(key, "s3")
+	key = hmacSHA256(key, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(key, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s", s.AccessKey, scope, signedHeaders, signature))
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	h := hmac.New(sha256.New, key)
+	h.Write([]byte(data))
+	return h.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
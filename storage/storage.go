@@ -0,0 +1,69 @@
+// Package storage stores downloaded transaction and data item data.
+//
+// A Backend keeps data by transaction or data item ID. FS stores it in a
+// local directory and S3 in any S3-compatible object store, so archival
+// pipelines can write straight to object storage.
+//
+// Example usage:
+//
+//	b, err := storage.NewFS("/var/lib/goar")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	err = storage.Download(ctx, client.New("https://arweave.net"), b, id)
+package storage
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"regexp"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+)
+
+// Backend stores data by transaction or data item ID.
+//
+// Implementations must be safe for concurrent use. Get returns an error
+// with code goar.ErrNotFound for unknown IDs, and IDs that are not
+// base64url are rejected with goar.ErrInvalidInput.
+type Backend interface {
+	// Put stores size bytes read from r under id, replacing any previous data.
+	Put(ctx context.Context, id string, r io.Reader, size int64) error
+	// Get returns a reader over the data of id, which the caller must close.
+	Get(ctx context.Context, id string) (io.ReadCloser, error)
+	// Exists reports whether data is stored under id.
+	Exists(ctx context.Context, id string) (bool, error)
+}
+
+var validID = regexp.MustCompile(`^[A-Za-z0-9_-]{1,128}$`)
+
+// checkID returns an error with code goar.ErrInvalidInput if id is not a base64url ID
+func checkID(id string) error {
+	if !validID.MatchString(id) {
+		return goar.Errorf(goar.ErrInvalidInput, "invalid ID %q", id)
+	}
+	return nil
+}
+
+// Download fetches the data of id from the gateway of c into b, unless b already has it.
+//
+// Returns whether data was downloaded, or the request or storage error.
+func Download(ctx context.Context, c *client.Client, b Backend, id string) (bool, error) {
+	if err := checkID(id); err != nil {
+		return false, err
+	}
+	ok, err := b.Exists(ctx, id)
+	if err != nil || ok {
+		return false, err
+	}
+	data, err := c.GetTransactionDataContext(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	if err := b.Put(ctx, id, bytes.NewReader(data), int64(len(data))); err != nil {
+		return false, err
+	}
+	return true, nil
+}
@@ -0,0 +1,183 @@
+package tag
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/liteseed/goar"
+)
+
+// Limits of ANS-104 on the tags of a data item, in bytes for names and values
+const (
+	MAX_TAGS             = 128
+	MAX_TAG_KEY_LENGTH   = 1024
+	MAX_TAG_VALUE_LENGTH = 3072
+)
+
+// Names of well-known tags
+const (
+	CONTENT_TYPE = "Content-Type"
+	APP_NAME     = "App-Name"
+	APP_VERSION  = "App-Version"
+	UNIX_TIME    = "Unix-Time"
+)
+
+// Tags is a list of plain text tags with lookup helpers.
+//
+// Names are matched exactly, as they are by gateways: "content-type" does
+// not match a "Content-Type" tag.
+type Tags []Tag
+
+// Get returns the value of the first tag named name, or "" if there is none.
+func (t Tags) Get(name string) string {
+	v, _ := t.Lookup(name)
+	return v
+}
+
+// Lookup returns the value of the first tag named name and whether there is one.
+func (t Tags) Lookup(name string) (string, bool) {
+	for _, tag := range t {
+		if tag.Name == name {
+			return tag.Value, true
+		}
+	}
+	return "", false
+}
+
+// GetAll returns the values of every tag named name, in order.
+func (t Tags) GetAll(name string) []string {
+	var values []string
+	for _, tag := range t {
+		if tag.Name == name {
+			values = append(values, tag.Value)
+		}
+	}
+	return values
+}
+
+// Has reports whether there is a tag named name.
+func (t Tags) Has(name string) bool {
+	_, ok := t.Lookup(name)
+	return ok
+}
+
+// Builder assembles a list of plain text tags, checked against the ANS-104 limits by Build.
+//
+// Add appends a tag unless the same name and value are already present,
+// while Set and the With helpers replace every tag of the same name, so
+// that a name set twice holds the last value. The order of the tags is
+// kept, as it is part of the signature of an upload.
+//
+// Example:
+//
+//	tags, err := tag.NewBuilder().
+//		WithContentType("application/json").
+//		WithAppName("MyApp").
+//		WithUnixTime(time.Now()).
+//		Add("Topic", "weather").
+//		Build()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+type Builder struct {
+	tags []Tag
+}
+
+// NewBuilder returns an empty Builder.
+func NewBuilder() *Builder {
+	return &Builder{}
+}
+
+// Add appends the tag name: value, unless it is already present.
+func (b *Builder) Add(name string, value string) *Builder {
+	for _, t := range b.tags {
+		if t.Name == name && t.Value == value {
+			return b
+		}
+	}
+	b.tags = append(b.tags, Tag{Name: name, Value: value})
+	return b
+}
+
+// Set replaces the tags named name with name: value, in place of the first one or at the end.
+func (b *Builder) Set(name string, value string) *Builder {
+	i := 0
+	set := false
+	for _, t := range b.tags {
+		if t.Name == name {
+			if set {
+				continue
+			}
+			t.Value = value
+			set = true
+		}
+		b.tags[i] = t
+		i++
+	}
+	b.tags = b.tags[:i]
+	if !set {
+		b.tags = append(b.tags, Tag{Name: name, Value: value})
+	}
+	return b
+}
+
+// Remove removes the tags named name.
+func (b *Builder) Remove(name string) *Builder {
+	i := 0
+	for _, t := range b.tags {
+		if t.Name != name {
+			b.tags[i] = t
+			i++
+		}
+	}
+	b.tags = b.tags[:i]
+	return b
+}
+
+// WithContentType sets the Content-Type tag, the MIME type gateways serve the data with.
+func (b *Builder) WithContentType(contentType string) *Builder {
+	return b.Set(CONTENT_TYPE, contentType)
+}
+
+// WithAppName sets the App-Name tag.
+func (b *Builder) WithAppName(name string) *Builder {
+	return b.Set(APP_NAME, name)
+}
+
+// WithAppVersion sets the App-Version tag.
+func (b *Builder) WithAppVersion(version string) *Builder {
+	return b.Set(APP_VERSION, version)
+}
+
+// WithUnixTime sets the Unix-Time tag to t in seconds.
+func (b *Builder) WithUnixTime(t time.Time) *Builder {
+	return b.Set(UNIX_TIME, strconv.FormatInt(t.Unix(), 10))
+}
+
+// Build returns a copy of the tags.
+//
+// Returns an error with code goar.ErrInvalidInput if there are more than
+// MAX_TAGS tags, or if a name or value is empty or longer than
+// MAX_TAG_KEY_LENGTH or MAX_TAG_VALUE_LENGTH bytes.
+func (b *Builder) Build() (Tags, error) {
+	if err := Validate(b.tags); err != nil {
+		return nil, err
+	}
+	return append(Tags{}, b.tags...), nil
+}
+
+// Validate checks plain text tags against the ANS-104 limits, see Builder.Build.
+func Validate(tags []Tag) error {
+	if len(tags) > MAX_TAGS {
+		return goar.Errorf(goar.ErrInvalidInput, "%d tags, at most %d allowed", len(tags), MAX_TAGS)
+	}
+	for _, t := range tags {
+		if len(t.Name) == 0 || len(t.Name) > MAX_TAG_KEY_LENGTH {
+			return goar.Errorf(goar.ErrInvalidInput, "tag name %.32q must be 1 to %d bytes", t.Name, MAX_TAG_KEY_LENGTH)
+		}
+		if len(t.Value) == 0 || len(t.Value) > MAX_TAG_VALUE_LENGTH {
+			return goar.Errorf(goar.ErrInvalidInput, "value of tag %.32q must be 1 to %d bytes", t.Name, MAX_TAG_VALUE_LENGTH)
+		}
+	}
+	return nil
+}
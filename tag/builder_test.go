@@ -0,0 +1,87 @@
+package tag
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuilder(t *testing.T) {
+	t.Run("Helpers", func(t *testing.T) {
+		tags, err := NewBuilder().
+			WithContentType("text/plain").
+			WithAppName("MyApp").
+			WithAppVersion("1.0.0").
+			WithUnixTime(time.Unix(1700000000, 0)).
+			Build()
+		require.NoError(t, err)
+		assert.Equal(t, Tags{
+			{Name: "Content-Type", Value: "text/plain"},
+			{Name: "App-Name", Value: "MyApp"},
+			{Name: "App-Version", Value: "1.0.0"},
+			{Name: "Unix-Time", Value: "1700000000"},
+		}, tags)
+	})
+
+	t.Run("Duplicates", func(t *testing.T) {
+		tags, err := NewBuilder().
+			Add("Topic", "a").
+			WithContentType("text/plain").
+			Add("Topic", "b").
+			Add("Topic", "a").
+			Add("Content-Type", "text/html").
+			WithContentType("application/json").
+			Build()
+		require.NoError(t, err)
+		assert.Equal(t, Tags{
+			{Name: "Topic", Value: "a"},
+			{Name: "Content-Type", Value: "application/json"},
+			{Name: "Topic", Value: "b"},
+		}, tags)
+
+		tags, err = NewBuilder().Add("Topic", "a").Add("Other", "x").Add("Topic", "b").Remove("Topic").Build()
+		require.NoError(t, err)
+		assert.Equal(t, Tags{{Name: "Other", Value: "x"}}, tags)
+	})
+
+	t.Run("Limits", func(t *testing.T) {
+		for name, b := range map[string]*Builder{
+			"Empty name":  NewBuilder().Add("", "value"),
+			"Empty value": NewBuilder().Add("name", ""),
+			"Long name":   NewBuilder().Add(strings.Repeat("n", MAX_TAG_KEY_LENGTH+1), "value"),
+			"Long value":  NewBuilder().Add("name", strings.Repeat("v", MAX_TAG_VALUE_LENGTH+1)),
+		} {
+			_, err := b.Build()
+			assert.ErrorIs(t, err, goar.ErrInvalidInput, name)
+		}
+
+		b := NewBuilder().Add(strings.Repeat("n", MAX_TAG_KEY_LENGTH), strings.Repeat("v", MAX_TAG_VALUE_LENGTH))
+		for i := range MAX_TAGS - 1 {
+			b.Add("Index", string(rune('a'+i%26))+strings.Repeat("x", i/26))
+		}
+		_, err := b.Build()
+		require.NoError(t, err)
+		_, err = b.Add("One", "too many").Build()
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+	})
+}
+
+func TestTags(t *testing.T) {
+	tags := Tags{{Name: "Content-Type", Value: "text/plain"}, {Name: "Topic", Value: "a"}, {Name: "Topic", Value: "b"}}
+
+	assert.Equal(t, "text/plain", tags.Get("Content-Type"))
+	assert.Equal(t, "a", tags.Get("Topic"))
+	assert.Equal(t, "", tags.Get("content-type"))
+	assert.Equal(t, []string{"a", "b"}, tags.GetAll("Topic"))
+	assert.True(t, tags.Has("Topic"))
+	assert.False(t, tags.Has("App-Name"))
+
+	v, ok := Tags{{Name: "Empty", Value: ""}}.Lookup("Empty")
+	assert.True(t, ok)
+	assert.Equal(t, "", v)
+	assert.False(t, Tags(nil).Has("Empty"))
+}
@@ -0,0 +1,63 @@
+package tag
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/binary"
+	"slices"
+
+	"github.com/liteseed/goar/crypto"
+)
+
+// Hash returns a digest of tags that does not depend on their order, e.g. to key caches by metadata.
+//
+// Two tag sets have the same hash when they hold the same tags, each the
+// same number of times, in any order. Names and values are hashed as they
+// are, so tags must be compared in the same form: plain text tags and
+// their base64url encoding, see ConvertToBase64, hash differently, as do
+// names differing only in case. The digest is the base64url SHA-256 of a
+// canonical encoding of the tags, stable across versions and processes.
+//
+// Example:
+//
+//	a := []Tag{{Name: "App-Name", Value: "MyApp"}, {Name: "Content-Type", Value: "text/plain"}}
+//	b := []Tag{{Name: "Content-Type", Value: "text/plain"}, {Name: "App-Name", Value: "MyApp"}}
+//	fmt.Println(Hash(a) == Hash(b)) // true
+func Hash(tags []Tag) string {
+	entries := make([][]byte, len(tags))
+	for i, t := range tags {
+		entries[i] = encodeEntry(t)
+	}
+	slices.SortFunc(entries, bytes.Compare)
+	return hashEntries(entries)
+}
+
+// OrderedHash returns a digest of tags that depends on their order, as it does for the signature of an upload.
+//
+// Use it where the order of tags is significant, e.g. to identify the tags
+// of an item before it is signed. Do not compare it with Hash.
+func OrderedHash(tags []Tag) string {
+	entries := make([][]byte, len(tags))
+	for i, t := range tags {
+		entries[i] = encodeEntry(t)
+	}
+	return hashEntries(entries)
+}
+
+// encodeEntry encodes a tag as its length-prefixed name and value, so that no two tags encode alike
+func encodeEntry(t Tag) []byte {
+	b := binary.AppendUvarint(nil, uint64(len(t.Name)))
+	b = append(b, t.Name...)
+	b = binary.AppendUvarint(b, uint64(len(t.Value)))
+	return append(b, t.Value...)
+}
+
+// hashEntries returns the base64url SHA-256 of the number of entries followed by the entries
+func hashEntries(entries [][]byte) string {
+	h := sha256.New()
+	h.Write(binary.AppendUvarint(nil, uint64(len(entries))))
+	for _, e := range entries {
+		h.Write(e)
+	}
+	return crypto.Base64URLEncode(h.Sum(nil))
+}
@@ -0,0 +1,41 @@
+package tag
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHash(t *testing.T) {
+	a := []Tag{{Name: "App-Name", Value: "MyApp"}, {Name: "Content-Type", Value: "text/plain"}}
+	b := []Tag{{Name: "Content-Type", Value: "text/plain"}, {Name: "App-Name", Value: "MyApp"}}
+
+	t.Run("Order independent", func(t *testing.T) {
+		assert.Equal(t, Hash(a), Hash(b))
+		assert.NotEqual(t, OrderedHash(a), OrderedHash(b))
+		assert.Equal(t, OrderedHash(a), OrderedHash(append([]Tag{}, a...)))
+	})
+
+	t.Run("Distinct sets", func(t *testing.T) {
+		hashes := map[string]bool{}
+		for _, tags := range [][]Tag{
+			nil,
+			a,
+			a[:1],
+			append(append([]Tag{}, a...), a[0]),
+			{{Name: "App-Name", Value: "myapp"}, a[1]},
+			{{Name: "App-NameMyApp", Value: ""}, a[1]},
+			{{Name: "", Value: ""}},
+			*ConvertToBase64(&a),
+		} {
+			h := Hash(tags)
+			assert.False(t, hashes[h], "%v", tags)
+			hashes[h] = true
+		}
+		assert.Equal(t, Hash(nil), Hash([]Tag{}))
+	})
+
+	t.Run("Stable", func(t *testing.T) {
+		assert.Equal(t, "mIYACtZXtrC5y_bC3nZI3VMNUurgibhtzHlFrhbc_8E", Hash(a))
+	})
+}
@@ -20,6 +20,15 @@
 //
 //	// Convert to base64 format
 //	base64Tags := ConvertToBase64(&tags)
+//
+// Builder assembles tags checked against the ANS-104 limits, and Tags
+// looks up decoded tags by name:
+//
+//	tags, err := NewBuilder().WithContentType("application/json").WithAppName("MyApp").Build()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(tags.Get("Content-Type"))
 package tag
 
 import (
@@ -140,6 +149,34 @@ func Serialize(tags *[]Tag) ([]byte, error) {
 	return nil, nil
 }
 
+// SerializedSize returns the length of Serialize(&tags) in bytes, without encoding the tags.
+//
+// Example:
+//
+//	tags := []Tag{{Name: "Content-Type", Value: "text/plain"}}
+//	fmt.Println(SerializedSize(tags)) // 26
+func SerializedSize(tags []Tag) int {
+	if len(tags) == 0 {
+		return 0
+	}
+	// An Avro array is a block of items preceded by their count and followed by an empty block,
+	// and bytes are preceded by their length, all counts being zig-zag varints
+	size := avroLongSize(len(tags)) + 1
+	for _, t := range tags {
+		size += avroLongSize(len(t.Name)) + len(t.Name) + avroLongSize(len(t.Value)) + len(t.Value)
+	}
+	return size
+}
+
+// avroLongSize returns the size of the Avro encoding of the non-negative n
+func avroLongSize(n int) int {
+	size := 1
+	for v := uint64(n) << 1; v >= 0x80; v >>= 7 {
+		size++
+	}
+	return size
+}
+
 // Deserialize converts Avro-encoded byte data from an Arweave transaction into readable Tags.
 //
 // This function parses tag data from a binary stream, typically from a data item
@@ -165,16 +202,19 @@ func Serialize(tags *[]Tag) ([]byte, error) {
 func Deserialize(data []byte, startAt int) (*[]Tag, int, error) {
 	tags := &[]Tag{}
 	tagsEnd := startAt + 8 + 8
-	numberOfTags := int(data[startAt])
+	numberOfTags := binary.LittleEndian.Uint64(data[startAt : startAt+8])
 	numberOfTagBytesStart := startAt + 8
 	numberOfTagBytesEnd := numberOfTagBytesStart + 8
-	numberOfTagBytes := int(binary.LittleEndian.Uint16(data[numberOfTagBytesStart:numberOfTagBytesEnd]))
+	numberOfTagBytes := binary.LittleEndian.Uint64(data[numberOfTagBytesStart:numberOfTagBytesEnd])
 	if numberOfTags > 127 {
 		return tags, tagsEnd, errors.New("invalid data item - max tags 127")
 	}
 	if numberOfTags > 0 && numberOfTagBytes > 0 {
+		if numberOfTagBytes > uint64(len(data)-numberOfTagBytesEnd) {
+			return nil, tagsEnd, errors.New("invalid data item - tags exceed the binary")
+		}
 		bytesDataStart := numberOfTagBytesEnd
-		bytesDataEnd := numberOfTagBytesEnd + numberOfTagBytes
+		bytesDataEnd := numberOfTagBytesEnd + int(numberOfTagBytes)
 		bytesData := data[bytesDataStart:bytesDataEnd]
 
 		tags, err := fromAvro(bytesData)
@@ -1,6 +1,7 @@
 package tag
 
 import (
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -32,3 +33,30 @@ func TestDecodeAvro(t *testing.T) {
 	assert.Equal(t, len(*expected), len(*tags))
 	assert.ElementsMatch(t, *expected, *tags)
 }
+
+func TestSerializedSize(t *testing.T) {
+	assert.Equal(t, 0, SerializedSize(nil))
+	assert.Equal(t, 26, SerializedSize([]Tag{{Name: "Content-Type", Value: "text/plain"}}))
+
+	for _, tags := range [][]Tag{
+		{{Name: "a", Value: "b"}},
+		{{Name: strings.Repeat("n", 63), Value: strings.Repeat("v", 64)}},
+		{{Name: strings.Repeat("n", 1024), Value: strings.Repeat("v", 3072)}},
+		repeatTags(63),
+		repeatTags(64),
+		repeatTags(MAX_TAGS),
+	} {
+		b, err := Serialize(&tags)
+		assert.NoError(t, err)
+		assert.Equal(t, len(b), SerializedSize(tags))
+	}
+}
+
+// repeatTags returns n copies of the same tag
+func repeatTags(n int) []Tag {
+	tags := make([]Tag, n)
+	for i := range tags {
+		tags[i] = Tag{Name: "Index", Value: "1"}
+	}
+	return tags
+}
@@ -0,0 +1,218 @@
+// Command fixtures regenerates the generated binary test fixtures.
+//
+// The fixtures are written to test/generated, next to the third-party files
+// of the same names in test, which stay the reference for compatibility
+// with other implementations.
+//
+// Every fixture is derived from a fixed seed and the test wallet, so running
+// the command twice produces identical files. RSA-PSS signatures are salted:
+// the salt is read from a deterministic stream instead of crypto/rand, which
+// keeps signatures and IDs stable as well.
+//
+// Data items and bundles are assembled here from the ANS-104 layout rather
+// than with the data_item and bundle packages, then decoded and verified
+// with those packages, so a fixture never just mirrors the code it tests.
+//
+// Usage, from the test directory:
+//
+//	go run ./fixtures [-dir generated] [-wallet signer.json]
+//
+// To add a fixture, e.g. for a new signature type, append it to fixtures.
+package main
+
+import (
+	"bytes"
+	gocrypto "crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/binary"
+	"flag"
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction"
+	"github.com/liteseed/goar/transaction/bundle"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// fixture is a generated file. generate returns its content and a summary of the values tests assert on.
+type fixture struct {
+	name     string
+	generate func(s *signer.Signer) ([]byte, string, error)
+}
+
+var fixtures = []fixture{
+	{"1115BDataItem", func(s *signer.Signer) ([]byte, string, error) {
+		tags := []tag.Tag{
+			{Name: "Content-Type", Value: "text/plain"},
+			{Name: "App-Name", Value: "ArDrive-CLI"},
+			{Name: "App-Version", Value: "1.21.0"},
+		}
+		raw, id, err := dataItem(s, "1115BDataItem", tags, []byte("5670\n"))
+		if err != nil {
+			return nil, "", err
+		}
+		if err := verifyDataItem(raw); err != nil {
+			return nil, "", err
+		}
+		return raw, "id " + id, nil
+	}},
+	{"signed-bundle", func(s *signer.Signer) ([]byte, string, error) {
+		item, id, err := dataItem(s, "signed-bundle", nil, []byte("test test test test"))
+		if err != nil {
+			return nil, "", err
+		}
+		raw, err := bundleOf(item)
+		if err != nil {
+			return nil, "", err
+		}
+		ok, err := bundle.Verify(raw)
+		if err != nil {
+			return nil, "", err
+		}
+		if !ok {
+			return nil, "", fmt.Errorf("bundle does not verify")
+		}
+		return raw, fmt.Sprintf("item %s of %d bytes", id, len(item)), nil
+	}},
+	{"1MB.bin", func(s *signer.Signer) ([]byte, string, error) {
+		data := make([]byte, 1024*1024)
+		if _, err := newStream("1MB.bin").Read(data); err != nil {
+			return nil, "", err
+		}
+		tx := transaction.New(data, "", "0", nil)
+		if err := tx.PrepareChunks(data); err != nil {
+			return nil, "", err
+		}
+		return data, fmt.Sprintf("data root %s, %d chunks", tx.DataRoot, len(tx.ChunkData.Chunks)), nil
+	}},
+}
+
+func main() {
+	dir := flag.String("dir", "generated", "directory the fixtures are written to")
+	wallet := flag.String("wallet", "signer.json", "JWK file of the signing key")
+	flag.Parse()
+
+	s, err := signer.FromPath(*wallet)
+	if err != nil {
+		log.Fatal(err)
+	}
+	if err := os.MkdirAll(*dir, 0o755); err != nil {
+		log.Fatal(err)
+	}
+	for _, f := range fixtures {
+		b, summary, err := f.generate(s)
+		if err != nil {
+			log.Fatalf("%s: %v", f.name, err)
+		}
+		if err := os.WriteFile(filepath.Join(*dir, f.name), b, 0o644); err != nil {
+			log.Fatal(err)
+		}
+		fmt.Printf("%s: %d bytes, %s\n", f.name, len(b), summary)
+	}
+}
+
+// dataItem builds a signed ANS-104 data item without target or anchor, returning its binary and ID.
+func dataItem(s *signer.Signer, seed string, tags []tag.Tag, data []byte) ([]byte, string, error) {
+	owner, err := crypto.Base64URLDecode(s.Owner())
+	if err != nil {
+		return nil, "", err
+	}
+	rawTags, err := tag.Serialize(&tags)
+	if err != nil {
+		return nil, "", err
+	}
+
+	deepHash := crypto.DeepHash([][]byte{
+		[]byte("dataitem"),
+		[]byte("1"),
+		[]byte("1"),
+		owner,
+		{},
+		{},
+		rawTags,
+		data,
+	})
+	hashed := sha256.Sum256(deepHash[:])
+	signature, err := rsa.SignPSS(newStream(seed), s.PrivateKey, gocrypto.SHA256, hashed[:], &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthAuto,
+		Hash:       gocrypto.SHA256,
+	})
+	if err != nil {
+		return nil, "", err
+	}
+
+	var raw bytes.Buffer
+	raw.Write(binary.LittleEndian.AppendUint16(nil, 1))
+	raw.Write(signature)
+	raw.Write(owner)
+	raw.Write([]byte{0, 0}) // No target, no anchor
+	raw.Write(binary.LittleEndian.AppendUint64(nil, uint64(len(tags))))
+	raw.Write(binary.LittleEndian.AppendUint64(nil, uint64(len(rawTags))))
+	raw.Write(rawTags)
+	raw.Write(data)
+	return raw.Bytes(), crypto.Base64URLEncode(crypto.SHA256(signature)), nil
+}
+
+// verifyDataItem checks that the data item package decodes and verifies raw
+func verifyDataItem(raw []byte) error {
+	d, err := data_item.Decode(raw)
+	if err != nil {
+		return err
+	}
+	return d.Verify()
+}
+
+// bundleOf builds an ANS-104 bundle containing the given data items
+func bundleOf(items ...[]byte) ([]byte, error) {
+	var raw bytes.Buffer
+	raw.Write(uint256(len(items)))
+	for _, item := range items {
+		id := crypto.SHA256(item[2:514])
+		raw.Write(uint256(len(item)))
+		raw.Write(id)
+	}
+	for _, item := range items {
+		if err := verifyDataItem(item); err != nil {
+			return nil, err
+		}
+		raw.Write(item)
+	}
+	return raw.Bytes(), nil
+}
+
+// uint256 encodes n as a 32-byte little-endian integer
+func uint256(n int) []byte {
+	b := make([]byte, 32)
+	binary.LittleEndian.PutUint64(b, uint64(n))
+	return b
+}
+
+// stream is a deterministic byte stream: SHA-256 of the seed and a block counter
+type stream struct {
+	seed    []byte
+	counter uint64
+	buf     []byte
+}
+
+func newStream(seed string) *stream {
+	return &stream{seed: []byte(seed)}
+}
+
+func (s *stream) Read(p []byte) (int, error) {
+	for i := range p {
+		if len(s.buf) == 0 {
+			block := sha256.Sum256(binary.LittleEndian.AppendUint64(append([]byte{}, s.seed...), s.counter))
+			s.buf = block[:]
+			s.counter++
+		}
+		p[i] = s.buf[0]
+		s.buf = s.buf[1:]
+	}
+	return len(p), nil
+}
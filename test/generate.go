@@ -0,0 +1,14 @@
+// Package test holds the fixtures shared by the tests of every package.
+//
+// The binary fixtures in generated, 1115BDataItem, signed-bundle and
+// 1MB.bin, are derived from fixed seeds and signer.json. Regenerate them
+// with:
+//
+//	go generate ./test
+//
+// The files of the same names in test, rebar3 and lotsofdata.bin are
+// third-party files whose expectations come from other implementations,
+// and are not generated.
+package test
+
+//go:generate go run ./fixtures
@@ -0,0 +1,42 @@
+package test
+
+import (
+	"os"
+	"testing"
+
+	"github.com/liteseed/goar/transaction"
+	"github.com/liteseed/goar/transaction/bundle"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestGenerated verifies the generated fixtures against the values printed by go generate
+func TestGenerated(t *testing.T) {
+	t.Run("1115BDataItem", func(t *testing.T) {
+		data, err := os.ReadFile("generated/1115BDataItem")
+		require.NoError(t, err)
+		d, err := data_item.Decode(data)
+		require.NoError(t, err)
+		assert.NoError(t, d.Verify())
+		assert.Equal(t, "6WlGjzzvG8hjss5OYZCmf3VSweUilhkgPfrrl9DnhwU", d.ID)
+	})
+
+	t.Run("signed-bundle", func(t *testing.T) {
+		data, err := os.ReadFile("generated/signed-bundle")
+		require.NoError(t, err)
+		b, err := bundle.Decode(data)
+		require.NoError(t, err)
+		require.Len(t, b.Items, 1)
+		assert.Equal(t, "tVK-ILdogfKcswJVLvFeScA9rMICQiDe3GVShd2OY68", b.Items[0].ID)
+		assert.NoError(t, b.Items[0].Verify())
+	})
+
+	t.Run("1MB.bin", func(t *testing.T) {
+		data, err := os.ReadFile("generated/1MB.bin")
+		require.NoError(t, err)
+		tx := transaction.New(data, "", "0", nil)
+		require.NoError(t, tx.PrepareChunks(data))
+		assert.Equal(t, "OIVuydTWhN7TPIKYy5PPnOej0DW7Q5LCqYkYBsWN1FY", tx.DataRoot)
+	})
+}
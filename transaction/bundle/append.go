@@ -0,0 +1,98 @@
+package bundle
+
+import (
+	"bytes"
+	"slices"
+	"sync"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// Append adds signed data items to the end of the bundle without rebuilding it.
+//
+// The headers and items are extended and the binaries of the new items are
+// appended to the binary of the bundle body, so a producer can accumulate
+// items over time, check Size against a threshold, and Finalize the bundle
+// when it is reached. Raw is cleared until Finalize rebuilds it, since the
+// item count and header table at its start grow with every item. Append
+// works on an empty Bundle as well as on bundles created by New or Decode.
+//
+// Returns an error with code goar.ErrInvalidInput if an item is not
+// signed, in which case no item is appended.
+//
+// Example:
+//
+//	b := &bundle.Bundle{}
+//	for item := range items {
+//		if err := b.Append(item); err != nil {
+//			log.Fatal(err)
+//		}
+//		if b.Size() >= threshold {
+//			post(b.Finalize())
+//			b = &bundle.Bundle{}
+//		}
+//	}
+func (b *Bundle) Append(items ...*data_item.DataItem) error {
+	headers := make([]Header, len(items))
+	for i, item := range items {
+		id, err := crypto.Base64URLDecode(item.ID)
+		if err != nil || len(id) != 32 {
+			return goar.Errorf(goar.ErrInvalidInput, "data item %d is not signed", i)
+		}
+		size := int(item.RawSize())
+		headers[i] = Header{ID: item.ID, Size: size, Raw: append(id, longTo32ByteArray(size)...)}
+	}
+
+	if b.body == nil {
+		// Appending to the clipped body of Raw copies it once instead of overwriting Raw
+		b.body = slices.Clip(b.Raw[min(len(b.Raw), 32+64*len(b.Headers)):])
+	}
+	body := bytes.NewBuffer(b.body)
+	for _, item := range items {
+		if err := item.WriteRawTo(body); err != nil {
+			return err
+		}
+	}
+	b.body = body.Bytes()
+	b.Headers = append(b.Headers, headers...)
+	for _, item := range items {
+		b.Items = append(b.Items, *item)
+	}
+	b.Raw = nil
+
+	// The indexes of ItemsByTag and ItemsByOwner are rebuilt with the new items
+	b.tagOnce, b.ownerOnce = sync.Once{}, sync.Once{}
+	b.byTag, b.byOwner = nil, nil
+	return nil
+}
+
+// Size returns the size in bytes of the bundle binary, including the items appended since the last Finalize.
+func (b *Bundle) Size() int {
+	if b.body == nil && b.Raw != nil {
+		return len(b.Raw)
+	}
+	return 32 + 64*len(b.Headers) + len(b.body)
+}
+
+// Finalize builds Raw, the bundle binary, after items were appended with Append, and returns it.
+//
+// The bundle can still be appended to afterwards, and finalized again.
+// Raw is returned as is if nothing was appended since the bundle was
+// created or last finalized.
+func (b *Bundle) Finalize() []byte {
+	if b.body == nil && b.Raw != nil {
+		return b.Raw
+	}
+	raw := make([]byte, 0, b.Size())
+	raw = append(raw, longTo32ByteArray(len(b.Headers))...)
+	for _, h := range b.Headers {
+		id, _ := crypto.Base64URLDecode(h.ID)
+		raw = append(raw, longTo32ByteArray(h.Size)...)
+		raw = append(raw, id...)
+	}
+	b.Raw = append(raw, b.body...)
+	b.body = nil
+	return b.Raw
+}
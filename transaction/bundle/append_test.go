@@ -0,0 +1,76 @@
+package bundle
+
+import (
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAppend(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	items := make([]*data_item.DataItem, 5)
+	values := make([]data_item.DataItem, 5)
+	for i := range items {
+		tags := []tag.Tag{{Name: "Index", Value: string(rune('a' + i))}}
+		items[i] = data_item.New([]byte{byte(i)}, "", "", &tags)
+		require.NoError(t, items[i].Sign(s))
+		values[i] = *items[i]
+	}
+	expected, err := New(&values)
+	require.NoError(t, err)
+
+	t.Run("Empty bundle", func(t *testing.T) {
+		b := &Bundle{}
+		assert.Equal(t, 32, b.Size())
+		for _, item := range items {
+			require.NoError(t, b.Append(item))
+			assert.Nil(t, b.Raw)
+		}
+		assert.Equal(t, len(expected.Raw), b.Size())
+		assert.Equal(t, expected.Raw, b.Finalize())
+		assert.Equal(t, expected.Raw, b.Raw)
+		assert.Len(t, b.Items, 5)
+	})
+
+	t.Run("Decoded bundle", func(t *testing.T) {
+		first, err := New(&[]data_item.DataItem{values[0], values[1]})
+		require.NoError(t, err)
+		raw := append([]byte{}, first.Raw...)
+		b, err := Decode(first.Raw)
+		require.NoError(t, err)
+		assert.Len(t, b.ItemsByTag("Index", "c"), 0)
+
+		require.NoError(t, b.Append(items[2], items[3]))
+		require.NoError(t, b.Append(items[4]))
+		assert.Equal(t, expected.Raw, b.Finalize())
+		assert.Equal(t, raw, first.Raw, "the binary of the decoded bundle is not overwritten")
+		assert.Len(t, b.ItemsByTag("Index", "c"), 1)
+
+		decoded, err := Decode(b.Raw)
+		require.NoError(t, err)
+		assert.NoError(t, decoded.VerifyDeep())
+	})
+
+	t.Run("Finalized bundle", func(t *testing.T) {
+		b := &Bundle{}
+		require.NoError(t, b.Append(items[0], items[1], items[2]))
+		b.Finalize()
+		require.NoError(t, b.Append(items[3], items[4]))
+		assert.Equal(t, expected.Raw, b.Finalize())
+	})
+
+	t.Run("Unsigned item", func(t *testing.T) {
+		b := &Bundle{}
+		require.NoError(t, b.Append(items[0]))
+		err := b.Append(items[1], data_item.New([]byte("unsigned"), "", "", nil))
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+		assert.Len(t, b.Items, 1)
+		assert.Len(t, b.Headers, 1)
+	})
+}
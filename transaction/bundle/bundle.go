@@ -1,14 +1,19 @@
 package bundle
 
 import (
-	"errors"
+	"bytes"
+	"context"
 
+	"github.com/liteseed/goar"
 	"github.com/liteseed/goar/crypto"
 	"github.com/liteseed/goar/transaction/data_item"
 )
 
 // New Create a data bundle from a group of data items
 // Learn more: https://github.com/ArweaveTeam/arweave-standards/blob/master/ans/ANS-104.md
+//
+// The whole bundle binary is built in Raw. Use WriteTo, WriteFile or
+// NewStream to assemble large bundles without holding them in memory.
 func New(ds *[]data_item.DataItem) (*Bundle, error) {
 	b := &Bundle{}
 
@@ -21,70 +26,142 @@ func New(ds *[]data_item.DataItem) (*Bundle, error) {
 	b.Items = *ds
 	N := len(*ds)
 
-	var headersBytes []byte
-	var dataItemsBytes []byte
+	// Allocate the whole bundle once, large items would otherwise be copied on every growth
+	size := 32 + 64*N
+	for _, h := range *headers {
+		size += h.Size
+	}
+	buf := bytes.NewBuffer(make([]byte, 0, size))
+	buf.Write(longTo32ByteArray(N))
 
-	for i := 0; i < N; i++ {
-		h := (*headers)[i]
-		sizeBytes := longTo32ByteArray(h.Size)
+	for _, h := range *headers {
 		idBytes, err := crypto.Base64URLDecode(h.ID)
 		if err != nil {
 			return nil, err
 		}
-		headersBytes = append(headersBytes, sizeBytes...)
-		headersBytes = append(headersBytes, idBytes...)
+		buf.Write(longTo32ByteArray(h.Size))
+		buf.Write(idBytes)
+	}
 
-		d := (*ds)[i]
-		// Get complete raw data including payload for streaming data items
-		rawData, err := d.GetRawWithData()
-		if err != nil {
+	for i := range *ds {
+		// Write the complete binary, including the payload of streaming data items
+		if err := (*ds)[i].WriteRawTo(buf); err != nil {
 			return nil, err
 		}
-		dataItemsBytes = append(dataItemsBytes, rawData...)
 	}
 
-	raw := make([]byte, 0)
-	raw = append(raw, longTo32ByteArray(N)...)
-	raw = append(raw, headersBytes...)
-	raw = append(raw, dataItemsBytes...)
-	b.Raw = raw
+	b.Raw = buf.Bytes()
 	return b, nil
 }
 
 // Decode raw bytes into a Bundle
+//
+// Returns an error with code goar.ErrDecode if the header table does not
+// match the length of data or an item is malformed.
 func Decode(data []byte) (*Bundle, error) {
-	// length must more than 32
-	if len(data) < 32 {
-		return nil, errors.New("binary length must more than 32")
+	entries, err := readHeaderTable(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
 	}
-	headers, N := decodeBundleHeader(data)
 	bundle := &Bundle{
-		Items: make([]data_item.DataItem, N),
-		Raw:   data,
+		Headers: make([]Header, len(entries)),
+		Items:   make([]data_item.DataItem, len(entries)),
+		Raw:     data,
 	}
-	bundleStart := 32 + 64*N
-	for i := 0; i < N; i++ {
-		header := headers[i]
-		bundleEnd := bundleStart + header.Size
-		dataItem, err := data_item.Decode(data[bundleStart:bundleEnd])
+	for i, entry := range entries {
+		bundle.Headers[i] = Header{ID: entry.ID, Size: entry.Size, Raw: data[32+64*i : 32+64*(i+1)]}
+		dataItem, err := data_item.Decode(data[entry.Offset : entry.Offset+int64(entry.Size)])
 		if err != nil {
 			return nil, err
 		}
 		bundle.Items[i] = *dataItem
-		bundleStart = bundleEnd
 	}
 	return bundle, nil
 }
 
+// Verify checks that the header table of a bundle binary adds up to its length, see VerifyDeep to verify its items.
+//
+// Returns false and an error with code goar.ErrDecode saying why if it does not.
 func Verify(data []byte) (bool, error) {
-	// length must more than 32
-	if len(data) < 32 {
-		return false, errors.New("binary length must more than 32")
+	if _, err := readHeaderTable(bytes.NewReader(data), int64(len(data))); err != nil {
+		return false, err
 	}
-	headers, N := decodeBundleHeader(data)
-	dataItemSize := 0
-	for i := 0; i < N; i++ {
-		dataItemSize += headers[i].Size
+	return true, nil
+}
+
+// VerifyDeep verifies the signature and ID of every data item in the bundle.
+//
+// Unlike Verify, which only checks the binary layout, VerifyDeep checks
+// each item's signature and its ID against the header table. Items are
+// verified in parallel on the shared verification pool, see
+// crypto.SetVerifyConcurrency.
+//
+// A bundle with its binary in Raw is verified from Raw, every item decoded
+// on its own, so that all invalid items are reported, malformed ones
+// included. A gateway can thus verify a bundle it received before decoding
+// it:
+//
+//	err := (&bundle.Bundle{Raw: data}).VerifyDeep()
+//
+// Bundles without a binary, e.g. appended to and not finalized yet, are
+// verified from Items.
+//
+// Returns a *VerifyError holding the result of every item if any item is
+// invalid, with the code of the first one, usually goar.ErrInvalidSignature,
+// or an error with code goar.ErrDecode if the header table does not match
+// Raw.
+func (b *Bundle) VerifyDeep() error {
+	return b.VerifyDeepContext(context.Background())
+}
+
+// VerifyDeepContext is VerifyDeep bound to ctx.
+//
+// ctx is checked before each item is verified. When it is done, the
+// remaining items are skipped and ctx.Err() is returned unless an item
+// failed.
+func (b *Bundle) VerifyDeepContext(ctx context.Context) error {
+	if b.Raw != nil {
+		_, err := verifyItems(ctx, bytes.NewReader(b.Raw), int64(len(b.Raw)), nil)
+		return err
+	}
+	results := make([]ItemResult, len(b.Items))
+	for i := range b.Items {
+		results[i] = ItemResult{Index: i, ID: b.Items[i].ID, Size: int(b.Items[i].RawSize())}
+		if i < len(b.Headers) {
+			results[i].ID = b.Headers[i].ID
+		}
+	}
+	return verifyResults(ctx, results, func(result *ItemResult) error {
+		item := &b.Items[result.Index]
+		if result.ID != item.ID {
+			return goar.Errorf(goar.ErrInvalidSignature, "header ID %s does not match %s", result.ID, item.ID)
+		}
+		verify := item.VerifyRaw
+		if !item.HasRawData() {
+			verify = item.Verify
+		}
+		return goar.Wrap(goar.ErrInvalidSignature, verify())
+	}, nil)
+}
+
+// Filter creates a bundle holding only the items for which keep returns true.
+//
+// Items are copied to the new bundle in order, with their original binary,
+// so their IDs and signatures stay valid; the headers and the bundle binary
+// are regenerated. This lets a bundler drop spam or oversized items before
+// re-posting a bundle. The returned bundle may be empty.
+//
+// Example:
+//
+//	small, err := b.Filter(func(item *data_item.DataItem) bool {
+//		return item.RawSize() <= 1024*1024
+//	})
+func (b *Bundle) Filter(keep func(item *data_item.DataItem) bool) (*Bundle, error) {
+	items := []data_item.DataItem{}
+	for i := range b.Items {
+		if keep(&b.Items[i]) {
+			items = append(items, b.Items[i])
+		}
 	}
-	return len(data) == dataItemSize+32+64*N, nil
+	return New(&items)
 }
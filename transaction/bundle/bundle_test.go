@@ -1,10 +1,17 @@
 package bundle
 
 import (
+	"bytes"
+	"encoding/binary"
+	"math"
 	"os"
 	"testing"
 
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/transaction/data_item"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestDecode(t *testing.T) {
@@ -17,3 +24,151 @@ func TestDecode(t *testing.T) {
 	assert.NotNil(t, b)
 
 }
+
+func TestDecodeTruncated(t *testing.T) {
+	data, err := os.ReadFile("../../test/signed-bundle")
+	assert.NoError(t, err)
+
+	_, err = Decode(data[:16])
+	assert.ErrorIs(t, err, goar.ErrDecode)
+
+	_, err = Decode(data[:64])
+	assert.ErrorIs(t, err, goar.ErrDecode)
+
+	_, err = Decode(data[:len(data)-10])
+	assert.ErrorIs(t, err, goar.ErrDecode)
+
+	ok, err := Verify(data[:64])
+	assert.ErrorIs(t, err, goar.ErrDecode)
+	assert.False(t, ok)
+
+	// A count of 2^58 overflows 64*N
+	hostile := make([]byte, 96)
+	hostile[7] = 0x04
+	_, err = Decode(hostile)
+	assert.ErrorIs(t, err, goar.ErrDecode)
+	ok, err = Verify(hostile)
+	assert.ErrorIs(t, err, goar.ErrDecode)
+	assert.False(t, ok)
+}
+
+// TestDecodeOverflowingSizes decodes header tables whose item sizes add up to
+// the bundle length only once they wrap around
+func TestDecodeOverflowingSizes(t *testing.T) {
+	// 3 items of MaxInt64-100, MaxInt64 and 278 bytes in a 400 byte bundle
+	wrapping := make([]byte, 400)
+	wrapping[0] = 3
+	binary.LittleEndian.PutUint64(wrapping[32:], math.MaxInt64-100)
+	binary.LittleEndian.PutUint64(wrapping[96:], math.MaxInt64)
+	binary.LittleEndian.PutUint64(wrapping[160:], 278)
+
+	// 1 item of 2^64+176 bytes in a 272 byte bundle
+	wide := make([]byte, 272)
+	wide[0] = 1
+	wide[32] = 176
+	wide[40] = 1
+
+	for name, data := range map[string][]byte{"Wrapping": wrapping, "Wide": wide} {
+		t.Run(name, func(t *testing.T) {
+			_, err := Decode(data)
+			assert.ErrorIs(t, err, goar.ErrDecode)
+			ok, err := Verify(data)
+			assert.ErrorIs(t, err, goar.ErrDecode)
+			assert.False(t, ok)
+			_, err = NewReader(bytes.NewReader(data), int64(len(data)))
+			assert.ErrorIs(t, err, goar.ErrDecode)
+			assert.ErrorIs(t, (&Bundle{Raw: data}).VerifyDeep(), goar.ErrDecode)
+		})
+	}
+}
+
+func TestNewStreaming(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte{7}, 100_000)
+	streamed := data_item.NewFromReader(bytes.NewReader(data), int64(len(data)), "", "", nil)
+	require.NoError(t, streamed.Sign(s))
+	inMemory := data_item.New([]byte("small"), "", "", nil)
+	require.NoError(t, inMemory.Sign(s))
+
+	b, err := New(&[]data_item.DataItem{*streamed, *inMemory})
+	require.NoError(t, err)
+	assert.Equal(t, int(streamed.RawSize()), b.Headers[0].Size)
+	assert.Equal(t, 32+64*2+int(streamed.RawSize()+inMemory.RawSize()), len(b.Raw))
+	assert.Equal(t, len(b.Raw), cap(b.Raw))
+
+	decoded, err := Decode(b.Raw)
+	require.NoError(t, err)
+	require.Len(t, decoded.Items, 2)
+	assert.Equal(t, streamed.ID, decoded.Items[0].ID)
+	assert.Equal(t, data, decoded.Items[0].RawData())
+	assert.Equal(t, inMemory.ID, decoded.Items[1].ID)
+}
+
+func TestVerifyDeep(t *testing.T) {
+	data, err := os.ReadFile("../../test/signed-bundle")
+	require.NoError(t, err)
+
+	b, err := Decode(data)
+	require.NoError(t, err)
+	assert.NoError(t, b.VerifyDeep())
+
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	streamed := data_item.NewFromReader(bytes.NewReader([]byte("streamed")), 8, "", "", nil)
+	require.NoError(t, streamed.Sign(s))
+	created, err := New(&[]data_item.DataItem{*streamed})
+	require.NoError(t, err)
+	assert.NoError(t, created.VerifyDeep())
+
+	// Flip the last byte of the last item's data
+	tampered := bytes.Clone(data)
+	tampered[len(tampered)-1] ^= 0xff
+	b, err = Decode(tampered)
+	require.NoError(t, err)
+	err = b.VerifyDeep()
+	assert.ErrorIs(t, err, goar.ErrInvalidSignature)
+	assert.ErrorContains(t, err, b.Items[len(b.Items)-1].ID)
+}
+
+func TestFilter(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	var items []data_item.DataItem
+	for _, data := range []string{"keep", "spam", "keep too"} {
+		item := data_item.New([]byte(data), "", "", nil)
+		require.NoError(t, item.Sign(s))
+		items = append(items, *item)
+	}
+	b, err := New(&items)
+	require.NoError(t, err)
+	decoded, err := Decode(b.Raw)
+	require.NoError(t, err)
+
+	filtered, err := decoded.Filter(func(item *data_item.DataItem) bool {
+		return string(item.RawData()) != "spam"
+	})
+	require.NoError(t, err)
+	require.Len(t, filtered.Items, 2)
+	require.Len(t, filtered.Headers, 2)
+	assert.Equal(t, items[0].ID, filtered.Headers[0].ID)
+	assert.Equal(t, items[2].ID, filtered.Headers[1].ID)
+	assert.Equal(t, int(items[2].RawSize()), filtered.Headers[1].Size)
+
+	ok, err := Verify(filtered.Raw)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	redecoded, err := Decode(filtered.Raw)
+	require.NoError(t, err)
+	assert.NoError(t, redecoded.VerifyDeep())
+	assert.Equal(t, []byte("keep too"), redecoded.Items[1].RawData())
+
+	// The original bundle is left untouched
+	assert.Len(t, decoded.Items, 3)
+
+	empty, err := decoded.Filter(func(*data_item.DataItem) bool { return false })
+	require.NoError(t, err)
+	assert.Empty(t, empty.Items)
+	assert.Len(t, empty.Raw, 32)
+}
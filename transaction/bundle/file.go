@@ -0,0 +1,210 @@
+package bundle
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// ItemResult is the verification result of one data item of a bundle, see VerifyFile and Bundle.VerifyDeep.
+type ItemResult struct {
+	Index  int    // Position of the item in the bundle
+	ID     string // ID of the item according to the header table
+	Offset int64  // Position of the item binary in the bundle, 0 for a bundle without its binary
+	Size   int    // Size of the item binary
+	Err    error  // Why the item is invalid, nil if it verified
+}
+
+// VerifyError is the error returned by VerifyFile and Bundle.VerifyDeep when
+// data items are invalid. It unwraps to the error of the first invalid item,
+// so errors.Is matches its code, and holds the result of every item so that
+// a gateway can tell exactly which items of a bundle are invalid and why.
+//
+// Example:
+//
+//	var verr *bundle.VerifyError
+//	if errors.As(b.VerifyDeep(), &verr) {
+//		for _, r := range verr.Results {
+//			if r.Err != nil {
+//				log.Printf("rejecting data item %d (%s): %v", r.Index, r.ID, r.Err)
+//			}
+//		}
+//	}
+type VerifyError struct {
+	Results []ItemResult // Result of every item in bundle order
+}
+
+func (e *VerifyError) Error() string {
+	failed := 0
+	for _, r := range e.Results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	first := e.first()
+	return fmt.Sprintf("%d of %d data items are invalid, first: data item %d (%s): %v", failed, len(e.Results), first.Index, first.ID, first.Err)
+}
+
+// Unwrap returns the error of the first invalid item.
+func (e *VerifyError) Unwrap() error {
+	return e.first().Err
+}
+
+// first returns the result of the first invalid item
+func (e *VerifyError) first() *ItemResult {
+	for i := range e.Results {
+		if e.Results[i].Err != nil {
+			return &e.Results[i]
+		}
+	}
+	return &ItemResult{}
+}
+
+// Progress is called by VerifyFile after each item is verified, with the
+// number of items verified so far. Calls are never concurrent but arrive
+// in completion order, not bundle order.
+type Progress func(result ItemResult, done int, total int)
+
+// VerifyFile verifies a bundle file without loading it into memory.
+//
+// The header table is checked against the file size first, then every item
+// is read at its offset, decoded, checked against its header ID and its
+// signature verified, as Bundle.VerifyDeep does for a bundle in memory.
+// Items are verified in parallel on the shared verification pool, see
+// crypto.SetVerifyConcurrency. Only the headers of the items are loaded:
+// their data is hashed as it is read from the file, so memory does not grow
+// with the size of the items.
+//
+// Parameters:
+//   - path: The bundle file
+//   - progress: Optional function called after each item
+//
+// Returns the result of every item in bundle order. The error has code
+// goar.ErrDecode if the header table does not match the file, in which case
+// no item is verified, or is a *VerifyError with the code of the first
+// invalid item, usually goar.ErrInvalidSignature, if any item failed.
+//
+// Example:
+//
+//	results, err := bundle.VerifyFile("bundle.bin", func(r bundle.ItemResult, done, total int) {
+//		fmt.Printf("%d/%d %s %v\n", done, total, r.ID, r.Err)
+//	})
+func VerifyFile(path string, progress Progress) ([]ItemResult, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	return verifyItems(context.Background(), f, info.Size(), progress)
+}
+
+// verifyItems verifies the items of the bundle of size bytes in r
+func verifyItems(ctx context.Context, r io.ReaderAt, size int64, progress Progress) ([]ItemResult, error) {
+	results, err := readHeaderTable(r, size)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyResults(ctx, results, func(result *ItemResult) error {
+		return verifyItem(r, result)
+	}, progress)
+	return results, err
+}
+
+// verifyResults sets the Err of every result with verify on the shared verification pool.
+//
+// ctx is checked before each item is verified. Returns a *VerifyError if
+// any item failed, otherwise ctx.Err().
+func verifyResults(ctx context.Context, results []ItemResult, verify func(result *ItemResult) error, progress Progress) error {
+	var mu sync.Mutex
+	done := 0
+	jobs := make(chan int)
+	var wg sync.WaitGroup
+	for w := 0; w < min(crypto.VerifyConcurrency(), len(results)); w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range jobs {
+				results[i].Err = verify(&results[i])
+				mu.Lock()
+				done++
+				if progress != nil {
+					progress(results[i], done, len(results))
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+	for i := range results {
+		if ctx.Err() != nil {
+			break
+		}
+		jobs <- i
+	}
+	close(jobs)
+	wg.Wait()
+
+	for _, r := range results {
+		if r.Err != nil {
+			return &VerifyError{Results: results}
+		}
+	}
+	return ctx.Err()
+}
+
+// readHeaderTable reads the header table of a bundle of size bytes and
+// returns one result per item with its ID, offset and size
+func readHeaderTable(r io.ReaderAt, size int64) ([]ItemResult, error) {
+	count := make([]byte, 32)
+	if _, err := r.ReadAt(count, 0); err != nil {
+		return nil, goar.Errorf(goar.ErrDecode, "binary length must more than 32")
+	}
+	N, ok := headerField(count)
+	// Compare the count before multiplying, which a hostile count overflows
+	if !ok || N > (size-32)/64 {
+		return nil, goar.Errorf(goar.ErrDecode, "binary too small for bundle header")
+	}
+	table := make([]byte, 64*N)
+	if _, err := r.ReadAt(table, 32); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+
+	results := make([]ItemResult, N)
+	offset := 32 + 64*N
+	for i := range results {
+		entry := table[64*i : 64*(i+1)]
+		// offset <= size here, so size-offset cannot overflow where offset+itemSize could
+		itemSize, ok := headerField(entry[:32])
+		if !ok || itemSize <= 0 || itemSize > size-offset {
+			return nil, goar.Errorf(goar.ErrDecode, "data item %d exceeds bundle length", i)
+		}
+		results[i] = ItemResult{Index: i, ID: crypto.Base64URLEncode(entry[32:]), Offset: offset, Size: int(itemSize)}
+		offset += itemSize
+	}
+	if offset != size {
+		return nil, goar.Errorf(goar.ErrDecode, "bundle has %d trailing bytes", size-offset)
+	}
+	return results, nil
+}
+
+// verifyItem decodes the header of the item of result and verifies it, hashing its data straight from r
+func verifyItem(r io.ReaderAt, result *ItemResult) error {
+	item, err := data_item.DecodeStream(io.NewSectionReader(r, result.Offset, int64(result.Size)), int64(result.Size))
+	if err != nil {
+		return err
+	}
+	if item.ID != result.ID {
+		return goar.Errorf(goar.ErrInvalidSignature, "header ID %s does not match %s", result.ID, item.ID)
+	}
+	return goar.Wrap(goar.ErrInvalidSignature, item.Verify())
+}
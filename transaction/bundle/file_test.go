@@ -0,0 +1,168 @@
+package bundle
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyFile(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	var items []data_item.DataItem
+	for _, data := range []string{"first", "second", "third"} {
+		item := data_item.New([]byte(data), "", "", nil)
+		require.NoError(t, item.Sign(s))
+		items = append(items, *item)
+	}
+	b, err := New(&items)
+	require.NoError(t, err)
+
+	dir := t.TempDir()
+	write := func(name string, data []byte) string {
+		path := filepath.Join(dir, name)
+		require.NoError(t, os.WriteFile(path, data, 0o644))
+		return path
+	}
+
+	t.Run("Valid", func(t *testing.T) {
+		seen := map[int]bool{}
+		results, err := VerifyFile(write("valid", b.Raw), func(r ItemResult, done, total int) {
+			assert.Equal(t, 3, total)
+			assert.Equal(t, len(seen)+1, done)
+			seen[r.Index] = true
+		})
+		require.NoError(t, err)
+		require.Len(t, results, 3)
+		assert.Len(t, seen, 3)
+		offset := int64(32 + 64*3)
+		for i, r := range results {
+			assert.Equal(t, items[i].ID, r.ID)
+			assert.Equal(t, offset, r.Offset)
+			assert.NoError(t, r.Err)
+			offset += int64(r.Size)
+		}
+	})
+
+	t.Run("Tampered item", func(t *testing.T) {
+		tampered := append([]byte(nil), b.Raw...)
+		// Flip the last byte of the second item's data
+		second := headerTable(t, b)[1]
+		tampered[second.Offset+int64(second.Size)-1] ^= 0xff
+		results, err := VerifyFile(write("tampered", tampered), nil)
+		assert.ErrorIs(t, err, goar.ErrInvalidSignature)
+		assert.ErrorContains(t, err, "1 of 3")
+		require.Len(t, results, 3)
+		assert.NoError(t, results[0].Err)
+		assert.Error(t, results[1].Err)
+		assert.NoError(t, results[2].Err)
+	})
+
+	t.Run("Bad header table", func(t *testing.T) {
+		_, err := VerifyFile(write("truncated", b.Raw[:len(b.Raw)-1]), nil)
+		assert.ErrorIs(t, err, goar.ErrDecode)
+		_, err = VerifyFile(write("trailing", append(append([]byte(nil), b.Raw...), 0)), nil)
+		assert.ErrorIs(t, err, goar.ErrDecode)
+		_, err = VerifyFile(write("short", b.Raw[:16]), nil)
+		assert.ErrorIs(t, err, goar.ErrDecode)
+
+		// A count of 2^58 overflows 64*N
+		hostile := make([]byte, 96)
+		hostile[7] = 0x04
+		_, err = VerifyFile(write("hostile", hostile), nil)
+		assert.ErrorIs(t, err, goar.ErrDecode)
+		assert.ErrorIs(t, (&Bundle{Raw: hostile}).VerifyDeep(), goar.ErrDecode)
+		_, err = NewReader(bytes.NewReader(hostile), int64(len(hostile)))
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+
+	t.Run("Signed bundle", func(t *testing.T) {
+		results, err := VerifyFile("../../test/signed-bundle", nil)
+		require.NoError(t, err)
+		assert.NotEmpty(t, results)
+	})
+}
+
+// TestVerifyDeepResults verifies the per-item results of VerifyDeep on a bundle binary
+func TestVerifyDeepResults(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	var items []data_item.DataItem
+	for _, data := range []string{"first", "second", "third"} {
+		item := data_item.New([]byte(data), "", "", nil)
+		require.NoError(t, item.Sign(s))
+		items = append(items, *item)
+	}
+	b, err := New(&items)
+	require.NoError(t, err)
+	table := headerTable(t, b)
+
+	t.Run("Valid", func(t *testing.T) {
+		assert.NoError(t, (&Bundle{Raw: b.Raw}).VerifyDeep())
+	})
+
+	t.Run("Per-item errors", func(t *testing.T) {
+		bad := append([]byte(nil), b.Raw...)
+		// Unknown signature type for the first item, a swapped header ID for the second
+		bad[table[0].Offset] = 0xff
+		copy(bad[32+64+32:32+64+64], bad[32+64*2+32:32+64*3])
+		_, err := Decode(bad)
+		require.Error(t, err)
+
+		err = (&Bundle{Raw: bad}).VerifyDeep()
+		assert.ErrorContains(t, err, "2 of 3")
+		assert.ErrorIs(t, err, goar.ErrDecode)
+		var verr *VerifyError
+		require.ErrorAs(t, err, &verr)
+		results := verr.Results
+		require.Len(t, results, 3)
+		for i, r := range results {
+			assert.Equal(t, i, r.Index)
+			assert.Equal(t, table[i].Offset, r.Offset)
+		}
+		assert.ErrorIs(t, results[0].Err, goar.ErrDecode)
+		assert.ErrorIs(t, results[1].Err, goar.ErrInvalidSignature)
+		assert.ErrorContains(t, results[1].Err, "header ID")
+		assert.NoError(t, results[2].Err)
+	})
+
+	t.Run("Without the binary", func(t *testing.T) {
+		swapped := append([]data_item.DataItem(nil), items...)
+		swapped[1].Signature = swapped[0].Signature
+		err := (&Bundle{Items: swapped}).VerifyDeep()
+		var verr *VerifyError
+		require.ErrorAs(t, err, &verr)
+		assert.NoError(t, verr.Results[0].Err)
+		assert.ErrorIs(t, verr.Results[1].Err, goar.ErrInvalidSignature)
+		assert.NoError(t, verr.Results[2].Err)
+	})
+
+	t.Run("Bad header table", func(t *testing.T) {
+		err := (&Bundle{Raw: b.Raw[:len(b.Raw)-1]}).VerifyDeep()
+		assert.ErrorIs(t, err, goar.ErrDecode)
+		var verr *VerifyError
+		assert.False(t, errors.As(err, &verr))
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, b.VerifyDeepContext(ctx), context.Canceled)
+	})
+}
+
+// headerTable returns the header table of b
+func headerTable(t *testing.T, b *Bundle) []ItemResult {
+	r, err := readHeaderTable(bytes.NewReader(b.Raw), int64(len(b.Raw)))
+	require.NoError(t, err)
+	return r
+}
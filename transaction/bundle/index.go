@@ -0,0 +1,73 @@
+package bundle
+
+import (
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// tagKey is a tag name and value indexed by ItemsByTag
+type tagKey struct {
+	name  string
+	value string
+}
+
+// ItemsByTag returns the items carrying the tag name with value, in bundle order.
+//
+// The index of all the tags of the bundle is built on the first call, so
+// routing many items of a decoded bundle scans it once. Items whose tags do
+// not decode are left out. The index is not updated if Items is modified
+// afterwards. ItemsByTag is safe for concurrent use.
+//
+// Example:
+//
+//	b, err := bundle.Decode(raw)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, item := range b.ItemsByTag("App-Name", "MyApp") {
+//		route(item)
+//	}
+func (b *Bundle) ItemsByTag(name string, value string) []*data_item.DataItem {
+	b.tagOnce.Do(func() {
+		b.byTag = map[tagKey][]int{}
+		for i := range b.Items {
+			tags, err := b.Items[i].GetTags()
+			if err != nil || tags == nil {
+				continue
+			}
+			seen := map[tagKey]bool{}
+			for _, t := range *tags {
+				key := tagKey{t.Name, t.Value}
+				if !seen[key] {
+					seen[key] = true
+					b.byTag[key] = append(b.byTag[key], i)
+				}
+			}
+		}
+	})
+	return b.items(b.byTag[tagKey{name, value}])
+}
+
+// ItemsByOwner returns the items signed by address, in bundle order.
+//
+// Addresses are compared in the native format of each signature type, see
+// data_item.DataItem.GetOwnerAddress. As with ItemsByTag, the index is
+// built on the first call and ItemsByOwner is safe for concurrent use.
+func (b *Bundle) ItemsByOwner(address string) []*data_item.DataItem {
+	b.ownerOnce.Do(func() {
+		b.byOwner = map[string][]int{}
+		for i := range b.Items {
+			owner := b.Items[i].GetOwnerAddress()
+			b.byOwner[owner] = append(b.byOwner[owner], i)
+		}
+	})
+	return b.items(b.byOwner[address])
+}
+
+// items returns the items at positions
+func (b *Bundle) items(positions []int) []*data_item.DataItem {
+	items := make([]*data_item.DataItem, len(positions))
+	for i, position := range positions {
+		items[i] = &b.Items[position]
+	}
+	return items
+}
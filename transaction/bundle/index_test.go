@@ -0,0 +1,78 @@
+package bundle
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemsIndex(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	ed, err := signer.NewED25519()
+	require.NoError(t, err)
+
+	items := []data_item.DataItem{}
+	for i, spec := range []struct {
+		signer signer.ItemSigner
+		tags   []tag.Tag
+	}{
+		{s, []tag.Tag{{Name: "App-Name", Value: "A"}, {Name: "Type", Value: "post"}}},
+		{ed, []tag.Tag{{Name: "App-Name", Value: "B"}}},
+		{s, []tag.Tag{{Name: "App-Name", Value: "A"}, {Name: "App-Name", Value: "A"}}},
+		{ed, nil},
+	} {
+		item := data_item.New([]byte{byte(i)}, "", "", &spec.tags)
+		require.NoError(t, item.Sign(spec.signer))
+		items = append(items, *item)
+	}
+	built, err := New(&items)
+	require.NoError(t, err)
+	b, err := Decode(built.Raw)
+	require.NoError(t, err)
+
+	ids := func(items []*data_item.DataItem) []string {
+		result := []string{}
+		for _, item := range items {
+			result = append(result, item.ID)
+		}
+		return result
+	}
+
+	t.Run("ByTag", func(t *testing.T) {
+		assert.Equal(t, []string{items[0].ID, items[2].ID}, ids(b.ItemsByTag("App-Name", "A")))
+		assert.Equal(t, []string{items[1].ID}, ids(b.ItemsByTag("App-Name", "B")))
+		assert.Equal(t, []string{items[0].ID}, ids(b.ItemsByTag("Type", "post")))
+		assert.Empty(t, b.ItemsByTag("Type", "A"))
+		assert.Same(t, &b.Items[1], b.ItemsByTag("App-Name", "B")[0])
+	})
+
+	t.Run("ByOwner", func(t *testing.T) {
+		assert.Equal(t, []string{items[0].ID, items[2].ID}, ids(b.ItemsByOwner(s.Address)))
+		assert.Equal(t, []string{items[1].ID, items[3].ID}, ids(b.ItemsByOwner(items[1].GetOwnerAddress())))
+		assert.Empty(t, b.ItemsByOwner("unknown"))
+	})
+
+	t.Run("Concurrent", func(t *testing.T) {
+		b, err := Decode(built.Raw)
+		require.NoError(t, err)
+		var wg sync.WaitGroup
+		for range 8 {
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				assert.Len(t, b.ItemsByTag("App-Name", "A"), 2)
+			}()
+			go func() {
+				defer wg.Done()
+				assert.Len(t, b.ItemsByOwner(s.Address), 2)
+			}()
+		}
+		wg.Wait()
+	})
+}
@@ -0,0 +1,111 @@
+package bundle
+
+import (
+	"io"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// Tags marking the data of a transaction or data item as an ANS-104 bundle
+const (
+	BUNDLE_FORMAT  = "binary" // Value of the Bundle-Format tag
+	BUNDLE_VERSION = "2.0.0"  // Value of the Bundle-Version tag
+)
+
+// Tags returns the Bundle-Format and Bundle-Version tags of a transaction or data item holding a bundle.
+func Tags() []tag.Tag {
+	return []tag.Tag{
+		{Name: "Bundle-Format", Value: BUNDLE_FORMAT},
+		{Name: "Bundle-Version", Value: BUNDLE_VERSION},
+	}
+}
+
+// ToDataItem wraps the bundle in a data item, nesting it as ANS-104 allows.
+//
+// The data item holds the bundle binary as its data, with the bundle tags
+// followed by tags. Bundle-Format and Bundle-Version tags in tags are
+// replaced. The returned item is unsigned: sign it, then post it to a
+// bundler or in a bundle of its own, as any other data item.
+//
+// Parameters:
+//   - target: Optional target address of the data item
+//   - anchor: Optional anchor of the data item
+//   - tags: Optional additional tags
+//
+// Example:
+//
+//	inner, err := bundle.New(&items)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	nested := inner.ToDataItem("", "", nil)
+//	err = nested.Sign(s)
+func (b *Bundle) ToDataItem(target string, anchor string, tags *[]tag.Tag) *data_item.DataItem {
+	nestedTags := Tags()
+	if tags != nil {
+		for _, t := range *tags {
+			if !isBundleTag(t.Name) {
+				nestedTags = append(nestedTags, t)
+			}
+		}
+	}
+	return data_item.New(b.Raw, target, anchor, &nestedTags)
+}
+
+// IsBundle reports whether the tags of a data item mark its data as a bundle, see Tags.
+func IsBundle(d *data_item.DataItem) bool {
+	tags, err := d.GetTags()
+	if err != nil || tags == nil {
+		return false
+	}
+	var format, version bool
+	for _, t := range *tags {
+		switch t.Name {
+		case "Bundle-Format":
+			format = t.Value == BUNDLE_FORMAT
+		case "Bundle-Version":
+			version = t.Value == BUNDLE_VERSION
+		}
+	}
+	return format && version
+}
+
+// FromDataItem decodes the bundle nested in a data item.
+//
+// The data of the item is read into memory, from its DataReader for
+// streaming items. To read a large nested bundle from a file, pass the
+// reader returned by Reader.ItemData to NewReader instead.
+//
+// Returns an error with code goar.ErrInvalidInput if the item is not tagged
+// as a bundle, see IsBundle, or goar.ErrDecode if its data is not a valid
+// bundle.
+//
+// Example:
+//
+//	if bundle.IsBundle(item) {
+//		inner, err := bundle.FromDataItem(item)
+//		...
+//	}
+func FromDataItem(d *data_item.DataItem) (*Bundle, error) {
+	if !IsBundle(d) {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "data item %s is not tagged as a bundle", d.ID)
+	}
+	data := d.RawData()
+	if d.DataReader != nil {
+		if _, err := d.DataReader.Seek(0, io.SeekStart); err != nil {
+			return nil, goar.Wrap(goar.ErrDecode, err)
+		}
+		var err error
+		if data, err = io.ReadAll(d.DataReader); err != nil {
+			return nil, goar.Wrap(goar.ErrDecode, err)
+		}
+	}
+	return Decode(data)
+}
+
+// isBundleTag reports whether name is the name of a tag set by Tags
+func isBundleTag(name string) bool {
+	return name == "Bundle-Format" || name == "Bundle-Version"
+}
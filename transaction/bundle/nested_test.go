@@ -0,0 +1,70 @@
+package bundle
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNestedBundle verifies a bundle wrapped in a data item survives an outer bundle
+func TestNestedBundle(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	var items []data_item.DataItem
+	for _, data := range []string{"first", "second"} {
+		item := data_item.New([]byte(data), "", "", nil)
+		require.NoError(t, item.Sign(s))
+		items = append(items, *item)
+	}
+	inner, err := New(&items)
+	require.NoError(t, err)
+
+	tags := []tag.Tag{{Name: "Bundle-Format", Value: "json"}, {Name: "App-Name", Value: "goar"}}
+	nested := inner.ToDataItem("", "", &tags)
+	require.NoError(t, nested.Sign(s))
+	assert.Equal(t, append(Tags(), tag.Tag{Name: "App-Name", Value: "goar"}), *nested.Tags)
+	assert.True(t, IsBundle(nested))
+
+	plain := data_item.New([]byte("plain"), "", "", nil)
+	require.NoError(t, plain.Sign(s))
+	outer, err := New(&[]data_item.DataItem{*nested, *plain})
+	require.NoError(t, err)
+
+	decoded, err := Decode(outer.Raw)
+	require.NoError(t, err)
+	require.True(t, IsBundle(&decoded.Items[0]))
+	assert.False(t, IsBundle(&decoded.Items[1]))
+
+	got, err := FromDataItem(&decoded.Items[0])
+	require.NoError(t, err)
+	require.Len(t, got.Items, 2)
+	assert.Equal(t, items[0].ID, got.Items[0].ID)
+	assert.Equal(t, []byte("second"), got.Items[1].RawData())
+	assert.NoError(t, got.VerifyDeep())
+
+	t.Run("Streaming", func(t *testing.T) {
+		r, err := NewReader(bytes.NewReader(outer.Raw), int64(len(outer.Raw)))
+		require.NoError(t, err)
+		header, err := r.ItemHeader(0)
+		require.NoError(t, err)
+		got, err := FromDataItem(header)
+		require.NoError(t, err)
+		assert.Equal(t, items[1].ID, got.Items[1].ID)
+	})
+
+	t.Run("NotABundle", func(t *testing.T) {
+		_, err := FromDataItem(&decoded.Items[1])
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+
+		fake := data_item.New([]byte("x"), "", "", func() *[]tag.Tag { t := Tags(); return &t }())
+		require.NoError(t, fake.Sign(s))
+		_, err = FromDataItem(fake)
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
@@ -0,0 +1,145 @@
+package bundle
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// Reader gives random access to the data items of a bundle binary without loading it into memory.
+//
+// Only the header table is read by NewReader. Items are read from the
+// underlying io.ReaderAt when accessed, so a reader over a bundle file of
+// several gigabytes holds 64 bytes per item. It is safe for concurrent use
+// if the io.ReaderAt is, as *os.File is.
+//
+// Example:
+//
+//	f, err := os.Open("bundle.bin")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	info, _ := f.Stat()
+//	r, err := bundle.NewReader(f, info.Size())
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for i := 0; i < r.ItemCount(); i++ {
+//		item, err := r.ItemHeader(i)
+//		...
+//	}
+type Reader struct {
+	r     io.ReaderAt
+	items []ItemResult
+}
+
+// NewReader reads the header table of the bundle binary of size bytes in r.
+//
+// Returns an error with code goar.ErrDecode if the header table does not
+// match size.
+func NewReader(r io.ReaderAt, size int64) (*Reader, error) {
+	items, err := readHeaderTable(r, size)
+	if err != nil {
+		return nil, err
+	}
+	return &Reader{r: r, items: items}, nil
+}
+
+// ItemCount returns the number of data items in the bundle.
+func (br *Reader) ItemCount() int {
+	return len(br.items)
+}
+
+// ItemID returns the ID of data item i according to the header table.
+func (br *Reader) ItemID(i int) string {
+	return br.items[i].ID
+}
+
+// ItemHeader decodes the header of data item i, leaving its data in the bundle.
+//
+// The returned data item is a streaming one reading its data from the
+// bundle, see data_item.DecodeStream, so it can be verified with Verify or
+// copied with WriteRawTo without being held in memory.
+//
+// Returns an error with code goar.ErrInvalidInput if i is out of range, or
+// goar.ErrDecode if the item is invalid or its ID does not match the header
+// table.
+func (br *Reader) ItemHeader(i int) (*data_item.DataItem, error) {
+	entry, err := br.entry(i)
+	if err != nil {
+		return nil, err
+	}
+	item, err := data_item.DecodeStream(io.NewSectionReader(br.r, entry.Offset, int64(entry.Size)), int64(entry.Size))
+	if err != nil {
+		return nil, fmt.Errorf("data item %d: %w", i, err)
+	}
+	if err := checkID(entry, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// DecodeItem reads and decodes data item i, including its data, as Decode does for a bundle in memory.
+//
+// Returns an error with code goar.ErrInvalidInput if i is out of range, or
+// goar.ErrDecode if the item is invalid or its ID does not match the header
+// table.
+func (br *Reader) DecodeItem(i int) (*data_item.DataItem, error) {
+	entry, err := br.entry(i)
+	if err != nil {
+		return nil, err
+	}
+	raw := make([]byte, entry.Size)
+	if _, err := br.r.ReadAt(raw, entry.Offset); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	item, err := data_item.Decode(raw)
+	if err != nil {
+		return nil, fmt.Errorf("data item %d: %w", i, err)
+	}
+	if err := checkID(entry, item); err != nil {
+		return nil, err
+	}
+	return item, nil
+}
+
+// ItemData returns a reader over the data payload of data item i and its size.
+//
+// Only the item header is read; the data is read from the bundle as the
+// returned reader is consumed.
+//
+// Example:
+//
+//	data, _, err := r.ItemData(0)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	_, err = io.Copy(os.Stdout, data)
+func (br *Reader) ItemData(i int) (*io.SectionReader, int64, error) {
+	item, err := br.ItemHeader(i)
+	if err != nil {
+		return nil, 0, err
+	}
+	entry := br.items[i]
+	dataStart := entry.Offset + int64(len(item.Raw))
+	return io.NewSectionReader(br.r, dataStart, item.DataSize), item.DataSize, nil
+}
+
+// entry returns the header table entry of item i
+func (br *Reader) entry(i int) (ItemResult, error) {
+	if i < 0 || i >= len(br.items) {
+		return ItemResult{}, goar.Errorf(goar.ErrInvalidInput, "data item %d out of range [0, %d)", i, len(br.items))
+	}
+	return br.items[i], nil
+}
+
+// checkID checks the ID of a decoded item against its header table entry
+func checkID(entry ItemResult, item *data_item.DataItem) error {
+	if item.ID != entry.ID {
+		return goar.Errorf(goar.ErrDecode, "data item %d: header ID %s does not match %s", entry.Index, entry.ID, item.ID)
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+package bundle
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestReader verifies items are accessed individually from the header table
+func TestReader(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	var items []data_item.DataItem
+	for _, data := range []string{"first", "", "third"} {
+		item := data_item.New([]byte(data), "", "", nil)
+		require.NoError(t, item.Sign(s))
+		items = append(items, *item)
+	}
+	b, err := New(&items)
+	require.NoError(t, err)
+
+	r, err := NewReader(bytes.NewReader(b.Raw), int64(len(b.Raw)))
+	require.NoError(t, err)
+	require.Equal(t, 3, r.ItemCount())
+
+	for i, expected := range []string{"first", "", "third"} {
+		assert.Equal(t, items[i].ID, r.ItemID(i))
+
+		header, err := r.ItemHeader(i)
+		require.NoError(t, err)
+		assert.Equal(t, items[i].ID, header.ID)
+		assert.Equal(t, int64(len(expected)), header.GetDataSize())
+		assert.NoError(t, header.Verify())
+
+		item, err := r.DecodeItem(i)
+		require.NoError(t, err)
+		assert.Equal(t, []byte(expected), item.RawData())
+
+		data, size, err := r.ItemData(i)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(expected)), size)
+		got, err := io.ReadAll(data)
+		require.NoError(t, err)
+		assert.Equal(t, expected, string(got))
+	}
+
+	t.Run("Out of range", func(t *testing.T) {
+		_, err := r.ItemHeader(3)
+		assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err))
+		_, err = r.DecodeItem(-1)
+		assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err))
+	})
+
+	t.Run("ID mismatch", func(t *testing.T) {
+		tampered := append([]byte(nil), b.Raw...)
+		// Overwrite the ID of the first entry of the header table
+		copy(tampered[64:96], make([]byte, 32))
+		r, err := NewReader(bytes.NewReader(tampered), int64(len(tampered)))
+		require.NoError(t, err)
+		_, err = r.ItemHeader(0)
+		assert.Equal(t, goar.ErrDecode, goar.CodeOf(err))
+		_, err = r.DecodeItem(0)
+		assert.Equal(t, goar.ErrDecode, goar.CodeOf(err))
+	})
+
+	t.Run("Bad header table", func(t *testing.T) {
+		_, err := NewReader(bytes.NewReader(b.Raw), int64(len(b.Raw))-1)
+		assert.Equal(t, goar.ErrDecode, goar.CodeOf(err))
+	})
+}
@@ -0,0 +1,128 @@
+package bundle
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// Size returns the size in bytes of the bundle binary of items, as written by WriteTo.
+func Size(items []*data_item.DataItem) int64 {
+	size := int64(32 + 64*len(items))
+	for _, item := range items {
+		size += item.RawSize()
+	}
+	return size
+}
+
+// WriteTo writes the bundle binary of signed items to w without building it in memory.
+//
+// The item count and header table are written first, then every item in
+// order with WriteRawTo, so the data of streaming data items is copied
+// from their reader straight to w. Only the header table is held in memory.
+//
+// Parameters:
+//   - w: The destination of the bundle binary
+//   - items: The signed data items, in bundle order
+//
+// Returns the number of bytes written, an error with code
+// goar.ErrInvalidInput if an item is not signed, or the write error. An
+// item whose reader holds less or more data than its DataSize fails the
+// write, since the header table would no longer match.
+//
+// Example:
+//
+//	f, err := os.Create("bundle.bin")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	n, err := bundle.WriteTo(f, items)
+func WriteTo(w io.Writer, items []*data_item.DataItem) (int64, error) {
+	header := make([]byte, 0, 32+64*len(items))
+	header = append(header, longTo32ByteArray(len(items))...)
+	for i, item := range items {
+		id, err := crypto.Base64URLDecode(item.ID)
+		if err != nil || len(id) != 32 {
+			return 0, goar.Errorf(goar.ErrInvalidInput, "data item %d is not signed", i)
+		}
+		header = append(header, longTo32ByteArray(int(item.RawSize()))...)
+		header = append(header, id...)
+	}
+
+	cw := &countingWriter{w: w}
+	if _, err := cw.Write(header); err != nil {
+		return cw.n, err
+	}
+	for i, item := range items {
+		start := cw.n
+		if err := item.WriteRawTo(cw); err != nil {
+			return cw.n, fmt.Errorf("data item %d: %w", i, err)
+		}
+		if written := cw.n - start; written != item.RawSize() {
+			return cw.n, fmt.Errorf("data item %d: wrote %d bytes, expected %d", i, written, item.RawSize())
+		}
+	}
+	return cw.n, nil
+}
+
+// WriteFile writes the bundle binary of signed items to the file at path, see WriteTo.
+//
+// The file is created or truncated. It is removed if writing fails, so a
+// partial bundle is never left behind.
+func WriteFile(path string, items []*data_item.DataItem) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	bw := bufio.NewWriterSize(f, 1<<20)
+	_, err = WriteTo(bw, items)
+	if err == nil {
+		err = bw.Flush()
+	}
+	if closeErr := f.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Remove(path)
+	}
+	return err
+}
+
+// NewStream returns a reader over the bundle binary of signed items, and its size.
+//
+// The binary is produced by WriteTo on a goroutine as the reader is
+// consumed, so it can be chunked or uploaded without being held in memory,
+// e.g. with Transaction.PrepareChunksFromReader. A WriteTo error is
+// returned by Read. Closing the reader early stops the goroutine.
+//
+// Example:
+//
+//	r, size := bundle.NewStream(items)
+//	defer r.Close()
+//	err := tx.PrepareChunksFromReader(r, size, nil)
+func NewStream(items []*data_item.DataItem) (io.ReadCloser, int64) {
+	pr, pw := io.Pipe()
+	go func() {
+		_, err := WriteTo(pw, items)
+		pw.CloseWithError(err)
+	}()
+	return pr, Size(items)
+}
+
+// countingWriter counts the bytes written to w
+type countingWriter struct {
+	w io.Writer
+	n int64
+}
+
+func (cw *countingWriter) Write(p []byte) (int, error) {
+	n, err := cw.w.Write(p)
+	cw.n += int64(n)
+	return n, err
+}
@@ -0,0 +1,74 @@
+package bundle
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestWriteTo verifies streamed bundles match New without holding items in memory
+func TestWriteTo(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte{7}, 300_000)
+	streamed := data_item.NewFromReader(bytes.NewReader(data), int64(len(data)), "", "", nil)
+	require.NoError(t, streamed.Sign(s))
+	inMemory := data_item.New([]byte("small"), "", "", nil)
+	require.NoError(t, inMemory.Sign(s))
+	items := []*data_item.DataItem{streamed, inMemory}
+
+	expected, err := New(&[]data_item.DataItem{*streamed, *inMemory})
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(expected.Raw)), Size(items))
+
+	var buf bytes.Buffer
+	n, err := WriteTo(&buf, items)
+	require.NoError(t, err)
+	assert.Equal(t, int64(len(expected.Raw)), n)
+	assert.Equal(t, expected.Raw, buf.Bytes())
+
+	t.Run("File", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "bundle.bin")
+		require.NoError(t, WriteFile(path, items))
+		results, err := VerifyFile(path, nil)
+		require.NoError(t, err)
+		require.Len(t, results, 2)
+		assert.Equal(t, streamed.ID, results[0].ID)
+	})
+
+	t.Run("Stream", func(t *testing.T) {
+		r, size := NewStream(items)
+		defer r.Close()
+		b, err := io.ReadAll(r)
+		require.NoError(t, err)
+		assert.Equal(t, int64(len(b)), size)
+		assert.Equal(t, expected.Raw, b)
+	})
+
+	t.Run("Unsigned", func(t *testing.T) {
+		_, err := WriteTo(io.Discard, []*data_item.DataItem{data_item.New([]byte("x"), "", "", nil)})
+		assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err))
+
+		r, _ := NewStream([]*data_item.DataItem{data_item.New([]byte("x"), "", "", nil)})
+		_, err = io.ReadAll(r)
+		assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err))
+	})
+
+	t.Run("Short reader", func(t *testing.T) {
+		short := *streamed
+		short.DataReader = bytes.NewReader(data[:1000])
+		path := filepath.Join(t.TempDir(), "bundle.bin")
+		assert.Error(t, WriteFile(path, []*data_item.DataItem{&short}))
+		_, err := os.Stat(path)
+		assert.True(t, os.IsNotExist(err))
+	})
+}
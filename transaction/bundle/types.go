@@ -1,6 +1,10 @@
 package bundle
 
-import "github.com/liteseed/goar/transaction/data_item"
+import (
+	"sync"
+
+	"github.com/liteseed/goar/transaction/data_item"
+)
 
 type Header struct {
 	ID   string
@@ -12,4 +16,10 @@ type Bundle struct {
 	Headers []Header             `json:"bundle_header"`
 	Items   []data_item.DataItem `json:"items"`
 	Raw     []byte
+
+	body      []byte           // Binaries of Items appended with Append, until Finalize builds Raw
+	byTag     map[tagKey][]int // Positions of the items carrying a tag, built by ItemsByTag
+	byOwner   map[string][]int // Positions of the items of an owner address, built by ItemsByOwner
+	tagOnce   sync.Once
+	ownerOnce sync.Once
 }
@@ -1,7 +1,8 @@
 package bundle
 
 import (
-	"log"
+	"encoding/binary"
+	"math"
 
 	"github.com/liteseed/goar/crypto"
 	"github.com/liteseed/goar/transaction/data_item"
@@ -16,26 +17,13 @@ func generateBundleHeader(d *[]data_item.DataItem) (*[]Header, error) {
 			return nil, err
 		}
 
-		size := len(dataItem.Raw)
+		size := int(dataItem.RawSize())
 		raw := append(idBytes, longTo32ByteArray(size)...)
 		headers = append(headers, Header{ID: dataItem.ID, Size: size, Raw: raw})
 	}
 	return &headers, nil
 }
 
-func decodeBundleHeader(data []byte) ([]Header, int) {
-	N := byteArrayToLong(data[:32])
-	var headers []Header
-	for i := 32; i < 32+64*N; i += 64 {
-		log.Println(i, i+32, i+32, i+64)
-		log.Println(len(data[i:i+32]), len(data[i+32:i+64]))
-		size := byteArrayToLong(data[i : i+32])
-		id := crypto.Base64URLEncode(data[i+32 : i+64])
-		headers = append(headers, Header{ID: id, Size: size, Raw: data[i : i+64]})
-	}
-	return headers, N
-}
-
 func longTo32ByteArray(long int) []byte {
 	byteArray := []byte{0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0, 0}
 	for i := 0; i < len(byteArray); i++ {
@@ -53,3 +41,18 @@ func byteArrayToLong(b []byte) int {
 	}
 	return value
 }
+
+// headerField reads a 32-byte count or size field of a bundle header, false
+// if it does not fit in an int64. Unlike byteArrayToLong it never wraps.
+func headerField(b []byte) (int64, bool) {
+	for _, c := range b[8:] {
+		if c != 0 {
+			return 0, false
+		}
+	}
+	value := binary.LittleEndian.Uint64(b[:8])
+	if value > math.MaxInt64 {
+		return 0, false
+	}
+	return int64(value), true
+}
@@ -1,6 +1,7 @@
 package bundle
 
 import (
+	"bytes"
 	"log"
 	"os"
 	"testing"
@@ -9,13 +10,14 @@ import (
 	"github.com/stretchr/testify/assert"
 )
 
-func TestDecodeBundleHeader(t *testing.T) {
+func TestReadHeaderTable(t *testing.T) {
 	data, err := os.ReadFile("../../test/signed-bundle")
 	if err != nil {
 		log.Fatal(err)
 	}
-	headers, N := decodeBundleHeader(data)
-	assert.Equal(t, N, 1)
+	headers, err := readHeaderTable(bytes.NewReader(data), int64(len(data)))
+	assert.NoError(t, err)
+	assert.Len(t, headers, 1)
 	assert.Equal(t, 1063, headers[0].Size)
 	assert.Equal(t, "Rh71hbi1SjdweiLSgJQioZ4VLlsnN0PM1Zzkzo_S3w0", headers[0].ID)
 }
@@ -0,0 +1,193 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+
+	"github.com/liteseed/goar/crypto"
+)
+
+// ChunkReader hashes the chunks of data as it is read, to compute its data root in a single pass.
+//
+// Reads pass through to the underlying reader unchanged, so the data can be
+// copied elsewhere, e.g. to a file or an upload, while its chunk hashes are
+// computed. Only the hash of each completed chunk is kept, so the memory
+// used grows with the number of chunks and not with the size of the data.
+// The chunk layout is the one of PrepareChunks, which depends on the total
+// size, so that size must be known upfront.
+//
+// Once all the data is read, Chunks, Leaves, DataRoot and ChunkData return
+// the same results as PrepareChunks would for the whole data.
+//
+// Example:
+//
+//	f, err := os.Open("dataset.tar")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	info, _ := f.Stat()
+//
+//	cr := transaction.NewChunkReader(f, info.Size())
+//	if _, err := io.Copy(io.Discard, cr); err != nil {
+//		log.Fatal(err)
+//	}
+//	dataRoot, err := cr.DataRoot()
+type ChunkReader struct {
+	r      io.Reader
+	size   int64
+	h      hash.Hash // Hash of the chunk in progress, nil once the last chunk is hashed
+	cursor int64     // Number of bytes read so far
+	end    int64     // End of the chunk in progress
+	final  bool      // Whether the chunk in progress is the last one
+	chunks []Chunk
+	err    error
+}
+
+// NewChunkReader returns a ChunkReader hashing the size bytes read from r.
+//
+// Reading more than size bytes from r is not attempted, and r ending before
+// size bytes fails with io.ErrUnexpectedEOF.
+func NewChunkReader(r io.Reader, size int64) *ChunkReader {
+	c := &ChunkReader{r: r, size: size, h: CurrentHasher().New()}
+	if size < 0 {
+		c.err = errors.New("data size cannot be negative")
+		return c
+	}
+	if size == 0 {
+		// Like PrepareChunks, empty data has no chunks and an empty data root
+		c.h = nil
+		return c
+	}
+	c.end, c.final = nextChunkEnd(0, size)
+	c.completeEmpty()
+	return c
+}
+
+// Read reads from the underlying reader and hashes the bytes read.
+//
+// It returns io.EOF once size bytes have been read.
+func (c *ChunkReader) Read(p []byte) (int, error) {
+	if c.err != nil {
+		return 0, c.err
+	}
+	if c.Done() {
+		return 0, io.EOF
+	}
+	if rest := c.end - c.cursor; int64(len(p)) > rest {
+		p = p[:rest]
+	}
+	n, err := c.r.Read(p)
+	c.h.Write(p[:n])
+	c.cursor += int64(n)
+	if c.cursor == c.end {
+		c.completeChunk()
+	}
+	if errors.Is(err, io.EOF) {
+		if c.Done() {
+			return n, io.EOF
+		}
+		err = io.ErrUnexpectedEOF
+	}
+	if err != nil {
+		c.err = err
+	}
+	return n, err
+}
+
+// Done reports whether all the data has been read and hashed.
+func (c *ChunkReader) Done() bool {
+	return c.h == nil
+}
+
+// Chunks returns the chunks hashed so far.
+//
+// Once all the data is read and its size is a multiple of MAX_CHUNK_SIZE,
+// the last chunk is the zero-length chunk that contributes to the data root
+// and that ChunkData discards.
+func (c *ChunkReader) Chunks() []Chunk {
+	return c.chunks
+}
+
+// Leaves returns the leaves of the Merkle tree of the data, once all of it is read.
+func (c *ChunkReader) Leaves() ([]Node, error) {
+	if err := c.checkDone(); err != nil {
+		return nil, err
+	}
+	return generateLeaves(c.chunks)
+}
+
+// DataRoot returns the base64url-encoded data root of the data, once all of it is read.
+func (c *ChunkReader) DataRoot() (string, error) {
+	leaves, err := c.Leaves()
+	if err != nil || len(leaves) == 0 {
+		return "", err
+	}
+	root, err := buildLayer(leaves, 0)
+	if err != nil {
+		return "", err
+	}
+	return crypto.Base64URLEncode(root.ID), nil
+}
+
+// ChunkData returns the data root, chunks and proofs of the data, once all of it is read.
+//
+// The result is the ChunkData PrepareChunks stores in a transaction, so
+// it can be set on one together with DataRoot and DataSize:
+//
+//	chunks, err := cr.ChunkData()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	tx.DataSize = fmt.Sprint(size)
+//	tx.DataRoot = chunks.DataRoot
+//	tx.ChunkData = chunks
+func (c *ChunkReader) ChunkData() (*ChunkData, error) {
+	if err := c.checkDone(); err != nil {
+		return nil, err
+	}
+	if len(c.chunks) == 0 {
+		return &ChunkData{Chunks: []Chunk{}, Proofs: []Proof{}}, nil
+	}
+	return chunksToChunkData(c.chunks)
+}
+
+// checkDone returns the read error, or an error if the data has not all been read
+func (c *ChunkReader) checkDone() error {
+	if c.err != nil {
+		return c.err
+	}
+	if !c.Done() {
+		return fmt.Errorf("read %d of %d bytes", c.cursor, c.size)
+	}
+	return nil
+}
+
+// completeChunk stores the hash of the chunk in progress and starts the next one
+func (c *ChunkReader) completeChunk() {
+	start := int64(0)
+	if len(c.chunks) > 0 {
+		start = int64(c.chunks[len(c.chunks)-1].MaxByteRange)
+	}
+	c.chunks = append(c.chunks, Chunk{
+		DataHash:     c.h.Sum(nil),
+		MinByteRange: int(start),
+		MaxByteRange: int(c.end),
+	})
+	if c.final {
+		c.h = nil
+		return
+	}
+	c.h.Reset()
+	c.end, c.final = nextChunkEnd(c.end, c.size)
+	c.completeEmpty()
+}
+
+// completeEmpty completes the chunk in progress if it is empty, which only the last one can be
+func (c *ChunkReader) completeEmpty() {
+	if c.end == c.cursor {
+		c.completeChunk()
+	}
+}
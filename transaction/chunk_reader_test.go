@@ -0,0 +1,89 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"os"
+	"strconv"
+	"testing"
+	"testing/iotest"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChunkReader verifies that a ChunkReader passes data through and matches PrepareChunks
+func TestChunkReader(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	t.Run("Matches PrepareChunks", func(t *testing.T) {
+		sizes := []int{0, 1, MIN_CHUNK_SIZE, MAX_CHUNK_SIZE, 2 * MAX_CHUNK_SIZE, MAX_CHUNK_SIZE + MIN_CHUNK_SIZE - 1, len(data)}
+		for _, size := range sizes {
+			t.Run(strconv.Itoa(size), func(t *testing.T) {
+				expected := &Transaction{}
+				require.NoError(t, expected.PrepareChunks(data[:size]))
+
+				// One byte reads cross every chunk boundary
+				cr := NewChunkReader(iotest.OneByteReader(bytes.NewReader(data[:size])), int64(size))
+				read, err := io.ReadAll(cr)
+				require.NoError(t, err)
+				assert.Equal(t, data[:size], read)
+				assert.True(t, cr.Done())
+
+				dataRoot, err := cr.DataRoot()
+				require.NoError(t, err)
+				assert.Equal(t, expected.DataRoot, dataRoot)
+				chunks, err := cr.ChunkData()
+				require.NoError(t, err)
+				assert.Equal(t, expected.ChunkData, chunks)
+			})
+		}
+	})
+
+	t.Run("Leaves", func(t *testing.T) {
+		cr := NewChunkReader(bytes.NewReader(data), int64(len(data)))
+		_, err := io.Copy(io.Discard, cr)
+		require.NoError(t, err)
+
+		chunks, err := chunkData(context.Background(), data)
+		require.NoError(t, err)
+		expected, err := generateLeaves(chunks)
+		require.NoError(t, err)
+		leaves, err := cr.Leaves()
+		require.NoError(t, err)
+		assert.Equal(t, expected, leaves)
+		assert.Equal(t, chunks, cr.Chunks())
+	})
+
+	t.Run("Incomplete", func(t *testing.T) {
+		cr := NewChunkReader(bytes.NewReader(data), int64(len(data)))
+		_, err := io.CopyN(io.Discard, cr, MAX_CHUNK_SIZE+1)
+		require.NoError(t, err)
+		assert.False(t, cr.Done())
+		assert.Len(t, cr.Chunks(), 1)
+		_, err = cr.DataRoot()
+		assert.Error(t, err)
+	})
+
+	t.Run("Short data", func(t *testing.T) {
+		cr := NewChunkReader(bytes.NewReader(data[:100]), 101)
+		_, err := io.ReadAll(cr)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		_, err = cr.ChunkData()
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+
+	t.Run("Long data", func(t *testing.T) {
+		cr := NewChunkReader(bytes.NewReader(data), 100)
+		read, err := io.ReadAll(cr)
+		require.NoError(t, err)
+		assert.Len(t, read, 100)
+	})
+
+	t.Run("Negative size", func(t *testing.T) {
+		_, err := NewChunkReader(bytes.NewReader(data), -1).Read(make([]byte, 1))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,191 @@
+package transaction
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+)
+
+// ChunkInfo describes one chunk of a prepared transaction.
+type ChunkInfo struct {
+	Index  int    // Index of the chunk, 0-based
+	Start  int64  // Offset of the first byte of the chunk within the data
+	Offset int64  // Offset of the last byte of the chunk within the data, as posted to /chunk
+	Size   int    // Size of the chunk in bytes
+	Proof  []byte // Merkle proof (data path) of the chunk
+
+	tx  *Transaction
+	src io.ReaderAt
+}
+
+// Reader returns a reader over the chunk's bytes in the data source.
+func (c *ChunkInfo) Reader() io.Reader {
+	return io.NewSectionReader(c.src, c.Start, int64(c.Size))
+}
+
+// Bytes reads the chunk's bytes from the data source.
+func (c *ChunkInfo) Bytes() ([]byte, error) {
+	b := make([]byte, c.Size)
+	if _, err := io.ReadFull(c.Reader(), b); err != nil {
+		return nil, fmt.Errorf("unable to read chunk %d: %w", c.Index, err)
+	}
+	return b, nil
+}
+
+// Result reads the chunk and returns it in the form posted to /chunk.
+func (c *ChunkInfo) Result() (*GetChunkResult, error) {
+	b, err := c.Bytes()
+	if err != nil {
+		return nil, err
+	}
+	return &GetChunkResult{
+		DataRoot: c.tx.DataRoot,
+		DataSize: c.tx.DataSize,
+		DataPath: crypto.Base64URLEncode(c.Proof),
+		Offset:   fmt.Sprint(c.Offset),
+		Chunk:    crypto.Base64URLEncode(b),
+	}, nil
+}
+
+// ChunkIterator iterates lazily over the chunks of a prepared transaction.
+//
+// Chunk data is only read when requested through ChunkInfo, so iterating
+// over a large file does not load it into memory.
+type ChunkIterator struct {
+	tx      *Transaction
+	src     io.ReaderAt
+	next    int
+	current *ChunkInfo
+	err     error
+}
+
+// Chunks returns an iterator over the chunks of the transaction, reading their data from src.
+//
+// The chunks must have been prepared with PrepareChunks or
+// PrepareChunksFromReader, and src must hold the same data, e.g. a
+// *bytes.Reader over the data or the *os.File it was prepared from.
+//
+// Example:
+//
+//	it := tx.Chunks(f)
+//	for it.Next() {
+//		chunk := it.Chunk()
+//		result, err := chunk.Result()
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		fmt.Printf("chunk %d: %d bytes ending at %d\n", chunk.Index, chunk.Size, chunk.Offset)
+//	}
+//	if err := it.Err(); err != nil {
+//		log.Fatal(err)
+//	}
+func (tx *Transaction) Chunks(src io.ReaderAt) *ChunkIterator {
+	it := &ChunkIterator{tx: tx, src: src}
+	if tx.ChunkData == nil {
+		it.err = errors.New("chunks have not been prepared")
+	}
+	return it
+}
+
+// Next advances to the next chunk, and returns false when there are no more chunks or preparation is missing.
+func (it *ChunkIterator) Next() bool {
+	if it.err != nil || it.next >= len(it.tx.ChunkData.Chunks) {
+		it.current = nil
+		return false
+	}
+	it.current = it.tx.chunkInfo(it.next, it.src)
+	it.next++
+	return true
+}
+
+// Chunk returns the current chunk, nil before the first call to Next or after the last chunk.
+func (it *ChunkIterator) Chunk() *ChunkInfo {
+	return it.current
+}
+
+// Err returns the error that stopped the iteration, if any.
+func (it *ChunkIterator) Err() error {
+	return it.err
+}
+
+// chunkInfo describes chunk i of the prepared chunk data, reading data from src
+func (tx *Transaction) chunkInfo(i int, src io.ReaderAt) *ChunkInfo {
+	chunk := tx.ChunkData.Chunks[i]
+	proof := tx.ChunkData.Proofs[i]
+	return &ChunkInfo{
+		Index:  i,
+		Start:  int64(chunk.MinByteRange),
+		Offset: int64(proof.Offset),
+		Size:   chunk.MaxByteRange - chunk.MinByteRange,
+		Proof:  proof.Proof,
+		tx:     tx,
+		src:    src,
+	}
+}
+
+// ChunkAt describes chunk i of the prepared transaction, reading its data from src.
+//
+// Returns an error if the chunks have not been prepared or i is out of range.
+func (tx *Transaction) ChunkAt(i int, src io.ReaderAt) (*ChunkInfo, error) {
+	if tx.ChunkData == nil {
+		return nil, errors.New("chunks have not been prepared")
+	}
+	if i < 0 || i >= len(tx.ChunkData.Chunks) {
+		return nil, fmt.Errorf("chunk index %d out of range", i)
+	}
+	return tx.chunkInfo(i, src), nil
+}
+
+// ChunksInRange describes the chunks covering bytes [start, end) of the
+// data, reading their data from src.
+//
+// The chunks are the fewest needed to serve the range: the first holds
+// start and the last holds end-1. Each one carries its Merkle proof, so a
+// reader that knows the data root can verify a partial read with
+// VerifyChunk without downloading the rest of the data.
+//
+// Parameters:
+//   - start: Offset of the first byte of the range
+//   - end: Offset just past the last byte of the range
+//   - src: The data the chunks were prepared from
+//
+// Returns an error if the chunks have not been prepared, or an error with
+// code goar.ErrInvalidInput if the range is empty or outside the data.
+//
+// Example:
+//
+//	chunks, err := tx.ChunksInRange(1<<20, 3<<20, f)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, chunk := range chunks {
+//		result, err := chunk.Result()
+//		if err != nil {
+//			log.Fatal(err)
+//		}
+//		fmt.Printf("chunk %d at %d: %d bytes\n", chunk.Index, chunk.Start, chunk.Size)
+//	}
+func (tx *Transaction) ChunksInRange(start int64, end int64, src io.ReaderAt) ([]*ChunkInfo, error) {
+	if tx.ChunkData == nil {
+		return nil, errors.New("chunks have not been prepared")
+	}
+	chunks := tx.ChunkData.Chunks
+	var size int64
+	if len(chunks) > 0 {
+		size = int64(chunks[len(chunks)-1].MaxByteRange)
+	}
+	if start < 0 || end <= start || end > size {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "invalid range [%d, %d) of %d bytes", start, end, size)
+	}
+
+	first := sort.Search(len(chunks), func(i int) bool { return int64(chunks[i].MaxByteRange) > start })
+	var infos []*ChunkInfo
+	for i := first; i < len(chunks) && int64(chunks[i].MinByteRange) < end; i++ {
+		infos = append(infos, tx.chunkInfo(i, src))
+	}
+	return infos, nil
+}
@@ -0,0 +1,120 @@
+package transaction
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChunks verifies that the chunk iterator yields the same chunks as GetChunk from memory and from a file
+func TestChunks(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	f, err := os.Open("../test/1MB.bin")
+	require.NoError(t, err)
+	defer f.Close()
+
+	tx := New(nil, "", "", nil)
+	require.NoError(t, tx.PrepareChunksFromReader(f, int64(len(data)), nil))
+
+	for name, src := range map[string]io.ReaderAt{"Bytes": bytes.NewReader(data), "File": f} {
+		t.Run(name, func(t *testing.T) {
+			var n int
+			var end int64
+			it := tx.Chunks(src)
+			for it.Next() {
+				chunk := it.Chunk()
+				assert.Equal(t, n, chunk.Index)
+				assert.Equal(t, end, chunk.Start)
+				end = chunk.Start + int64(chunk.Size)
+
+				b, err := chunk.Bytes()
+				require.NoError(t, err)
+				assert.Equal(t, data[chunk.Start:end], b)
+
+				expected, err := tx.GetChunk(n, data)
+				require.NoError(t, err)
+				result, err := chunk.Result()
+				require.NoError(t, err)
+				assert.Equal(t, expected, result)
+				n++
+			}
+			require.NoError(t, it.Err())
+			assert.Nil(t, it.Chunk())
+			assert.Equal(t, len(tx.ChunkData.Chunks), n)
+			assert.Equal(t, int64(len(data)), end)
+		})
+	}
+
+	t.Run("Not prepared", func(t *testing.T) {
+		it := New(nil, "", "", nil).Chunks(bytes.NewReader(data))
+		assert.False(t, it.Next())
+		assert.Error(t, it.Err())
+	})
+
+	t.Run("Out of range", func(t *testing.T) {
+		_, err := tx.ChunkAt(len(tx.ChunkData.Chunks), f)
+		assert.Error(t, err)
+	})
+
+	t.Run("Short source", func(t *testing.T) {
+		chunk, err := tx.ChunkAt(len(tx.ChunkData.Chunks)-1, bytes.NewReader(data[:100]))
+		require.NoError(t, err)
+		_, err = chunk.Result()
+		assert.Error(t, err)
+	})
+}
+
+// TestChunksInRange verifies ranges map to the fewest chunks, each with a valid proof
+func TestChunksInRange(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	tx := New(nil, "", "", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	root, err := crypto.Base64URLDecode(tx.DataRoot)
+	require.NoError(t, err)
+	src := bytes.NewReader(data)
+	last := len(tx.ChunkData.Chunks) - 1
+	size := int64(len(data))
+
+	tests := []struct {
+		name       string
+		start, end int64
+		first, n   int
+	}{
+		{"First byte", 0, 1, 0, 1},
+		{"Whole first chunk", 0, MAX_CHUNK_SIZE, 0, 1},
+		{"Across a boundary", MAX_CHUNK_SIZE - 1, MAX_CHUNK_SIZE + 1, 0, 2},
+		{"Second chunk", MAX_CHUNK_SIZE, MAX_CHUNK_SIZE + 10, 1, 1},
+		{"Last byte", size - 1, size, last, 1},
+		{"Everything", 0, size, 0, last + 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chunks, err := tx.ChunksInRange(tt.start, tt.end, src)
+			require.NoError(t, err)
+			require.Len(t, chunks, tt.n)
+			assert.Equal(t, tt.first, chunks[0].Index)
+			assert.LessOrEqual(t, chunks[0].Start, tt.start)
+			assert.GreaterOrEqual(t, chunks[len(chunks)-1].Offset, tt.end-1)
+			for _, chunk := range chunks {
+				b, err := chunk.Bytes()
+				require.NoError(t, err)
+				assert.NoError(t, VerifyChunk(root, int(chunk.Start), b, chunk.Proof))
+			}
+		})
+	}
+
+	for _, r := range [][2]int64{{-1, 1}, {10, 10}, {10, 5}, {0, size + 1}} {
+		_, err := tx.ChunksInRange(r[0], r[1], src)
+		assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err), r)
+	}
+	_, err = New(nil, "", "", nil).ChunksInRange(0, 1, src)
+	assert.Error(t, err)
+}
@@ -0,0 +1,84 @@
+package data_item
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/liteseed/goar/tag"
+)
+
+// Clone returns a copy of the data item that shares its data source.
+//
+// The data is not read or copied: a streaming item keeps its reader, and an
+// item decoded from a binary streams its data from the original Raw slice.
+// If the reader implements io.ReaderAt (e.g. *os.File or *bytes.Reader),
+// the clone gets its own io.SectionReader over it, so the original and the
+// clone can be signed and written concurrently; otherwise they share the
+// reader's position and must not be used concurrently.
+//
+// The clone keeps the ID and signature of the original until it is signed again.
+func (d *DataItem) Clone() *DataItem {
+	c := &DataItem{
+		ID:            d.ID,
+		Signature:     d.Signature,
+		SignatureType: d.SignatureType,
+		Owner:         d.Owner,
+		OwnerAddress:  d.OwnerAddress,
+		Target:        d.Target,
+		Anchor:        d.Anchor,
+		Data:          d.Data,
+		Raw:           d.Raw,
+		MaxSize:       d.MaxSize,
+		MemoryLimit:   d.MemoryLimit,
+		DataReader:    d.DataReader,
+		DataSize:      d.DataSize,
+		dataStart:     d.dataStart,
+		ownerStart:    d.ownerStart,
+		tagsStart:     d.tagsStart,
+		lazy:          d.lazy,
+	}
+	if d.Tags != nil {
+		tags := append([]tag.Tag{}, *d.Tags...)
+		c.Tags = &tags
+	}
+	if ra, ok := d.DataReader.(io.ReaderAt); ok && d.DataSize > 0 {
+		c.DataReader = io.NewSectionReader(ra, 0, d.DataSize)
+	}
+	return c
+}
+
+// WithTags returns an unsigned copy of the data item with its tags replaced.
+//
+// The data source is shared as in Clone, so changing metadata of a large
+// item does not require reconstructing its reader. The copy must be signed
+// before use; signing streams the data once more to compute the new ID.
+//
+// Example:
+//
+//	retagged := d.WithTags(&[]tag.Tag{{Name: "Content-Type", Value: "image/png"}})
+//	if err := retagged.Sign(s); err != nil {
+//		return err
+//	}
+func (d *DataItem) WithTags(tags *[]tag.Tag) *DataItem {
+	c := d.Clone()
+	if c.Raw != nil && c.dataStart > 0 && c.DataReader == nil {
+		// Stream the data from the signed binary, which is dropped below
+		c.DataReader = bytes.NewReader(c.Raw[c.dataStart:])
+		c.DataSize = int64(len(c.Raw) - c.dataStart)
+		if c.lazy {
+			c.GetOwner()
+		}
+	}
+	if tags == nil {
+		tags = &[]tag.Tag{}
+	}
+	c.Tags = tags
+	c.ID = ""
+	c.Signature = ""
+	c.Raw = nil
+	c.dataStart = 0
+	c.ownerStart = 0
+	c.tagsStart = 0
+	c.lazy = false
+	return c
+}
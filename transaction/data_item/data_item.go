@@ -1,21 +1,25 @@
 package data_item
 
 import (
+	"bytes"
+	"context"
 	"encoding/binary"
 	"errors"
 	"fmt"
 	"io"
 	"os"
+	"strconv"
 
+	"github.com/liteseed/goar"
 	"github.com/liteseed/goar/crypto"
 	"github.com/liteseed/goar/signer"
 	"github.com/liteseed/goar/tag"
 )
 
 const (
-	MAX_TAGS             = 128
-	MAX_TAG_KEY_LENGTH   = 1024
-	MAX_TAG_VALUE_LENGTH = 3072
+	MAX_TAGS             = tag.MAX_TAGS
+	MAX_TAG_KEY_LENGTH   = tag.MAX_TAG_KEY_LENGTH
+	MAX_TAG_VALUE_LENGTH = tag.MAX_TAG_VALUE_LENGTH
 )
 
 // New Create a new DataItem
@@ -48,151 +52,458 @@ func NewFromReader(dataReader io.ReadSeeker, dataSize int64, target string, anch
 	}
 }
 
-// Decode a [DataItem] from bytes
+// Decode a [DataItem] from bytes, see DecodeStream for items too large to hold in memory
 func Decode(raw []byte) (*DataItem, error) {
+	d, err := DecodeLazy(raw)
+	if err != nil {
+		return nil, err
+	}
+	if err = d.Materialize(); err != nil {
+		return nil, err
+	}
+	return d, nil
+}
+
+// DecodeLazy parses the layout of a [DataItem] binary without materializing its fields.
+// ID, Signature, Owner, Tags and Data are left empty and computed from Raw on first access
+// through GetID, GetSignature, GetOwner, GetTags and GetData, or all at once with Materialize.
+// Target and Anchor are small and decoded eagerly.
+// Ingestion services that only need the ID and size of most items avoid roughly half the allocations of Decode.
+func DecodeLazy(raw []byte) (*DataItem, error) {
 	N := len(raw)
 	if N < 2 {
-		return nil, errors.New("binary too small")
+		return nil, goar.Errorf(goar.ErrDecode, "binary too small")
 	}
 
 	signatureType, signatureLength, publicKeyLength, err := getSignatureMetadata(raw[:2])
 	if err != nil {
-		return nil, err
+		return nil, goar.Wrap(goar.ErrDecode, err)
 	}
 
-	signatureStart := 2
-	signatureEnd := signatureLength + signatureStart
-
-	rawSig := raw[signatureStart:signatureEnd]
-	signature := crypto.Base64URLEncode(rawSig)
-	rawId := crypto.SHA256(rawSig)
-	id := crypto.Base64URLEncode(rawId)
-	ownerStart := signatureEnd
+	ownerStart := 2 + signatureLength
 	ownerEnd := ownerStart + publicKeyLength
-	owner := crypto.Base64URLEncode(raw[ownerStart:ownerEnd])
+	if N < ownerEnd+2 {
+		return nil, goar.Errorf(goar.ErrDecode, "binary too small")
+	}
 
 	position := ownerEnd
+	if raw[position] == 1 && N < position+33 {
+		return nil, goar.Errorf(goar.ErrDecode, "binary too small - target")
+	}
 	target, position := getTarget(&raw, position)
+	if N < position+1 || (raw[position] == 1 && N < position+33) {
+		return nil, goar.Errorf(goar.ErrDecode, "binary too small - anchor")
+	}
 	anchor, position := getAnchor(&raw, position)
-	tags, position, err := tag.Deserialize(raw, position)
-	if err != nil {
-		return nil, err
+
+	tagsStart := position
+	if N < tagsStart+16 {
+		return nil, goar.Errorf(goar.ErrDecode, "binary too small - tags")
+	}
+	dataStart := tagsStart + 16
+	numberOfTags := binary.LittleEndian.Uint64(raw[tagsStart : tagsStart+8])
+	numberOfTagBytes := binary.LittleEndian.Uint64(raw[tagsStart+8 : tagsStart+16])
+	if numberOfTags > 0 && numberOfTagBytes > 0 {
+		if numberOfTagBytes > uint64(N-dataStart) {
+			return nil, goar.Errorf(goar.ErrDecode, "binary too small - tags")
+		}
+		dataStart += int(numberOfTagBytes)
 	}
-	data := crypto.Base64URLEncode(raw[position:])
 
 	return &DataItem{
-		ID:            id,
 		SignatureType: signatureType,
-		Signature:     signature,
-		Owner:         owner,
 		Target:        target,
 		Anchor:        anchor,
-		Tags:          tags,
-		Data:          data,
 		Raw:           raw,
+		dataStart:     dataStart,
+		ownerStart:    ownerStart,
+		tagsStart:     tagsStart,
+		lazy:          true,
 	}, nil
 }
 
-func (d *DataItem) Sign(s *signer.Signer) error {
-	d.Owner = s.Owner()
-	deepHashChunk, err := d.getDataItemChunk()
+// DecodeStream decodes the [DataItem] binary of size bytes in r, leaving the data payload in r.
+//
+// Only the header is read. The data item is a streaming one: Raw holds the
+// header, DataSize the data size and DataReader a window of r over the
+// data, so Verify, WriteRawTo and GetRawWithData read it from r when needed
+// and Data stays empty. Unlike Decode, a data item of any size can thus be
+// verified from a file with memory proportional to its header:
+//
+//	f, err := os.Open("item.bin")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	info, _ := f.Stat()
+//	d, err := data_item.DecodeStream(f, info.Size())
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if err := d.Verify(); err != nil {
+//		log.Fatal(err)
+//	}
+//
+// It is also used to access large items of bundles on disk, see bundle.Reader.
+//
+// Returns an error with code goar.ErrDecode if the header is invalid or
+// does not fit in size bytes.
+func DecodeStream(r io.ReaderAt, size int64) (*DataItem, error) {
+	if size < 2 {
+		return nil, goar.Errorf(goar.ErrDecode, "binary too small")
+	}
+	prefix := make([]byte, 2)
+	if _, err := r.ReadAt(prefix, 0); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	_, signatureLength, publicKeyLength, err := getSignatureMetadata(prefix)
 	if err != nil {
-		return err
+		return nil, goar.Wrap(goar.ErrDecode, err)
 	}
 
-	rawSignature, err := crypto.Sign(deepHashChunk, s.PrivateKey)
-	if err != nil {
-		return err
+	// Read up to the tag header assuming a target and an anchor are present,
+	// then the tags once their length is known
+	headerSize := min(size, int64(2+signatureLength+publicKeyLength+33+33+16))
+	header := make([]byte, headerSize)
+	if _, err := r.ReadAt(header, 0); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	position := 2 + signatureLength + publicKeyLength
+	for range 2 {
+		if int64(position) >= headerSize {
+			return nil, goar.Errorf(goar.ErrDecode, "binary too small")
+		}
+		if header[position] == 1 {
+			position += 32
+		}
+		position++
+	}
+	if int64(position+16) > headerSize {
+		return nil, goar.Errorf(goar.ErrDecode, "binary too small - tags")
+	}
+	dataStart := int64(position + 16)
+	numberOfTags := binary.LittleEndian.Uint64(header[position : position+8])
+	numberOfTagBytes := binary.LittleEndian.Uint64(header[position+8 : position+16])
+	if numberOfTags > 0 && numberOfTagBytes > 0 {
+		if numberOfTagBytes > uint64(size-dataStart) {
+			return nil, goar.Errorf(goar.ErrDecode, "binary too small - tags")
+		}
+		dataStart += int64(numberOfTagBytes)
+	}
+	if dataStart > size {
+		return nil, goar.Errorf(goar.ErrDecode, "binary too small - tags")
+	}
+	if dataStart > headerSize {
+		header = append(header, make([]byte, dataStart-headerSize)...)
+		if _, err := r.ReadAt(header[headerSize:], headerSize); err != nil {
+			return nil, goar.Wrap(goar.ErrDecode, err)
+		}
 	}
+	header = header[:dataStart]
 
-	rawOwner, err := crypto.Base64URLDecode(s.Owner())
+	d, err := DecodeLazy(header)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if err = d.Materialize(); err != nil {
+		return nil, err
+	}
+	// The data follows the header in r, not in Raw
+	d.dataStart = 0
+	if dataSize := size - dataStart; dataSize > 0 {
+		d.DataReader = io.NewSectionReader(r, dataStart, dataSize)
+		d.DataSize = dataSize
 	}
+	return d, nil
+}
 
-	rawTarget, err := crypto.Base64URLDecode(d.Target)
-	if err != nil {
+// Materialize computes every field of a lazily decoded [DataItem] from Raw.
+// It is a no-op for data items that were not created by DecodeLazy or that were already materialized.
+func (d *DataItem) Materialize() error {
+	if !d.lazy {
+		return nil
+	}
+	if _, err := d.GetTags(); err != nil {
 		return err
 	}
-	rawAnchor := []byte(d.Anchor)
+	d.GetID()
+	d.GetSignature()
+	d.GetOwner()
+	d.GetOwnerAddress()
+	d.GetData()
+	d.lazy = false
+	return nil
+}
 
-	rawTags, err := tag.Serialize(d.Tags)
-	if err != nil {
-		return err
+// GetID returns the ID, computing it from Raw for lazily decoded data items
+func (d *DataItem) GetID() string {
+	if d.ID == "" && d.lazy {
+		d.ID = crypto.Base64URLEncode(crypto.SHA256(d.RawSignature()))
 	}
-	// For streaming data, we now handle large files without loading into memory
-	// The Raw field construction will handle streaming separately
-	var rawData []byte
-	var isStreaming = d.DataReader != nil && d.DataSize > 0
-
-	if !isStreaming {
-		// Handle small/in-memory data
-		if d.Data != "" {
-			var err error
-			rawData, err = crypto.Base64URLDecode(d.Data)
-			if err != nil {
-				return err
-			}
-		} else {
-			rawData = []byte{}
+	return d.ID
+}
+
+// GetSignature returns the signature, computing it from Raw for lazily decoded data items
+func (d *DataItem) GetSignature() string {
+	if d.Signature == "" && d.lazy {
+		d.Signature = crypto.Base64URLEncode(d.RawSignature())
+	}
+	return d.Signature
+}
+
+// GetOwner returns the owner, computing it from Raw for lazily decoded data items
+func (d *DataItem) GetOwner() string {
+	if d.Owner == "" && d.lazy {
+		d.Owner = crypto.Base64URLEncode(d.RawOwner())
+	}
+	return d.Owner
+}
+
+// GetOwnerAddress returns the address of the owner in the native format of its signature type:
+// base64url SHA-256 of the owner for Arweave, EIP-55 0x-prefixed hex for Ethereum and base58 for
+// Solana and ED25519. It is computed from the owner bytes on first access.
+func (d *DataItem) GetOwnerAddress() string {
+	if d.OwnerAddress != "" {
+		return d.OwnerAddress
+	}
+	owner := d.RawOwner()
+	if len(owner) == 0 {
+		return ""
+	}
+	switch d.SignatureType {
+	case Arweave:
+		d.OwnerAddress = crypto.Base64URLEncode(crypto.SHA256(owner))
+	case Ethereum:
+		d.OwnerAddress, _ = crypto.GetEthereumAddress(owner)
+	case Solana, ED25519:
+		d.OwnerAddress = crypto.Base58Encode(owner)
+	}
+	return d.OwnerAddress
+}
+
+// TargetAddress returns the target as a typed address, the zero Address if the data item has none.
+//
+// Returns an error with code goar.ErrInvalidInput if Target is not a valid address.
+func (d *DataItem) TargetAddress() (goar.Address, error) {
+	return goar.ParseAddress(d.Target)
+}
+
+// SetTarget sets the target from a typed address, clearing it for the zero Address.
+func (d *DataItem) SetTarget(a goar.Address) {
+	d.Target = a.String()
+}
+
+// AnchorValue returns the anchor as a typed value, the zero Anchor if the data item has none.
+//
+// Returns an error with code goar.ErrInvalidInput if Anchor is not 32 bytes long.
+func (d *DataItem) AnchorValue() (goar.Anchor, error) {
+	return goar.NewAnchor([]byte(d.Anchor))
+}
+
+// SetAnchor sets the anchor from a typed value, clearing it for the zero Anchor.
+func (d *DataItem) SetAnchor(a goar.Anchor) {
+	d.Anchor = a.Raw()
+}
+
+// GetTags returns the tags, deserializing them from Raw for lazily decoded data items
+func (d *DataItem) GetTags() (*[]tag.Tag, error) {
+	if d.Tags == nil && d.lazy {
+		tags, _, err := tag.Deserialize(d.Raw, d.tagsStart)
+		if err != nil {
+			return nil, goar.Wrap(goar.ErrDecode, err)
 		}
+		d.Tags = tags
 	}
+	return d.Tags, nil
+}
 
-	if isStreaming {
-		// For streaming data, we'll defer Raw construction
-		// The Raw field will be built on-demand when GetRawWithData() is called
-		// Here we just create a partial Raw without the data portion
-		raw := d.buildHeaderOnly(rawSignature, rawOwner, rawTarget, rawAnchor, rawTags)
-		rawID := crypto.SHA256(rawSignature)
+// TagList returns the tags with lookup helpers, e.g. d.TagList() then tags.Get("Content-Type")
+func (d *DataItem) TagList() (tag.Tags, error) {
+	tags, err := d.GetTags()
+	if err != nil || tags == nil {
+		return nil, err
+	}
+	return tag.Tags(*tags), nil
+}
 
-		d.Owner = s.Owner()
-		d.Signature = crypto.Base64URLEncode(rawSignature)
-		d.ID = crypto.Base64URLEncode(rawID)
-		d.Raw = raw // Contains only header, data streamed later
-		return nil
+// GetData returns the base64url data, encoding it from Raw for lazily decoded data items
+func (d *DataItem) GetData() string {
+	if d.Data == "" && d.lazy {
+		d.Data = crypto.Base64URLEncode(d.Raw[d.dataStart:])
 	}
+	return d.Data
+}
 
-	// Build Raw for small/in-memory data
-	raw := make([]byte, 0)
-	raw = binary.LittleEndian.AppendUint16(raw, uint16(1))
-	raw = append(raw, rawSignature...)
-	raw = append(raw, rawOwner...)
+// RawSignature returns the signature bytes, sliced from Raw without copying when possible
+func (d *DataItem) RawSignature() []byte {
+	if d.ownerStart > 0 {
+		return d.Raw[2:d.ownerStart]
+	}
+	rawSignature, _ := crypto.Base64URLDecode(d.Signature)
+	return rawSignature
+}
 
-	if d.Target == "" {
-		raw = append(raw, 0)
-	} else {
-		raw = append(raw, 1)
+// RawOwner returns the owner bytes, sliced from Raw without copying when possible
+func (d *DataItem) RawOwner() []byte {
+	if d.ownerStart > 0 {
+		return d.Raw[d.ownerStart : d.ownerStart+SignatureConfig[d.SignatureType].PublicKeyLength]
 	}
-	raw = append(raw, rawTarget...)
+	rawOwner, _ := crypto.Base64URLDecode(d.Owner)
+	return rawOwner
+}
 
-	if d.Anchor == "" {
-		raw = append(raw, 0)
-	} else {
-		raw = append(raw, 1)
+// RawData returns the data payload, sliced from Raw without copying when possible
+func (d *DataItem) RawData() []byte {
+	if d.dataStart > 0 {
+		return d.Raw[d.dataStart:]
 	}
-	raw = append(raw, rawAnchor...)
-	numberOfTags := make([]byte, 8)
-	binary.LittleEndian.PutUint16(numberOfTags, uint16(len(*d.Tags)))
-	raw = append(raw, numberOfTags...)
+	rawData, _ := crypto.Base64URLDecode(d.Data)
+	return rawData
+}
 
-	tagsLength := make([]byte, 8)
-	binary.LittleEndian.PutUint16(tagsLength, uint16(len(rawTags)))
-	raw = append(raw, tagsLength...)
-	raw = append(raw, rawTags...)
-	raw = append(raw, rawData...)
-	rawID := crypto.SHA256(rawSignature)
+// Sign signs the data item with s and builds its binary in Raw.
+//
+// The signature type is taken from s: *signer.Signer produces Arweave
+// (type 1) data items, *signer.EthereumSigner Ethereum (type 3) ones and
+// *signer.ED25519Signer ED25519 (type 2) or Solana (type 4) ones.
+// Streamed data items get a Raw holding the header only, see GetRawWithData.
+//
+// Returns an error with code goar.ErrItemTooLarge if the data exceeds
+// MaxSize, goar.ErrSigningDenied if the signer's Approver denies it, or
+// goar.ErrInvalidInput if the target or anchor is invalid.
+func (d *DataItem) Sign(s signer.ItemSigner) error {
+	return d.SignContext(context.Background(), s)
+}
 
+// SignContext is Sign bound to ctx.
+//
+// ctx is checked between the reads of a streamed data item, see
+// crypto.DeepHashStreamContext, so that signing large data can be aborted.
+func (d *DataItem) SignContext(ctx context.Context, s signer.ItemSigner) error {
+	if err := d.CheckSize(); err != nil {
+		return err
+	}
+	// Invalid targets and anchors would produce a binary that cannot be decoded
+	if _, err := d.TargetAddress(); err != nil {
+		return err
+	}
+	if _, err := d.AnchorValue(); err != nil {
+		return err
+	}
+	tags := []tag.Tag{}
+	if d.Tags != nil {
+		tags = append(tags, *d.Tags...)
+	}
+	err := s.Approve(&signer.SigningRequest{
+		Kind:     signer.KindDataItem,
+		Target:   d.Target,
+		Quantity: "0",
+		Fee:      "0",
+		DataSize: d.GetDataSize(),
+		Tags:     tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	d.SignatureType = s.SignatureType()
 	d.Owner = s.Owner()
+	d.OwnerAddress = ""
+	deepHashChunk, err := d.getDataItemChunk(ctx)
+	if err != nil {
+		return err
+	}
+
+	rawSignature, err := s.SignMessage(deepHashChunk)
+	if err != nil {
+		return err
+	}
 	d.Signature = crypto.Base64URLEncode(rawSignature)
-	d.ID = crypto.Base64URLEncode(rawID)
+	if err = d.Encode(); err != nil {
+		return err
+	}
+	d.GetOwnerAddress()
+	return nil
+}
+
+// Encode builds the ANS-104 binary of the signed data item in Raw from its fields, and sets its ID.
+//
+// It is the inverse of Decode and is called by Sign. Use it to serialize a
+// data item whose fields were set or changed directly, e.g. a decoded item
+// whose signature was produced elsewhere. Fields are written as they are:
+// an item changed after signing encodes, but no longer verifies until it
+// is signed again. Streamed data items get a Raw holding the header only,
+// as with Sign.
+//
+// Returns an error with code goar.ErrInvalidInput if the signature or owner
+// do not match the signature type, or the target, anchor or tags are
+// invalid.
+//
+// Example:
+//
+//	d, _ := data_item.Decode(raw)
+//	d.Signature = crypto.Base64URLEncode(externalSignature)
+//	if err := d.Encode(); err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Println(d.ID)
+func (d *DataItem) Encode() error {
+	if err := d.Materialize(); err != nil {
+		return err
+	}
+	meta, ok := SignatureConfig[d.signatureType()]
+	if !ok {
+		return goar.Errorf(goar.ErrInvalidInput, "unsupported signature type: %d", d.SignatureType)
+	}
+	rawSignature, err := crypto.Base64URLDecode(d.Signature)
+	if err != nil || len(rawSignature) != meta.SignatureLength {
+		return goar.Errorf(goar.ErrInvalidInput, "signature must be %d bytes for signature type %d", meta.SignatureLength, d.signatureType())
+	}
+	rawOwner, err := crypto.Base64URLDecode(d.Owner)
+	if err != nil || len(rawOwner) != meta.PublicKeyLength {
+		return goar.Errorf(goar.ErrInvalidInput, "owner must be %d bytes for signature type %d", meta.PublicKeyLength, d.signatureType())
+	}
+	if _, err := d.TargetAddress(); err != nil {
+		return err
+	}
+	if _, err := d.AnchorValue(); err != nil {
+		return err
+	}
+	rawTarget, err := crypto.Base64URLDecode(d.Target)
+	if err != nil {
+		return goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	rawTags, err := tag.Serialize(d.Tags)
+	if err != nil {
+		return goar.Wrap(goar.ErrInvalidInput, err)
+	}
+
+	raw := d.buildHeaderOnly(rawSignature, rawOwner, rawTarget, []byte(d.Anchor), rawTags)
+	header := len(raw)
+	if d.DataReader != nil && d.DataSize > 0 {
+		// The data is streamed from DataReader, see GetRawWithData
+		d.dataStart = 0
+	} else {
+		rawData, err := crypto.Base64URLDecode(d.Data)
+		if err != nil {
+			return goar.Wrap(goar.ErrInvalidInput, err)
+		}
+		raw = append(raw, rawData...)
+		d.dataStart = header
+	}
+	d.SignatureType = d.signatureType()
+	d.ID = crypto.Base64URLEncode(crypto.SHA256(rawSignature))
 	d.Raw = raw
+	d.ownerStart = 2 + len(rawSignature)
+	d.tagsStart = header - 16 - len(rawTags)
+	d.lazy = false
 	return nil
 }
 
 // buildHeaderOnly creates the header portion of Raw data without the data payload
 func (d *DataItem) buildHeaderOnly(rawSignature, rawOwner, rawTarget, rawAnchor, rawTags []byte) []byte {
 	raw := make([]byte, 0)
-	raw = binary.LittleEndian.AppendUint16(raw, uint16(1))
+	raw = binary.LittleEndian.AppendUint16(raw, uint16(d.SignatureType))
 	raw = append(raw, rawSignature...)
 	raw = append(raw, rawOwner...)
 
@@ -210,21 +521,32 @@ func (d *DataItem) buildHeaderOnly(rawSignature, rawOwner, rawTarget, rawAnchor,
 	}
 	raw = append(raw, rawAnchor...)
 	numberOfTags := make([]byte, 8)
-	binary.LittleEndian.PutUint16(numberOfTags, uint16(len(*d.Tags)))
+	if d.Tags != nil {
+		binary.LittleEndian.PutUint64(numberOfTags, uint64(len(*d.Tags)))
+	}
 	raw = append(raw, numberOfTags...)
 
 	tagsLength := make([]byte, 8)
-	binary.LittleEndian.PutUint16(tagsLength, uint16(len(rawTags)))
+	binary.LittleEndian.PutUint64(tagsLength, uint64(len(rawTags)))
 	raw = append(raw, tagsLength...)
 	raw = append(raw, rawTags...)
 
 	return raw
 }
 
-// GetRawWithData returns the complete raw data including the data payload
-// This is needed for bundle creation where the full DataItem binary is required
+// GetRawWithData returns the complete raw data including the data payload.
+//
+// Streaming data items are read into a single allocation of RawSize bytes.
+// If MemoryLimit is set and the item is larger, an error with code
+// goar.ErrItemTooLarge is returned instead of allocating.
+//
+// Deprecated: Use WriteRawTo, which streams the item without holding it in memory.
 func (d *DataItem) GetRawWithData() ([]byte, error) {
 	if d.DataReader != nil && d.DataSize > 0 {
+		if size := d.RawSize(); d.MemoryLimit > 0 && size > d.MemoryLimit {
+			return nil, goar.Wrap(goar.ErrItemTooLarge, &SizeError{Limit: d.MemoryLimit, Size: size})
+		}
+
 		// For streaming data, combine header (in Raw) with streamed data
 		reader, err := d.getDataReader()
 		if err != nil {
@@ -242,38 +564,75 @@ func (d *DataItem) GetRawWithData() ([]byte, error) {
 	return d.Raw, nil
 }
 
-// combineHeaderWithStreamedData combines the header (stored in Raw) with streamed data
-// WARNING: This method reads the entire data stream into memory for bundle compatibility
-// This defeats the purpose of streaming for very large files, but is required for ANS-104 compatibility
-func (d *DataItem) combineHeaderWithStreamedData(reader io.ReadSeeker) ([]byte, error) {
-	// Allocate buffer for the complete raw data
-	totalSize := int64(len(d.Raw)) + d.DataSize
-	result := make([]byte, 0, totalSize)
-
-	// Add the header portion (already in d.Raw)
-	result = append(result, d.Raw...)
-
-	// Stream the data in chunks to avoid huge single allocations
-	const chunkSize = 32768 // 32KB chunks
-	buffer := make([]byte, chunkSize)
+// RawSize returns the size in bytes of the complete binary written by WriteRawTo.
+func (d *DataItem) RawSize() int64 {
+	if d.DataReader != nil && d.DataSize > 0 {
+		return int64(len(d.Raw)) + d.DataSize
+	}
+	return int64(len(d.Raw))
+}
 
-	for {
-		n, err := reader.Read(buffer)
-		if n > 0 {
-			result = append(result, buffer[:n]...)
-		}
-		if err == io.EOF {
-			break
-		}
-		if err != nil {
-			return nil, fmt.Errorf("error reading data stream: %v", err)
-		}
+// EncodedSize returns the size in bytes of the binary of the data item once signed, without building it.
+//
+// Unlike RawSize, it does not need the item to be signed or encoded, so
+// that bundles can be planned and size quotas enforced before signing.
+// The size depends on the signature type, Arweave if SignatureType is not
+// set: set it to the type of the signer, e.g. ED25519, to size an item
+// that another key will sign. The result equals RawSize after signing.
+//
+// Returns an error with code goar.ErrInvalidInput if the signature type
+// is not supported, or the error of decoding lazily decoded tags.
+//
+// Example:
+//
+//	d := data_item.New(data, "", "", &tags)
+//	size, err := d.EncodedSize()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if size > quota {
+//		log.Fatalf("item of %d bytes exceeds the quota", size)
+//	}
+func (d *DataItem) EncodedSize() (int64, error) {
+	meta, ok := SignatureConfig[d.signatureType()]
+	if !ok {
+		return 0, goar.Errorf(goar.ErrInvalidInput, "unsupported signature type: %d", d.SignatureType)
+	}
+	tags, err := d.GetTags()
+	if err != nil {
+		return 0, err
+	}
+	size := int64(2 + meta.SignatureLength + meta.PublicKeyLength + 1 + 1 + 16)
+	if d.Target != "" {
+		size += 32
+	}
+	if d.Anchor != "" {
+		size += 32
 	}
+	if tags != nil {
+		size += int64(tag.SerializedSize(*tags))
+	}
+	switch {
+	case d.DataReader != nil && d.DataSize > 0:
+		size += d.DataSize
+	case d.dataStart > 0 && d.Data == "":
+		size += int64(len(d.Raw) - d.dataStart)
+	default:
+		size += int64(crypto.Base64URLDecodedLen(len(d.Data)))
+	}
+	return size, nil
+}
 
+// combineHeaderWithStreamedData reads the data directly after the header in a buffer allocated once
+func (d *DataItem) combineHeaderWithStreamedData(reader io.ReadSeeker) ([]byte, error) {
+	result := make([]byte, d.RawSize())
+	n := copy(result, d.Raw)
+	if _, err := io.ReadFull(reader, result[n:]); err != nil {
+		return nil, fmt.Errorf("error reading data stream: %v", err)
+	}
 	return result, nil
 }
 
-// getDataReader returns the provided data reader
 func (d *DataItem) getDataReader() (io.ReadSeeker, error) {
 	if d.DataReader != nil {
 		return d.DataReader, nil
@@ -345,6 +704,9 @@ func (d *DataItem) GetDataSize() int64 {
 	if d.DataSize > 0 {
 		return d.DataSize
 	}
+	if d.dataStart > 0 {
+		return int64(len(d.Raw) - d.dataStart)
+	}
 	// For base64 encoded data, decode to get actual size
 	rawData, err := crypto.Base64URLDecode(d.Data)
 	if err != nil {
@@ -353,52 +715,154 @@ func (d *DataItem) GetDataSize() int64 {
 	return int64(len(rawData))
 }
 
+// CheckSize verifies that the data is not larger than MaxSize.
+//
+// Bundlers limit the size of the items they accept, e.g. on free tiers.
+// Checking before signing lets callers route large data to an L1
+// transaction instead of failing at upload time.
+//
+// Returns nil if MaxSize is 0 or the data fits, and otherwise an error
+// with code goar.ErrItemTooLarge wrapping a *SizeError.
+//
+// Example:
+//
+//	d := data_item.New(data, "", "", nil)
+//	d.MaxSize = 10 * 1024 * 1024
+//	var sizeErr *data_item.SizeError
+//	if errors.As(d.CheckSize(), &sizeErr) {
+//		log.Printf("Uploading %d bytes as a transaction", sizeErr.Size)
+//	}
+func (d *DataItem) CheckSize() error {
+	if d.MaxSize <= 0 {
+		return nil
+	}
+	if size := d.GetDataSize(); size > d.MaxSize {
+		return goar.Wrap(goar.ErrItemTooLarge, &SizeError{Limit: d.MaxSize, Size: size})
+	}
+	return nil
+}
+
 func (d *DataItem) Verify() error {
-	// Verify ID
-	rawSignature, err := crypto.Base64URLDecode(d.Signature)
+	if err := d.Materialize(); err != nil {
+		return err
+	}
+	// For verification, we need to compute the DeepHash
+	// This requires reading the data, which we'll do temporarily
+	chunks, err := d.getDataItemChunk(context.Background())
 	if err != nil {
 		return err
 	}
+	return d.verifyChunk(chunks)
+}
 
-	rawId := crypto.SHA256(rawSignature)
-	id := crypto.Base64URLEncode(rawId)
+// HasRawData reports whether Raw holds the complete binary including the data payload, as required by VerifyRaw.
+func (d *DataItem) HasRawData() bool {
+	return d.dataStart > 0 && d.dataStart <= len(d.Raw)
+}
 
-	if id != d.ID {
-		return errors.New("invalid data item - signature and id don't match")
+// VerifyRaw verifies a decoded DataItem by deep-hashing the data payload
+// straight from Raw, where it is already present un-encoded.
+// This avoids decoding Data back into memory, which matters for large items
+// decoded from bundles. Raw must hold the complete binary, as produced by
+// Decode or by Sign for in-memory data.
+func (d *DataItem) VerifyRaw() error {
+	if !d.HasRawData() {
+		return errors.New("raw data item not available")
 	}
-
-	// For verification, we need to compute the DeepHash
-	// This requires reading the data, which we'll do temporarily
-	chunks, err := d.getDataItemChunk()
+	if err := d.Materialize(); err != nil {
+		return err
+	}
+	rawOwner, err := crypto.Base64URLDecode(d.Owner)
 	if err != nil {
 		return err
 	}
-
-	publicKey, err := crypto.GetPublicKeyFromOwner(d.Owner)
+	rawTarget, err := crypto.Base64URLDecode(d.Target)
 	if err != nil {
 		return err
 	}
-	err = crypto.Verify(chunks, rawSignature, publicKey)
+	rawTags, err := tag.Serialize(d.Tags)
 	if err != nil {
 		return err
 	}
+	chunks := [][]byte{
+		[]byte("dataitem"),
+		[]byte("1"),
+		[]byte(strconv.Itoa(d.signatureType())),
+		rawOwner,
+		rawTarget,
+		[]byte(d.Anchor),
+		rawTags,
+	}
+	data := d.Raw[d.dataStart:]
+	deepHashChunk, err := crypto.DeepHashMixed(chunks, bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return err
+	}
+	return d.verifyChunk(deepHashChunk[:])
+}
+
+// verifyChunk checks the ID, the signature over the given deep hash and the
+// ANS-104 field limits.
+func (d *DataItem) verifyChunk(chunks []byte) error {
+	// Verify ID
+	rawSignature, err := crypto.Base64URLDecode(d.Signature)
+	if err != nil {
+		return err
+	}
+
+	rawId := crypto.SHA256(rawSignature)
+	id := crypto.Base64URLEncode(rawId)
+
+	if id != d.ID {
+		return goar.Errorf(goar.ErrInvalidSignature, "invalid data item - signature and id don't match")
+	}
+
+	switch d.signatureType() {
+	case Arweave:
+		publicKey, err := crypto.GetPublicKeyFromOwner(d.Owner)
+		if err != nil {
+			return err
+		}
+		err = crypto.Verify(chunks, rawSignature, publicKey)
+		if err != nil {
+			return goar.Wrap(goar.ErrInvalidSignature, err)
+		}
+	case Ethereum:
+		rawOwner, err := crypto.Base64URLDecode(d.Owner)
+		if err != nil {
+			return err
+		}
+		if err := crypto.VerifyEthereum(chunks, rawSignature, rawOwner); err != nil {
+			return goar.Wrap(goar.ErrInvalidSignature, err)
+		}
+	case ED25519, Solana:
+		rawOwner, err := crypto.Base64URLDecode(d.Owner)
+		if err != nil {
+			return err
+		}
+		if err := crypto.VerifyED25519(chunks, rawSignature, rawOwner); err != nil {
+			return goar.Wrap(goar.ErrInvalidSignature, err)
+		}
+	default:
+		return goar.Errorf(goar.ErrInvalidSignature, "unsupported signature type %d", d.SignatureType)
+	}
 
 	// VERIFY TAGS
 	if len(*d.Tags) > MAX_TAGS {
-		return errors.New("invalid data item - tags cannot be more than 128")
+		return goar.Errorf(goar.ErrInvalidInput, "invalid data item - tags cannot be more than 128")
 	}
 
 	for _, t := range *d.Tags {
 		if len([]byte(t.Name)) == 0 || len([]byte(t.Name)) > MAX_TAG_KEY_LENGTH {
-			return errors.New("invalid data item - tag key too long")
+			return goar.Errorf(goar.ErrInvalidInput, "invalid data item - tag key too long")
 		}
 		if len([]byte(t.Value)) == 0 || len([]byte(t.Value)) > MAX_TAG_VALUE_LENGTH {
-			return errors.New("invalid data item - tag value too long")
+			return goar.Errorf(goar.ErrInvalidInput, "invalid data item - tag value too long")
 		}
 	}
 
-	if len([]byte(d.Anchor)) > 32 {
-		return errors.New("invalid data item - anchor should be 32 bytes")
+	if _, err := d.AnchorValue(); err != nil {
+		return goar.Errorf(goar.ErrInvalidInput, "invalid data item - anchor should be 32 bytes")
 	}
 	return nil
 }
@@ -2,13 +2,19 @@ package data_item
 
 import (
 	"bytes"
+	"context"
 	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
 	"fmt"
 	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
 	"github.com/liteseed/goar/signer"
 	"github.com/liteseed/goar/tag"
 	"github.com/stretchr/testify/assert"
@@ -182,6 +188,252 @@ func TestVerifyDataItem(t *testing.T) {
 	})
 }
 
+// TestDecodeLazy tests on-demand field computation from Raw
+func TestDecodeLazy(t *testing.T) {
+	data, err := os.ReadFile("../../test/1115BDataItem")
+	require.NoError(t, err)
+
+	t.Run("DecodeLazy - Fields computed on access", func(t *testing.T) {
+		dataItem, err := DecodeLazy(data)
+		require.NoError(t, err)
+
+		assert.Empty(t, dataItem.ID)
+		assert.Empty(t, dataItem.Owner)
+		assert.Empty(t, dataItem.Data)
+		assert.Nil(t, dataItem.Tags)
+
+		assert.Equal(t, "QpmY8mZmFEC8RxNsgbxSV6e36OF6quIYaPRKzvUco0o", dataItem.GetID())
+		assert.Equal(t, int64(5), dataItem.GetDataSize())
+		assert.Equal(t, "NTY3MAo", dataItem.GetData())
+
+		tags, err := dataItem.GetTags()
+		require.NoError(t, err)
+		assert.Len(t, *tags, 3)
+
+		list, err := dataItem.TagList()
+		require.NoError(t, err)
+		assert.Len(t, list, 3)
+		assert.Equal(t, (*tags)[0].Value, list.Get((*tags)[0].Name))
+		assert.False(t, list.Has("Missing"))
+	})
+
+	t.Run("DecodeLazy - Matches Decode after Materialize", func(t *testing.T) {
+		lazy, err := DecodeLazy(data)
+		require.NoError(t, err)
+		require.NoError(t, lazy.Materialize())
+
+		eager, err := Decode(data)
+		require.NoError(t, err)
+
+		assert.Equal(t, eager.ID, lazy.ID)
+		assert.Equal(t, eager.Signature, lazy.Signature)
+		assert.Equal(t, eager.Owner, lazy.Owner)
+		assert.Equal(t, eager.Data, lazy.Data)
+		assert.Equal(t, eager.Tags, lazy.Tags)
+	})
+
+	t.Run("DecodeLazy - Verify", func(t *testing.T) {
+		dataItem, err := DecodeLazy(data)
+		require.NoError(t, err)
+		assert.NoError(t, dataItem.Verify())
+	})
+
+	t.Run("DecodeLazy - Truncated", func(t *testing.T) {
+		_, err := DecodeLazy(data[:600])
+		assert.Error(t, err)
+		_, err = DecodeLazy(data[:1])
+		assert.Error(t, err)
+	})
+}
+
+// TestDecodeStreamHeader verifies headers decode without reading the data
+func TestDecodeStreamHeader(t *testing.T) {
+	data, err := os.ReadFile("../../test/1115BDataItem")
+	require.NoError(t, err)
+
+	dataItem, err := DecodeStream(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+	eager, err := Decode(data)
+	require.NoError(t, err)
+	assert.Equal(t, eager.ID, dataItem.ID)
+	assert.Equal(t, eager.Owner, dataItem.Owner)
+	assert.Equal(t, eager.Tags, dataItem.Tags)
+	assert.Equal(t, int64(5), dataItem.DataSize)
+	assert.Equal(t, int64(len(data)), dataItem.RawSize())
+	assert.Len(t, dataItem.Raw, len(data)-5)
+	assert.NoError(t, dataItem.Verify())
+
+	var buf bytes.Buffer
+	require.NoError(t, dataItem.WriteRawTo(&buf))
+	assert.Equal(t, data, buf.Bytes())
+
+	_, err = DecodeStream(bytes.NewReader(data), 600)
+	assert.Equal(t, goar.ErrDecode, goar.CodeOf(err))
+	_, err = DecodeStream(bytes.NewReader(data), 1)
+	assert.Equal(t, goar.ErrDecode, goar.CodeOf(err))
+}
+
+// TestDecodeStream verifies large items on disk are verified without loading their data
+func TestDecodeStream(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte("goar"), 1<<20)
+	tags := &[]tag.Tag{{Name: "Content-Type", Value: "application/octet-stream"}}
+	d := NewFromReader(bytes.NewReader(data), int64(len(data)), "", "", tags)
+	require.NoError(t, d.Sign(s))
+
+	path := filepath.Join(t.TempDir(), "item.bin")
+	require.NoError(t, d.WriteRawFile(path))
+	f, err := os.Open(path)
+	require.NoError(t, err)
+	defer f.Close()
+	info, err := f.Stat()
+	require.NoError(t, err)
+
+	decoded, err := DecodeStream(f, info.Size())
+	require.NoError(t, err)
+	assert.Equal(t, d.ID, decoded.ID)
+	assert.Equal(t, tags, decoded.Tags)
+	assert.Equal(t, int64(len(data)), decoded.DataSize)
+	assert.Less(t, len(decoded.Raw), 2048)
+	require.NoError(t, decoded.Verify())
+	assert.Empty(t, decoded.Data)
+
+	t.Run("Tampered", func(t *testing.T) {
+		tampered, err := os.ReadFile(path)
+		require.NoError(t, err)
+		tampered[len(tampered)-1] ^= 1
+		decoded, err := DecodeStream(bytes.NewReader(tampered), int64(len(tampered)))
+		require.NoError(t, err)
+		assert.Error(t, decoded.Verify())
+	})
+}
+
+// TestEncode verifies decoded items encode back to the same binary
+func TestEncode(t *testing.T) {
+	data, err := os.ReadFile("../../test/1115BDataItem")
+	require.NoError(t, err)
+
+	t.Run("Round trip", func(t *testing.T) {
+		for _, decode := range []func([]byte) (*DataItem, error){Decode, DecodeLazy} {
+			d, err := decode(append([]byte(nil), data...))
+			require.NoError(t, err)
+			require.NoError(t, d.Encode())
+			assert.Equal(t, data, d.Raw)
+			assert.Equal(t, "QpmY8mZmFEC8RxNsgbxSV6e36OF6quIYaPRKzvUco0o", d.ID)
+			assert.NoError(t, d.VerifyRaw())
+		}
+	})
+
+	t.Run("Header only", func(t *testing.T) {
+		d, err := DecodeStream(bytes.NewReader(data), int64(len(data)))
+		require.NoError(t, err)
+		require.NoError(t, d.Encode())
+		assert.Equal(t, data[:len(data)-5], d.Raw)
+		var buf bytes.Buffer
+		require.NoError(t, d.WriteRawTo(&buf))
+		assert.Equal(t, data, buf.Bytes())
+	})
+
+	t.Run("Modified", func(t *testing.T) {
+		d, err := Decode(data)
+		require.NoError(t, err)
+		d.Tags = &[]tag.Tag{{Name: "Content-Type", Value: "text/plain"}}
+		require.NoError(t, d.Encode())
+		decoded, err := Decode(d.Raw)
+		require.NoError(t, err)
+		assert.Equal(t, d.Tags, decoded.Tags)
+		assert.Error(t, decoded.Verify())
+
+		s, err := signer.FromPath("../../test/signer.json")
+		require.NoError(t, err)
+		require.NoError(t, d.Sign(s))
+		decoded, err = Decode(d.Raw)
+		require.NoError(t, err)
+		assert.NoError(t, decoded.Verify())
+	})
+
+	t.Run("Tags over 64 KiB", func(t *testing.T) {
+		s, err := signer.FromPath("../../test/signer.json")
+		require.NoError(t, err)
+		var tags []tag.Tag
+		for i := 0; i < 30; i++ {
+			tags = append(tags, tag.Tag{Name: fmt.Sprint("Tag-", i), Value: strings.Repeat("v", 3000)})
+		}
+		d := New([]byte("data"), "", "", &tags)
+		require.NoError(t, d.Sign(s))
+		rawTags, err := tag.Serialize(d.Tags)
+		require.NoError(t, err)
+		require.Greater(t, len(rawTags), 1<<16)
+
+		for _, decode := range []func([]byte) (*DataItem, error){
+			Decode,
+			DecodeLazy,
+			func(b []byte) (*DataItem, error) { return DecodeStream(bytes.NewReader(b), int64(len(b))) },
+		} {
+			decoded, err := decode(d.Raw)
+			require.NoError(t, err)
+			require.NoError(t, decoded.Materialize())
+			assert.Equal(t, d.ID, decoded.ID)
+			assert.Equal(t, d.Tags, decoded.Tags)
+			assert.NoError(t, decoded.Verify())
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		unsigned := New([]byte("data"), "", "", nil)
+		assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(unsigned.Encode()))
+
+		d, err := Decode(data)
+		require.NoError(t, err)
+		d.Owner = d.Owner[:10]
+		assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(d.Encode()))
+	})
+}
+
+// TestVerifyRaw tests verification straight from the Raw bytes
+func TestVerifyRaw(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+
+	t.Run("VerifyRaw - Stub", func(t *testing.T) {
+		data, err := os.ReadFile("../../test/1115BDataItem")
+		require.NoError(t, err)
+
+		dataItem, err := Decode(data)
+		require.NoError(t, err)
+		assert.NoError(t, dataItem.VerifyRaw())
+	})
+
+	t.Run("VerifyRaw - Signed data item", func(t *testing.T) {
+		tags := &[]tag.Tag{{Name: "tag1", Value: "value1"}}
+		dataItem := New([]byte("raw verification"), "OXcT1sVRSA5eGwt2k6Yuz8-3e3g9WJi5uSE99CWqsBs", "thisSentenceIs32BytesLongTrustMe", tags)
+		require.NoError(t, dataItem.Sign(s))
+		assert.NoError(t, dataItem.VerifyRaw())
+
+		decoded, err := Decode(dataItem.Raw)
+		require.NoError(t, err)
+		assert.NoError(t, decoded.VerifyRaw())
+	})
+
+	t.Run("VerifyRaw - Tampered data", func(t *testing.T) {
+		dataItem := New([]byte("raw verification"), "", "", nil)
+		require.NoError(t, dataItem.Sign(s))
+
+		raw := bytes.Clone(dataItem.Raw)
+		raw[len(raw)-1] ^= 0xff
+		decoded, err := Decode(raw)
+		require.NoError(t, err)
+		assert.Error(t, decoded.VerifyRaw())
+	})
+
+	t.Run("VerifyRaw - Not decoded", func(t *testing.T) {
+		dataItem := New([]byte("raw verification"), "", "", nil)
+		assert.Error(t, dataItem.VerifyRaw())
+	})
+}
+
 // MockReadSeeker implements io.ReadSeeker for testing streaming functionality
 type MockReadSeeker struct {
 	data     []byte
@@ -296,7 +548,7 @@ func TestNewFromReader(t *testing.T) {
 		data := []byte("Test data with target and anchor")
 		reader := NewMockReadSeeker(data)
 		target := "OXcT1sVRSA5eGwt2k6Yuz8-3e3g9WJi5uSE99CWqsBs"
-		anchor := "test_anchor_string"
+		anchor := "test_anchor_string_of_32_bytes!!"
 		tags := &[]tag.Tag{{Name: "Type", Value: "Test"}}
 
 		dataItem := NewFromReader(reader, int64(len(data)), target, anchor, tags)
@@ -854,3 +1106,409 @@ func TestWriteRawMemoryEfficiency(t *testing.T) {
 		// of WriteRawTo without memory allocation proportional to data size.
 	})
 }
+
+// TestErrorCodes tests that decode and verification errors carry error codes
+func TestErrorCodes(t *testing.T) {
+	data, err := os.ReadFile("../../test/1115BDataItem")
+	require.NoError(t, err)
+
+	_, err = Decode(data[:100])
+	assert.ErrorIs(t, err, goar.ErrDecode)
+
+	_, err = Decode([]byte{9, 0, 0})
+	assert.ErrorIs(t, err, goar.ErrDecode)
+
+	raw := bytes.Clone(data)
+	raw[len(raw)-1] ^= 0xff
+	dataItem, err := Decode(raw)
+	require.NoError(t, err)
+	assert.ErrorIs(t, dataItem.Verify(), goar.ErrInvalidSignature)
+}
+
+func TestSignApproval(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+
+	var req *signer.SigningRequest
+	s.Approver = signer.ApproverFunc(func(r *signer.SigningRequest) error {
+		req = r
+		return fmt.Errorf("policy: %d bytes is too large", r.DataSize)
+	})
+
+	tags := &[]tag.Tag{{Name: "App-Name", Value: "goar"}}
+	dataItem := New([]byte("hello"), "", "", tags)
+	err = dataItem.Sign(s)
+	assert.ErrorIs(t, err, goar.ErrSigningDenied)
+	assert.Empty(t, dataItem.ID)
+
+	require.NotNil(t, req)
+	assert.Equal(t, signer.KindDataItem, req.Kind)
+	assert.Equal(t, int64(5), req.DataSize)
+	assert.Equal(t, *tags, req.Tags)
+}
+
+func TestOwnerAddress(t *testing.T) {
+	// rawItem builds a data item binary with the given signature type, owner and no target, anchor or tags
+	rawItem := func(signatureType int, owner []byte) []byte {
+		raw := binary.LittleEndian.AppendUint16(nil, uint16(signatureType))
+		raw = append(raw, make([]byte, SignatureConfig[signatureType].SignatureLength)...)
+		raw = append(raw, owner...)
+		raw = append(raw, 0, 0)
+		raw = append(raw, make([]byte, 16)...)
+		return append(raw, []byte("data")...)
+	}
+
+	t.Run("Ethereum", func(t *testing.T) {
+		owner, err := hex.DecodeString("0479be667ef9dcbbac55a06295ce870b07029bfcdb2dce28d959f2815b16f81798483ada7726a3c4655da4fbfc0e1108a8fd17b448a68554199c47d08ffb10d4b8")
+		require.NoError(t, err)
+		dataItem, err := Decode(rawItem(Ethereum, owner))
+		require.NoError(t, err)
+		assert.Equal(t, "0x7E5F4552091A69125d5DfCb7b8C2659029395Bdf", dataItem.OwnerAddress)
+	})
+
+	t.Run("Solana", func(t *testing.T) {
+		owner := make([]byte, 32)
+		owner[31] = 1
+		dataItem, err := DecodeLazy(rawItem(Solana, owner))
+		require.NoError(t, err)
+		assert.Equal(t, "11111111111111111111111111111112", dataItem.GetOwnerAddress())
+	})
+
+	t.Run("Arweave", func(t *testing.T) {
+		data, err := os.ReadFile("../../test/1115BDataItem")
+		require.NoError(t, err)
+		dataItem, err := Decode(data)
+		require.NoError(t, err)
+		address, err := crypto.GetAddressFromOwner(dataItem.Owner)
+		require.NoError(t, err)
+		assert.Equal(t, address, dataItem.OwnerAddress)
+	})
+
+	t.Run("Signed", func(t *testing.T) {
+		s, err := signer.FromPath("../../test/signer.json")
+		require.NoError(t, err)
+		dataItem := New([]byte("hello"), "", "", nil)
+		require.NoError(t, dataItem.Sign(s))
+		assert.Equal(t, s.Address, dataItem.OwnerAddress)
+	})
+}
+
+func TestCheckSize(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+
+	d := New([]byte("0123456789"), "", "", nil)
+	assert.NoError(t, d.CheckSize())
+
+	d.MaxSize = 10
+	assert.NoError(t, d.CheckSize())
+
+	d.MaxSize = 4
+	err = d.Sign(s)
+	assert.ErrorIs(t, err, goar.ErrItemTooLarge)
+	var sizeErr *SizeError
+	require.ErrorAs(t, err, &sizeErr)
+	assert.Equal(t, int64(4), sizeErr.Limit)
+	assert.Equal(t, int64(10), sizeErr.Size)
+	assert.Empty(t, d.Signature)
+
+	r := NewFromReader(bytes.NewReader(make([]byte, 64)), 64, "", "", nil)
+	r.MaxSize = 32
+	assert.ErrorIs(t, r.CheckSize(), goar.ErrItemTooLarge)
+}
+
+func TestTargetAndAnchor(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+
+	target, err := goar.ParseAddress("Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek")
+	require.NoError(t, err)
+	anchor, err := goar.NewAnchor(bytes.Repeat([]byte{'a'}, 32))
+	require.NoError(t, err)
+
+	d := New([]byte("data"), "", "", nil)
+	d.SetTarget(target)
+	d.SetAnchor(anchor)
+	require.NoError(t, d.Sign(s))
+
+	decoded, err := Decode(d.Raw)
+	require.NoError(t, err)
+	decodedTarget, err := decoded.TargetAddress()
+	require.NoError(t, err)
+	assert.Equal(t, target, decodedTarget)
+	decodedAnchor, err := decoded.AnchorValue()
+	require.NoError(t, err)
+	assert.Equal(t, anchor, decodedAnchor)
+
+	// Anchors shorter than 32 bytes used to produce undecodable binaries
+	d = New([]byte("data"), "", "short anchor", nil)
+	assert.ErrorIs(t, d.Sign(s), goar.ErrInvalidInput)
+	assert.Empty(t, d.Signature)
+
+	d = New([]byte("data"), "not an address", "", nil)
+	assert.ErrorIs(t, d.Sign(s), goar.ErrInvalidInput)
+}
+
+func TestClone(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	data := bytes.Repeat([]byte("goar"), 1024)
+
+	t.Run("Streaming", func(t *testing.T) {
+		d := NewFromReader(bytes.NewReader(data), int64(len(data)), "", "", &[]tag.Tag{{Name: "Version", Value: "1"}})
+		require.NoError(t, d.Sign(s))
+
+		c := d.Clone()
+		assert.Equal(t, d.ID, c.ID)
+		(*c.Tags)[0].Value = "changed"
+		assert.Equal(t, "1", (*d.Tags)[0].Value)
+		assert.NotSame(t, d.DataReader, c.DataReader)
+
+		retagged := d.WithTags(&[]tag.Tag{{Name: "Version", Value: "2"}})
+		assert.Empty(t, retagged.ID)
+		require.NoError(t, retagged.Sign(s))
+		assert.NotEqual(t, d.ID, retagged.ID)
+		assert.NoError(t, retagged.Verify())
+		assert.NoError(t, d.Verify())
+
+		raw, err := retagged.GetRawWithData()
+		require.NoError(t, err)
+		decoded, err := Decode(raw)
+		require.NoError(t, err)
+		assert.Equal(t, data, decoded.RawData())
+		assert.Equal(t, "2", (*decoded.Tags)[0].Value)
+	})
+
+	t.Run("Decoded", func(t *testing.T) {
+		d := New(data, "", "", nil)
+		require.NoError(t, d.Sign(s))
+		lazy, err := DecodeLazy(d.Raw)
+		require.NoError(t, err)
+
+		retagged := lazy.WithTags(&[]tag.Tag{{Name: "Content-Type", Value: "text/plain"}})
+		assert.Nil(t, retagged.Raw)
+		require.NoError(t, retagged.Sign(s))
+		assert.NoError(t, retagged.Verify())
+
+		raw, err := retagged.GetRawWithData()
+		require.NoError(t, err)
+		decoded, err := Decode(raw)
+		require.NoError(t, err)
+		assert.Equal(t, data, decoded.RawData())
+		assert.Equal(t, d.ID, lazy.GetID())
+	})
+}
+
+func TestMemoryLimit(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+
+	data := bytes.Repeat([]byte{1}, 4096)
+	d := NewFromReader(bytes.NewReader(data), int64(len(data)), "", "", nil)
+	require.NoError(t, d.Sign(s))
+	assert.Equal(t, int64(len(d.Raw)+len(data)), d.RawSize())
+
+	d.MemoryLimit = 4096
+	_, err = d.GetRawWithData()
+	assert.ErrorIs(t, err, goar.ErrItemTooLarge)
+	var sizeErr *SizeError
+	require.ErrorAs(t, err, &sizeErr)
+	assert.Equal(t, d.RawSize(), sizeErr.Size)
+
+	d.MemoryLimit = d.RawSize()
+	raw, err := d.GetRawWithData()
+	require.NoError(t, err)
+	assert.Len(t, raw, int(d.RawSize()))
+	assert.Equal(t, data, raw[len(d.Raw):])
+
+	var buf bytes.Buffer
+	require.NoError(t, d.WriteRawTo(&buf))
+	assert.Equal(t, raw, buf.Bytes())
+}
+
+// TestEthereumDataItem verifies data items signed with Ethereum keys round-trip and verify
+func TestEthereumDataItem(t *testing.T) {
+	s, err := signer.EthereumFromHex("4c0883a69102937d6231471b5dbb6204fe5129617082792ae468d01a3f362318")
+	require.NoError(t, err)
+	tags := []tag.Tag{{Name: "Content-Type", Value: "text/plain"}}
+	dataItem := New([]byte("hello"), "", "", &tags)
+	require.NoError(t, dataItem.Sign(s))
+
+	assert.Equal(t, Ethereum, dataItem.SignatureType)
+	assert.Equal(t, s.Address, dataItem.OwnerAddress)
+	assert.Equal(t, uint16(Ethereum), binary.LittleEndian.Uint16(dataItem.Raw))
+	assert.Len(t, dataItem.RawSignature(), SignatureConfig[Ethereum].SignatureLength)
+	require.NoError(t, dataItem.Verify())
+
+	decoded, err := Decode(dataItem.Raw)
+	require.NoError(t, err)
+	assert.Equal(t, dataItem.ID, decoded.ID)
+	assert.Equal(t, s.Address, decoded.OwnerAddress)
+	require.NoError(t, decoded.Verify())
+	require.NoError(t, decoded.VerifyRaw())
+
+	t.Run("Tampered", func(t *testing.T) {
+		raw := bytes.Clone(dataItem.Raw)
+		raw[len(raw)-1] ^= 0xff
+		tampered, err := Decode(raw)
+		require.NoError(t, err)
+		assert.Equal(t, goar.ErrInvalidSignature, goar.CodeOf(tampered.VerifyRaw()))
+	})
+
+	t.Run("Streaming", func(t *testing.T) {
+		data := bytes.Repeat([]byte("goar"), 1024)
+		streamed := NewFromReader(bytes.NewReader(data), int64(len(data)), "", "", nil)
+		require.NoError(t, streamed.Sign(s))
+		require.NoError(t, streamed.Verify())
+		raw, err := streamed.GetRawWithData()
+		require.NoError(t, err)
+		decoded, err := Decode(raw)
+		require.NoError(t, err)
+		require.NoError(t, decoded.VerifyRaw())
+	})
+}
+
+// TestED25519DataItem verifies data items signed with ED25519 and Solana keys round-trip and verify
+func TestED25519DataItem(t *testing.T) {
+	s, err := signer.NewED25519()
+	require.NoError(t, err)
+	solana := signer.ED25519FromPrivateKey(s.PrivateKey)
+	solana.Solana = true
+
+	for _, tt := range []struct {
+		name          string
+		signer        *signer.ED25519Signer
+		signatureType int
+	}{
+		{"ED25519", s, ED25519},
+		{"Solana", solana, Solana},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			tags := []tag.Tag{{Name: "Content-Type", Value: "text/plain"}}
+			dataItem := New([]byte("hello"), "", "", &tags)
+			require.NoError(t, dataItem.Sign(tt.signer))
+			assert.Equal(t, tt.signatureType, dataItem.SignatureType)
+			assert.Equal(t, tt.signer.Address, dataItem.OwnerAddress)
+			require.NoError(t, dataItem.Verify())
+
+			decoded, err := Decode(dataItem.Raw)
+			require.NoError(t, err)
+			assert.Equal(t, tt.signatureType, decoded.SignatureType)
+			assert.Equal(t, dataItem.ID, decoded.ID)
+			assert.Equal(t, tt.signer.Address, decoded.OwnerAddress)
+			require.NoError(t, decoded.VerifyRaw())
+
+			raw := bytes.Clone(dataItem.Raw)
+			raw[len(raw)-1] ^= 0xff
+			tampered, err := Decode(raw)
+			require.NoError(t, err)
+			assert.Equal(t, goar.ErrInvalidSignature, goar.CodeOf(tampered.VerifyRaw()))
+		})
+	}
+}
+
+func TestNewFromStream(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	data := bytes.Repeat([]byte("piped data "), 1000)
+	tags := &[]tag.Tag{{Name: "Content-Type", Value: "text/plain"}}
+
+	signed := func(t *testing.T, item *DataItem) *DataItem {
+		require.NoError(t, item.Sign(s))
+		var buffer bytes.Buffer
+		require.NoError(t, item.WriteRawTo(&buffer))
+		decoded, err := Decode(buffer.Bytes())
+		require.NoError(t, err)
+		assert.Equal(t, item.ID, decoded.ID)
+		assert.NoError(t, decoded.Verify())
+		return decoded
+	}
+
+	t.Run("NewFromStream - Pipe", func(t *testing.T) {
+		item, err := NewFromStream(io.MultiReader(bytes.NewReader(data)), int64(len(data)), "", "", tags)
+		require.NoError(t, err)
+		spooled := item.DataReader.(*spoolFile).Name()
+		decoded := signed(t, item)
+		assert.Equal(t, data, decoded.RawData())
+
+		require.NoError(t, item.Close())
+		_, err = os.Stat(spooled)
+		assert.True(t, os.IsNotExist(err))
+	})
+
+	t.Run("NewFromStream - Read to end", func(t *testing.T) {
+		item, err := NewFromStream(io.MultiReader(bytes.NewReader(data)), -1, "", "", tags)
+		require.NoError(t, err)
+		defer item.Close()
+		assert.Equal(t, int64(len(data)), item.DataSize)
+		assert.Equal(t, data, signed(t, item).RawData())
+	})
+
+	t.Run("NewFromStream - Seekable from its position", func(t *testing.T) {
+		reader := bytes.NewReader(append([]byte("skip"), data...))
+		_, err := reader.Seek(4, io.SeekStart)
+		require.NoError(t, err)
+		item, err := NewFromStream(reader, 100, "", "", tags)
+		require.NoError(t, err)
+		assert.IsType(t, &io.SectionReader{}, item.DataReader)
+		assert.Equal(t, data[:100], signed(t, item).RawData())
+		assert.NoError(t, item.Close())
+	})
+
+	t.Run("NewFromStream - Short input", func(t *testing.T) {
+		_, err := NewFromStream(io.MultiReader(bytes.NewReader(data)), int64(len(data)+1), "", "", nil)
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+	})
+
+	t.Run("NewFromStream - Cancel signing", func(t *testing.T) {
+		item, err := NewFromStream(bytes.NewReader(data), int64(len(data)), "", "", tags)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, item.SignContext(ctx, s), context.Canceled)
+		assert.Empty(t, item.Signature)
+	})
+}
+
+func TestEncodedSize(t *testing.T) {
+	s, err := signer.FromPath("../../test/signer.json")
+	require.NoError(t, err)
+	ed, err := signer.NewED25519()
+	require.NoError(t, err)
+	tags := []tag.Tag{{Name: "Content-Type", Value: "text/plain"}, {Name: "App-Name", Value: strings.Repeat("a", 200)}}
+	target := "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek"
+	anchor := strings.Repeat("a", 32)
+
+	for name, tc := range map[string]struct {
+		item   func() *DataItem
+		signer signer.ItemSigner
+	}{
+		"Empty":     {func() *DataItem { return New(nil, "", "", nil) }, s},
+		"Data":      {func() *DataItem { return New([]byte("hello"), "", "", nil) }, s},
+		"All":       {func() *DataItem { return New([]byte("hello"), target, anchor, &tags) }, s},
+		"ED25519":   {func() *DataItem { return New([]byte("hello"), target, "", &tags) }, ed},
+		"Streaming": {func() *DataItem { return NewFromReader(bytes.NewReader(make([]byte, 1000)), 1000, "", anchor, &tags) }, s},
+	} {
+		d := tc.item()
+		d.SignatureType = tc.signer.SignatureType()
+		size, err := d.EncodedSize()
+		require.NoError(t, err, name)
+
+		require.NoError(t, d.Sign(tc.signer), name)
+		assert.Equal(t, d.RawSize(), size, name)
+		after, err := d.EncodedSize()
+		require.NoError(t, err, name)
+		assert.Equal(t, size, after, name)
+
+		if d.DataReader == nil {
+			lazy, err := DecodeLazy(d.Raw)
+			require.NoError(t, err, name)
+			size, err = lazy.EncodedSize()
+			require.NoError(t, err, name)
+			assert.Equal(t, int64(len(d.Raw)), size, name)
+		}
+	}
+
+	_, err = (&DataItem{SignatureType: 9}).EncodedSize()
+	assert.ErrorIs(t, err, goar.ErrInvalidInput)
+}
@@ -0,0 +1,102 @@
+package data_item
+
+import (
+	"io"
+	"os"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/tag"
+)
+
+// NewFromStream creates a streaming data item from a reader that may not be seekable, such as a pipe.
+//
+// Signing reads the data twice, so a reader that cannot seek, e.g. stdin
+// fed by `cat big.tar |`, is first copied to a temporary file that becomes
+// the DataReader. Seekable readers, such as regular files, are used as they
+// are. Call Close once the item is written to release the temporary file.
+//
+// Parameters:
+//   - r: The data
+//   - size: The size of the data in bytes, or -1 to read r to its end
+//   - target: Optional target address
+//   - anchor: Optional anchor
+//   - tags: Optional tags
+//
+// Returns an error with code goar.ErrInvalidInput if r ends before size bytes.
+//
+// Example:
+//
+//	item, err := data_item.NewFromStream(os.Stdin, size, "", "", &tags)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer item.Close()
+//	if err := item.Sign(s); err != nil {
+//		log.Fatal(err)
+//	}
+//	err = item.WriteRawTo(os.Stdout)
+func NewFromStream(r io.Reader, size int64, target string, anchor string, tags *[]tag.Tag) (*DataItem, error) {
+	if seeker, ok := r.(io.ReadSeeker); ok && size >= 0 {
+		if start, err := seeker.Seek(0, io.SeekCurrent); err == nil {
+			// The data item reads its data from offset 0, the current position of r
+			return NewFromReader(io.NewSectionReader(readerAt{seeker}, start, size), size, target, anchor, tags), nil
+		}
+	}
+
+	f, err := os.CreateTemp("", "goar-item-*")
+	if err != nil {
+		return nil, err
+	}
+	spooled := &spoolFile{f}
+	var n int64
+	if size < 0 {
+		n, err = io.Copy(f, r)
+	} else {
+		n, err = io.CopyN(f, r, size)
+		if err == io.EOF {
+			err = goar.Errorf(goar.ErrInvalidInput, "data holds %d bytes, expected %d", n, size)
+		}
+	}
+	if err != nil {
+		spooled.Close()
+		return nil, err
+	}
+	return NewFromReader(spooled, n, target, anchor, tags), nil
+}
+
+// Close releases the DataReader of the data item if it is an io.Closer, such as the temporary file of NewFromStream.
+func (d *DataItem) Close() error {
+	if c, ok := d.DataReader.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+// spoolFile is a temporary file removed when closed
+type spoolFile struct {
+	*os.File
+}
+
+func (f *spoolFile) Close() error {
+	err := f.File.Close()
+	if removeErr := os.Remove(f.Name()); err == nil {
+		err = removeErr
+	}
+	return err
+}
+
+// readerAt reads at an offset of a seekable reader, moving its position
+type readerAt struct {
+	r io.ReadSeeker
+}
+
+func (ra readerAt) ReadAt(p []byte, off int64) (int, error) {
+	if _, err := ra.r.Seek(off, io.SeekStart); err != nil {
+		return 0, err
+	}
+	n, err := io.ReadFull(ra.r, p)
+	if err == io.ErrUnexpectedEOF {
+		err = io.EOF
+	}
+	return n, err
+}
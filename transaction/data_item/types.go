@@ -1,6 +1,7 @@
 package data_item
 
 import (
+	"fmt"
 	"io"
 
 	"github.com/liteseed/goar/tag"
@@ -11,13 +12,32 @@ type DataItem struct {
 	Signature     string     `json:"signature"`
 	SignatureType int        `json:"signature_type"`
 	Owner         string     `json:"owner"`
+	OwnerAddress  string     `json:"owner_address,omitempty"` // Owner address in the signer chain's native format
 	Target        string     `json:"target"`
 	Anchor        string     `json:"anchor"`
 	Tags          *[]tag.Tag `json:"tags"`
 	Data          string     `json:"data"` // Used only for serialization/deserialization
 	Raw           []byte
+	MaxSize       int64 `json:"-"` // Maximum data size accepted by Sign, 0 for no limit, see CheckSize
 
 	// Fields for streaming large data
-	DataReader io.ReadSeeker `json:"-"` // Seekable reader for large data (required for multiple passes)
-	DataSize   int64         `json:"-"` // Size of data for streaming
+	DataReader  io.ReadSeeker `json:"-"` // Seekable reader for large data (required for multiple passes)
+	DataSize    int64         `json:"-"` // Size of data for streaming
+	MemoryLimit int64         `json:"-"` // Maximum size GetRawWithData may allocate, 0 for no limit
+
+	dataStart  int  // Offset of the data payload within Raw (0 when unknown)
+	ownerStart int  // Offset of the owner within Raw (0 when unknown)
+	tagsStart  int  // Offset of the tag header within Raw (0 when unknown)
+	lazy       bool // String fields are still to be computed from Raw
+}
+
+// SizeError reports a data item exceeding a configured maximum size.
+// Errors returned by CheckSize and GetRawWithData wrap it with the code goar.ErrItemTooLarge.
+type SizeError struct {
+	Limit int64 // Maximum data size in bytes
+	Size  int64 // Actual data size in bytes
+}
+
+func (e *SizeError) Error() string {
+	return fmt.Sprintf("data item is %d bytes, more than the limit of %d bytes", e.Size, e.Limit)
 }
@@ -1,10 +1,11 @@
 package data_item
 
 import (
-	"encoding/base64"
+	"context"
 	"encoding/binary"
 	"fmt"
 	"io"
+	"strconv"
 
 	"github.com/liteseed/goar/crypto"
 	"github.com/liteseed/goar/tag"
@@ -49,7 +50,7 @@ var SignatureConfig = map[int]SignatureMeta{
 func getTarget(data *[]byte, position int) (string, int) {
 	target := ""
 	if (*data)[position] == 1 {
-		target = base64.RawURLEncoding.EncodeToString((*data)[position+1 : position+1+32])
+		target = crypto.Base64URLEncode((*data)[position+1 : position+1+32])
 		position += 32
 	}
 	return target, position + 1
@@ -63,6 +64,15 @@ func getAnchor(data *[]byte, position int) (string, int) {
 	}
 	return anchor, position + 1
 }
+
+// signatureType returns the signature type, Arweave for data items that have never been signed
+func (d *DataItem) signatureType() int {
+	if d.SignatureType == 0 {
+		return Arweave
+	}
+	return d.SignatureType
+}
+
 func getSignatureMetadata(data []byte) (SignatureType int, SignatureLength int, PublicKeyLength int, err error) {
 	SignatureType = int(binary.LittleEndian.Uint16(data))
 	signatureMeta, ok := SignatureConfig[SignatureType]
@@ -76,7 +86,7 @@ func getSignatureMetadata(data []byte) (SignatureType int, SignatureLength int,
 }
 
 // This function assembles DataItem data in a format specified by ANS-104 and hashes it using DeepHash
-func (d *DataItem) getDataItemChunk() ([]byte, error) {
+func (d *DataItem) getDataItemChunk(ctx context.Context) ([]byte, error) {
 	rawOwner, err := crypto.Base64URLDecode(d.Owner)
 	if err != nil {
 		return nil, err
@@ -95,7 +105,7 @@ func (d *DataItem) getDataItemChunk() ([]byte, error) {
 
 	// Use streaming approach for large data
 	if d.DataReader != nil && d.DataSize > 0 {
-		return d.getDataItemChunkStreaming(rawOwner, rawTarget, rawAnchor, rawTags)
+		return d.getDataItemChunkStreaming(ctx, rawOwner, rawTarget, rawAnchor, rawTags)
 	}
 
 	// Handle in-memory data
@@ -112,7 +122,7 @@ func (d *DataItem) getDataItemChunk() ([]byte, error) {
 	chunks := [][]byte{
 		[]byte("dataitem"),
 		[]byte("1"),
-		[]byte("1"),
+		[]byte(strconv.Itoa(d.signatureType())),
 		rawOwner,
 		rawTarget,
 		rawAnchor,
@@ -124,12 +134,12 @@ func (d *DataItem) getDataItemChunk() ([]byte, error) {
 }
 
 // getDataItemChunkStreaming computes the DataItem hash using streaming for large data
-func (d *DataItem) getDataItemChunkStreaming(rawOwner, rawTarget, rawAnchor, rawTags []byte) ([]byte, error) {
+func (d *DataItem) getDataItemChunkStreaming(ctx context.Context, rawOwner, rawTarget, rawAnchor, rawTags []byte) ([]byte, error) {
 	// Prepare the chunks that come before the data
 	chunks := [][]byte{
 		[]byte("dataitem"),
 		[]byte("1"),
-		[]byte("1"),
+		[]byte(strconv.Itoa(d.signatureType())),
 		rawOwner,
 		rawTarget,
 		rawAnchor,
@@ -150,7 +160,7 @@ func (d *DataItem) getDataItemChunkStreaming(rawOwner, rawTarget, rawAnchor, raw
 	}
 
 	// Use streaming DeepHash for the mixed case
-	deepHashChunk, err := crypto.DeepHashMixed(chunks, reader, d.DataSize)
+	deepHashChunk, err := crypto.DeepHashMixedContext(ctx, chunks, reader, d.DataSize)
 	if err != nil {
 		return nil, err
 	}
@@ -0,0 +1,149 @@
+package transaction
+
+import (
+	"cmp"
+	"encoding/json"
+	"slices"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/tag"
+)
+
+const (
+	// MAX_TAGS_SIZE is the maximum total size in bytes of the names and values of a transaction's tags
+	MAX_TAGS_SIZE = 2048
+	// DEMOTED_TAGS names the tag DemoteTags adds to point to the tags moved into the data, its value is their JSON pointer
+	DEMOTED_TAGS = "Demoted-Tags"
+	// DEMOTED_TAGS_POINTER is the value of the DEMOTED_TAGS tag
+	DEMOTED_TAGS_POINTER = "/tags"
+	// DEMOTED_CONTENT_TYPE is the Content-Type of data holding demoted tags
+	DEMOTED_CONTENT_TYPE = "application/json"
+)
+
+// DemotedData is the JSON document DemoteTags stores as the data of a transaction.
+type DemotedData struct {
+	Tags []tag.Tag `json:"tags"` // Tags moved out of the transaction
+	Data string    `json:"data"` // Base64url-encoded original data
+}
+
+// DemotionReport describes the tags moved by DemoteTags.
+type DemotionReport struct {
+	Moved    []tag.Tag // Tags moved into the data, in their original order
+	Size     int       // Size of the original tags, see TagsSize
+	KeptSize int       // Size of the tags left on the transaction, including DEMOTED_TAGS
+}
+
+// TagsSize returns the size of plain text tags as limited by MAX_TAGS_SIZE.
+func TagsSize(tags []tag.Tag) int {
+	size := 0
+	for _, t := range tags {
+		size += len(t.Name) + len(t.Value)
+	}
+	return size
+}
+
+// DemoteTags moves tags that exceed the L1 limit into the data as JSON, leaving a pointer tag.
+//
+// Transactions whose tags add up to more than MAX_TAGS_SIZE bytes are
+// rejected by miners. When that is the case, DemoteTags moves the largest
+// tags into a DemotedData document that replaces the data, until the
+// remaining ones fit, and adds a DEMOTED_TAGS tag pointing to them. The
+// Content-Type tag is always moved, as the transaction data becomes
+// DEMOTED_CONTENT_TYPE. RestoreTags reverses it. Tags that already fit
+// are returned unchanged with an empty report, so DemoteTags can be
+// applied to every upload of a service that accepts arbitrary metadata.
+//
+// Returns the data and tags of the transaction and the report of what
+// was moved, or an error if the document cannot be encoded.
+//
+// Example:
+//
+//	data, tags, report, err := transaction.DemoteTags(data, tags)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, t := range report.Moved {
+//		log.Printf("moved tag %s (%d bytes) into the data", t.Name, len(t.Value))
+//	}
+//	tx := transaction.NewDataTransaction(data, &tags)
+func DemoteTags(data []byte, tags []tag.Tag) ([]byte, []tag.Tag, *DemotionReport, error) {
+	report := &DemotionReport{Size: TagsSize(tags), KeptSize: TagsSize(tags)}
+	if report.Size <= MAX_TAGS_SIZE {
+		return data, tags, report, nil
+	}
+
+	// Move the largest tags first, so that as many tags as possible stay queryable
+	moved := make([]bool, len(tags))
+	order := make([]int, len(tags))
+	for i := range order {
+		order[i] = i
+	}
+	slices.SortStableFunc(order, func(a, b int) int {
+		return cmp.Compare(TagsSize(tags[b:b+1]), TagsSize(tags[a:a+1]))
+	})
+	size := report.Size + len(DEMOTED_TAGS) + len(DEMOTED_TAGS_POINTER) + len(tag.CONTENT_TYPE) + len(DEMOTED_CONTENT_TYPE)
+	for i, t := range tags {
+		if t.Name == tag.CONTENT_TYPE {
+			moved[i] = true
+			size -= TagsSize(tags[i : i+1])
+		}
+	}
+	for _, i := range order {
+		if size <= MAX_TAGS_SIZE {
+			break
+		}
+		if !moved[i] {
+			moved[i] = true
+			size -= TagsSize(tags[i : i+1])
+		}
+	}
+
+	kept := []tag.Tag{}
+	for i, t := range tags {
+		if moved[i] {
+			report.Moved = append(report.Moved, t)
+		} else {
+			kept = append(kept, t)
+		}
+	}
+	kept = append(kept,
+		tag.Tag{Name: tag.CONTENT_TYPE, Value: DEMOTED_CONTENT_TYPE},
+		tag.Tag{Name: DEMOTED_TAGS, Value: DEMOTED_TAGS_POINTER},
+	)
+	report.KeptSize = TagsSize(kept)
+
+	demoted, err := json.Marshal(DemotedData{Tags: report.Moved, Data: crypto.Base64URLEncode(data)})
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	return demoted, kept, report, nil
+}
+
+// RestoreTags reverses DemoteTags, returning the original data and the tags moved into it.
+//
+// Data and tags without a DEMOTED_TAGS tag are returned unchanged. The
+// restored tags are the ones left on the transaction, without the tags
+// added by DemoteTags, followed by the moved ones.
+//
+// Returns an error with code goar.ErrDecode if data is not a DemotedData document.
+func RestoreTags(data []byte, tags []tag.Tag) ([]byte, []tag.Tag, error) {
+	if !tag.Tags(tags).Has(DEMOTED_TAGS) {
+		return data, tags, nil
+	}
+	var demoted DemotedData
+	if err := json.Unmarshal(data, &demoted); err != nil {
+		return nil, nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	original, err := crypto.Base64URLDecode(demoted.Data)
+	if err != nil {
+		return nil, nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	restored := []tag.Tag{}
+	for _, t := range tags {
+		if t.Name != DEMOTED_TAGS && t.Name != tag.CONTENT_TYPE {
+			restored = append(restored, t)
+		}
+	}
+	return original, append(restored, demoted.Tags...), nil
+}
@@ -0,0 +1,65 @@
+package transaction
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDemoteTags(t *testing.T) {
+	data := []byte("Hello, Arweave!")
+
+	t.Run("Fitting tags", func(t *testing.T) {
+		tags := []tag.Tag{{Name: "Content-Type", Value: "text/plain"}, {Name: "App-Name", Value: "goar"}}
+		demotedData, demotedTags, report, err := DemoteTags(data, tags)
+		require.NoError(t, err)
+		assert.Equal(t, data, demotedData)
+		assert.Equal(t, tags, demotedTags)
+		assert.Empty(t, report.Moved)
+		assert.Equal(t, 34, report.Size)
+	})
+
+	t.Run("Oversized tags", func(t *testing.T) {
+		tags := []tag.Tag{
+			{Name: "Content-Type", Value: "text/plain"},
+			{Name: "Description", Value: strings.Repeat("d", 1500)},
+			{Name: "App-Name", Value: "goar"},
+			{Name: "Abstract", Value: strings.Repeat("a", 1000)},
+			{Name: "Summary", Value: strings.Repeat("s", 100)},
+		}
+		demotedData, demotedTags, report, err := DemoteTags(data, tags)
+		require.NoError(t, err)
+		assert.Equal(t, []tag.Tag{tags[0], tags[1]}, report.Moved)
+		assert.Equal(t, TagsSize(tags), report.Size)
+		assert.Equal(t, TagsSize(demotedTags), report.KeptSize)
+		assert.LessOrEqual(t, report.KeptSize, MAX_TAGS_SIZE)
+		assert.Equal(t, []tag.Tag{
+			tags[2],
+			tags[3],
+			tags[4],
+			{Name: "Content-Type", Value: DEMOTED_CONTENT_TYPE},
+			{Name: DEMOTED_TAGS, Value: DEMOTED_TAGS_POINTER},
+		}, demotedTags)
+		assert.JSONEq(t, `{"tags":[{"name":"Content-Type","value":"text/plain"},{"name":"Description","value":"`+strings.Repeat("d", 1500)+`"}],"data":"SGVsbG8sIEFyd2VhdmUh"}`, string(demotedData))
+
+		restoredData, restoredTags, err := RestoreTags(demotedData, demotedTags)
+		require.NoError(t, err)
+		assert.Equal(t, data, restoredData)
+		assert.ElementsMatch(t, tags, restoredTags)
+	})
+
+	t.Run("Restore without demotion", func(t *testing.T) {
+		tags := []tag.Tag{{Name: "App-Name", Value: "goar"}}
+		restoredData, restoredTags, err := RestoreTags(data, tags)
+		require.NoError(t, err)
+		assert.Equal(t, data, restoredData)
+		assert.Equal(t, tags, restoredTags)
+
+		_, _, err = RestoreTags(data, []tag.Tag{{Name: DEMOTED_TAGS, Value: DEMOTED_TAGS_POINTER}})
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
@@ -0,0 +1,86 @@
+package transaction
+
+import (
+	"crypto/sha256"
+	"hash"
+	"sync/atomic"
+)
+
+// Hasher computes the SHA-256 digests of chunking and Merkle trees, see SetHasher.
+type Hasher interface {
+	// New returns a SHA-256 hash.Hash, used for data read by PrepareChunksFromReader
+	New() hash.Hash
+	// Sum returns the SHA-256 digest of data
+	Sum(data []byte) []byte
+}
+
+// StdHasher is the default Hasher, backed by crypto/sha256.
+type StdHasher struct{}
+
+// New returns sha256.New().
+func (StdHasher) New() hash.Hash { return sha256.New() }
+
+// Sum returns sha256.Sum256(data).
+func (StdHasher) Sum(data []byte) []byte {
+	sum := sha256.Sum256(data)
+	return sum[:]
+}
+
+// funcHasher is the Hasher of NewHasher
+type funcHasher func() hash.Hash
+
+func (f funcHasher) New() hash.Hash { return f() }
+
+func (f funcHasher) Sum(data []byte) []byte {
+	h := f()
+	h.Write(data)
+	return h.Sum(nil)
+}
+
+// NewHasher returns a Hasher computing digests with the hash.Hash returned by newHash, which must be SHA-256.
+//
+// Example:
+//
+//	import sha256simd "github.com/minio/sha256-simd"
+//
+//	transaction.SetHasher(transaction.NewHasher(sha256simd.New))
+func NewHasher(newHash func() hash.Hash) Hasher {
+	return funcHasher(newHash)
+}
+
+// hasher is the Hasher of chunking and Merkle trees
+var hasher atomic.Pointer[Hasher]
+
+func init() {
+	SetHasher(nil)
+}
+
+// SetHasher sets the Hasher used to hash chunks and Merkle tree nodes across all goroutines.
+//
+// Hashing chunks dominates the CPU spent preparing large uploads, so
+// services may plug in an accelerated SHA-256 implementation. It must
+// compute the same digests as crypto/sha256, which already uses the SHA
+// instructions of recent amd64 and arm64 CPUs: measure the gain with
+// BenchmarkPrepareChunks before switching. Checkpoints of
+// PrepareChunksFromReader hold the state of the hash and can only be
+// resumed with a Hasher whose hash.Hash implements
+// encoding.BinaryMarshaler in the same format.
+//
+// Parameters:
+//   - h: The Hasher to use, StdHasher if nil
+func SetHasher(h Hasher) {
+	if h == nil {
+		h = StdHasher{}
+	}
+	hasher.Store(&h)
+}
+
+// CurrentHasher returns the Hasher used to hash chunks and Merkle tree nodes.
+func CurrentHasher() Hasher {
+	return *hasher.Load()
+}
+
+// sum returns the SHA-256 digest of data computed by the current Hasher
+func sum(data []byte) []byte {
+	return CurrentHasher().Sum(data)
+}
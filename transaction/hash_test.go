@@ -0,0 +1,83 @@
+package transaction
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"hash"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetHasher(t *testing.T) {
+	t.Cleanup(func() { SetHasher(nil) })
+	data := bytes.Repeat([]byte{1, 2, 3}, 3*MAX_CHUNK_SIZE)
+
+	expected := &Transaction{}
+	require.NoError(t, expected.PrepareChunks(data))
+	assert.IsType(t, StdHasher{}, CurrentHasher())
+
+	t.Run("custom hasher", func(t *testing.T) {
+		var calls atomic.Int64
+		SetHasher(NewHasher(func() hash.Hash {
+			calls.Add(1)
+			return sha256.New()
+		}))
+		defer SetHasher(nil)
+
+		computed := &Transaction{}
+		require.NoError(t, computed.PrepareChunks(data))
+		assert.Equal(t, expected.DataRoot, computed.DataRoot)
+		assert.Positive(t, calls.Load())
+	})
+
+	t.Run("reader", func(t *testing.T) {
+		var calls atomic.Int64
+		SetHasher(NewHasher(func() hash.Hash {
+			calls.Add(1)
+			return sha256.New()
+		}))
+		defer SetHasher(nil)
+
+		computed := &Transaction{}
+		require.NoError(t, computed.PrepareChunksFromReader(bytes.NewReader(data), int64(len(data)), nil))
+		assert.Equal(t, expected.DataRoot, computed.DataRoot)
+		assert.Positive(t, calls.Load())
+	})
+
+	t.Run("reset", func(t *testing.T) {
+		SetHasher(NewHasher(sha256.New))
+		SetHasher(nil)
+		assert.IsType(t, StdHasher{}, CurrentHasher())
+	})
+}
+
+// BenchmarkPrepareChunks measures chunking and Merkle tree hashing of 16 MiB of data per Hasher.
+//
+// Add an accelerated Hasher to the list to compare it with crypto/sha256. Run it with:
+//
+//	go test ./transaction -run '^$' -bench PrepareChunks
+func BenchmarkPrepareChunks(b *testing.B) {
+	b.Cleanup(func() { SetHasher(nil) })
+	data := bytes.Repeat([]byte("goar"), 4<<20)
+
+	hashers := []struct {
+		name   string
+		hasher Hasher
+	}{
+		{"std", StdHasher{}},
+		{"func", NewHasher(sha256.New)},
+	}
+	for _, h := range hashers {
+		b.Run(h.name, func(b *testing.B) {
+			SetHasher(h.hasher)
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				tx := &Transaction{}
+				require.NoError(b, tx.PrepareChunks(data))
+			}
+		})
+	}
+}
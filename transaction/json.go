@@ -0,0 +1,148 @@
+package transaction
+
+import (
+	"encoding/json"
+	"strconv"
+	"unicode/utf8"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/tag"
+)
+
+// transactionFields has the fields of Transaction without its JSON methods
+type transactionFields Transaction
+
+// transactionJSON is the JSON form of a transaction: the gateway fields, and the chunks when they are prepared
+type transactionJSON struct {
+	*transactionFields
+	Chunks []Chunk `json:"chunks,omitempty"` // Chunks of ChunkData, whose proofs are rebuilt when decoding
+}
+
+// MarshalJSON encodes the transaction in the JSON format of gateways.
+//
+// Prepared chunks are kept under "chunks", without their proofs, so a
+// transaction decoded with UnmarshalJSON can be uploaded without chunking
+// its data again; gateways ignore the field. Nil tags are encoded as an
+// empty list.
+func (tx *Transaction) MarshalJSON() ([]byte, error) {
+	fields := transactionFields(*tx)
+	if fields.Tags == nil || *fields.Tags == nil {
+		fields.Tags = &[]tag.Tag{}
+	}
+	v := transactionJSON{transactionFields: &fields}
+	if tx.ChunkData != nil {
+		v.Chunks = tx.ChunkData.Chunks
+	}
+	return json.Marshal(v)
+}
+
+// UnmarshalJSON decodes a transaction encoded by MarshalJSON or served by a gateway.
+//
+// Tags are base64url encoded on the wire. Tags whose names and values are
+// not all base64url, e.g. written by hand or copied from GraphQL results,
+// are taken as plain text and encoded. The chunks stored by MarshalJSON
+// are restored in ChunkData, with their proofs.
+//
+// Returns an error with code goar.ErrDecode if the restored chunks do not
+// match DataRoot.
+func (tx *Transaction) UnmarshalJSON(b []byte) error {
+	var fields transactionFields
+	v := transactionJSON{transactionFields: &fields}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	*tx = Transaction(fields)
+	tx.Tags = normalizeTags(tx.Tags)
+	if len(v.Chunks) > 0 {
+		chunks := v.Chunks
+		// chunkData ends data filling its last chunk with a zero-length chunk, which ChunkData drops
+		if last := chunks[len(chunks)-1]; last.MaxByteRange-last.MinByteRange == MAX_CHUNK_SIZE {
+			chunks = append(chunks, Chunk{DataHash: sum(nil), MinByteRange: last.MaxByteRange, MaxByteRange: last.MaxByteRange})
+		}
+		chunkData, err := chunksToChunkData(chunks)
+		if err != nil {
+			return goar.Wrap(goar.ErrDecode, err)
+		}
+		if chunkData.DataRoot != tx.DataRoot {
+			return goar.Errorf(goar.ErrDecode, "chunks have data root %s, the transaction %s", chunkData.DataRoot, tx.DataRoot)
+		}
+		tx.ChunkData = chunkData
+	}
+	return nil
+}
+
+// Normalize brings a decoded transaction to the form Sign, Verify and the uploader expect.
+//
+// It sets the format to 2 if unset, the quantity, reward and data size to
+// their canonical form, see NormalizeWinston, encodes plain text tags as
+// UnmarshalJSON does, and prepares the chunks of inline data that are not
+// prepared yet. A transaction fetched with client.GetTransactionByID can
+// then be verified, re-chunked and submitted again unchanged.
+//
+// Returns an error with code goar.ErrInvalidInput if an amount is not a
+// whole number or the inline data does not match DataRoot, or
+// goar.ErrDecode if Data is not base64url.
+//
+// Example:
+//
+//	tx, err := c.GetTransactionByID(id)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if err := tx.Normalize(); err != nil {
+//		log.Fatal(err)
+//	}
+//	err = tx.Verify()
+func (tx *Transaction) Normalize() error {
+	if tx.Format == 0 {
+		tx.Format = 2
+	}
+	var err error
+	if tx.Quantity, err = NormalizeWinston(tx.Quantity); err != nil {
+		return err
+	}
+	if tx.Reward, err = NormalizeWinston(tx.Reward); err != nil {
+		return err
+	}
+	tx.Tags = normalizeTags(tx.Tags)
+
+	data, err := crypto.Base64URLDecode(tx.Data)
+	if err != nil {
+		return goar.Wrap(goar.ErrDecode, err)
+	}
+	if tx.DataSize == "" && len(data) > 0 {
+		tx.DataSize = strconv.Itoa(len(data))
+	}
+	dataSize, err := NormalizeWinston(tx.DataSize)
+	if err != nil {
+		return goar.Errorf(goar.ErrInvalidInput, "invalid data size %q", tx.DataSize)
+	}
+	tx.DataSize = dataSize
+	if len(data) > 0 && tx.ChunkData == nil {
+		return tx.PrepareChunksExpecting(data, tx.DataRoot)
+	}
+	return nil
+}
+
+// normalizeTags returns tags base64url encoded, encoding them unless every name and value already is
+func normalizeTags(tags *[]tag.Tag) *[]tag.Tag {
+	if tags == nil || len(*tags) == 0 {
+		return &[]tag.Tag{}
+	}
+	for _, t := range *tags {
+		if !isBase64URL(t.Name, true) || !isBase64URL(t.Value, false) {
+			return tag.ConvertToBase64(tags)
+		}
+	}
+	return tags
+}
+
+// isBase64URL reports whether s is canonical unpadded base64url, decoding to UTF-8 text if text is set
+func isBase64URL(s string, text bool) bool {
+	b, err := crypto.Base64URLDecode(s)
+	if err != nil || crypto.Base64URLEncode(b) != s {
+		return false
+	}
+	return !text || utf8.Valid(b)
+}
@@ -0,0 +1,129 @@
+package transaction
+
+import (
+	"bytes"
+	"crypto/rand"
+	"encoding/json"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTransactionJSON(t *testing.T) {
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+	tags := []tag.Tag{{Name: "Content-Type", Value: "application/octet-stream"}}
+
+	for name, size := range map[string]int{
+		"Small":             100,
+		"Chunked":           3*MAX_CHUNK_SIZE/2 + 1000,
+		"Filled last chunk": 2 * MAX_CHUNK_SIZE,
+		"Halved chunks":     MAX_CHUNK_SIZE + MIN_CHUNK_SIZE/2,
+	} {
+		t.Run(name, func(t *testing.T) {
+			data := make([]byte, size)
+			_, _ = rand.Read(data)
+			tx, err := NewDataTransactionFromReader(bytes.NewReader(data), int64(size), &tags)
+			require.NoError(t, err)
+			tx.Owner = s.Owner()
+			tx.Reward = "1000"
+			require.NoError(t, tx.Sign(s))
+
+			b, err := json.Marshal(tx)
+			require.NoError(t, err)
+			var decoded Transaction
+			require.NoError(t, json.Unmarshal(b, &decoded))
+
+			assert.Equal(t, tx.ChunkData, decoded.ChunkData)
+			assert.Equal(t, tx.Tags, decoded.Tags)
+			assert.NoError(t, decoded.Verify())
+			chunk, err := decoded.ChunkAt(len(decoded.ChunkData.Chunks)-1, bytes.NewReader(data))
+			require.NoError(t, err)
+			result, err := chunk.Result()
+			require.NoError(t, err)
+			expected, err := tx.ChunkAt(len(tx.ChunkData.Chunks)-1, bytes.NewReader(data))
+			require.NoError(t, err)
+			expectedResult, err := expected.Result()
+			require.NoError(t, err)
+			assert.Equal(t, expectedResult, result)
+		})
+	}
+
+	t.Run("Header only", func(t *testing.T) {
+		tx := New(nil, "", "0", nil)
+		b, err := json.Marshal(tx)
+		require.NoError(t, err)
+		assert.Contains(t, string(b), `"tags":[]`)
+		assert.NotContains(t, string(b), `"chunks"`)
+
+		var decoded Transaction
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		assert.Nil(t, decoded.ChunkData)
+	})
+
+	t.Run("Plain tags", func(t *testing.T) {
+		var decoded Transaction
+		require.NoError(t, json.Unmarshal([]byte(`{"format":2,"tags":[{"name":"Content-Type","value":"text/plain"}]}`), &decoded))
+		assert.Equal(t, tag.ConvertToBase64(&[]tag.Tag{{Name: "Content-Type", Value: "text/plain"}}), decoded.Tags)
+
+		encoded := tag.ConvertToBase64(&tags)
+		b, err := json.Marshal(map[string]any{"tags": encoded})
+		require.NoError(t, err)
+		require.NoError(t, json.Unmarshal(b, &decoded))
+		assert.Equal(t, encoded, decoded.Tags)
+	})
+
+	t.Run("Chunks of another data root", func(t *testing.T) {
+		tx := New([]byte("data"), "", "0", nil)
+		require.NoError(t, tx.PrepareChunks([]byte("data")))
+		tx.DataRoot = crypto.Base64URLEncode(make([]byte, 32))
+		b, err := json.Marshal(tx)
+		require.NoError(t, err)
+		var decoded Transaction
+		assert.ErrorIs(t, json.Unmarshal(b, &decoded), goar.ErrDecode)
+	})
+}
+
+func TestNormalize(t *testing.T) {
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+	data := []byte("inline data")
+
+	signed := New(data, "", "0", &[]tag.Tag{{Name: "App-Name", Value: "goar"}})
+	require.NoError(t, signed.PrepareChunks(data))
+	signed.Owner = s.Owner()
+	signed.Reward = "1000"
+	require.NoError(t, signed.Sign(s))
+
+	// A gateway answer without the chunks, with plain tags and padded amounts
+	fetched := &Transaction{
+		ID:        signed.ID,
+		Owner:     signed.Owner,
+		Tags:      &[]tag.Tag{{Name: "App-Name", Value: "goar"}},
+		Quantity:  "",
+		Reward:    "0001000",
+		Data:      signed.Data,
+		DataRoot:  signed.DataRoot,
+		Signature: signed.Signature,
+	}
+	require.NoError(t, fetched.Normalize())
+	assert.Equal(t, 2, fetched.Format)
+	assert.Equal(t, "0", fetched.Quantity)
+	assert.Equal(t, "1000", fetched.Reward)
+	assert.Equal(t, signed.DataSize, fetched.DataSize)
+	assert.Equal(t, signed.Tags, fetched.Tags)
+	assert.Equal(t, signed.ChunkData, fetched.ChunkData)
+	assert.NoError(t, fetched.Verify())
+
+	t.Run("Invalid", func(t *testing.T) {
+		assert.ErrorIs(t, (&Transaction{Reward: "1e12"}).Normalize(), goar.ErrInvalidInput)
+		assert.ErrorIs(t, (&Transaction{DataSize: "-1"}).Normalize(), goar.ErrInvalidInput)
+		assert.ErrorIs(t, (&Transaction{Data: signed.Data, DataRoot: "other"}).Normalize(), goar.ErrInvalidInput)
+		assert.ErrorIs(t, (&Transaction{Data: "!"}).Normalize(), goar.ErrDecode)
+	})
+}
@@ -0,0 +1,117 @@
+package transaction
+
+import (
+	"math/big"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/tag"
+)
+
+// kind is what a transaction was constructed for, checked again by Sign
+type kind int
+
+const (
+	kindAny      kind = iota // Created with New, which allows transfers carrying data
+	kindTransfer             // Created with NewTransfer
+	kindData                 // Created with NewDataTransaction
+)
+
+// NewTransfer creates a transaction sending quantity winston to target, without data.
+//
+// Parameters:
+//   - target: The address receiving the AR
+//   - quantity: The amount to send in winston, greater than 0
+//   - tags: Optional metadata tags. Can be nil.
+//
+// Sign fails if data is added to the transaction afterwards; use New to
+// combine a transfer with data on purpose.
+//
+// Returns an error with code goar.ErrInvalidInput if target is not a valid
+// address or quantity is not a positive integer in the form of ParseWinston.
+//
+// Example:
+//
+//	tx, err := transaction.NewTransfer(target, "1000000000000", nil) // 1 AR
+func NewTransfer(target string, quantity string, tags *[]tag.Tag) (*Transaction, error) {
+	address, err := goar.ParseAddress(target)
+	if err != nil {
+		return nil, err
+	}
+	if address.IsZero() {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "transfer has no target")
+	}
+	q, err := parseQuantity(quantity)
+	if err != nil {
+		return nil, err
+	}
+	if q.Sign() == 0 {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "transfer quantity must be greater than 0")
+	}
+	tx := New(nil, target, quantity, tags)
+	tx.kind = kindTransfer
+	return tx, nil
+}
+
+// NewDataTransaction creates a transaction storing data, without target or quantity.
+//
+// Parameters:
+//   - data: The data to store. Can be nil for a transaction holding only tags.
+//   - tags: Optional metadata tags. Can be nil.
+//
+// Sign fails if a target or quantity is set on the transaction afterwards;
+// use New to combine data with a transfer on purpose.
+//
+// Example:
+//
+//	tags := []tag.Tag{{Name: "Content-Type", Value: "text/plain"}}
+//	tx := transaction.NewDataTransaction([]byte("Hello!"), &tags)
+func NewDataTransaction(data []byte, tags *[]tag.Tag) *Transaction {
+	tx := New(data, "", "0", tags)
+	tx.kind = kindData
+	return tx
+}
+
+// HasData reports whether the transaction carries data, inline or as a data root.
+func (tx *Transaction) HasData() bool {
+	return tx.Data != "" || tx.DataRoot != "" || (tx.DataSize != "" && tx.DataSize != "0")
+}
+
+// IsTransfer reports whether the transaction sends AR, i.e. has a quantity greater than 0.
+func (tx *Transaction) IsTransfer() bool {
+	q, ok := new(big.Int).SetString(tx.Quantity, 10)
+	return ok && q.Sign() > 0
+}
+
+// checkKind returns an error with code goar.ErrInvalidInput if the
+// transaction no longer matches the constructor it was created with
+func (tx *Transaction) checkKind() error {
+	switch tx.kind {
+	case kindTransfer:
+		if tx.HasData() {
+			return goar.Errorf(goar.ErrInvalidInput, "transfer must not carry data")
+		}
+		if !tx.IsTransfer() || tx.Target == "" {
+			return goar.Errorf(goar.ErrInvalidInput, "transfer must have a target and a quantity greater than 0")
+		}
+	case kindData:
+		if tx.Target != "" {
+			return goar.Errorf(goar.ErrInvalidInput, "data transaction must not have a target")
+		}
+		if _, err := parseQuantity(tx.Quantity); err != nil {
+			return err
+		}
+		if tx.IsTransfer() {
+			return goar.Errorf(goar.ErrInvalidInput, "data transaction must have a quantity of 0")
+		}
+	}
+	return nil
+}
+
+// parseQuantity parses a non-negative amount of winston, see ParseWinston
+func parseQuantity(quantity string) (*big.Int, error) {
+	q, err := ParseWinston(quantity)
+	if err != nil {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "invalid quantity %q", quantity)
+	}
+	return q, nil
+}
@@ -0,0 +1,75 @@
+package transaction
+
+import (
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const recipient = "1seRanklLU_1VTGkEk7P0xAwMJfA7owA1JHW5KyZKlY"
+
+func TestNewTransfer(t *testing.T) {
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+
+	tx, err := NewTransfer(recipient, "1000", nil)
+	require.NoError(t, err)
+	assert.True(t, tx.IsTransfer())
+	assert.False(t, tx.HasData())
+	tx.Owner = s.Owner()
+	require.NoError(t, tx.Sign(s))
+	assert.Empty(t, tx.DataRoot)
+	assert.NoError(t, tx.Verify())
+
+	for _, c := range []struct{ target, quantity string }{
+		{"", "1000"},
+		{"not-an-address", "1000"},
+		{recipient, "0"},
+		{recipient, "-5"},
+		{recipient, "1.5"},
+		{recipient, ""},
+	} {
+		_, err := NewTransfer(c.target, c.quantity, nil)
+		assert.ErrorIs(t, err, goar.ErrInvalidInput, "%q %q", c.target, c.quantity)
+	}
+
+	// Data added after construction is rejected
+	tx, err = NewTransfer(recipient, "1000", nil)
+	require.NoError(t, err)
+	require.NoError(t, tx.PrepareChunks([]byte("hybrid")))
+	assert.ErrorIs(t, tx.Sign(s), goar.ErrInvalidInput)
+}
+
+func TestNewDataTransaction(t *testing.T) {
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+
+	tx := NewDataTransaction([]byte("data"), nil)
+	assert.True(t, tx.HasData())
+	assert.False(t, tx.IsTransfer())
+	tx.Owner = s.Owner()
+	require.NoError(t, tx.Sign(s))
+	assert.NotEmpty(t, tx.DataRoot)
+
+	// Transactions holding only tags are allowed
+	tx = NewDataTransaction(nil, nil)
+	assert.False(t, tx.HasData())
+	tx.Owner = s.Owner()
+	require.NoError(t, tx.Sign(s))
+
+	tx = NewDataTransaction([]byte("data"), nil)
+	tx.Quantity = "10"
+	assert.ErrorIs(t, tx.Sign(s), goar.ErrInvalidInput)
+
+	tx = NewDataTransaction([]byte("data"), nil)
+	tx.Target = recipient
+	assert.ErrorIs(t, tx.Sign(s), goar.ErrInvalidInput)
+
+	// New still allows transfers carrying data on purpose
+	tx = New([]byte("data"), recipient, "10", nil)
+	tx.Owner = s.Owner()
+	assert.NoError(t, tx.Sign(s))
+}
@@ -1,6 +1,7 @@
 package transaction
 
 import (
+	"context"
 	"errors"
 	"math"
 	"reflect"
@@ -53,7 +54,7 @@ const (
 //	}
 //	fmt.Printf("Root hash: %x\n", rootNode.ID)
 func generateTree(data []byte) (*Node, error) {
-	chunks, err := chunkData(data)
+	chunks, err := chunkData(context.Background(), data)
 	if err != nil {
 		return nil, err
 	}
@@ -79,6 +80,7 @@ func generateTree(data []byte) (*Node, error) {
 // zero-length chunks that may be generated at the end of the chunking process.
 //
 // Parameters:
+//   - ctx: Checked between chunks, see chunkData
 //   - data: The raw data to be chunked and processed
 //
 // Returns ChunkData containing the data root, chunks, and proofs, or an error
@@ -87,17 +89,27 @@ func generateTree(data []byte) (*Node, error) {
 // Example:
 //
 //	data := []byte("Data to be uploaded to Arweave")
-//	chunkData, err := generateTransactionChunks(data)
+//	chunkData, err := generateTransactionChunks(ctx, data)
 //	if err != nil {
 //		log.Fatal(err)
 //	}
 //	fmt.Printf("Generated %d chunks with root: %s\n",
 //		len(chunkData.Chunks), chunkData.DataRoot)
-func generateTransactionChunks(data []byte) (*ChunkData, error) {
-	chunks, err := chunkData(data)
+func generateTransactionChunks(ctx context.Context, data []byte) (*ChunkData, error) {
+	chunks, err := chunkData(ctx, data)
 	if err != nil {
 		return nil, err
 	}
+	return chunksToChunkData(chunks)
+}
+
+// chunksToChunkData builds the Merkle tree over already hashed chunks and
+// generates the data root and proofs.
+//
+// The chunks must follow chunkData's layout, including the trailing
+// zero-length chunk when the data size is a multiple of MAX_CHUNK_SIZE;
+// that chunk contributes to the root and is discarded afterwards.
+func chunksToChunkData(chunks []Chunk) (*ChunkData, error) {
 	leaves, err := generateLeaves(chunks)
 	if err != nil {
 		return nil, err
@@ -132,15 +144,16 @@ func generateTransactionChunks(data []byte) (*ChunkData, error) {
 //   - Each chunk includes a SHA256 hash and byte range information
 //
 // Parameters:
+//   - ctx: Checked before each chunk is hashed, to abort chunking large data
 //   - data: The raw data to be chunked
 //
 // Returns a slice of Chunk structs containing hash and range information
-// for each chunk, or an error if chunking fails.
+// for each chunk, or ctx.Err() if ctx is done first.
 //
 // Example:
 //
 //	data := []byte("Data to be chunked")
-//	chunks, err := chunkData(data)
+//	chunks, err := chunkData(ctx, data)
 //	if err != nil {
 //		log.Fatal(err)
 //	}
@@ -148,13 +161,16 @@ func generateTransactionChunks(data []byte) (*ChunkData, error) {
 //		fmt.Printf("Chunk %d: bytes %d-%d, hash: %x\n",
 //			i, chunk.MinByteRange, chunk.MaxByteRange, chunk.DataHash)
 //	}
-func chunkData(data []byte) ([]Chunk, error) {
+func chunkData(ctx context.Context, data []byte) ([]Chunk, error) {
 	var chunks []Chunk
 
 	rest := data
 	cursor := 0
 
 	for len(rest) >= MAX_CHUNK_SIZE {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
 		chunkSize := MAX_CHUNK_SIZE
 		byteLength := len(rest)
 
@@ -165,7 +181,7 @@ func chunkData(data []byte) ([]Chunk, error) {
 		}
 
 		chunk := rest[:chunkSize]
-		dataSha := crypto.SHA256(chunk)
+		dataSha := sum(chunk)
 
 		cursor += len(chunk)
 		chunks = append(chunks, Chunk{
@@ -177,7 +193,7 @@ func chunkData(data []byte) ([]Chunk, error) {
 		rest = rest[chunkSize:]
 	}
 
-	hash := crypto.SHA256(rest)
+	hash := sum(rest)
 	chunks = append(chunks, Chunk{
 		DataHash:     hash[:],
 		MinByteRange: cursor,
@@ -210,7 +226,7 @@ func chunkData(data []byte) ([]Chunk, error) {
 func generateLeaves(chunks []Chunk) ([]Node, error) {
 	var leaves []Node
 	for _, chunk := range chunks {
-		ID := crypto.SHA256(append(crypto.SHA256(chunk.DataHash), crypto.SHA256(intToByteArray(chunk.MaxByteRange))...))
+		ID := sum(append(sum(chunk.DataHash), sum(intToByteArray(chunk.MaxByteRange))...))
 		leaves = append(leaves, Node{
 			ID:           ID,
 			DataHash:     chunk.DataHash,
@@ -289,11 +305,11 @@ func hashBranch(left *Node, right *Node) (*Node, error) {
 	if right == nil {
 		return left, nil
 	}
-	ID := crypto.SHA256(
-		append(crypto.SHA256(left.ID),
+	ID := sum(
+		append(sum(left.ID),
 			append(
-				crypto.SHA256(right.ID),
-				crypto.SHA256(intToByteArray(left.MaxByteRange))...,
+				sum(right.ID),
+				sum(intToByteArray(left.MaxByteRange))...,
 			)...,
 		),
 	)
@@ -389,7 +405,7 @@ func validatePath(id []byte, dest int, leftBound int, rightBound int, path []byt
 	if len(path) == HASH_SIZE+NOTE_SIZE {
 		pathData := path[0:HASH_SIZE]
 		endOffsetBuffer := path[len(pathData) : len(pathData)+NOTE_SIZE]
-		h := crypto.SHA256(append(crypto.SHA256(pathData), crypto.SHA256(endOffsetBuffer)...))
+		h := sum(append(sum(pathData), sum(endOffsetBuffer)...))
 		if reflect.DeepEqual(id, h) {
 			return &ValidatePathResult{
 				Offset:     rightBound - 1,
@@ -400,6 +416,9 @@ func validatePath(id []byte, dest int, leftBound int, rightBound int, path []byt
 		}
 		return nil, errors.New("invalid path")
 	}
+	if len(path) < HASH_SIZE*2+NOTE_SIZE {
+		return nil, errors.New("invalid path")
+	}
 	left := path[0:HASH_SIZE]
 	right := path[len(left) : len(left)+HASH_SIZE]
 	offsetBuffer := path[len(left)+len(right) : len(left)+len(right)+NOTE_SIZE]
@@ -407,11 +426,11 @@ func validatePath(id []byte, dest int, leftBound int, rightBound int, path []byt
 	remainder := path[len(left)+len(right)+len(offsetBuffer):]
 
 	var p []byte
-	p = append(p, crypto.SHA256(left)...)
-	p = append(p, crypto.SHA256(right)...)
-	p = append(p, crypto.SHA256(offsetBuffer)...)
+	p = append(p, sum(left)...)
+	p = append(p, sum(right)...)
+	p = append(p, sum(offsetBuffer)...)
 
-	if reflect.DeepEqual(id, crypto.SHA256(p)) {
+	if reflect.DeepEqual(id, sum(p)) {
 		if dest < offset {
 			return validatePath(
 				left,
@@ -432,6 +451,190 @@ func validatePath(id []byte, dest int, leftBound int, rightBound int, path []byt
 	return nil, errors.New("no valid path")
 }
 
+// ValidatePath verifies that proof is the Merkle path of the chunk holding
+// byte offset within data of size bytes whose Merkle root is root.
+//
+// The path is walked as validatePath in arweave-js does with a left bound
+// of 0, but offsets outside the data are rejected rather than clamped. The
+// proof is a data_path as served by /chunk and returned by GenerateProofs,
+// or a tx_path checked against the tx_root of a block. Only the path is
+// checked: the content of the chunk is not, see ValidateChunk for the
+// check gateways perform on uploaded chunks.
+//
+// Parameters:
+//   - root: The raw Merkle root, e.g. the decoded data root of a transaction
+//   - offset: Any byte offset within the chunk, relative to the start of the data
+//   - size: The total size of the data
+//   - proof: The raw Merkle proof
+//
+// Returns ValidatePathResult with the byte range of the chunk, or an error
+// if offset is outside the data or the proof does not lead from root to a
+// leaf holding offset.
+//
+// Example:
+//
+//	result, err := transaction.ValidatePath(dataRoot, offset, dataSize, dataPath)
+//	if err != nil {
+//		log.Printf("Invalid proof: %v", err)
+//	}
+//	fmt.Printf("Chunk of %d bytes at [%d, %d)\n", result.ChunkSize, result.LeftBound, result.RightBound)
+func ValidatePath(root []byte, offset int, size int, proof []byte) (*ValidatePathResult, error) {
+	if offset < 0 || offset >= size {
+		return nil, errors.New("offset is outside of the data")
+	}
+	return validatePath(root, offset, 0, size, proof)
+}
+
+// GenerateProofs returns the Merkle proof of each chunk of data, in order, as PrepareChunks computes them.
+//
+// The Proof of a chunk is its data_path, which ValidatePath and
+// ValidateChunk check against the data root, and its Offset the offset of
+// the last byte of the chunk. Data of zero length has no chunks, so no
+// proofs.
+//
+// Example:
+//
+//	proofs, err := transaction.GenerateProofs(data)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for _, p := range proofs {
+//		fmt.Printf("Chunk ending at %d: %d bytes of proof\n", p.Offset, len(p.Proof))
+//	}
+func GenerateProofs(data []byte) ([]Proof, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+	chunks, err := generateTransactionChunks(context.Background(), data)
+	if err != nil {
+		return nil, err
+	}
+	return chunks.Proofs, nil
+}
+
+// ValidateChunk verifies that chunk is the data found at offset within a dataset
+// of dataSize bytes whose Merkle root is root.
+//
+// Unlike a plain path validation, this also hashes the chunk and checks it
+// against the leaf of the data path, and checks that the chunk length matches
+// the leaf's byte range. This is the full integrity check gateways perform
+// on chunks they receive.
+//
+// Parameters:
+//   - root: The raw data root of the transaction
+//   - offset: Any byte offset within the chunk, relative to the start of the data
+//   - dataSize: The total size of the transaction data
+//   - chunk: The raw chunk data
+//   - dataPath: The raw Merkle proof (data_path) of the chunk
+//
+// Returns ValidatePathResult with the chunk boundaries, or an error if
+// offset is outside the data, as with ValidatePath, or the proof or the
+// chunk is invalid.
+//
+// Example:
+//
+//	result, err := ValidateChunk(dataRoot, 0, dataSize, chunk, dataPath)
+//	if err != nil {
+//		log.Printf("Invalid chunk: %v", err)
+//	}
+func ValidateChunk(root []byte, offset int, dataSize int, chunk []byte, dataPath []byte) (*ValidatePathResult, error) {
+	result, err := ValidatePath(root, offset, dataSize, dataPath)
+	if err != nil {
+		return nil, err
+	}
+	leafHash := dataPath[len(dataPath)-HASH_SIZE-NOTE_SIZE : len(dataPath)-NOTE_SIZE]
+	if !reflect.DeepEqual(sum(chunk), leafHash) {
+		return nil, errors.New("chunk does not match data path")
+	}
+	if len(chunk) != result.ChunkSize {
+		return nil, errors.New("chunk size does not match data path")
+	}
+	return result, nil
+}
+
+// VerifyChunk verifies that chunk is the data found at offset within the
+// dataset whose Merkle root is root.
+//
+// It performs the same checks as ValidateChunk, but does not require the
+// total data size: the chunk's end offset is read from the leaf of the
+// proof, which is itself covered by the root. The offset must fall within
+// the chunk's byte range.
+//
+// Parameters:
+//   - root: The raw data root of the transaction
+//   - offset: Any byte offset within the chunk, relative to the start of the data
+//   - chunk: The raw chunk data
+//   - proof: The raw Merkle proof (data_path) of the chunk
+//
+// Returns nil if the chunk and its proof are valid, or an error describing
+// the first failed check.
+//
+// Example:
+//
+//	if err := VerifyChunk(dataRoot, offset, chunk, dataPath); err != nil {
+//		log.Printf("Invalid chunk: %v", err)
+//	}
+func VerifyChunk(root []byte, offset int, chunk []byte, proof []byte) error {
+	if len(proof) < HASH_SIZE+NOTE_SIZE {
+		return errors.New("invalid path")
+	}
+	end := byteArrayToInt(proof[len(proof)-NOTE_SIZE:])
+	if offset < 0 || offset >= end {
+		return errors.New("offset is outside of the chunk")
+	}
+	result, err := ValidateChunk(root, offset, end, chunk, proof)
+	if err != nil {
+		return err
+	}
+	if offset < result.LeftBound {
+		return errors.New("offset is outside of the chunk")
+	}
+	return nil
+}
+
+// ValidateTxPath verifies that txPath proves dataRoot belongs to the
+// transaction holding byte offset of a block whose tx_root is txRoot.
+//
+// Blocks commit to the data of their transactions with a Merkle tree whose
+// leaves are the data roots of the transactions, each ending at the
+// cumulative size of the data before it. Chunks served by /chunk carry a
+// tx_path proving their transaction's leaf in that tree, so together with
+// the data_path, see ValidateChunk, a chunk is verified against the block
+// header alone.
+//
+// Parameters:
+//   - txRoot: The raw tx_root of the block
+//   - offset: Any byte offset within the transaction's data, relative to the start of the block
+//   - blockSize: The block_size of the block
+//   - dataRoot: The raw data root the leaf must hold
+//   - txPath: The raw Merkle proof (tx_path) of the transaction
+//
+// Returns ValidatePathResult with the boundaries of the transaction's data
+// within the block, or an error if the proof is invalid or its leaf is not
+// dataRoot.
+//
+// Example:
+//
+//	result, err := ValidateTxPath(txRoot, blockOffset, blockSize, dataRoot, txPath)
+//	if err != nil {
+//		log.Printf("Invalid tx_path: %v", err)
+//	}
+//	dataSize := result.RightBound - result.LeftBound
+func ValidateTxPath(txRoot []byte, offset int, blockSize int, dataRoot []byte, txPath []byte) (*ValidatePathResult, error) {
+	result, err := validatePath(txRoot, offset, 0, blockSize, txPath)
+	if err != nil {
+		return nil, err
+	}
+	leaf := txPath[len(txPath)-HASH_SIZE-NOTE_SIZE : len(txPath)-NOTE_SIZE]
+	if !reflect.DeepEqual(leaf, dataRoot) {
+		return nil, errors.New("data root does not match tx path")
+	}
+	if offset < result.LeftBound || offset >= result.RightBound {
+		return nil, errors.New("offset is outside of the transaction")
+	}
+	return result, nil
+}
+
 // flatten is a generic utility function that flattens nested slices into a single slice.
 //
 // This function recursively processes nested slice structures and flattens them
@@ -2,10 +2,12 @@
 package transaction
 
 import (
+	"bytes"
 	"os"
 	"strconv"
 	"testing"
 
+	"github.com/liteseed/goar"
 	"github.com/liteseed/goar/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -17,6 +19,8 @@ const (
 	pathBase64URL = "7EAC9FsACQRwe4oIzu7Mza9KjgWKT4toYxDYGjWrCdp0QgsrYS6AueMJ_rM6ZEGslGqjUekzD3WSe7B5_fwipgAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAACAAAnH6dASdQCigcL43lp0QclqBaSncF4TspuvxoFbn2L18EXpQrP1wkbwdIjSSWQQRt_F31yNvxtc09KkPFtzMKAwAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAABAAAIHiHU9QwOImFzjqSlfxkJJCtSbAox6TbbFhQvlEapSgAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAQAAA" // Expected proof path for first chunk
 	offset        = 262143                                                                                                                                                                                                                                                                                                                                                   // Expected offset for test data
 	dataSize      = 836907                                                                                                                                                                                                                                                                                                                                                   // Expected data size for test data
+
+	oneMBRoot = "o1tTTjbC7hIZN6KbUUYjlkQoDl2k8VXNuBDcGIs52Hc" // Expected root hash for 1MB.bin test file
 )
 
 // TestMerkle verifies comprehensive Merkle tree functionality
@@ -155,3 +159,238 @@ func TestMerkle(t *testing.T) {
 		assert.Error(t, err)
 	})
 }
+
+// TestValidatePath verifies the exported proof API against the arweave-js fixtures of the rebar3 file
+func TestValidatePath(t *testing.T) {
+	data, err := os.ReadFile("../test/rebar3")
+	require.NoError(t, err)
+	require.Len(t, data, dataSize)
+	root, err := crypto.Base64URLDecode(rootBase64URL)
+	require.NoError(t, err)
+	path, err := crypto.Base64URLDecode(pathBase64URL)
+	require.NoError(t, err)
+
+	t.Run("Fixture", func(t *testing.T) {
+		result, err := ValidatePath(root, offset, dataSize, path)
+		require.NoError(t, err)
+		assert.Equal(t, &ValidatePathResult{Offset: 262143, LeftBound: 0, RightBound: 262144, ChunkSize: 262144}, result)
+
+		// Any offset within the chunk resolves to it
+		result, err = ValidatePath(root, 0, dataSize, path)
+		require.NoError(t, err)
+		assert.Equal(t, 262144, result.RightBound)
+	})
+
+	t.Run("GenerateProofs", func(t *testing.T) {
+		proofs, err := GenerateProofs(data)
+		require.NoError(t, err)
+		require.Len(t, proofs, 4)
+		assert.Equal(t, pathBase64URL, crypto.Base64URLEncode(proofs[0].Proof))
+		assert.Equal(t, offset, proofs[0].Offset)
+
+		left := 0
+		for _, p := range proofs {
+			result, err := ValidatePath(root, p.Offset, dataSize, p.Proof)
+			require.NoError(t, err)
+			assert.Equal(t, left, result.LeftBound)
+			assert.Equal(t, p.Offset+1, result.RightBound)
+			left = result.RightBound
+		}
+		assert.Equal(t, dataSize, left)
+
+		proofs, err = GenerateProofs(nil)
+		require.NoError(t, err)
+		assert.Empty(t, proofs)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		_, err := ValidatePath(root, dataSize-1, dataSize, path)
+		assert.Error(t, err)
+		_, err = ValidatePath(root, offset, dataSize, path[:len(path)-1])
+		assert.Error(t, err)
+		_, err = ValidatePath(root[1:], offset, dataSize, path)
+		assert.Error(t, err)
+		_, err = ValidatePath(root, offset, 0, path)
+		assert.Error(t, err)
+		_, err = ValidatePath(root, dataSize, dataSize, path)
+		assert.Error(t, err)
+		_, err = ValidatePath(root, -1, dataSize, path)
+		assert.Error(t, err)
+	})
+}
+
+// TestValidateChunk verifies full chunk integrity checks against the data root
+func TestValidateChunk(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	tx := New(data, "", "", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+
+	root, err := crypto.Base64URLDecode(tx.DataRoot)
+	require.NoError(t, err)
+
+	t.Run("should validate every chunk", func(t *testing.T) {
+		for i, c := range tx.ChunkData.Chunks {
+			proof := tx.ChunkData.Proofs[i]
+			result, err := ValidateChunk(root, c.MinByteRange, len(data), data[c.MinByteRange:c.MaxByteRange], proof.Proof)
+			require.NoError(t, err)
+			assert.Equal(t, c.MinByteRange, result.LeftBound)
+			assert.Equal(t, c.MaxByteRange, result.RightBound)
+		}
+	})
+
+	t.Run("should reject tampered chunk", func(t *testing.T) {
+		c := tx.ChunkData.Chunks[1]
+		chunk := append([]byte{}, data[c.MinByteRange:c.MaxByteRange]...)
+		chunk[0] ^= 0xff
+		_, err := ValidateChunk(root, c.MinByteRange, len(data), chunk, tx.ChunkData.Proofs[1].Proof)
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject chunk with another chunk's proof", func(t *testing.T) {
+		c := tx.ChunkData.Chunks[0]
+		_, err := ValidateChunk(root, c.MinByteRange, len(data), data[c.MinByteRange:c.MaxByteRange], tx.ChunkData.Proofs[1].Proof)
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject truncated proof", func(t *testing.T) {
+		c := tx.ChunkData.Chunks[0]
+		_, err := ValidateChunk(root, c.MinByteRange, len(data), data[c.MinByteRange:c.MaxByteRange], tx.ChunkData.Proofs[0].Proof[:70])
+		assert.Error(t, err)
+	})
+
+	t.Run("should reject offsets outside the data", func(t *testing.T) {
+		last := len(tx.ChunkData.Chunks) - 1
+		c := tx.ChunkData.Chunks[last]
+		proof := tx.ChunkData.Proofs[last].Proof
+		for _, offset := range []int{-1, len(data), len(data) + MAX_CHUNK_SIZE} {
+			_, err := ValidateChunk(root, offset, len(data), data[c.MinByteRange:c.MaxByteRange], proof)
+			assert.ErrorContains(t, err, "offset is outside of the data", offset)
+			_, err = ValidatePath(root, offset, len(data), proof)
+			assert.ErrorContains(t, err, "offset is outside of the data", offset)
+		}
+	})
+}
+
+// TestVerifyChunk verifies chunk content checks without the data size using the 1MB.bin fixture
+func TestVerifyChunk(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	tx := New(data, "", "", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	require.Equal(t, oneMBRoot, tx.DataRoot)
+
+	root, err := crypto.Base64URLDecode(oneMBRoot)
+	require.NoError(t, err)
+
+	// 1MB.bin splits into four full chunks
+	require.Len(t, tx.ChunkData.Chunks, 4)
+	for i, c := range tx.ChunkData.Chunks {
+		assert.Equal(t, i*MAX_CHUNK_SIZE, c.MinByteRange)
+		assert.Equal(t, (i+1)*MAX_CHUNK_SIZE, c.MaxByteRange)
+	}
+
+	t.Run("should verify every chunk at any offset within it", func(t *testing.T) {
+		for i, c := range tx.ChunkData.Chunks {
+			chunk := data[c.MinByteRange:c.MaxByteRange]
+			proof := tx.ChunkData.Proofs[i].Proof
+			assert.NoError(t, VerifyChunk(root, c.MinByteRange, chunk, proof))
+			assert.NoError(t, VerifyChunk(root, c.MaxByteRange-1, chunk, proof))
+		}
+	})
+
+	t.Run("should reject offset outside the chunk", func(t *testing.T) {
+		c := tx.ChunkData.Chunks[1]
+		chunk := data[c.MinByteRange:c.MaxByteRange]
+		proof := tx.ChunkData.Proofs[1].Proof
+		assert.Error(t, VerifyChunk(root, c.MinByteRange-1, chunk, proof))
+		assert.Error(t, VerifyChunk(root, c.MaxByteRange, chunk, proof))
+		assert.Error(t, VerifyChunk(root, -1, chunk, proof))
+	})
+
+	t.Run("should reject tampered chunk", func(t *testing.T) {
+		c := tx.ChunkData.Chunks[2]
+		chunk := append([]byte{}, data[c.MinByteRange:c.MaxByteRange]...)
+		chunk[len(chunk)-1] ^= 0xff
+		assert.Error(t, VerifyChunk(root, c.MinByteRange, chunk, tx.ChunkData.Proofs[2].Proof))
+	})
+
+	t.Run("should reject truncated chunk", func(t *testing.T) {
+		c := tx.ChunkData.Chunks[0]
+		assert.Error(t, VerifyChunk(root, c.MinByteRange, data[c.MinByteRange:c.MaxByteRange-1], tx.ChunkData.Proofs[0].Proof))
+	})
+
+	t.Run("should reject wrong root", func(t *testing.T) {
+		c := tx.ChunkData.Chunks[0]
+		other, err := crypto.Base64URLDecode(rootBase64URL)
+		require.NoError(t, err)
+		assert.Error(t, VerifyChunk(other, c.MinByteRange, data[c.MinByteRange:c.MaxByteRange], tx.ChunkData.Proofs[0].Proof))
+	})
+
+	t.Run("should reject short proof", func(t *testing.T) {
+		assert.Error(t, VerifyChunk(root, 0, data[:MAX_CHUNK_SIZE], []byte{1, 2, 3}))
+	})
+}
+
+// TestPrepareChunksExpecting verifies that a data root mismatch is reported without changing the transaction
+func TestPrepareChunksExpecting(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	tx := New(data, "", "", nil)
+	require.NoError(t, tx.PrepareChunksExpecting(data, oneMBRoot))
+	assert.Equal(t, oneMBRoot, tx.DataRoot)
+
+	other := New(nil, "", "", nil)
+	err = other.PrepareChunksExpecting(data[:len(data)-1], oneMBRoot)
+	assert.ErrorIs(t, err, goar.ErrInvalidInput)
+	assert.Empty(t, other.DataRoot)
+	assert.Nil(t, other.ChunkData)
+
+	streamed := New(nil, "", "", nil)
+	require.NoError(t, streamed.PrepareChunksFromReader(bytes.NewReader(data), int64(len(data)), &PrepareOptions{DataRoot: oneMBRoot}))
+	assert.Equal(t, tx.ChunkData, streamed.ChunkData)
+
+	err = streamed.PrepareChunksFromReader(bytes.NewReader(data[1:]), int64(len(data)-1), &PrepareOptions{DataRoot: oneMBRoot})
+	assert.ErrorIs(t, err, goar.ErrInvalidInput)
+	assert.Equal(t, oneMBRoot, streamed.DataRoot)
+}
+
+// TestValidateTxPath verifies transactions are proven against a block's tx_root
+func TestValidateTxPath(t *testing.T) {
+	root, err := crypto.Base64URLDecode(oneMBRoot)
+	require.NoError(t, err)
+	other, err := crypto.Base64URLDecode(rootBase64URL)
+	require.NoError(t, err)
+
+	// A block holding 1MB.bin between two smaller transactions
+	const size = 4 * MAX_CHUNK_SIZE
+	block, err := chunksToChunkData([]Chunk{
+		{DataHash: other, MinByteRange: 0, MaxByteRange: 100},
+		{DataHash: root, MinByteRange: 100, MaxByteRange: 100 + size},
+		{DataHash: other, MinByteRange: 100 + size, MaxByteRange: 200 + size},
+	})
+	require.NoError(t, err)
+	txRoot, err := crypto.Base64URLDecode(block.DataRoot)
+	require.NoError(t, err)
+	txPath := block.Proofs[1].Proof
+	blockSize := 200 + size
+
+	for _, offset := range []int{100, 100 + MAX_CHUNK_SIZE, 99 + size} {
+		result, err := ValidateTxPath(txRoot, offset, blockSize, root, txPath)
+		require.NoError(t, err)
+		assert.Equal(t, 100, result.LeftBound)
+		assert.Equal(t, 100+size, result.RightBound)
+	}
+
+	_, err = ValidateTxPath(txRoot, 100, blockSize, other, txPath)
+	assert.Error(t, err, "wrong data root")
+	_, err = ValidateTxPath(txRoot, 99, blockSize, root, txPath)
+	assert.Error(t, err, "offset in another transaction")
+	_, err = ValidateTxPath(txRoot, 100+size, blockSize, root, txPath)
+	assert.Error(t, err, "offset in another transaction")
+	_, err = ValidateTxPath(root, 100, blockSize, root, txPath)
+	assert.Error(t, err, "wrong tx root")
+}
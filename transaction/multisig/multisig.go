@@ -0,0 +1,303 @@
+// Package multisig implements experimental N-of-M approval of data items.
+//
+// Arweave has no native multisig: every transaction and data item has a
+// single owner. This package adds an application-level scheme instead. An
+// organization defines a Policy listing the owners allowed to approve and
+// how many approvals are required. Each approver signs the same message,
+// a deep hash of the policy and of the payload's target, anchor, tags and
+// data, which deliberately excludes the submitter's key. Once the threshold
+// is met, the Envelope is wrapped in a data item that any key can sign and
+// upload, and readers verify the approvals with Open.
+//
+// Example usage:
+//
+//	policy := &multisig.Policy{Owners: []string{alice.Owner(), bob.Owner(), carol.Owner()}, Threshold: 2}
+//	env, err := multisig.New(policy, data_item.New(data, "", "", &tags))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	_ = env.Sign(alice)
+//	_ = env.Sign(bob)
+//	item, err := env.DataItem()
+//	if err != nil {
+//		log.Fatal(err) // Threshold not met
+//	}
+//	_ = item.Sign(submitter)
+package multisig
+
+import (
+	"encoding/json"
+	"slices"
+	"strconv"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/canonical"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// VERSION is the version of the envelope format, in the Multisig-Version tag
+const VERSION = "1"
+
+// Tag names of envelope data items
+const (
+	TAG_VERSION = "Multisig-Version"
+	TAG_POLICY  = "Multisig-Policy"
+)
+
+// Policy defines who may approve a payload and how many approvals are required.
+type Policy struct {
+	Owners    []string `json:"owners"`    // Base64url-encoded public keys of the approvers
+	Threshold int      `json:"threshold"` // Number of distinct approvals required
+}
+
+// Signature is the approval of one policy owner.
+type Signature struct {
+	Owner     string `json:"owner"`     // Base64url-encoded public key of the approver
+	Signature string `json:"signature"` // Base64url-encoded RSA-PSS signature of the envelope message
+}
+
+// Envelope holds a payload and the approvals collected for it.
+type Envelope struct {
+	Version    string      `json:"version"`          // Envelope format version
+	Policy     Policy      `json:"policy"`           // Approval policy
+	Target     string      `json:"target,omitempty"` // Base64url-encoded target address of the payload
+	Anchor     string      `json:"anchor,omitempty"` // Anchor of the payload
+	Tags       []tag.Tag   `json:"tags,omitempty"`   // Tags of the payload
+	Data       string      `json:"data"`             // Base64url-encoded data of the payload
+	Signatures []Signature `json:"signatures"`       // Approvals, at most one per owner
+}
+
+// Validate checks that the policy has distinct owners and a threshold between 1 and the number of owners.
+func (p *Policy) Validate() error {
+	if p.Threshold < 1 || p.Threshold > len(p.Owners) {
+		return goar.Errorf(goar.ErrInvalidInput, "threshold %d out of range for %d owners", p.Threshold, len(p.Owners))
+	}
+	for i, owner := range p.Owners {
+		if slices.Contains(p.Owners[:i], owner) {
+			return goar.Errorf(goar.ErrInvalidInput, "duplicate policy owner")
+		}
+		if _, err := crypto.GetPublicKeyFromOwner(owner); err != nil {
+			return goar.Wrap(goar.ErrInvalidInput, err)
+		}
+	}
+	return nil
+}
+
+// ID returns the base64url-encoded identifier of the policy.
+//
+// The ID does not depend on the order of the owners, so that organizations
+// can find their envelopes by the Multisig-Policy tag.
+func (p *Policy) ID() string {
+	owners := slices.Clone(p.Owners)
+	slices.Sort(owners)
+	chunks := []any{[]byte("multisig-policy"), []byte(strconv.Itoa(p.Threshold))}
+	for _, owner := range owners {
+		chunks = append(chunks, []byte(owner))
+	}
+	hash := crypto.DeepHash(chunks)
+	return crypto.Base64URLEncode(crypto.SHA256(hash[:]))
+}
+
+// New creates an envelope without approvals for the payload of an unsigned, in-memory data item.
+//
+// The payload's owner and signature, if any, are ignored.
+//
+// Returns an error with code goar.ErrInvalidInput if the policy is invalid
+// or the data item streams its data.
+func New(policy *Policy, payload *data_item.DataItem) (*Envelope, error) {
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+	if payload.DataReader != nil {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "streaming data items are not supported")
+	}
+	e := &Envelope{
+		Version:    VERSION,
+		Policy:     Policy{Owners: slices.Clone(policy.Owners), Threshold: policy.Threshold},
+		Target:     payload.Target,
+		Anchor:     payload.Anchor,
+		Data:       payload.Data,
+		Signatures: []Signature{},
+	}
+	if payload.Tags != nil {
+		e.Tags = slices.Clone(*payload.Tags)
+	}
+	return e, nil
+}
+
+// Message returns the deep hash every approver signs.
+func (e *Envelope) Message() ([]byte, error) {
+	rawTarget, err := crypto.Base64URLDecode(e.Target)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	rawData, err := crypto.Base64URLDecode(e.Data)
+	if err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	rawTags, err := tag.Serialize(&e.Tags)
+	if err != nil {
+		return nil, err
+	}
+	hash := crypto.DeepHash([]any{
+		[]byte("multisig"),
+		[]byte(e.Version),
+		[]byte(e.Policy.ID()),
+		rawTarget,
+		[]byte(e.Anchor),
+		rawTags,
+		rawData,
+	})
+	return hash[:], nil
+}
+
+// Sign adds the approval of s, replacing a previous approval of the same owner.
+//
+// The signer's Approver is consulted with kind signer.KindMultisig.
+//
+// Returns an error with code goar.ErrInvalidInput if s is not a policy
+// owner, or an error if approval is denied or signing fails.
+func (e *Envelope) Sign(s *signer.Signer) error {
+	owner := s.Owner()
+	if !slices.Contains(e.Policy.Owners, owner) {
+		return goar.Errorf(goar.ErrInvalidInput, "%s is not an owner of policy %s", s.Address, e.Policy.ID())
+	}
+	rawData, err := crypto.Base64URLDecode(e.Data)
+	if err != nil {
+		return goar.Wrap(goar.ErrDecode, err)
+	}
+	err = s.Approve(&signer.SigningRequest{
+		Kind:     signer.KindMultisig,
+		Target:   e.Target,
+		Quantity: "0",
+		Fee:      "0",
+		DataSize: int64(len(rawData)),
+		Tags:     slices.Clone(e.Tags),
+	})
+	if err != nil {
+		return err
+	}
+
+	message, err := e.Message()
+	if err != nil {
+		return err
+	}
+	rawSignature, err := crypto.Sign(message, s.PrivateKey)
+	if err != nil {
+		return err
+	}
+	e.Signatures = slices.DeleteFunc(e.Signatures, func(sig Signature) bool { return sig.Owner == owner })
+	e.Signatures = append(e.Signatures, Signature{Owner: owner, Signature: crypto.Base64URLEncode(rawSignature)})
+	return nil
+}
+
+// Approvals returns the number of valid approvals from distinct policy owners.
+//
+// Signatures from keys outside the policy, duplicates and signatures that
+// do not verify are not counted.
+func (e *Envelope) Approvals() (int, error) {
+	message, err := e.Message()
+	if err != nil {
+		return 0, err
+	}
+	var approved []string
+	for _, sig := range e.Signatures {
+		if !slices.Contains(e.Policy.Owners, sig.Owner) || slices.Contains(approved, sig.Owner) {
+			continue
+		}
+		publicKey, err := crypto.GetPublicKeyFromOwner(sig.Owner)
+		if err != nil {
+			continue
+		}
+		rawSignature, err := crypto.Base64URLDecode(sig.Signature)
+		if err != nil {
+			continue
+		}
+		if crypto.Verify(message, rawSignature, publicKey) == nil {
+			approved = append(approved, sig.Owner)
+		}
+	}
+	return len(approved), nil
+}
+
+// Verify checks that the policy is valid and that the threshold of approvals is met.
+//
+// Returns an error with code goar.ErrInvalidSignature if there are too few valid approvals.
+func (e *Envelope) Verify() error {
+	if e.Version != VERSION {
+		return goar.Errorf(goar.ErrDecode, "unsupported multisig version %q", e.Version)
+	}
+	if err := e.Policy.Validate(); err != nil {
+		return err
+	}
+	approvals, err := e.Approvals()
+	if err != nil {
+		return err
+	}
+	if approvals < e.Policy.Threshold {
+		return goar.Errorf(goar.ErrInvalidSignature, "%d of %d required approvals", approvals, e.Policy.Threshold)
+	}
+	return nil
+}
+
+// Payload returns the approved payload as a new unsigned data item.
+func (e *Envelope) Payload() *data_item.DataItem {
+	rawData, _ := crypto.Base64URLDecode(e.Data)
+	tags := slices.Clone(e.Tags)
+	return data_item.New(rawData, e.Target, e.Anchor, &tags)
+}
+
+// DataItem wraps the envelope in an unsigned data item, ready to be signed by any key and uploaded.
+//
+// The data item holds the envelope as canonical JSON, see package canonical,
+// and is tagged with Content-Type, Multisig-Version and Multisig-Policy.
+//
+// Returns an error if the envelope does not verify.
+func (e *Envelope) DataItem() (*data_item.DataItem, error) {
+	if err := e.Verify(); err != nil {
+		return nil, err
+	}
+	data, err := canonical.Marshal(e)
+	if err != nil {
+		return nil, err
+	}
+	tags := []tag.Tag{
+		{Name: "Content-Type", Value: "application/json"},
+		{Name: TAG_VERSION, Value: e.Version},
+		{Name: TAG_POLICY, Value: e.Policy.ID()},
+	}
+	return data_item.New(data, "", "", &tags), nil
+}
+
+// Open decodes and verifies the envelope held by a data item.
+//
+// Only the approvals are verified; the signature of the data item itself,
+// which only identifies the submitter, is checked with its Verify method.
+//
+// Returns an error with code goar.ErrDecode if the data item is not an
+// envelope, or the error of Verify.
+func Open(item *data_item.DataItem) (*Envelope, error) {
+	if item.Tags == nil || !hasTag(*item.Tags, TAG_VERSION) {
+		return nil, goar.Errorf(goar.ErrDecode, "not a multisig envelope")
+	}
+	var e Envelope
+	if err := json.Unmarshal(item.RawData(), &e); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	if err := e.Verify(); err != nil {
+		return nil, err
+	}
+	return &e, nil
+}
+
+func hasTag(tags []tag.Tag, name string) bool {
+	for _, t := range tags {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
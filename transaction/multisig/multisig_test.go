@@ -0,0 +1,105 @@
+package multisig
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newSigner generates a small key, approvers are not bound to the ANS-104 key size
+func newSigner(t *testing.T) *signer.Signer {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	return signer.FromPrivateKey(key)
+}
+
+func TestEnvelope(t *testing.T) {
+	alice, bob, carol, mallory := newSigner(t), newSigner(t), newSigner(t), newSigner(t)
+	policy := &Policy{Owners: []string{alice.Owner(), bob.Owner(), carol.Owner()}, Threshold: 2}
+	tags := []tag.Tag{{Name: "Content-Type", Value: "text/plain"}}
+
+	newEnvelope := func(t *testing.T) *Envelope {
+		e, err := New(policy, data_item.New([]byte("approved release"), "", "", &tags))
+		require.NoError(t, err)
+		return e
+	}
+
+	t.Run("Threshold", func(t *testing.T) {
+		e := newEnvelope(t)
+		require.NoError(t, e.Sign(alice))
+		assert.ErrorIs(t, e.Verify(), goar.ErrInvalidSignature)
+		_, err := e.DataItem()
+		assert.Error(t, err)
+
+		// Signing twice does not count twice
+		require.NoError(t, e.Sign(alice))
+		assert.Len(t, e.Signatures, 1)
+		assert.ErrorIs(t, e.Verify(), goar.ErrInvalidSignature)
+
+		require.NoError(t, e.Sign(bob))
+		assert.NoError(t, e.Verify())
+	})
+
+	t.Run("Not an owner", func(t *testing.T) {
+		e := newEnvelope(t)
+		assert.ErrorIs(t, e.Sign(mallory), goar.ErrInvalidInput)
+	})
+
+	t.Run("Tampered payload", func(t *testing.T) {
+		e := newEnvelope(t)
+		require.NoError(t, e.Sign(alice))
+		require.NoError(t, e.Sign(carol))
+		e.Tags = []tag.Tag{{Name: "Content-Type", Value: "text/html"}}
+		assert.ErrorIs(t, e.Verify(), goar.ErrInvalidSignature)
+	})
+
+	t.Run("Round trip", func(t *testing.T) {
+		e := newEnvelope(t)
+		require.NoError(t, e.Sign(bob))
+		require.NoError(t, e.Sign(carol))
+
+		item, err := e.DataItem()
+		require.NoError(t, err)
+		// The submitter needs a 4096-bit key like any ANS-104 owner
+		submitter, err := signer.FromPath("../../test/signer.json")
+		require.NoError(t, err)
+		require.NoError(t, item.Sign(submitter))
+		decoded, err := data_item.Decode(item.Raw)
+		require.NoError(t, err)
+		require.NoError(t, decoded.Verify())
+
+		opened, err := Open(decoded)
+		require.NoError(t, err)
+		assert.Equal(t, policy.ID(), opened.Policy.ID())
+		payload := opened.Payload()
+		assert.Equal(t, []byte("approved release"), payload.RawData())
+		assert.Equal(t, tags, *payload.Tags)
+
+		_, err = Open(data_item.New([]byte("{}"), "", "", nil))
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
+
+func TestPolicy(t *testing.T) {
+	a, b := newSigner(t), newSigner(t)
+
+	assert.Equal(t,
+		(&Policy{Owners: []string{a.Owner(), b.Owner()}, Threshold: 1}).ID(),
+		(&Policy{Owners: []string{b.Owner(), a.Owner()}, Threshold: 1}).ID(),
+	)
+	assert.NotEqual(t,
+		(&Policy{Owners: []string{a.Owner(), b.Owner()}, Threshold: 1}).ID(),
+		(&Policy{Owners: []string{a.Owner(), b.Owner()}, Threshold: 2}).ID(),
+	)
+
+	assert.ErrorIs(t, (&Policy{Owners: []string{a.Owner()}, Threshold: 2}).Validate(), goar.ErrInvalidInput)
+	assert.ErrorIs(t, (&Policy{Owners: []string{a.Owner(), a.Owner()}, Threshold: 1}).Validate(), goar.ErrInvalidInput)
+	assert.ErrorIs(t, (&Policy{Owners: []string{"not a key"}, Threshold: 1}).Validate(), goar.ErrInvalidInput)
+}
@@ -0,0 +1,88 @@
+package transaction
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/liteseed/goar"
+)
+
+// ParseWinston parses an amount of winston in the form gateways accept for quantities and rewards.
+//
+// The accepted grammar is "0" or a non-zero decimal digit followed by
+// decimal digits: no sign, leading zeros, spaces, fraction, exponent or
+// digit separators. Gateways reject "1e12" or "0001", so these are reported
+// locally instead; use NormalizeWinston to bring such an amount to the
+// accepted form.
+//
+// Returns an error with code goar.ErrInvalidInput if s is not in the
+// accepted form.
+//
+// Example:
+//
+//	q, err := transaction.ParseWinston("1000000000000") // 1 AR
+func ParseWinston(s string) (*big.Int, error) {
+	if !isWinston(s) {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "invalid winston amount %q", s)
+	}
+	q, _ := new(big.Int).SetString(s, 10)
+	return q, nil
+}
+
+// NormalizeWinston returns amount s in the form accepted by ParseWinston.
+//
+// Surrounding spaces, a leading plus sign and leading zeros are removed,
+// and an empty amount is 0. Amounts that are not whole non-negative
+// numbers, such as "1e12", "1.5" or "-1", are still rejected rather than
+// rounded.
+//
+// Returns an error with code goar.ErrInvalidInput if s is not a whole
+// non-negative decimal number.
+//
+// Example:
+//
+//	q, err := transaction.NormalizeWinston(" 0001000 ") // "1000"
+func NormalizeWinston(s string) (string, error) {
+	trimmed := strings.TrimSpace(s)
+	if trimmed == "" {
+		return "0", nil
+	}
+	digits := strings.TrimPrefix(trimmed, "+")
+	if digits != "" && strings.Trim(digits, "0") == "" {
+		return "0", nil
+	}
+	digits = strings.TrimLeft(digits, "0")
+	if !isWinston(digits) {
+		return "", goar.Errorf(goar.ErrInvalidInput, "invalid winston amount %q", s)
+	}
+	return digits, nil
+}
+
+// checkAmounts returns an error with code goar.ErrInvalidInput if the
+// quantity or reward is set and not in the form accepted by ParseWinston
+func (tx *Transaction) checkAmounts() error {
+	if tx.Quantity != "" {
+		if _, err := ParseWinston(tx.Quantity); err != nil {
+			return goar.Errorf(goar.ErrInvalidInput, "invalid quantity %q", tx.Quantity)
+		}
+	}
+	if tx.Reward != "" {
+		if _, err := ParseWinston(tx.Reward); err != nil {
+			return goar.Errorf(goar.ErrInvalidInput, "invalid reward %q", tx.Reward)
+		}
+	}
+	return nil
+}
+
+// isWinston reports whether s matches the grammar of ParseWinston
+func isWinston(s string) bool {
+	if s == "" || (s[0] == '0' && len(s) > 1) {
+		return false
+	}
+	for i := 0; i < len(s); i++ {
+		if s[i] < '0' || s[i] > '9' {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,105 @@
+package transaction
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseWinston(t *testing.T) {
+	for _, s := range []string{"0", "1", "10", "1000000000000", "123456789012345678901234567890"} {
+		q, err := ParseWinston(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, s, q.String())
+	}
+	for _, s := range []string{"", "00", "0001", "1e12", "1E12", "1.5", "1.0", "-1", "+1", " 1", "1 ", "1_000", "0x10", "１"} {
+		_, err := ParseWinston(s)
+		assert.ErrorIs(t, err, goar.ErrInvalidInput, s)
+	}
+}
+
+func TestNormalizeWinston(t *testing.T) {
+	for s, expected := range map[string]string{
+		"":          "0",
+		" ":         "0",
+		"0":         "0",
+		"000":       "0",
+		"+0":        "0",
+		"0001":      "1",
+		" 0001000 ": "1000",
+		"+42":       "42",
+		"42":        "42",
+	} {
+		normalized, err := NormalizeWinston(s)
+		require.NoError(t, err, s)
+		assert.Equal(t, expected, normalized, s)
+	}
+	for _, s := range []string{"+", "1e12", "1.5", "-1", "--1", "+-1", "1 000", "0x10", "NaN"} {
+		_, err := NormalizeWinston(s)
+		assert.ErrorIs(t, err, goar.ErrInvalidInput, s)
+	}
+}
+
+func TestSignChecksAmounts(t *testing.T) {
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+
+	tx := New([]byte("data"), "", "0", nil)
+	tx.Reward = "1e12"
+	assert.ErrorIs(t, tx.Sign(s), goar.ErrInvalidInput)
+
+	tx.Reward = "1000"
+	tx.Quantity = "0001"
+	assert.ErrorIs(t, tx.Sign(s), goar.ErrInvalidInput)
+
+	tx.Quantity = "0"
+	assert.NoError(t, tx.Sign(s))
+
+	_, err = NewTransfer(s.Address, "01", nil)
+	assert.ErrorIs(t, err, goar.ErrInvalidInput)
+}
+
+func FuzzParseWinston(f *testing.F) {
+	for _, s := range []string{"0", "1", "1000000000000", "0001", "1e12", "-1", "+1", " 1", "1.5", ""} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		q, err := ParseWinston(s)
+		if err != nil {
+			require.ErrorIs(t, err, goar.ErrInvalidInput)
+			return
+		}
+		// Accepted amounts are exactly the canonical decimal form of a non-negative integer
+		assert.Equal(t, s, q.String())
+		assert.GreaterOrEqual(t, q.Sign(), 0)
+	})
+}
+
+func FuzzNormalizeWinston(f *testing.F) {
+	for _, s := range []string{"0", "000", "0001", " 42 ", "+7", "1e12", "-1", "", "+"} {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, s string) {
+		normalized, err := NormalizeWinston(s)
+		if err != nil {
+			require.ErrorIs(t, err, goar.ErrInvalidInput)
+			return
+		}
+		// The normalized amount is accepted and keeps the value of s
+		q, err := ParseWinston(normalized)
+		require.NoError(t, err)
+		expected, ok := new(big.Int).SetString(strings.TrimSpace(s), 10)
+		if !ok {
+			expected = new(big.Int)
+		}
+		assert.Equal(t, expected, q)
+		again, err := NormalizeWinston(normalized)
+		require.NoError(t, err)
+		assert.Equal(t, normalized, again)
+	})
+}
@@ -0,0 +1,73 @@
+package transaction
+
+import (
+	"io"
+	"os"
+
+	"github.com/liteseed/goar/tag"
+)
+
+// NewDataTransactionFromReader creates a data transaction whose data stays in src instead of the Data field.
+//
+// The size bytes of src are hashed once to set DataSize, DataRoot and
+// ChunkData, holding only the chunk hashes in memory. Data is left empty,
+// so the JSON of the transaction is its header alone and posting it does
+// not hold a base64 copy of the data, which is a third larger than the
+// data itself. The chunks are read from DataSource when uploading, so src
+// must stay readable, and unchanged, until the upload completes.
+//
+// Parameters:
+//   - src: The data, e.g. an *os.File
+//   - size: The number of bytes of src to store
+//   - tags: Optional metadata tags. Can be nil.
+//
+// Returns an error if reading src fails.
+//
+// Example:
+//
+//	tx, err := transaction.NewDataTransactionFromReader(bytes.NewReader(data), int64(len(data)), nil)
+func NewDataTransactionFromReader(src io.ReaderAt, size int64, tags *[]tag.Tag) (*Transaction, error) {
+	tx := NewDataTransaction(nil, tags)
+	if err := tx.PrepareChunksFromReader(io.NewSectionReader(src, 0, size), size, nil); err != nil {
+		return nil, err
+	}
+	tx.DataSource = src
+	return tx, nil
+}
+
+// NewDataTransactionFromFile creates a data transaction storing the file at path, see NewDataTransactionFromReader.
+//
+// The file is kept open as DataSource until Close is called.
+//
+// Example:
+//
+//	tx, err := transaction.NewDataTransactionFromFile("dataset.tar", nil)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer tx.Close()
+func NewDataTransactionFromFile(path string, tags *[]tag.Tag) (*Transaction, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	tx, err := NewDataTransactionFromReader(f, info.Size(), tags)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+	return tx, nil
+}
+
+// Close closes DataSource if it is an io.Closer, such as the file opened by NewDataTransactionFromFile.
+func (tx *Transaction) Close() error {
+	if c, ok := tx.DataSource.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
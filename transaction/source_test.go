@@ -0,0 +1,54 @@
+package transaction
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/liteseed/goar/signer"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestNewDataTransactionFromFile verifies data kept in a file matches inline data but is not serialized
+func TestNewDataTransactionFromFile(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	inline := NewDataTransaction(data, nil)
+	require.NoError(t, inline.PrepareChunks(data))
+
+	tx, err := NewDataTransactionFromFile("../test/1MB.bin", nil)
+	require.NoError(t, err)
+	defer tx.Close()
+	assert.Empty(t, tx.Data)
+	assert.Equal(t, inline.DataSize, tx.DataSize)
+	assert.Equal(t, inline.DataRoot, tx.DataRoot)
+	assert.True(t, tx.HasData())
+
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+	tx.Owner = s.Owner()
+	require.NoError(t, tx.Sign(s))
+	assert.NoError(t, tx.Verify())
+	assert.Equal(t, inline.DataRoot, tx.DataRoot)
+
+	b, err := json.Marshal(tx)
+	require.NoError(t, err)
+	assert.Less(t, len(b), 4096)
+	assert.Contains(t, string(b), `"data":""`)
+
+	chunk, err := tx.ChunkAt(3, tx.DataSource)
+	require.NoError(t, err)
+	result, err := chunk.Result()
+	require.NoError(t, err)
+	expected, err := inline.ChunkAt(3, bytes.NewReader(data))
+	require.NoError(t, err)
+	expectedResult, err := expected.Result()
+	require.NoError(t, err)
+	assert.Equal(t, expectedResult, result)
+
+	require.NoError(t, tx.Close())
+	_, err = NewDataTransactionFromFile("../test/missing.bin", nil)
+	assert.ErrorIs(t, err, os.ErrNotExist)
+}
@@ -0,0 +1,256 @@
+package transaction
+
+import (
+	"context"
+	"encoding"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"os"
+)
+
+// DEFAULT_CHECKPOINT_INTERVAL is the number of bytes hashed between two checkpoints (64MB).
+const DEFAULT_CHECKPOINT_INTERVAL = 256 * MAX_CHUNK_SIZE
+
+// ChunkingState is the resumable state of a streaming chunk preparation.
+//
+// It records how far the source has been hashed, the hashes of every
+// completed chunk and the hashing state of the chunk in progress, so that
+// preparation can continue after a restart without rehashing from byte zero.
+type ChunkingState struct {
+	DataSize    int64   `json:"data_size"`              // Total size of the data being chunked
+	Cursor      int64   `json:"cursor"`                 // Number of bytes hashed so far
+	Chunks      []Chunk `json:"chunks"`                 // Completed chunks
+	PartialHash []byte  `json:"partial_hash,omitempty"` // Marshaled SHA-256 state of the chunk in progress
+}
+
+// PrepareOptions configures PrepareChunksFromReader.
+type PrepareOptions struct {
+	CheckpointPath     string // File the chunking state is saved to and resumed from; empty disables checkpoints
+	CheckpointInterval int64  // Bytes hashed between checkpoints; defaults to DEFAULT_CHECKPOINT_INTERVAL
+	DataRoot           string // Expected data root; when set, a different result fails without updating the transaction
+}
+
+// PrepareChunksFromReader computes and stores the chunk data for size bytes read from r.
+//
+// This is the streaming counterpart of PrepareChunks: the data is hashed as it
+// is read and only the chunk hashes are kept in memory. The resulting DataSize,
+// DataRoot and ChunkData are identical to those produced by PrepareChunks. The
+// transaction's Data field is left untouched, so the data must be uploaded
+// from the source as well.
+//
+// When opts.CheckpointPath is set, the chunking state is written to that file
+// every opts.CheckpointInterval bytes and when reading fails. If the file
+// already exists, preparation resumes from the saved cursor: r is seeked to it
+// when it implements io.Seeker, otherwise r must already be positioned there.
+// The checkpoint file is removed once preparation completes.
+//
+// Parameters:
+//   - r: The source of the data
+//   - size: The exact number of bytes to read from r
+//   - opts: Checkpoint options, may be nil
+//
+// Returns an error if reading, checkpointing or chunking fails, otherwise
+// updates the transaction's DataSize, ChunkData, and DataRoot fields.
+//
+// Example:
+//
+//	f, err := os.Open("dataset.tar")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	info, _ := f.Stat()
+//
+//	err = tx.PrepareChunksFromReader(f, info.Size(), &transaction.PrepareOptions{
+//		CheckpointPath: "dataset.tar.chunks",
+//	})
+//	if err != nil {
+//		// Run again with the same options to resume
+//		log.Fatal(err)
+//	}
+func (tx *Transaction) PrepareChunksFromReader(r io.Reader, size int64, opts *PrepareOptions) error {
+	return tx.PrepareChunksFromReaderContext(context.Background(), r, size, opts)
+}
+
+// PrepareChunksFromReaderContext is PrepareChunksFromReader bound to ctx.
+//
+// ctx is checked before each chunk is read. When it is done, the chunking
+// state is checkpointed if opts.CheckpointPath is set, so that a later call
+// resumes where preparation stopped, and ctx.Err() is returned.
+func (tx *Transaction) PrepareChunksFromReaderContext(ctx context.Context, r io.Reader, size int64, opts *PrepareOptions) error {
+	if size < 0 {
+		return errors.New("data size cannot be negative")
+	}
+	if size == 0 {
+		return tx.PrepareChunksContext(ctx, nil)
+	}
+	if opts == nil {
+		opts = &PrepareOptions{}
+	}
+	interval := opts.CheckpointInterval
+	if interval <= 0 {
+		interval = DEFAULT_CHECKPOINT_INTERVAL
+	}
+
+	state := &ChunkingState{DataSize: size, Chunks: []Chunk{}}
+	if opts.CheckpointPath != "" {
+		saved, err := LoadChunkingState(opts.CheckpointPath)
+		if err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		if saved != nil {
+			if saved.DataSize != size {
+				return fmt.Errorf("checkpoint is for %d bytes, not %d", saved.DataSize, size)
+			}
+			state = saved
+			if s, ok := r.(io.Seeker); ok {
+				if _, err := s.Seek(state.Cursor, io.SeekStart); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	h := CurrentHasher().New()
+	if len(state.PartialHash) > 0 {
+		u, ok := h.(encoding.BinaryUnmarshaler)
+		if !ok {
+			return errors.New("hasher cannot resume a checkpoint")
+		}
+		if err := u.UnmarshalBinary(state.PartialHash); err != nil {
+			return err
+		}
+	}
+
+	checkpoint := func() error {
+		if opts.CheckpointPath == "" {
+			return nil
+		}
+		m, ok := h.(encoding.BinaryMarshaler)
+		if !ok {
+			return errors.New("hasher cannot save a checkpoint")
+		}
+		partial, err := m.MarshalBinary()
+		if err != nil {
+			return err
+		}
+		state.PartialHash = partial
+		return state.Save(opts.CheckpointPath)
+	}
+
+	lastCheckpoint := state.Cursor
+	for {
+		start := state.chunkStart()
+		end, final := nextChunkEnd(start, size)
+
+		err := ctx.Err()
+		if err == nil {
+			err = copyToHash(h, r, end-state.Cursor, &state.Cursor)
+		}
+		if err != nil {
+			if cerr := checkpoint(); cerr != nil {
+				return errors.Join(err, cerr)
+			}
+			return err
+		}
+		state.Chunks = append(state.Chunks, Chunk{
+			DataHash:     h.Sum(nil),
+			MinByteRange: int(start),
+			MaxByteRange: int(end),
+		})
+		h.Reset()
+
+		if final {
+			break
+		}
+		if state.Cursor-lastCheckpoint >= interval {
+			if err := checkpoint(); err != nil {
+				return err
+			}
+			lastCheckpoint = state.Cursor
+		}
+	}
+
+	chunks, err := chunksToChunkData(state.Chunks)
+	if err != nil {
+		return err
+	}
+	if opts.CheckpointPath != "" {
+		if err := os.Remove(opts.CheckpointPath); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+	if err := checkDataRoot(opts.DataRoot, chunks.DataRoot); err != nil {
+		return err
+	}
+	tx.DataSize = fmt.Sprint(size)
+	tx.ChunkData = chunks
+	tx.DataRoot = chunks.DataRoot
+	return nil
+}
+
+// LoadChunkingState reads a chunking state saved by PrepareChunksFromReader.
+func LoadChunkingState(path string) (*ChunkingState, error) {
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	state := &ChunkingState{}
+	if err := json.Unmarshal(b, state); err != nil {
+		return nil, err
+	}
+	if state.Cursor < state.chunkStart() || state.Cursor > state.DataSize {
+		return nil, errors.New("invalid checkpoint: cursor out of range")
+	}
+	return state, nil
+}
+
+// Save atomically writes the chunking state to path.
+func (s *ChunkingState) Save(path string) error {
+	b, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// chunkStart returns the offset at which the chunk in progress starts.
+func (s *ChunkingState) chunkStart() int64 {
+	if len(s.Chunks) == 0 {
+		return 0
+	}
+	return int64(s.Chunks[len(s.Chunks)-1].MaxByteRange)
+}
+
+// nextChunkEnd returns the end of the chunk starting at start, following the
+// same rules as chunkData. final is true for the last chunk, which may be
+// empty when size is a multiple of MAX_CHUNK_SIZE.
+func nextChunkEnd(start int64, size int64) (end int64, final bool) {
+	rest := size - start
+	if rest < MAX_CHUNK_SIZE {
+		return size, true
+	}
+	chunkSize := int64(MAX_CHUNK_SIZE)
+	next := rest - MAX_CHUNK_SIZE
+	if next > 0 && next < MIN_CHUNK_SIZE {
+		chunkSize = (rest + 1) / 2
+	}
+	return start + chunkSize, false
+}
+
+// copyToHash copies n bytes from r into h, advancing cursor by the number of bytes copied.
+func copyToHash(h hash.Hash, r io.Reader, n int64, cursor *int64) error {
+	copied, err := io.CopyN(h, r, n)
+	*cursor += copied
+	if errors.Is(err, io.EOF) {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
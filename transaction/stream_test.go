@@ -0,0 +1,136 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// failingReader returns an error once limit bytes have been read.
+type failingReader struct {
+	r     io.Reader
+	limit int
+}
+
+func (f *failingReader) Read(p []byte) (int, error) {
+	if f.limit <= 0 {
+		return 0, errors.New("source interrupted")
+	}
+	if len(p) > f.limit {
+		p = p[:f.limit]
+	}
+	n, err := f.r.Read(p)
+	f.limit -= n
+	return n, err
+}
+
+// cancelingReader cancels a context once limit bytes have been read.
+type cancelingReader struct {
+	r      io.Reader
+	limit  int
+	cancel context.CancelFunc
+}
+
+func (c *cancelingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	if c.limit -= n; c.limit <= 0 {
+		c.cancel()
+	}
+	return n, err
+}
+
+// TestPrepareChunksFromReader verifies that streaming preparation matches PrepareChunks and survives interruptions
+func TestPrepareChunksFromReader(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	expected := New(data, "", "", nil)
+	require.NoError(t, expected.PrepareChunks(data))
+
+	t.Run("Matches PrepareChunks", func(t *testing.T) {
+		sizes := []int{1, MIN_CHUNK_SIZE, MAX_CHUNK_SIZE, 2 * MAX_CHUNK_SIZE, MAX_CHUNK_SIZE + MIN_CHUNK_SIZE - 1, len(data)}
+		for _, size := range sizes {
+			want := New(nil, "", "", nil)
+			require.NoError(t, want.PrepareChunks(data[:size]))
+
+			tx := New(nil, "", "", nil)
+			require.NoError(t, tx.PrepareChunksFromReader(bytes.NewReader(data[:size]), int64(size), nil))
+			assert.Equal(t, want.DataRoot, tx.DataRoot, size)
+			assert.Equal(t, want.DataSize, tx.DataSize, size)
+			assert.Equal(t, want.ChunkData, tx.ChunkData, size)
+		}
+	})
+
+	t.Run("Resume after interruption", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		opts := &PrepareOptions{CheckpointPath: path, CheckpointInterval: MAX_CHUNK_SIZE}
+
+		// Fail in the middle of the third chunk
+		tx := New(nil, "", "", nil)
+		err := tx.PrepareChunksFromReader(&failingReader{r: bytes.NewReader(data), limit: 2*MAX_CHUNK_SIZE + 1000}, int64(len(data)), opts)
+		require.Error(t, err)
+
+		state, err := LoadChunkingState(path)
+		require.NoError(t, err)
+		assert.Equal(t, int64(2*MAX_CHUNK_SIZE+1000), state.Cursor)
+		assert.Len(t, state.Chunks, 2)
+		assert.NotEmpty(t, state.PartialHash)
+
+		// The reader is seeked to the cursor before hashing resumes
+		require.NoError(t, tx.PrepareChunksFromReader(bytes.NewReader(data), int64(len(data)), opts))
+		assert.Equal(t, expected.DataRoot, tx.DataRoot)
+		assert.Equal(t, expected.ChunkData, tx.ChunkData)
+
+		_, err = os.Stat(path)
+		assert.True(t, errors.Is(err, os.ErrNotExist))
+	})
+
+	t.Run("Size mismatch", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		require.NoError(t, (&ChunkingState{DataSize: 10}).Save(path))
+
+		tx := New(nil, "", "", nil)
+		err := tx.PrepareChunksFromReader(bytes.NewReader(data), int64(len(data)), &PrepareOptions{CheckpointPath: path})
+		assert.Error(t, err)
+	})
+
+	t.Run("Short source", func(t *testing.T) {
+		tx := New(nil, "", "", nil)
+		err := tx.PrepareChunksFromReader(bytes.NewReader(data[:100]), 200, nil)
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	})
+	t.Run("Cancel", func(t *testing.T) {
+		path := filepath.Join(t.TempDir(), "state.json")
+		opts := &PrepareOptions{CheckpointPath: path}
+		ctx, cancel := context.WithCancel(context.Background())
+		defer cancel()
+
+		tx := New(nil, "", "", nil)
+		err := tx.PrepareChunksFromReaderContext(ctx, &cancelingReader{r: bytes.NewReader(data), limit: MAX_CHUNK_SIZE, cancel: cancel}, int64(len(data)), opts)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, tx.DataRoot)
+
+		state, err := LoadChunkingState(path)
+		require.NoError(t, err)
+		assert.Len(t, state.Chunks, 1)
+
+		require.NoError(t, tx.PrepareChunksFromReaderContext(context.Background(), bytes.NewReader(data), int64(len(data)), opts))
+		assert.Equal(t, expected.DataRoot, tx.DataRoot)
+	})
+
+	t.Run("Cancel in memory", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		tx := New(nil, "", "", nil)
+		assert.ErrorIs(t, tx.PrepareChunksContext(ctx, data), context.Canceled)
+		assert.Nil(t, tx.ChunkData)
+		assert.NoError(t, tx.PrepareChunksContext(ctx, data[:100]), "a single chunk is hashed without checking ctx")
+	})
+}
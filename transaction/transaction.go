@@ -18,7 +18,9 @@ package transaction
 
 import (
 	"errors"
+	"strconv"
 
+	"github.com/liteseed/goar"
 	"github.com/liteseed/goar/crypto"
 	"github.com/liteseed/goar/signer"
 	"github.com/liteseed/goar/tag"
@@ -74,7 +76,10 @@ func New(data []byte, target string, quantity string, tags *[]tag.Tag) *Transact
 // Parameters:
 //   - s: A signer containing the private key to sign with
 //
-// Returns an error if signing fails or if the transaction format is unsupported.
+// Returns an error with code goar.ErrInvalidInput if Target is not a valid
+// address, Quantity or Reward is set but not in the form of ParseWinston,
+// or the transaction no longer matches NewTransfer or NewDataTransaction,
+// or an error if signing fails or the transaction format is unsupported.
 //
 // Example:
 //
@@ -86,10 +91,22 @@ func New(data []byte, target string, quantity string, tags *[]tag.Tag) *Transact
 //	}
 //	fmt.Printf("Transaction signed with ID: %s", tx.ID)
 func (tx *Transaction) Sign(s *signer.Signer) error {
+	if _, err := tx.TargetAddress(); err != nil {
+		return err
+	}
+	if err := tx.checkAmounts(); err != nil {
+		return err
+	}
+	if err := tx.checkKind(); err != nil {
+		return err
+	}
 	payload, err := tx.getSignatureData()
 	if err != nil {
 		return err
 	}
+	if err = s.Approve(tx.signingRequest()); err != nil {
+		return err
+	}
 	rawSignature, err := crypto.Sign(payload, s.PrivateKey)
 	if err != nil {
 		return err
@@ -99,6 +116,61 @@ func (tx *Transaction) Sign(s *signer.Signer) error {
 	return nil
 }
 
+// TargetAddress returns the target as a typed address, the zero Address for transactions without a target.
+//
+// Returns an error with code goar.ErrInvalidInput if Target is not a valid address.
+func (tx *Transaction) TargetAddress() (goar.Address, error) {
+	return goar.ParseAddress(tx.Target)
+}
+
+// SetTarget sets the target from a typed address, clearing it for the zero Address.
+func (tx *Transaction) SetTarget(a goar.Address) {
+	tx.Target = a.String()
+}
+
+// TagList returns the tags with base64url-decoded names and values and lookup helpers, e.g. tags.Get("Content-Type").
+//
+// Returns an error with code goar.ErrDecode if a name or value is not valid base64url.
+func (tx *Transaction) TagList() (tag.Tags, error) {
+	if tx.Tags == nil {
+		return nil, nil
+	}
+	tags := make(tag.Tags, 0, len(*tx.Tags))
+	for _, t := range *tx.Tags {
+		name, err := crypto.Base64URLDecode(t.Name)
+		if err != nil {
+			return nil, goar.Wrap(goar.ErrDecode, err)
+		}
+		value, err := crypto.Base64URLDecode(t.Value)
+		if err != nil {
+			return nil, goar.Wrap(goar.ErrDecode, err)
+		}
+		tags = append(tags, tag.Tag{Name: string(name), Value: string(value)})
+	}
+	return tags, nil
+}
+
+// signingRequest summarizes the transaction for the signer's approval hook.
+func (tx *Transaction) signingRequest() *signer.SigningRequest {
+	dataSize, _ := strconv.ParseInt(tx.DataSize, 10, 64)
+	tags := []tag.Tag{}
+	if tx.Tags != nil {
+		for _, t := range *tx.Tags {
+			name, _ := crypto.Base64URLDecode(t.Name)
+			value, _ := crypto.Base64URLDecode(t.Value)
+			tags = append(tags, tag.Tag{Name: string(name), Value: string(value)})
+		}
+	}
+	return &signer.SigningRequest{
+		Kind:     signer.KindTransaction,
+		Target:   tx.Target,
+		Quantity: tx.Quantity,
+		Fee:      tx.Reward,
+		DataSize: dataSize,
+		Tags:     tags,
+	}
+}
+
 // Verify verifies the transaction signature against the transaction data.
 //
 // This method:
@@ -124,13 +196,13 @@ func (tx *Transaction) Verify() error {
 	}
 	rawSignature, err := crypto.Base64URLDecode(tx.Signature)
 	if err != nil {
-		return err
+		return goar.Wrap(goar.ErrDecode, err)
 	}
 	publicKey, err := crypto.GetPublicKeyFromOwner(tx.Owner)
 	if err != nil {
-		return err
+		return goar.Wrap(goar.ErrDecode, err)
 	}
-	return crypto.Verify(signatureData, rawSignature, publicKey)
+	return goar.Wrap(goar.ErrInvalidSignature, crypto.Verify(signatureData, rawSignature, publicKey))
 }
 
 // getSignatureData generates the data that should be signed for this transaction.
@@ -180,9 +252,12 @@ func (tx *Transaction) getSignatureData() ([]byte, error) {
 		return nil, err
 	}
 
-	err = tx.PrepareChunks(data)
-	if err != nil {
-		return nil, err
+	// Keep chunks prepared from a reader, which leaves Data empty
+	if len(data) > 0 || tx.ChunkData == nil || len(tx.ChunkData.Chunks) == 0 {
+		err = tx.PrepareChunks(data)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	rawDataRoot, err := crypto.Base64URLDecode(tx.DataRoot)
@@ -2,8 +2,10 @@
 package transaction
 
 import (
+	"errors"
 	"testing"
 
+	"github.com/liteseed/goar"
 	"github.com/liteseed/goar/signer"
 	"github.com/liteseed/goar/tag"
 	"github.com/stretchr/testify/assert"
@@ -67,6 +69,38 @@ func TestSign(t *testing.T) {
 		err = tx.Verify()
 		assert.NoError(t, err)
 	})
+
+	t.Run("Sign with approval hook", func(t *testing.T) {
+		tags := &[]tag.Tag{{Name: "Content-Type", Value: "text/plain"}}
+		tx := New(data, "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek", "5", tags)
+		tx.Owner = s.Owner()
+		tx.LastTx = "lqsw6xgaaunfs8h3d6n54ci1lgm2tmtqvz3wke9v9ygq64q8s68yz2jfq5xy4nec"
+		tx.Reward = "1000"
+
+		approver, err := signer.FromPath("../test/signer.json")
+		require.NoError(t, err)
+
+		var req *signer.SigningRequest
+		approver.Approver = signer.ApproverFunc(func(r *signer.SigningRequest) error {
+			req = r
+			return errors.New("denied")
+		})
+		err = tx.Sign(approver)
+		assert.ErrorIs(t, err, goar.ErrSigningDenied)
+		assert.Empty(t, tx.Signature)
+
+		require.NotNil(t, req)
+		assert.Equal(t, signer.KindTransaction, req.Kind)
+		assert.Equal(t, "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek", req.Target)
+		assert.Equal(t, "5", req.Quantity)
+		assert.Equal(t, "1000", req.Fee)
+		assert.Equal(t, int64(len(data)), req.DataSize)
+		assert.Equal(t, []tag.Tag{{Name: "Content-Type", Value: "text/plain"}}, req.Tags)
+
+		approver.Approver = signer.ApproverFunc(func(*signer.SigningRequest) error { return nil })
+		require.NoError(t, tx.Sign(approver))
+		assert.NoError(t, tx.Verify())
+	})
 }
 
 // TestNew verifies transaction creation with various parameters
@@ -106,3 +140,40 @@ func TestNew(t *testing.T) {
 		// Note: New() converts tags to base64url format, so we can't directly compare
 	})
 }
+
+// TestTargetAddress verifies that invalid targets are rejected before signing
+func TestTargetAddress(t *testing.T) {
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+
+	tx := New(nil, "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek", "1000", nil)
+	target, err := tx.TargetAddress()
+	require.NoError(t, err)
+	tx.SetTarget(target)
+	assert.Equal(t, "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek", tx.Target)
+
+	tx = New(nil, "target", "1000", nil)
+	tx.Owner = s.Owner()
+	assert.ErrorIs(t, tx.Sign(s), goar.ErrInvalidInput)
+	assert.Empty(t, tx.Signature)
+}
+
+func TestTagList(t *testing.T) {
+	tags := []tag.Tag{{Name: "Content-Type", Value: "text/plain"}, {Name: "App-Name", Value: "MyApp"}}
+	tx := New([]byte("data"), "", "0", &tags)
+
+	list, err := tx.TagList()
+	require.NoError(t, err)
+	assert.Equal(t, tag.Tags(tags), list)
+	assert.Equal(t, "text/plain", list.Get("Content-Type"))
+	assert.True(t, list.Has("App-Name"))
+
+	tx.Tags = &[]tag.Tag{{Name: "!", Value: "!"}}
+	_, err = tx.TagList()
+	assert.ErrorIs(t, err, goar.ErrDecode)
+
+	tx.Tags = nil
+	list, err = tx.TagList()
+	require.NoError(t, err)
+	assert.False(t, list.Has("Content-Type"))
+}
@@ -1,10 +1,12 @@
 package transaction
 
 import (
-	"errors"
+	"bytes"
+	"context"
 	"fmt"
+	"io"
 
-	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar"
 	"github.com/liteseed/goar/tag"
 )
 
@@ -23,7 +25,7 @@ type Chunk struct {
 // Proofs allow verification that a chunk belongs to the larger dataset
 // without requiring the entire dataset.
 type Proof struct {
-	Offset int    `json:"offset"` // Byte offset where this chunk starts in the overall data
+	Offset int    `json:"offset"` // Offset of the last byte of the chunk in the overall data
 	Proof  []byte `json:"proof"`  // Merkle proof bytes for verification
 }
 
@@ -74,7 +76,10 @@ type Transaction struct {
 	DataSize  string     `json:"data_size"` // Size of the data in bytes
 	DataRoot  string     `json:"data_root"` // Merkle root hash of the data chunks
 
-	ChunkData *ChunkData `json:"-"` // Chunk data for large transactions (not serialized)
+	ChunkData  *ChunkData  `json:"-"` // Chunk data for large transactions (not serialized)
+	DataSource io.ReaderAt `json:"-"` // Data kept outside the transaction, see NewDataTransactionFromReader (not serialized)
+
+	kind kind // Constructor the transaction was created with, see NewTransfer and NewDataTransaction
 }
 
 // TransactionOffset represents the offset information for a transaction.
@@ -134,20 +139,15 @@ type GetChunkResult struct {
 //		log.Fatal(err)
 //	}
 //	fmt.Printf("Chunk offset: %s, size: %d bytes\n", chunk.Offset, len(chunk.Chunk))
+//
+// Deprecated: Use ChunkAt or Chunks, which read chunks from any io.ReaderAt
+// such as a file instead of requiring all the data in memory.
 func (tx *Transaction) GetChunk(i int, data []byte) (*GetChunkResult, error) {
-	if tx.ChunkData == nil {
-		return nil, errors.New("chunks have not been prepared")
+	chunk, err := tx.ChunkAt(i, bytes.NewReader(data))
+	if err != nil {
+		return nil, err
 	}
-	proof := tx.ChunkData.Proofs[i]
-	chunk := tx.ChunkData.Chunks[i]
-
-	return &GetChunkResult{
-		DataRoot: tx.DataRoot,
-		DataSize: tx.DataSize,
-		DataPath: crypto.Base64URLEncode(proof.Proof),
-		Offset:   fmt.Sprint(proof.Offset),
-		Chunk:    crypto.Base64URLEncode(data[chunk.MinByteRange:chunk.MaxByteRange]),
-	}, nil
+	return chunk.Result()
 }
 
 // PrepareChunks computes and stores the chunk data for the given data.
@@ -176,8 +176,16 @@ func (tx *Transaction) GetChunk(i int, data []byte) (*GetChunkResult, error) {
 //	}
 //	fmt.Printf("Data chunked into %d chunks\n", len(tx.ChunkData.Chunks))
 func (tx *Transaction) PrepareChunks(data []byte) error {
+	return tx.PrepareChunksContext(context.Background(), data)
+}
+
+// PrepareChunksContext is PrepareChunks bound to ctx.
+//
+// ctx is checked before each chunk is hashed, so that chunking large data
+// can be aborted; the transaction is left unchanged and ctx.Err() returned.
+func (tx *Transaction) PrepareChunksContext(ctx context.Context, data []byte) error {
 	if len(data) > 0 {
-		chunks, err := generateTransactionChunks(data)
+		chunks, err := generateTransactionChunks(ctx, data)
 		if err != nil {
 			return err
 		}
@@ -194,3 +202,45 @@ func (tx *Transaction) PrepareChunks(data []byte) error {
 	}
 	return nil
 }
+
+// PrepareChunksExpecting computes the chunk data like PrepareChunks and checks it against a known data root.
+//
+// Use it when re-uploading data that was previously chunked, possibly by
+// another tool: a data root that differs from the original means the chunks
+// would not match the transaction already on the network, so the mismatch is
+// reported before anything is signed or uploaded.
+//
+// Parameters:
+//   - data: The raw data to be chunked
+//   - dataRoot: The expected base64url-encoded data root
+//
+// Returns an error with code goar.ErrInvalidInput if the data root differs,
+// in which case the transaction is left unchanged, or an error if chunking fails.
+//
+// Example:
+//
+//	original, _ := client.GetTransactionByID(id)
+//	if err := tx.PrepareChunksExpecting(data, original.DataRoot); err != nil {
+//		log.Fatal(err)
+//	}
+func (tx *Transaction) PrepareChunksExpecting(data []byte, dataRoot string) error {
+	prepared := &Transaction{DataSize: tx.DataSize}
+	if err := prepared.PrepareChunks(data); err != nil {
+		return err
+	}
+	if err := checkDataRoot(dataRoot, prepared.DataRoot); err != nil {
+		return err
+	}
+	tx.DataSize = prepared.DataSize
+	tx.ChunkData = prepared.ChunkData
+	tx.DataRoot = prepared.DataRoot
+	return nil
+}
+
+// checkDataRoot returns an error if an expected data root is set and differs from the computed one
+func checkDataRoot(expected string, actual string) error {
+	if expected == "" || expected == actual {
+		return nil
+	}
+	return goar.Errorf(goar.ErrInvalidInput, "data root mismatch: expected %s, computed %s", expected, actual)
+}
@@ -0,0 +1,105 @@
+package transaction
+
+import (
+	"context"
+	"errors"
+	"io"
+	"strconv"
+
+	"github.com/liteseed/goar"
+)
+
+// VerifyData checks that data is the data the transaction commits to, e.g. after downloading it.
+//
+// The Merkle tree of data is rebuilt as PrepareChunks does and its root
+// compared with DataRoot, and its size with DataSize. As both fields are
+// signed, data that passes belongs to the transaction once its signature
+// is verified. The transaction is left unchanged.
+//
+// Returns an error with code goar.ErrInvalidSignature if data does not
+// match, or goar.ErrDecode if DataSize is not a number.
+//
+// Example:
+//
+//	tx, err := c.GetTransactionByID(id)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	data, err := c.GetTransactionData(id)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	if err := tx.VerifyData(data); err != nil {
+//		log.Fatal(err)
+//	}
+func (tx *Transaction) VerifyData(data []byte) error {
+	return tx.VerifyDataContext(context.Background(), data)
+}
+
+// VerifyDataContext is VerifyData bound to ctx, see PrepareChunksContext.
+func (tx *Transaction) VerifyDataContext(ctx context.Context, data []byte) error {
+	size, err := tx.dataSize()
+	if err != nil {
+		return err
+	}
+	if int64(len(data)) != size {
+		return goar.Errorf(goar.ErrInvalidSignature, "data is %d bytes, the transaction has %d", len(data), size)
+	}
+	computed := &Transaction{}
+	if err := computed.PrepareChunksContext(ctx, data); err != nil {
+		return err
+	}
+	return tx.checkComputedRoot(computed.DataRoot)
+}
+
+// VerifyDataReader is VerifyData for data read from r, of which only the chunk hashes are held in memory.
+//
+// r must hold exactly DataSize bytes. Data shorter or longer than that
+// does not match; other read errors are returned as they are.
+func (tx *Transaction) VerifyDataReader(r io.Reader) error {
+	return tx.VerifyDataReaderContext(context.Background(), r)
+}
+
+// VerifyDataReaderContext is VerifyDataReader bound to ctx, see PrepareChunksFromReaderContext.
+func (tx *Transaction) VerifyDataReaderContext(ctx context.Context, r io.Reader) error {
+	size, err := tx.dataSize()
+	if err != nil {
+		return err
+	}
+	computed := &Transaction{}
+	err = computed.PrepareChunksFromReaderContext(ctx, r, size, nil)
+	if errors.Is(err, io.ErrUnexpectedEOF) {
+		return goar.Errorf(goar.ErrInvalidSignature, "data is shorter than the %d bytes of the transaction", size)
+	}
+	if err != nil {
+		return err
+	}
+	n, err := r.Read(make([]byte, 1))
+	if n > 0 {
+		return goar.Errorf(goar.ErrInvalidSignature, "data is longer than the %d bytes of the transaction", size)
+	}
+	if err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return tx.checkComputedRoot(computed.DataRoot)
+}
+
+// dataSize returns DataSize as a number, or an error with code goar.ErrDecode
+func (tx *Transaction) dataSize() (int64, error) {
+	if tx.DataSize == "" {
+		return 0, nil
+	}
+	size, err := strconv.ParseInt(tx.DataSize, 10, 64)
+	if err != nil || size < 0 {
+		return 0, goar.Errorf(goar.ErrDecode, "invalid data size %q", tx.DataSize)
+	}
+	return size, nil
+}
+
+// checkComputedRoot returns an error with code goar.ErrInvalidSignature unless dataRoot is DataRoot
+func (tx *Transaction) checkComputedRoot(dataRoot string) error {
+	if dataRoot != tx.DataRoot {
+		return goar.Errorf(goar.ErrInvalidSignature, "data root mismatch: the transaction has %s, computed %s", tx.DataRoot, dataRoot)
+	}
+	return nil
+}
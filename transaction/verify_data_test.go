@@ -0,0 +1,61 @@
+package transaction
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVerifyData(t *testing.T) {
+	data := make([]byte, 2*MAX_CHUNK_SIZE+1000)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	tx := New(data, "", "0", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	dataRoot := tx.DataRoot
+
+	altered := bytes.Clone(data)
+	altered[MAX_CHUNK_SIZE+1] ^= 1
+
+	t.Run("Match", func(t *testing.T) {
+		assert.NoError(t, tx.VerifyData(data))
+		assert.NoError(t, tx.VerifyDataReader(bytes.NewReader(data)))
+		assert.Equal(t, dataRoot, tx.DataRoot)
+
+		empty := New(nil, "", "0", nil)
+		require.NoError(t, empty.PrepareChunks(nil))
+		assert.NoError(t, empty.VerifyData(nil))
+		assert.NoError(t, empty.VerifyDataReader(bytes.NewReader(nil)))
+	})
+
+	t.Run("Mismatch", func(t *testing.T) {
+		for name, d := range map[string][]byte{
+			"Altered": altered,
+			"Shorter": data[:len(data)-1],
+			"Longer":  append(bytes.Clone(data), 0),
+		} {
+			assert.ErrorIs(t, tx.VerifyData(d), goar.ErrInvalidSignature, name)
+			assert.ErrorIs(t, tx.VerifyDataReader(bytes.NewReader(d)), goar.ErrInvalidSignature, name)
+		}
+		assert.Equal(t, dataRoot, tx.DataRoot)
+	})
+
+	t.Run("Errors", func(t *testing.T) {
+		err := tx.VerifyDataReader(&failingReader{r: bytes.NewReader(data), limit: 1000})
+		assert.EqualError(t, err, "source interrupted")
+
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		assert.ErrorIs(t, tx.VerifyDataContext(ctx, data), context.Canceled)
+		assert.ErrorIs(t, tx.VerifyDataReaderContext(ctx, bytes.NewReader(data)), context.Canceled)
+
+		invalid := *tx
+		invalid.DataSize = "many"
+		assert.ErrorIs(t, invalid.VerifyData(data), goar.ErrDecode)
+	})
+}
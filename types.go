@@ -0,0 +1,154 @@
+package goar
+
+import (
+	"fmt"
+
+	"github.com/liteseed/goar/crypto"
+)
+
+// Address is a 32-byte Arweave address: the SHA-256 hash of an owner's
+// public key, as used in transaction and data item targets.
+//
+// The zero Address stands for no target. Address marshals to and from
+// its base64url text form, so it can be used in JSON documents.
+type Address [32]byte
+
+// Anchor is the 32-byte anchor of an ANS-104 data item.
+//
+// Data items store the anchor as raw bytes, unlike the base64url last_tx
+// of layer 1 transactions. The zero Anchor stands for no anchor. Anchor
+// marshals to and from base64url text.
+type Anchor [32]byte
+
+// ParseAddress decodes a base64url address.
+//
+// Returns the zero Address for an empty string, or an error with code
+// ErrInvalidInput if s is not the unpadded base64url encoding of 32 bytes.
+//
+// Example:
+//
+//	target, err := goar.ParseAddress("1seRanklLU_1VTGkEk7P0xAwMJfA7owA1JHW5KyZKlY")
+func ParseAddress(s string) (Address, error) {
+	var a Address
+	if s == "" {
+		return a, nil
+	}
+	if err := decode32(a[:], s); err != nil {
+		return a, Errorf(ErrInvalidInput, "invalid address %q: %v", s, err)
+	}
+	return a, nil
+}
+
+// String returns the base64url form of the address, or an empty string for the zero Address.
+func (a Address) String() string {
+	if a.IsZero() {
+		return ""
+	}
+	return crypto.Base64URLEncode(a[:])
+}
+
+// IsZero reports whether a is the zero Address.
+func (a Address) IsZero() bool {
+	return a == Address{}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a Address) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *Address) UnmarshalText(text []byte) error {
+	parsed, err := ParseAddress(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// NewAnchor creates an anchor from raw bytes.
+//
+// Returns the zero Anchor for empty input, or an error with code
+// ErrInvalidInput if b is not exactly 32 bytes long.
+func NewAnchor(b []byte) (Anchor, error) {
+	var a Anchor
+	if len(b) == 0 {
+		return a, nil
+	}
+	if len(b) != len(a) {
+		return a, Errorf(ErrInvalidInput, "anchor must be %d bytes, got %d", len(a), len(b))
+	}
+	copy(a[:], b)
+	return a, nil
+}
+
+// ParseAnchor decodes a base64url anchor.
+//
+// Returns the zero Anchor for an empty string, or an error with code
+// ErrInvalidInput if s is not the unpadded base64url encoding of 32 bytes.
+func ParseAnchor(s string) (Anchor, error) {
+	var a Anchor
+	if s == "" {
+		return a, nil
+	}
+	if err := decode32(a[:], s); err != nil {
+		return a, Errorf(ErrInvalidInput, "invalid anchor %q: %v", s, err)
+	}
+	return a, nil
+}
+
+// Raw returns the anchor as stored in the DataItem.Anchor field, or an empty string for the zero Anchor.
+func (a Anchor) Raw() string {
+	if a.IsZero() {
+		return ""
+	}
+	return string(a[:])
+}
+
+// String returns the base64url form of the anchor, or an empty string for the zero Anchor.
+func (a Anchor) String() string {
+	if a.IsZero() {
+		return ""
+	}
+	return crypto.Base64URLEncode(a[:])
+}
+
+// IsZero reports whether a is the zero Anchor.
+func (a Anchor) IsZero() bool {
+	return a == Anchor{}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (a Anchor) MarshalText() ([]byte, error) {
+	return []byte(a.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (a *Anchor) UnmarshalText(text []byte) error {
+	parsed, err := ParseAnchor(string(text))
+	if err != nil {
+		return err
+	}
+	*a = parsed
+	return nil
+}
+
+// decode32 decodes the base64url string s into the 32-byte dst.
+//
+// Unlike crypto.Base64URLDecode it only accepts the canonical form, so that
+// every value has a single spelling and parsing round-trips.
+func decode32(dst []byte, s string) error {
+	b, err := crypto.Base64URLDecode(s)
+	if err != nil {
+		return err
+	}
+	if crypto.Base64URLEncode(b) != s {
+		return fmt.Errorf("%q is not canonical base64url", s)
+	}
+	if len(b) != len(dst) {
+		return fmt.Errorf("expected %d bytes, got %d", len(dst), len(b))
+	}
+	copy(dst, b)
+	return nil
+}
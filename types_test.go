@@ -0,0 +1,71 @@
+package goar
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAddress(t *testing.T) {
+	const s = "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek"
+
+	a, err := ParseAddress(s)
+	require.NoError(t, err)
+	assert.Equal(t, s, a.String())
+	assert.False(t, a.IsZero())
+
+	zero, err := ParseAddress("")
+	require.NoError(t, err)
+	assert.True(t, zero.IsZero())
+	assert.Empty(t, zero.String())
+
+	for _, invalid := range []string{"target", s[:40], s + "AAAA", "not+base64url/"} {
+		_, err := ParseAddress(invalid)
+		assert.ErrorIs(t, err, ErrInvalidInput, invalid)
+	}
+
+	// Only the canonical spelling parses: no padding, standard alphabet or trailing bits
+	var url Address
+	copy(url[:], []byte{0xfb, 0xff})
+	standard := strings.NewReplacer("-", "+", "_", "/").Replace(url.String())
+	for _, spelling := range []string{s + "=", s[:42] + "l", standard} {
+		_, err := ParseAddress(spelling)
+		assert.ErrorIs(t, err, ErrInvalidInput, spelling)
+	}
+
+	b, err := json.Marshal(struct{ Target Address }{a})
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"Target":"`+s+`"}`, string(b))
+	var decoded struct{ Target Address }
+	require.NoError(t, json.Unmarshal(b, &decoded))
+	assert.Equal(t, a, decoded.Target)
+	assert.ErrorIs(t, json.Unmarshal([]byte(`{"Target":"short"}`), &decoded), ErrInvalidInput)
+}
+
+func TestAnchor(t *testing.T) {
+	raw := bytes.Repeat([]byte{'a'}, 32)
+
+	a, err := NewAnchor(raw)
+	require.NoError(t, err)
+	assert.Equal(t, string(raw), a.Raw())
+
+	parsed, err := ParseAnchor(a.String())
+	require.NoError(t, err)
+	assert.Equal(t, a, parsed)
+
+	zero, err := NewAnchor(nil)
+	require.NoError(t, err)
+	assert.True(t, zero.IsZero())
+	assert.Empty(t, zero.Raw())
+
+	_, err = NewAnchor(raw[:31])
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = ParseAnchor("short")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+	_, err = ParseAnchor(a.String() + "=")
+	assert.ErrorIs(t, err, ErrInvalidInput)
+}
@@ -0,0 +1,118 @@
+package uploader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/liteseed/goar"
+)
+
+// Concurrency defaults
+const (
+	DEFAULT_MIN_CONCURRENCY = 1               // Lowest number of parallel chunk uploads
+	DEFAULT_MAX_CONCURRENCY = 32              // Highest number of parallel chunk uploads
+	DEFAULT_RETRY_DELAY     = 1 * time.Second // Base delay before retrying a failed chunk
+	MAX_CHUNK_ATTEMPTS      = 10              // Upload attempts per chunk before UploadChunks gives up
+)
+
+// Controller adapts the number of parallel chunk uploads to what the gateway sustains.
+//
+// It implements additive-increase/multiplicative-decrease (AIMD): every
+// accepted chunk grows the concurrency limit by roughly one per window of
+// successes, while a throttling signal (HTTP 429, 5xx or a network
+// failure/timeout) halves it. Over time the limit converges to the
+// gateway's sustainable throughput. Rejections that are the chunk's own
+// fault (other 4xx responses) do not change the limit.
+//
+// A Controller is safe for concurrent use.
+type Controller struct {
+	Min        int           // Lower bound of the concurrency limit
+	Max        int           // Upper bound of the concurrency limit
+	RetryDelay time.Duration // Base delay before retrying a failed chunk, multiplied by the attempt number
+
+	mu       sync.Mutex
+	cond     *sync.Cond
+	limit    float64
+	inFlight int
+}
+
+// NewController creates a Controller that starts at minimum parallel uploads and never exceeds maximum.
+//
+// Example:
+//
+//	ctl := uploader.NewController(2, 16)
+//	err := u.UploadChunks(ctl)
+func NewController(minimum int, maximum int) *Controller {
+	if minimum < 1 {
+		minimum = 1
+	}
+	if maximum < minimum {
+		maximum = minimum
+	}
+	c := &Controller{Min: minimum, Max: maximum, RetryDelay: DEFAULT_RETRY_DELAY, limit: float64(minimum)}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Acquire blocks until an upload slot is available under the current limit.
+func (c *Controller) Acquire() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for c.inFlight >= c.current() {
+		c.cond.Wait()
+	}
+	c.inFlight++
+}
+
+// Release frees an upload slot and adjusts the limit from the outcome of the upload.
+//
+// Parameters:
+//   - code: HTTP status code of the response, or -1 if there was none
+//   - err: The error returned by the upload, if any
+func (c *Controller) Release(code int, err error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.inFlight--
+
+	switch {
+	case isThrottled(code, err):
+		c.limit = max(float64(c.Min), c.limit/2)
+	case err == nil && code >= 200 && code < 300:
+		c.limit = min(float64(c.Max), c.limit+1/c.limit)
+	}
+	c.cond.Broadcast()
+}
+
+// Concurrency returns the current concurrency limit.
+func (c *Controller) Concurrency() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.current()
+}
+
+// InFlight returns the number of uploads currently holding a slot.
+func (c *Controller) InFlight() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.inFlight
+}
+
+// current returns the integer limit. The caller must hold c.mu.
+func (c *Controller) current() int {
+	return max(c.Min, min(c.Max, int(c.limit)))
+}
+
+// isThrottled reports whether an upload outcome indicates the gateway is overloaded.
+func isThrottled(code int, err error) bool {
+	if code == http.StatusTooManyRequests || code >= 500 {
+		return true
+	}
+	switch goar.CodeOf(err) {
+	case goar.ErrRateLimited, goar.ErrGateway, goar.ErrNetwork, goar.ErrDial:
+		return true
+	}
+	return errors.Is(err, context.DeadlineExceeded)
+}
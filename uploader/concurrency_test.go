@@ -0,0 +1,286 @@
+package uploader
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/chaos"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestController verifies additive increase and multiplicative decrease of the concurrency limit
+func TestController(t *testing.T) {
+	c := NewController(1, 4)
+	assert.Equal(t, 1, c.Concurrency())
+
+	// One success per slot in the window grows the limit by one
+	for i := 0; i < 10; i++ {
+		c.Acquire()
+		c.Release(200, nil)
+	}
+	assert.Equal(t, 4, c.Concurrency())
+
+	c.Acquire()
+	c.Release(429, errors.New("429: Too Many Requests"))
+	assert.Equal(t, 2, c.Concurrency())
+
+	c.Acquire()
+	c.Release(-1, goar.Wrap(goar.ErrNetwork, errors.New("timeout")))
+	assert.Equal(t, 1, c.Concurrency())
+
+	// Client errors are not a congestion signal
+	c.Acquire()
+	c.Release(400, errors.New("400: invalid_proof"))
+	assert.Equal(t, 1, c.Concurrency())
+	assert.Equal(t, 0, c.InFlight())
+
+	t.Run("Bounds", func(t *testing.T) {
+		c := NewController(0, -1)
+		assert.Equal(t, 1, c.Min)
+		assert.Equal(t, 1, c.Max)
+	})
+}
+
+// TestUploadChunks verifies parallel chunk uploads with throttling and retries
+func TestUploadChunks(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	tx := transaction.New(data, "", "0", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+
+	t.Run("Throttled", func(t *testing.T) {
+		var calls, inFlight, peak atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			n := inFlight.Add(1)
+			defer inFlight.Add(-1)
+			for p := peak.Load(); n > p && !peak.CompareAndSwap(p, n); p = peak.Load() {
+			}
+			time.Sleep(5 * time.Millisecond)
+			// Throttle every third request
+			if calls.Add(1)%3 == 0 {
+				w.WriteHeader(http.StatusTooManyRequests)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		uploader, err := New(client.New(server.URL), tx)
+		require.NoError(t, err)
+		uploader.Data = data
+		uploader.TxPosted = true
+
+		ctl := NewController(1, 2)
+		ctl.RetryDelay = time.Millisecond
+		require.NoError(t, uploader.UploadChunks(ctl))
+
+		p := uploader.Progress()
+		assert.Equal(t, len(tx.ChunkData.Chunks), p.Total)
+		assert.Equal(t, p.Total, p.Posted)
+		assert.Equal(t, 0, p.InFlight)
+		assert.GreaterOrEqual(t, p.Concurrency, 1)
+		assert.LessOrEqual(t, int(peak.Load()), 2)
+		assert.Equal(t, len(tx.ChunkData.Chunks), uploader.ChunkIndex)
+	})
+
+	t.Run("Fatal", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("invalid_proof"))
+		}))
+		defer server.Close()
+
+		uploader, err := New(client.New(server.URL), tx)
+		require.NoError(t, err)
+		uploader.Data = data
+		uploader.TxPosted = true
+
+		err = uploader.UploadChunks(nil)
+		assert.ErrorIs(t, err, goar.ErrChunkRejected)
+		assert.Zero(t, uploader.Progress().Posted)
+	})
+
+	t.Run("Faulty gateway", func(t *testing.T) {
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		ft := chaos.New(chaos.Faults{
+			ResetRate:       0.1,
+			RateLimitRate:   0.1,
+			ServerErrorRate: 0.15,
+			TruncateRate:    0.1,
+			LatencyRate:     0.5,
+			Latency:         5 * time.Millisecond,
+		}, 1)
+		uploader, err := New(ft.Client(server.URL), tx)
+		require.NoError(t, err)
+		uploader.Data = data
+		uploader.TxPosted = true
+
+		ctl := NewController(1, 4)
+		ctl.RetryDelay = time.Millisecond
+		require.NoError(t, uploader.UploadChunks(ctl))
+		assert.Equal(t, len(tx.ChunkData.Chunks), uploader.Progress().Posted)
+		assert.Greater(t, ft.Stats().Requests, len(tx.ChunkData.Chunks))
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// Cancel once the first chunk is accepted
+			if calls.Add(1) == 1 {
+				defer cancel()
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		uploader, err := New(client.New(server.URL), tx)
+		require.NoError(t, err)
+		uploader.Data = data
+		uploader.TxPosted = true
+
+		err = uploader.UploadChunksContext(ctx, NewController(1, 1))
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.ErrorIs(t, err, goar.ErrNetwork)
+		assert.Less(t, uploader.Progress().Posted, len(tx.ChunkData.Chunks))
+		assert.Zero(t, uploader.ChunkIndex)
+	})
+
+	t.Run("Retry budget", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			calls.Add(1)
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}))
+		defer server.Close()
+
+		uploader, err := New(client.New(server.URL), tx)
+		require.NoError(t, err)
+		uploader.Data = data
+		uploader.TxPosted = true
+		uploader.MaxErrors = 5
+
+		ctl := NewController(1, 1)
+		ctl.RetryDelay = time.Millisecond
+		err = uploader.UploadChunksContext(context.Background(), ctl)
+		assert.ErrorIs(t, err, goar.ErrGateway)
+		assert.ErrorContains(t, err, "after 5 failed chunk requests")
+		assert.Equal(t, int32(5), calls.Load())
+	})
+
+	t.Run("Failing gateway of a pool", func(t *testing.T) {
+		var downCalls, upCalls atomic.Int32
+		down := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			downCalls.Add(1)
+			w.WriteHeader(http.StatusBadGateway)
+		}))
+		defer down.Close()
+		up := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			upCalls.Add(1)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer up.Close()
+
+		c, err := client.NewPool([]string{down.URL, up.URL}, client.PoolOptions{FailureThreshold: 2, Cooldown: time.Hour})
+		require.NoError(t, err)
+		uploader, err := New(c, tx)
+		require.NoError(t, err)
+		uploader.Data = data
+		uploader.TxPosted = true
+		uploader.MaxErrors = 1
+
+		require.NoError(t, uploader.UploadChunks(NewController(1, 1)))
+		assert.LessOrEqual(t, downCalls.Load(), int32(2))
+		assert.Equal(t, int32(len(tx.ChunkData.Chunks)), upCalls.Load())
+	})
+
+	t.Run("Every circuit open", func(t *testing.T) {
+		var calls atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			// The gateway recovers after failing twice
+			if calls.Add(1) <= 2 {
+				w.WriteHeader(http.StatusServiceUnavailable)
+				return
+			}
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer server.Close()
+
+		c, err := client.NewPool([]string{server.URL}, client.PoolOptions{FailureThreshold: 2, Cooldown: 50 * time.Millisecond})
+		require.NoError(t, err)
+		uploader, err := New(c, tx)
+		require.NoError(t, err)
+		uploader.Data = data
+		uploader.TxPosted = true
+
+		ctl := NewController(1, 1)
+		ctl.RetryDelay = time.Millisecond
+		start := time.Now()
+		require.NoError(t, uploader.UploadChunks(ctl))
+		assert.GreaterOrEqual(t, time.Since(start), 50*time.Millisecond)
+		assert.Equal(t, int32(2+len(tx.ChunkData.Chunks)), calls.Load())
+	})
+
+	t.Run("Not prepared", func(t *testing.T) {
+		uploader, err := New(client.New("http://localhost:1984"), &transaction.Transaction{})
+		require.NoError(t, err)
+		assert.Error(t, uploader.UploadChunks(nil))
+	})
+}
+
+// BenchmarkUploadChunks compares parallel chunk uploads over HTTP/1.1 and HTTP/2.
+//
+// The gateway is simulated with a TLS test server adding a fixed latency per
+// chunk. Run it with:
+//
+//	go test ./uploader -run '^$' -bench UploadChunks
+func BenchmarkUploadChunks(b *testing.B) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(b, err)
+
+	tx := transaction.New(data, "", "0", nil)
+	require.NoError(b, tx.PrepareChunks(data))
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(2 * time.Millisecond)
+		w.WriteHeader(http.StatusOK)
+	}))
+	server.EnableHTTP2 = true
+	server.StartTLS()
+	defer server.Close()
+	tlsConfig := server.Client().Transport.(*http.Transport).TLSClientConfig
+
+	for _, http2 := range []bool{false, true} {
+		name := "HTTP/1.1"
+		if http2 {
+			name = "HTTP/2"
+		}
+		b.Run(name, func(b *testing.B) {
+			c := client.New(server.URL)
+			c.SetTransport(client.TransportOptions{HTTP2: http2, MaxStreamsPerHost: 8, TLSConfig: tlsConfig})
+			b.SetBytes(int64(len(data)))
+			for i := 0; i < b.N; i++ {
+				uploader, err := New(c, tx)
+				require.NoError(b, err)
+				uploader.Data = data
+				uploader.TxPosted = true
+				require.NoError(b, uploader.UploadChunks(nil))
+			}
+		})
+	}
+}
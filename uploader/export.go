@@ -0,0 +1,145 @@
+package uploader
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+)
+
+// Upload statuses reported by Record
+const (
+	StatusPending   = "pending"   // The transaction header has not been posted
+	StatusUploading = "uploading" // The header is posted, some chunks are not
+	StatusComplete  = "complete"  // The header and every chunk are posted
+	StatusError     = "error"     // The last request failed
+)
+
+// RECORD_COLUMNS is the CSV header written by WriteCSV. Columns are only ever appended.
+var RECORD_COLUMNS = []string{
+	"tx_id",
+	"owner",
+	"data_size",
+	"fee",
+	"chunks",
+	"chunks_posted",
+	"status",
+	"last_request_at",
+	"last_chunk_posted_at",
+	"last_status_code",
+	"last_error",
+}
+
+// Record is the exported state of one upload, with a stable schema for
+// accounting and analytics pipelines.
+type Record struct {
+	TxID              string     `json:"tx_id"`                          // Transaction ID
+	Owner             string     `json:"owner"`                          // Base64url-encoded owner of the transaction
+	DataSize          int64      `json:"data_size"`                      // Size of the data in bytes
+	Fee               string     `json:"fee"`                            // Transaction reward in Winston units
+	Chunks            int        `json:"chunks"`                         // Number of chunks in the transaction
+	ChunksPosted      int        `json:"chunks_posted"`                  // Number of chunks accepted by the gateway
+	Status            string     `json:"status"`                         // One of StatusPending, StatusUploading, StatusComplete or StatusError
+	LastRequestAt     *time.Time `json:"last_request_at,omitempty"`      // When the last request completed
+	LastChunkPostedAt *time.Time `json:"last_chunk_posted_at,omitempty"` // When the most recent chunk was accepted
+	LastStatusCode    int        `json:"last_status_code"`               // HTTP status code of the last request
+	LastError         string     `json:"last_error,omitempty"`           // Error message of the last failed request
+}
+
+// Record returns the exported state of the upload.
+func (tu *TransactionUploader) Record() Record {
+	r := Record{
+		LastStatusCode: tu.LastResponseStatus,
+		LastError:      tu.LastResponseError,
+	}
+	if t := tu.transaction; t != nil {
+		r.TxID = t.ID
+		r.Owner = t.Owner
+		r.Fee = t.Reward
+		r.DataSize, _ = strconv.ParseInt(t.DataSize, 10, 64)
+	}
+	if tu.LastRequestTimeEnd > 0 {
+		at := time.UnixMilli(tu.LastRequestTimeEnd).UTC()
+		r.LastRequestAt = &at
+	}
+
+	for _, s := range tu.Snapshot() {
+		r.Chunks++
+		if s.State != ChunkPosted {
+			continue
+		}
+		r.ChunksPosted++
+		if r.LastChunkPostedAt == nil || s.PostedAt.After(*r.LastChunkPostedAt) {
+			at := s.PostedAt.UTC()
+			r.LastChunkPostedAt = &at
+		}
+	}
+
+	switch {
+	case tu.TxPosted && r.ChunksPosted == r.Chunks:
+		r.Status = StatusComplete
+	case tu.LastResponseError != "":
+		r.Status = StatusError
+	case tu.TxPosted:
+		r.Status = StatusUploading
+	default:
+		r.Status = StatusPending
+	}
+	return r
+}
+
+// WriteCSV writes one row per upload, preceded by the RECORD_COLUMNS header.
+//
+// Timestamps are RFC 3339 in UTC and empty when unknown.
+//
+// Example:
+//
+//	f, _ := os.Create("uploads.csv")
+//	defer f.Close()
+//	err := uploader.WriteCSV(f, uploads)
+func WriteCSV(w io.Writer, uploads []*TransactionUploader) error {
+	cw := csv.NewWriter(w)
+	if err := cw.Write(RECORD_COLUMNS); err != nil {
+		return err
+	}
+	for _, tu := range uploads {
+		r := tu.Record()
+		row := []string{
+			r.TxID,
+			r.Owner,
+			strconv.FormatInt(r.DataSize, 10),
+			r.Fee,
+			strconv.Itoa(r.Chunks),
+			strconv.Itoa(r.ChunksPosted),
+			r.Status,
+			formatTime(r.LastRequestAt),
+			formatTime(r.LastChunkPostedAt),
+			strconv.Itoa(r.LastStatusCode),
+			r.LastError,
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteJSONL writes one JSON-encoded Record per line for every upload.
+func WriteJSONL(w io.Writer, uploads []*TransactionUploader) error {
+	enc := json.NewEncoder(w)
+	for _, tu := range uploads {
+		if err := enc.Encode(tu.Record()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func formatTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,83 @@
+package uploader
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestExport verifies the CSV and JSONL exports of upload state
+func TestExport(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tx := transaction.New(data, "", "0", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	tx.ID = "uploaded"
+	tx.Reward = "1000"
+
+	uploading, err := New(client.New(server.URL), tx)
+	require.NoError(t, err)
+	uploading.Data = data
+	uploading.TxPosted = true
+	require.NoError(t, uploading.UploadChunk(0))
+
+	pending, err := New(client.New(server.URL), &transaction.Transaction{ID: "pending", DataSize: "0"})
+	require.NoError(t, err)
+
+	uploads := []*TransactionUploader{uploading, pending}
+
+	t.Run("CSV", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteCSV(&buf, uploads))
+
+		rows, err := csv.NewReader(&buf).ReadAll()
+		require.NoError(t, err)
+		require.Len(t, rows, 3)
+		assert.Equal(t, RECORD_COLUMNS, rows[0])
+		assert.Equal(t, []string{"uploaded", "", "1048576", "1000", "4", "1", StatusUploading}, rows[1][:7])
+		assert.NotEmpty(t, rows[1][7])
+		assert.NotEmpty(t, rows[1][8])
+		assert.Equal(t, "200", rows[1][9])
+		assert.Equal(t, []string{"pending", "", "0", "", "0", "0", StatusPending, "", "", "0", ""}, rows[2])
+	})
+
+	t.Run("JSONL", func(t *testing.T) {
+		var buf bytes.Buffer
+		require.NoError(t, WriteJSONL(&buf, uploads))
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 2)
+		var r Record
+		require.NoError(t, json.Unmarshal([]byte(lines[0]), &r))
+		assert.Equal(t, "uploaded", r.TxID)
+		assert.Equal(t, 1, r.ChunksPosted)
+		assert.NotNil(t, r.LastChunkPostedAt)
+		assert.NotContains(t, lines[1], "last_request_at")
+	})
+
+	t.Run("Status", func(t *testing.T) {
+		uploading.LastResponseError = "500: oops"
+		assert.Equal(t, StatusError, uploading.Record().Status)
+		uploading.LastResponseError = ""
+		for i := 1; i < 4; i++ {
+			require.NoError(t, uploading.UploadChunk(i))
+		}
+		assert.Equal(t, StatusComplete, uploading.Record().Status)
+	})
+}
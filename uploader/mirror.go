@@ -0,0 +1,126 @@
+package uploader
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction"
+)
+
+// Mirror receives a copy of every chunk accepted by the gateway.
+//
+// Implementations must be safe for concurrent use, as UploadChunks posts
+// chunks in parallel.
+type Mirror interface {
+	// WriteChunk stores a chunk of transaction txID, with its proof.
+	WriteChunk(txID string, chunk *transaction.GetChunkResult) error
+}
+
+// DirMirror is a Mirror storing chunks in a local directory.
+//
+// Each chunk is written as <Dir>/<tx id>/chunks/<offset>.json, where offset
+// is the chunk's end offset within the transaction data and the file holds
+// the same JSON body that was posted to /chunk: data_root, data_size,
+// data_path, offset and the base64url-encoded chunk. The data is therefore
+// available locally as soon as it is uploaded, with proofs that can be
+// served or re-posted without downloading it again.
+type DirMirror struct {
+	Dir string // Root directory of the mirror
+}
+
+// WriteChunk writes the chunk file atomically, so a reader never sees a partial chunk.
+func (m *DirMirror) WriteChunk(txID string, chunk *transaction.GetChunkResult) error {
+	dir := filepath.Join(m.Dir, txID, "chunks")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	b, err := json.Marshal(chunk)
+	if err != nil {
+		return err
+	}
+	path := filepath.Join(dir, chunk.Offset+".json")
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, b, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Chunks returns the mirrored chunks of transaction txID, ordered by offset.
+func (m *DirMirror) Chunks(txID string) ([]transaction.GetChunkResult, error) {
+	paths, err := filepath.Glob(filepath.Join(m.Dir, txID, "chunks", "*.json"))
+	if err != nil {
+		return nil, err
+	}
+	chunks := make([]transaction.GetChunkResult, 0, len(paths))
+	offsets := make(map[string]int64, len(paths))
+	for _, path := range paths {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		var chunk transaction.GetChunkResult
+		if err := json.Unmarshal(b, &chunk); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		offset, err := strconv.ParseInt(chunk.Offset, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+		offsets[chunk.Offset] = offset
+		chunks = append(chunks, chunk)
+	}
+	sort.Slice(chunks, func(i, j int) bool {
+		return offsets[chunks[i].Offset] < offsets[chunks[j].Offset]
+	})
+	return chunks, nil
+}
+
+// Data reassembles the data of transaction txID from its mirrored chunks.
+//
+// Returns an error if a chunk is missing, i.e. the upload did not complete.
+func (m *DirMirror) Data(txID string) ([]byte, error) {
+	chunks, err := m.Chunks(txID)
+	if err != nil {
+		return nil, err
+	}
+	if len(chunks) == 0 {
+		return nil, fmt.Errorf("no chunks mirrored for %s: %w", txID, os.ErrNotExist)
+	}
+	size, err := strconv.ParseInt(chunks[0].DataSize, 10, 64)
+	if err != nil {
+		return nil, err
+	}
+	data := make([]byte, 0, size)
+	for _, chunk := range chunks {
+		b, err := crypto.Base64URLDecode(chunk.Chunk)
+		if err != nil {
+			return nil, err
+		}
+		offset, _ := strconv.ParseInt(chunk.Offset, 10, 64)
+		if int64(len(data)+len(b)) != offset+1 {
+			return nil, fmt.Errorf("chunk starting at byte %d of %s is missing", len(data), txID)
+		}
+		data = append(data, b...)
+	}
+	if int64(len(data)) != size {
+		return nil, fmt.Errorf("mirrored %d of %d bytes of %s", len(data), size, txID)
+	}
+	return data, nil
+}
+
+// mirror copies a posted chunk to the uploader's mirror, if any
+func (tu *TransactionUploader) mirror(chunk *transaction.GetChunkResult) error {
+	if tu.Mirror == nil {
+		return nil
+	}
+	if err := tu.Mirror.WriteChunk(tu.transaction.ID, chunk); err != nil {
+		return fmt.Errorf("unable to mirror chunk at offset %s: %w", chunk.Offset, err)
+	}
+	return nil
+}
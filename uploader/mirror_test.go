@@ -0,0 +1,129 @@
+package uploader
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDirMirror(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	tx := transaction.New(data, "", "0", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	tx.ID = "mirrored"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	mirror := &DirMirror{Dir: t.TempDir()}
+	uploader, err := New(client.New(server.URL), tx)
+	require.NoError(t, err)
+	uploader.Data = data
+	uploader.TxPosted = true
+	uploader.Mirror = mirror
+
+	// The first chunk goes through UploadChunk, the others through UploadChunks
+	require.NoError(t, uploader.UploadChunk(0))
+	_, err = mirror.Data(tx.ID)
+	assert.ErrorContains(t, err, "mirrored 262144 of 1048576 bytes")
+
+	require.NoError(t, uploader.UploadChunks(nil))
+
+	chunks, err := mirror.Chunks(tx.ID)
+	require.NoError(t, err)
+	require.Len(t, chunks, len(tx.ChunkData.Chunks))
+	for i, chunk := range chunks {
+		expected, err := tx.GetChunk(i, data)
+		require.NoError(t, err)
+		assert.Equal(t, *expected, chunk)
+	}
+
+	mirrored, err := mirror.Data(tx.ID)
+	require.NoError(t, err)
+	assert.Equal(t, data, mirrored)
+
+	_, err = mirror.Data("unknown")
+	assert.ErrorIs(t, err, os.ErrNotExist)
+
+	leftovers, err := filepath.Glob(filepath.Join(mirror.Dir, tx.ID, "chunks", "*.tmp"))
+	require.NoError(t, err)
+	assert.Empty(t, leftovers)
+}
+
+// TestUploadFromSource verifies that chunks are read from Source instead of Data
+func TestUploadFromSource(t *testing.T) {
+	f, err := os.Open("../test/1MB.bin")
+	require.NoError(t, err)
+	defer f.Close()
+	info, err := f.Stat()
+	require.NoError(t, err)
+
+	tx := transaction.New(nil, "", "0", nil)
+	require.NoError(t, tx.PrepareChunksFromReader(f, info.Size(), nil))
+	tx.ID = "from-source"
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	mirror := &DirMirror{Dir: t.TempDir()}
+	uploader, err := New(client.New(server.URL), tx)
+	require.NoError(t, err)
+	uploader.Source = f
+	uploader.TxPosted = true
+	uploader.Mirror = mirror
+	require.NoError(t, uploader.UploadChunks(nil))
+
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	mirrored, err := mirror.Data(tx.ID)
+	require.NoError(t, err)
+	assert.Equal(t, data, mirrored)
+}
+
+// TestUploadFromDataSource verifies that transactions keeping their data outside are posted header only
+func TestUploadFromDataSource(t *testing.T) {
+	tx, err := transaction.NewDataTransactionFromFile("../test/1MB.bin", nil)
+	require.NoError(t, err)
+	defer tx.Close()
+	tx.ID = "from-data-source"
+
+	var posted transaction.Transaction
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/tx" {
+			assert.NoError(t, json.NewDecoder(r.Body).Decode(&posted))
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	mirror := &DirMirror{Dir: t.TempDir()}
+	uploader, err := New(client.New(server.URL), tx)
+	require.NoError(t, err)
+	uploader.Mirror = mirror
+	require.NoError(t, uploader.PostTransaction())
+	assert.True(t, uploader.TxPosted)
+	assert.Equal(t, 0, uploader.ChunkIndex)
+	assert.Empty(t, posted.Data)
+	assert.Equal(t, tx.DataRoot, posted.DataRoot)
+	require.NoError(t, uploader.UploadChunks(nil))
+
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	mirrored, err := mirror.Data(tx.ID)
+	require.NoError(t, err)
+	assert.Equal(t, data, mirrored)
+}
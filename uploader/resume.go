@@ -0,0 +1,169 @@
+package uploader
+
+import (
+	"encoding/json"
+	"io"
+	"strconv"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/transaction"
+)
+
+// State is the persisted progress of a TransactionUploader, see Serialize and Resume.
+//
+// The transaction is stored without its data, which is read again from the
+// source on resume, so a state stays small whatever the size of the upload.
+type State struct {
+	Transaction        *transaction.Transaction `json:"transaction"`             // Signed transaction header, without data
+	DataRoot           string                   `json:"data_root"`               // Data root the source must match on resume
+	ChunkIndex         int                      `json:"chunk_index"`             // Index of the next chunk to upload
+	TxPosted           bool                     `json:"tx_posted"`               // Whether the transaction header has been posted
+	LastRequestTimeEnd int64                    `json:"last_request_time_end"`   // Timestamp of last request completion
+	LastResponseStatus int                      `json:"last_response_status"`    // HTTP status code from last request
+	LastResponseError  string                   `json:"last_response_error"`     // Error message from last failed request
+	PostedChunks       []int                    `json:"posted_chunks,omitempty"` // Chunks accepted by the gateway, skipped by UploadChunks
+}
+
+// Serialize returns the progress of the upload as JSON, to be passed to
+// Resume after a restart.
+//
+// Returns an error if the transaction's chunks have not been prepared.
+//
+// Example:
+//
+//	state, err := uploader.Serialize()
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	err = os.WriteFile("upload.json", state, 0o600)
+func (tu *TransactionUploader) Serialize() ([]byte, error) {
+	if tu.transaction == nil || tu.transaction.ChunkData == nil {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "chunks have not been prepared")
+	}
+	header := *tu.transaction
+	header.Data = ""
+	header.ChunkData = nil
+	state := State{
+		Transaction:        &header,
+		DataRoot:           tu.transaction.DataRoot,
+		ChunkIndex:         tu.ChunkIndex,
+		TxPosted:           tu.TxPosted,
+		LastRequestTimeEnd: tu.LastRequestTimeEnd,
+		LastResponseStatus: tu.LastResponseStatus,
+		LastResponseError:  tu.LastResponseError,
+	}
+	for _, s := range tu.Snapshot() {
+		if s.State == ChunkPosted {
+			state.PostedChunks = append(state.PostedChunks, s.Index)
+		}
+	}
+	return json.Marshal(state)
+}
+
+// Resume recreates an uploader from a state returned by Serialize.
+//
+// The chunks are prepared again from src, which must hold the same data as
+// when the state was saved, and chunks already accepted by the gateway are
+// skipped by UploadChunks.
+//
+// Parameters:
+//   - c: HTTP client for communicating with Arweave nodes
+//   - state: The JSON state returned by Serialize
+//   - src: The data of the transaction, e.g. the file being uploaded
+//
+// Returns an error with code goar.ErrDecode if the state is malformed, or
+// goar.ErrInvalidInput if src does not match the saved data root.
+//
+// Example:
+//
+//	state, err := os.ReadFile("upload.json")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	uploader, err := uploader.Resume(client, state, f)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	err = uploader.UploadChunks(nil)
+func Resume(c *client.Client, state []byte, src io.ReaderAt) (*TransactionUploader, error) {
+	var s State
+	if err := json.Unmarshal(state, &s); err != nil {
+		return nil, goar.Wrap(goar.ErrDecode, err)
+	}
+	if s.Transaction == nil {
+		return nil, goar.Errorf(goar.ErrDecode, "state has no transaction")
+	}
+	tu, err := resume(c, s.Transaction, s.DataRoot, src)
+	if err != nil {
+		return nil, err
+	}
+	tu.ChunkIndex = s.ChunkIndex
+	tu.TxPosted = s.TxPosted
+	tu.LastRequestTimeEnd = s.LastRequestTimeEnd
+	tu.LastResponseStatus = s.LastResponseStatus
+	tu.LastResponseError = s.LastResponseError
+	ct := tu.tracker()
+	for _, i := range s.PostedChunks {
+		ct.posted(i, 200, time.Time{})
+	}
+	return tu, nil
+}
+
+// FromTransactionID recreates an uploader for a transaction whose header is
+// already on the gateway, e.g. after a crash that lost the uploader state.
+//
+// The header is fetched from the gateway and the chunks are prepared again
+// from src. Every chunk is uploaded again by UploadChunks; the gateway
+// accepts chunks it already holds.
+//
+// Parameters:
+//   - c: HTTP client for communicating with Arweave nodes
+//   - id: The ID of the posted transaction
+//   - src: The data of the transaction
+//
+// Returns the request error, or an error with code goar.ErrInvalidInput if
+// the transaction has no data root or src does not match it.
+//
+// Example:
+//
+//	uploader, err := uploader.FromTransactionID(client, id, f)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	err = uploader.UploadChunks(nil)
+func FromTransactionID(c *client.Client, id string, src io.ReaderAt) (*TransactionUploader, error) {
+	tx, err := c.GetTransactionByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if tx.DataRoot == "" {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "transaction %s has no data root", id)
+	}
+	tu, err := resume(c, tx, tx.DataRoot, src)
+	if err != nil {
+		return nil, err
+	}
+	tu.TxPosted = true
+	return tu, nil
+}
+
+// resume prepares the chunks of tx from src, checking them against dataRoot,
+// and returns an uploader reading chunks from src
+func resume(c *client.Client, tx *transaction.Transaction, dataRoot string, src io.ReaderAt) (*TransactionUploader, error) {
+	size, err := strconv.ParseInt(tx.DataSize, 10, 64)
+	if err != nil || size < 0 {
+		return nil, goar.Errorf(goar.ErrDecode, "invalid data size %q", tx.DataSize)
+	}
+	if err := tx.PrepareChunksFromReader(io.NewSectionReader(src, 0, size), size, &transaction.PrepareOptions{DataRoot: dataRoot}); err != nil {
+		return nil, err
+	}
+	tu, err := New(c, tx)
+	if err != nil {
+		return nil, err
+	}
+	tu.Source = src
+	tu.TotalChunks = len(tx.ChunkData.Chunks)
+	return tu, nil
+}
@@ -0,0 +1,121 @@
+package uploader
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync/atomic"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestResume verifies uploads continue from a serialized state
+func TestResume(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	var chunks atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/chunk" {
+			chunks.Add(1)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+	c := client.New(server.URL)
+
+	tx := transaction.New(data, "", "0", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	tx.ID = "resumed"
+
+	uploader, err := New(c, tx)
+	require.NoError(t, err)
+	uploader.Data = data
+	uploader.TxPosted = true
+	require.NoError(t, uploader.UploadChunk(0))
+
+	state, err := uploader.Serialize()
+	require.NoError(t, err)
+	var saved State
+	require.NoError(t, json.Unmarshal(state, &saved))
+	assert.Empty(t, saved.Transaction.Data)
+	assert.Equal(t, tx.DataRoot, saved.DataRoot)
+	assert.Equal(t, []int{0}, saved.PostedChunks)
+
+	t.Run("Resume", func(t *testing.T) {
+		chunks.Store(0)
+		resumed, err := Resume(c, state, bytes.NewReader(data))
+		require.NoError(t, err)
+		assert.True(t, resumed.TxPosted)
+		assert.Equal(t, 1, resumed.ChunkIndex)
+		assert.Equal(t, len(tx.ChunkData.Chunks), resumed.TotalChunks)
+
+		require.NoError(t, resumed.UploadChunks(nil))
+		assert.Equal(t, int32(len(tx.ChunkData.Chunks)-1), chunks.Load())
+	})
+
+	t.Run("Different data", func(t *testing.T) {
+		other := bytes.Clone(data)
+		other[0] ^= 0xff
+		_, err := Resume(c, state, bytes.NewReader(other))
+		assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err))
+	})
+
+	t.Run("Malformed", func(t *testing.T) {
+		_, err := Resume(c, []byte("{"), bytes.NewReader(data))
+		assert.Equal(t, goar.ErrDecode, goar.CodeOf(err))
+		_, err = Resume(c, []byte("{}"), bytes.NewReader(data))
+		assert.Equal(t, goar.ErrDecode, goar.CodeOf(err))
+	})
+
+	t.Run("Unprepared", func(t *testing.T) {
+		u, err := New(c, transaction.New(nil, "", "0", nil))
+		require.NoError(t, err)
+		_, err = u.Serialize()
+		assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err))
+	})
+}
+
+// TestFromTransactionID verifies uploaders are reconstructed from a posted header
+func TestFromTransactionID(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	tx := transaction.New(data, "", "0", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	header := *tx
+	header.ID = "posted"
+	header.Data = ""
+
+	var chunks atomic.Int32
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tx/posted", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(header)
+	})
+	mux.HandleFunc("GET /tx/nodata", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(transaction.Transaction{ID: "nodata", DataSize: "0"})
+	})
+	mux.HandleFunc("POST /chunk", func(w http.ResponseWriter, r *http.Request) {
+		chunks.Add(1)
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	c := client.New(server.URL)
+
+	uploader, err := FromTransactionID(c, "posted", bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.True(t, uploader.TxPosted)
+	require.NoError(t, uploader.UploadChunks(nil))
+	assert.Equal(t, int32(len(tx.ChunkData.Chunks)), chunks.Load())
+
+	_, err = FromTransactionID(c, "nodata", bytes.NewReader(data))
+	assert.Equal(t, goar.ErrInvalidInput, goar.CodeOf(err))
+	_, err = FromTransactionID(c, "missing", bytes.NewReader(data))
+	assert.Equal(t, goar.ErrNotFound, goar.CodeOf(err))
+}
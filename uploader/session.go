@@ -0,0 +1,204 @@
+package uploader
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction"
+)
+
+// Session uploads the chunks of a transaction as they are handed to it, see CreateSession.
+//
+// Unlike TransactionUploader, a session never reads the data itself: the
+// caller, typically a service fed by a queue or by clients in other
+// languages, passes each chunk with AddChunk in any order, and every chunk
+// is checked against the signed data root before it is uploaded. A Session
+// is safe for concurrent use.
+type Session struct {
+	client   *client.Client
+	tx       *transaction.Transaction
+	mu       sync.Mutex
+	offsets  map[int64]int // Chunk index by start offset
+	received []bool
+	complete bool
+}
+
+// CreateSession posts the header of a signed transaction and returns a
+// session accepting its chunks.
+//
+// The transaction must carry its prepared chunks, but not its data: a
+// transaction encoded with MarshalJSON and decoded with UnmarshalJSON keeps
+// them, so the header can be signed and chunked elsewhere and handed over
+// as JSON.
+//
+// Parameters:
+//   - c: HTTP client for communicating with Arweave nodes
+//   - tx: The signed transaction, with ChunkData
+//
+// Returns an error with code goar.ErrInvalidInput if the chunks have not
+// been prepared or do not match the data root, goar.ErrInvalidSignature if
+// the signature is invalid, or the error of the gateway if the header is
+// rejected.
+//
+// Example:
+//
+//	session, err := CreateSession(client, tx)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	for msg := range queue {
+//		if err := session.AddChunk(msg.Offset, msg.Data); err != nil {
+//			log.Printf("Rejected chunk at %d: %v", msg.Offset, err)
+//		}
+//	}
+//	err = session.Complete()
+func CreateSession(c *client.Client, tx *transaction.Transaction) (*Session, error) {
+	return CreateSessionContext(context.Background(), c, tx)
+}
+
+// CreateSessionContext is CreateSession bound to ctx.
+func CreateSessionContext(ctx context.Context, c *client.Client, tx *transaction.Transaction) (*Session, error) {
+	if tx.ChunkData == nil {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "chunks have not been prepared")
+	}
+	if tx.ChunkData.DataRoot != tx.DataRoot {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "chunks have data root %s, the transaction %s", tx.ChunkData.DataRoot, tx.DataRoot)
+	}
+	if err := tx.Verify(); err != nil {
+		return nil, goar.Wrap(goar.ErrInvalidSignature, err)
+	}
+
+	header := *tx
+	header.Data = ""
+	if _, err := c.SubmitTransactionContext(ctx, &header); err != nil {
+		return nil, err
+	}
+
+	s := &Session{
+		client:   c,
+		tx:       tx,
+		offsets:  make(map[int64]int, len(tx.ChunkData.Chunks)),
+		received: make([]bool, len(tx.ChunkData.Chunks)),
+	}
+	for i, chunk := range tx.ChunkData.Chunks {
+		s.offsets[int64(chunk.MinByteRange)] = i
+	}
+	return s, nil
+}
+
+// Transaction returns the transaction whose chunks the session uploads.
+func (s *Session) Transaction() *transaction.Transaction {
+	return s.tx
+}
+
+// AddChunk validates the chunk starting at offset and uploads it.
+//
+// The chunk must hold exactly the bytes of one prepared chunk: its size and
+// hash are checked against the chunk, and its Merkle proof against the
+// signed data root, so invalid data never reaches the gateway. Adding a
+// chunk that has already been uploaded does nothing, so deliveries retried
+// by a queue are harmless.
+//
+// Parameters:
+//   - offset: Offset of the first byte of the chunk within the data
+//   - data: The raw chunk data
+//
+// Returns an error with code goar.ErrInvalidInput if the session is
+// complete, no chunk starts at offset or data does not match it, or the
+// error of the gateway if the upload fails, after which the chunk can be
+// added again.
+//
+// Example:
+//
+//	err := session.AddChunk(262144, chunk)
+//	if errors.Is(err, goar.ErrInvalidInput) {
+//		log.Printf("Discarding invalid chunk: %v", err)
+//	}
+func (s *Session) AddChunk(offset int64, data []byte) error {
+	return s.AddChunkContext(context.Background(), offset, data)
+}
+
+// AddChunkContext is AddChunk bound to ctx.
+func (s *Session) AddChunkContext(ctx context.Context, offset int64, data []byte) error {
+	s.mu.Lock()
+	i, ok := s.offsets[offset]
+	complete, received := s.complete, ok && s.received[i]
+	s.mu.Unlock()
+	if complete {
+		return goar.Errorf(goar.ErrInvalidInput, "session is complete")
+	}
+	if !ok {
+		return goar.Errorf(goar.ErrInvalidInput, "no chunk starts at offset %d", offset)
+	}
+	if received {
+		return nil
+	}
+
+	chunk := s.tx.ChunkData.Chunks[i]
+	proof := s.tx.ChunkData.Proofs[i]
+	if size := chunk.MaxByteRange - chunk.MinByteRange; len(data) != size {
+		return goar.Errorf(goar.ErrInvalidInput, "chunk at offset %d has %d bytes, expected %d", offset, len(data), size)
+	}
+	if !bytes.Equal(crypto.SHA256(data), chunk.DataHash) {
+		return goar.Errorf(goar.ErrInvalidInput, "chunk at offset %d does not match its hash", offset)
+	}
+	root, err := crypto.Base64URLDecode(s.tx.DataRoot)
+	if err != nil {
+		return goar.Wrap(goar.ErrInvalidInput, err)
+	}
+	if err := transaction.VerifyChunk(root, int(offset), data, proof.Proof); err != nil {
+		return goar.Wrap(goar.ErrInvalidInput, err)
+	}
+
+	_, err = s.client.UploadChunkContext(ctx, &transaction.GetChunkResult{
+		DataRoot: s.tx.DataRoot,
+		DataSize: s.tx.DataSize,
+		DataPath: crypto.Base64URLEncode(proof.Proof),
+		Offset:   fmt.Sprint(proof.Offset),
+		Chunk:    crypto.Base64URLEncode(data),
+	})
+	if err != nil {
+		return err
+	}
+	s.mu.Lock()
+	s.received[i] = true
+	s.mu.Unlock()
+	return nil
+}
+
+// Missing returns the start offsets of the chunks that have not been uploaded yet, in increasing order.
+func (s *Session) Missing() []int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var missing []int64
+	for offset, i := range s.offsets {
+		if !s.received[i] {
+			missing = append(missing, offset)
+		}
+	}
+	sort.Slice(missing, func(a, b int) bool { return missing[a] < missing[b] })
+	return missing
+}
+
+// Complete ends the session once every chunk has been uploaded.
+//
+// Later calls to AddChunk fail, and calling Complete again does nothing.
+//
+// Returns an error with code goar.ErrInvalidInput if chunks are missing,
+// see Missing, in which case the session stays open.
+func (s *Session) Complete() error {
+	missing := s.Missing()
+	if len(missing) > 0 {
+		return goar.Errorf(goar.ErrInvalidInput, "%d of %d chunks are missing, the first at offset %d", len(missing), len(s.received), missing[0])
+	}
+	s.mu.Lock()
+	s.complete = true
+	s.mu.Unlock()
+	return nil
+}
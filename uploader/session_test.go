@@ -0,0 +1,128 @@
+package uploader
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/signer"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSession(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	s, err := signer.FromPath("../test/signer.json")
+	require.NoError(t, err)
+
+	tx := transaction.New(data, "", "0", nil)
+	tx.Owner = s.Owner()
+	tx.LastTx = "anchor"
+	tx.Reward = "1000"
+	require.NoError(t, tx.Sign(s))
+
+	// The orchestrator only hands the header over, as JSON
+	b, err := json.Marshal(tx)
+	require.NoError(t, err)
+	var header transaction.Transaction
+	require.NoError(t, json.Unmarshal(b, &header))
+	header.Data = ""
+
+	var mu sync.Mutex
+	var posted []transaction.GetChunkResult
+	var headers []transaction.Transaction
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /tx", func(w http.ResponseWriter, r *http.Request) {
+		var tx transaction.Transaction
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&tx))
+		mu.Lock()
+		headers = append(headers, tx)
+		mu.Unlock()
+	})
+	mux.HandleFunc("POST /chunk", func(w http.ResponseWriter, r *http.Request) {
+		var chunk transaction.GetChunkResult
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&chunk))
+		mu.Lock()
+		posted = append(posted, chunk)
+		mu.Unlock()
+	})
+	server := httptest.NewServer(mux)
+	defer server.Close()
+	c := client.New(server.URL)
+
+	session, err := CreateSession(c, &header)
+	require.NoError(t, err)
+	require.Len(t, headers, 1)
+	assert.Equal(t, tx.ID, headers[0].ID)
+	assert.Empty(t, headers[0].Data)
+
+	chunks := tx.ChunkData.Chunks
+	require.Len(t, chunks, 4)
+	chunkAt := func(i int) (int64, []byte) {
+		return int64(chunks[i].MinByteRange), data[chunks[i].MinByteRange:chunks[i].MaxByteRange]
+	}
+
+	t.Run("Rejects invalid chunks", func(t *testing.T) {
+		offset, chunk := chunkAt(1)
+		assert.ErrorIs(t, session.AddChunk(offset+1, chunk), goar.ErrInvalidInput)
+		assert.ErrorIs(t, session.AddChunk(offset, chunk[1:]), goar.ErrInvalidInput)
+		tampered := append([]byte{}, chunk...)
+		tampered[0] ^= 1
+		assert.ErrorIs(t, session.AddChunk(offset, tampered), goar.ErrInvalidInput)
+		assert.Empty(t, posted)
+	})
+
+	// Out of order, with a redelivery
+	for _, i := range []int{3, 1, 1, 0} {
+		offset, chunk := chunkAt(i)
+		require.NoError(t, session.AddChunk(offset, chunk))
+	}
+	assert.Len(t, posted, 3)
+	offset, _ := chunkAt(2)
+	assert.Equal(t, []int64{offset}, session.Missing())
+	assert.ErrorIs(t, session.Complete(), goar.ErrInvalidInput)
+
+	offset, chunk := chunkAt(2)
+	require.NoError(t, session.AddChunk(offset, chunk))
+	assert.Empty(t, session.Missing())
+	require.NoError(t, session.Complete())
+	require.NoError(t, session.Complete())
+	assert.ErrorIs(t, session.AddChunk(offset, chunk), goar.ErrInvalidInput)
+
+	for _, result := range posted {
+		expected, err := tx.GetChunk(indexOf(t, tx, result.Offset), data)
+		require.NoError(t, err)
+		assert.Equal(t, *expected, result)
+	}
+
+	t.Run("Invalid transactions", func(t *testing.T) {
+		unprepared := transaction.New(nil, "", "0", nil)
+		_, err := CreateSession(c, unprepared)
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+
+		unsigned := header
+		unsigned.Reward = "1"
+		_, err = CreateSession(c, &unsigned)
+		assert.ErrorIs(t, err, goar.ErrInvalidSignature)
+		assert.Len(t, headers, 1)
+	})
+}
+
+// indexOf returns the index of the chunk whose proof ends at offset
+func indexOf(t *testing.T, tx *transaction.Transaction, offset string) int {
+	for i, proof := range tx.ChunkData.Proofs {
+		if strconv.Itoa(proof.Offset) == offset {
+			return i
+		}
+	}
+	t.Fatalf("no chunk ends at %s", offset)
+	return -1
+}
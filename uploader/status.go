@@ -0,0 +1,235 @@
+package uploader
+
+import (
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ChunkState describes where a single chunk is in its upload lifecycle.
+type ChunkState int
+
+// Chunk lifecycle states
+const (
+	ChunkPending ChunkState = iota // Not uploaded yet
+	ChunkPosted                    // Accepted by the gateway
+	ChunkMissing                   // Posted, but later confirmed missing on the gateway
+	ChunkFailed                    // Last upload attempt was rejected
+)
+
+// String returns a human-readable name for the chunk state.
+func (s ChunkState) String() string {
+	switch s {
+	case ChunkPending:
+		return "pending"
+	case ChunkPosted:
+		return "posted"
+	case ChunkMissing:
+		return "missing"
+	case ChunkFailed:
+		return "failed"
+	default:
+		return "unknown"
+	}
+}
+
+// ChunkStatus is the upload receipt of a single chunk.
+//
+// A ChunkStatus records the outcome of the latest upload attempt for the
+// chunk at Index, so that concurrent or out-of-order completion can be
+// represented rather than a single "next chunk" cursor.
+type ChunkStatus struct {
+	Index    int        `json:"index"`               // Index of the chunk within the transaction
+	State    ChunkState `json:"state"`               // Current lifecycle state
+	PostedAt time.Time  `json:"posted_at,omitempty"` // When the gateway accepted the chunk
+	Attempts int        `json:"attempts"`            // Number of upload attempts so far
+	Code     int        `json:"code,omitempty"`      // HTTP status code of the last attempt
+	Error    string     `json:"error,omitempty"`     // Error message of the last failed attempt
+}
+
+// chunkTracker keeps the status of every chunk of an upload.
+// It is safe for concurrent use.
+type chunkTracker struct {
+	mu       sync.Mutex
+	statuses []ChunkStatus
+	sizes    []int64 // Size of every chunk in bytes, nil if unknown
+	bytes    int64   // Bytes of the chunks in state ChunkPosted
+}
+
+// newChunkTracker creates a tracker with n pending chunks.
+func newChunkTracker(n int) *chunkTracker {
+	statuses := make([]ChunkStatus, n)
+	for i := range statuses {
+		statuses[i].Index = i
+	}
+	return &chunkTracker{statuses: statuses}
+}
+
+// size returns the size of chunk i in bytes, 0 if unknown
+func (ct *chunkTracker) size(i int) int64 {
+	if ct.sizes == nil {
+		return 0
+	}
+	return ct.sizes[i]
+}
+
+// postedBytes returns the number of bytes of the posted chunks.
+func (ct *chunkTracker) postedBytes() int64 {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return ct.bytes
+}
+
+// posted records a successful upload of chunk i.
+func (ct *chunkTracker) posted(i int, code int, at time.Time) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if i < 0 || i >= len(ct.statuses) {
+		return
+	}
+	s := &ct.statuses[i]
+	if s.State != ChunkPosted {
+		ct.bytes += ct.size(i)
+	}
+	s.State = ChunkPosted
+	s.PostedAt = at
+	s.Attempts++
+	s.Code = code
+	s.Error = ""
+}
+
+// failed records a rejected upload of chunk i.
+func (ct *chunkTracker) failed(i int, code int, err string) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if i < 0 || i >= len(ct.statuses) {
+		return
+	}
+	s := &ct.statuses[i]
+	if s.State == ChunkPosted {
+		ct.bytes -= ct.size(i)
+	}
+	s.State = ChunkFailed
+	s.Attempts++
+	s.Code = code
+	s.Error = err
+}
+
+// missing marks chunk i as confirmed missing on the gateway.
+func (ct *chunkTracker) missing(i int) {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	if i < 0 || i >= len(ct.statuses) {
+		return
+	}
+	if ct.statuses[i].State == ChunkPosted {
+		ct.bytes -= ct.size(i)
+	}
+	ct.statuses[i].State = ChunkMissing
+}
+
+// snapshot returns a copy of every chunk status.
+func (ct *chunkTracker) snapshot() []ChunkStatus {
+	ct.mu.Lock()
+	defer ct.mu.Unlock()
+	return append([]ChunkStatus(nil), ct.statuses...)
+}
+
+// tracker returns the chunk tracker, creating it once chunks are prepared.
+// It is safe for concurrent use.
+func (tu *TransactionUploader) tracker() *chunkTracker {
+	tu.chunksMu.Lock()
+	defer tu.chunksMu.Unlock()
+	if tu.chunks == nil && tu.transaction != nil && tu.transaction.ChunkData != nil {
+		chunks := tu.transaction.ChunkData.Chunks
+		tu.chunks = newChunkTracker(len(chunks))
+		tu.chunks.sizes = make([]int64, len(chunks))
+		for i, chunk := range chunks {
+			tu.chunks.sizes[i] = int64(chunk.MaxByteRange - chunk.MinByteRange)
+		}
+	}
+	return tu.chunks
+}
+
+// Snapshot returns the current status of every chunk of the upload.
+//
+// The returned slice is a copy and is safe to inspect while the upload
+// continues. It is empty until the transaction's chunks have been prepared.
+//
+// Example:
+//
+//	for _, s := range uploader.Snapshot() {
+//		fmt.Printf("chunk %d: %s (%d attempts)\n", s.Index, s.State, s.Attempts)
+//	}
+func (tu *TransactionUploader) Snapshot() []ChunkStatus {
+	ct := tu.tracker()
+	if ct == nil {
+		return []ChunkStatus{}
+	}
+	return ct.snapshot()
+}
+
+// MarkChunkMissing records that a previously posted chunk was confirmed
+// missing on the gateway, e.g. after an availability check.
+func (tu *TransactionUploader) MarkChunkMissing(chunkIndex int) {
+	if ct := tu.tracker(); ct != nil {
+		ct.missing(chunkIndex)
+	}
+}
+
+// ProgressFunc is called each time the gateway accepts a chunk of an upload, see TransactionUploader.OnProgress.
+//
+// uploadedBytes and totalBytes count the data of the accepted chunks and of
+// the whole transaction, chunkIndex is the chunk just accepted and
+// totalChunks the number of chunks of the transaction.
+type ProgressFunc func(uploadedBytes int64, totalBytes int64, chunkIndex int, totalChunks int)
+
+// reportProgress calls OnProgress after chunk i was accepted
+func (tu *TransactionUploader) reportProgress(ct *chunkTracker, i int) {
+	if tu.OnProgress == nil {
+		return
+	}
+	total, _ := strconv.ParseInt(tu.transaction.DataSize, 10, 64)
+	tu.progressMu.Lock()
+	defer tu.progressMu.Unlock()
+	tu.OnProgress(ct.postedBytes(), total, i, len(ct.statuses))
+}
+
+// Progress summarizes an upload.
+type Progress struct {
+	Total       int `json:"total"`       // Number of chunks in the transaction
+	Pending     int `json:"pending"`     // Chunks not uploaded yet
+	Posted      int `json:"posted"`      // Chunks accepted by the gateway
+	Missing     int `json:"missing"`     // Chunks confirmed missing after being posted
+	Failed      int `json:"failed"`      // Chunks whose last attempt was rejected
+	Concurrency int `json:"concurrency"` // Current parallel upload limit, 0 outside UploadChunks
+	InFlight    int `json:"in_flight"`   // Chunk uploads currently in progress
+}
+
+// Progress returns counts of chunks per state and the current upload concurrency.
+//
+// Example:
+//
+//	p := uploader.Progress()
+//	fmt.Printf("%d/%d chunks posted, %d parallel uploads\n", p.Posted, p.Total, p.Concurrency)
+func (tu *TransactionUploader) Progress() Progress {
+	var p Progress
+	for _, s := range tu.Snapshot() {
+		p.Total++
+		switch s.State {
+		case ChunkPending:
+			p.Pending++
+		case ChunkPosted:
+			p.Posted++
+		case ChunkMissing:
+			p.Missing++
+		case ChunkFailed:
+			p.Failed++
+		}
+	}
+	if ctl := tu.controller.Load(); ctl != nil {
+		p.Concurrency = ctl.Concurrency()
+		p.InFlight = ctl.InFlight()
+	}
+	return p
+}
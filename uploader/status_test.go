@@ -0,0 +1,155 @@
+package uploader
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/internal/retry"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestChunkState verifies chunk state names
+func TestChunkState(t *testing.T) {
+	assert.Equal(t, "pending", ChunkPending.String())
+	assert.Equal(t, "posted", ChunkPosted.String())
+	assert.Equal(t, "missing", ChunkMissing.String())
+	assert.Equal(t, "failed", ChunkFailed.String())
+	assert.Equal(t, "unknown", ChunkState(42).String())
+}
+
+// TestSnapshot verifies per-chunk status tracking during uploads
+func TestSnapshot(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+
+	var calls atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Reject the second chunk upload, accept everything else
+		if r.URL.Path == "/chunk" && calls.Add(1) == 2 {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte("invalid_proof"))
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tx := transaction.New(data, "", "0", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+
+	uploader, err := New(client.New(server.URL), tx)
+	require.NoError(t, err)
+	uploader.Data = data
+	uploader.TxPosted = true
+	uploader.clock = retry.NewFakeClock(time.Unix(100, 0))
+
+	snapshot := uploader.Snapshot()
+	require.Len(t, snapshot, len(tx.ChunkData.Chunks))
+	for i, s := range snapshot {
+		assert.Equal(t, i, s.Index)
+		assert.Equal(t, ChunkPending, s.State)
+	}
+
+	require.NoError(t, uploader.UploadChunk(2))
+	_ = uploader.UploadChunk(0)
+
+	snapshot = uploader.Snapshot()
+	assert.Equal(t, ChunkPosted, snapshot[2].State)
+	assert.Equal(t, 200, snapshot[2].Code)
+	assert.Equal(t, time.Unix(100, 0), snapshot[2].PostedAt)
+	assert.Equal(t, ChunkFailed, snapshot[0].State)
+	assert.Equal(t, 400, snapshot[0].Code)
+	assert.Equal(t, 1, snapshot[0].Attempts)
+	assert.Equal(t, ChunkPending, snapshot[1].State)
+
+	uploader.MarkChunkMissing(2)
+	assert.Equal(t, ChunkMissing, uploader.Snapshot()[2].State)
+
+	// Snapshots are copies
+	snapshot[1].State = ChunkPosted
+	assert.Equal(t, ChunkPending, uploader.Snapshot()[1].State)
+}
+
+// TestSnapshotWithoutChunks verifies snapshots before chunk preparation
+func TestSnapshotWithoutChunks(t *testing.T) {
+	uploader, err := New(client.New("http://localhost:1984"), &transaction.Transaction{})
+	require.NoError(t, err)
+	assert.Empty(t, uploader.Snapshot())
+	assert.NotPanics(t, func() { uploader.MarkChunkMissing(0) })
+}
+
+// TestSnapshotConcurrent verifies that the chunk tracker is created once when
+// it is first used from several goroutines, run it with -race
+func TestSnapshotConcurrent(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	tx := transaction.New(data, "", "0", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	uploader, err := New(client.New("http://localhost:1984"), tx)
+	require.NoError(t, err)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		uploader.MarkChunkMissing(0)
+	}()
+	go func() {
+		defer wg.Done()
+		_ = uploader.Snapshot()
+	}()
+	wg.Wait()
+	assert.Equal(t, ChunkMissing, uploader.Snapshot()[0].State)
+}
+
+// TestOnProgress verifies the progress callback of parallel and sequential chunk uploads
+func TestOnProgress(t *testing.T) {
+	data, err := os.ReadFile("../test/1MB.bin")
+	require.NoError(t, err)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	tx := transaction.New(data, "", "0", nil)
+	require.NoError(t, tx.PrepareChunks(data))
+	chunks := len(tx.ChunkData.Chunks)
+
+	uploader, err := New(client.New(server.URL), tx)
+	require.NoError(t, err)
+	uploader.Data = data
+	uploader.TxPosted = true
+
+	var uploaded []int64
+	seen := map[int]bool{}
+	uploader.OnProgress = func(uploadedBytes int64, totalBytes int64, chunkIndex int, totalChunks int) {
+		assert.Equal(t, int64(len(data)), totalBytes)
+		assert.Equal(t, chunks, totalChunks)
+		uploaded = append(uploaded, uploadedBytes)
+		seen[chunkIndex] = true
+	}
+
+	require.NoError(t, uploader.UploadChunk(0))
+	first := int64(tx.ChunkData.Chunks[0].MaxByteRange)
+	assert.Equal(t, []int64{first}, uploaded)
+
+	require.NoError(t, uploader.UploadChunks(NewController(1, 4)))
+	require.Len(t, uploaded, chunks)
+	assert.Len(t, seen, chunks)
+	assert.IsIncreasing(t, uploaded)
+	assert.Equal(t, int64(len(data)), uploaded[chunks-1])
+
+	// A missing chunk is uploaded again
+	uploader.MarkChunkMissing(0)
+	assert.Equal(t, int64(len(data))-first, uploader.tracker().postedBytes())
+	require.NoError(t, uploader.UploadChunks(nil))
+	assert.Equal(t, int64(len(data)), uploaded[len(uploaded)-1])
+}
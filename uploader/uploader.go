@@ -29,14 +29,21 @@
 package uploader
 
 import (
+	"bytes"
+	"context"
 	"errors"
 	"fmt"
+	"io"
 	"math"
-	"math/rand"
 	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
+	"github.com/liteseed/goar"
 	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/internal/retry"
 	"github.com/liteseed/goar/transaction"
 )
 
@@ -44,6 +51,7 @@ import (
 const (
 	MAX_CHUNKS_IN_BODY = 1     // Maximum number of chunks to include in transaction body
 	DELAY              = 30000 // Base delay in milliseconds for retry logic
+	MAX_UPLOAD_ERRORS  = 100   // Failed chunk requests tolerated by an upload when MaxErrors is 0
 )
 
 // FATAL_CHUNK_UPLOAD_ERRORS lists errors that should not be retried.
@@ -70,11 +78,21 @@ type TransactionUploader struct {
 	ChunkIndex         int                      // Index of the next chunk to upload
 	TxPosted           bool                     // Whether the transaction header has been posted
 	Data               []byte                   // Raw transaction data (for chunk generation)
+	Source             io.ReaderAt              // Optional source chunks are read from instead of Data, e.g. the file the chunks were prepared from
 	LastRequestTimeEnd int64                    // Timestamp of last request completion
 	TotalErrors        int                      // Running count of upload errors (not serialized)
 	LastResponseStatus int                      // HTTP status code from last request
 	LastResponseError  string                   // Error message from last failed request
 	TotalChunks        int                      // Total number of chunks in this transaction
+	Mirror             Mirror                   // Optional local copy of every chunk accepted by the gateway, see DirMirror
+	MaxErrors          int                      // Failed chunk requests tolerated before the upload gives up, MAX_UPLOAD_ERRORS if 0
+	OnProgress         ProgressFunc             // Optional callback called each time a chunk is accepted, never concurrently
+
+	chunks     *chunkTracker              // Per-chunk upload status (not serialized)
+	chunksMu   sync.Mutex                 // Guards the creation of chunks
+	controller atomic.Pointer[Controller] // Concurrency controller of the running UploadChunks (not serialized)
+	clock      retry.Clock                // Time source of retry delays, retry.SystemClock if nil
+	progressMu sync.Mutex                 // Serializes calls to OnProgress
 }
 
 // New creates a new TransactionUploader for the given transaction.
@@ -119,7 +137,8 @@ func New(c *client.Client, t *transaction.Transaction) (*TransactionUploader, er
 // and data chunks must be uploaded separately using UploadChunk.
 //
 // The method automatically determines the upload strategy based on the
-// MAX_CHUNKS_IN_BODY constant.
+// MAX_CHUNKS_IN_BODY constant. Transactions with a DataSource, see
+// transaction.NewDataTransactionFromReader, are always posted header only.
 //
 // Returns an error if the transaction submission fails.
 //
@@ -134,12 +153,18 @@ func New(c *client.Client, t *transaction.Transaction) (*TransactionUploader, er
 //		fmt.Println("Transaction posted successfully")
 //	}
 func (tu *TransactionUploader) PostTransaction() error {
-	if tu.TotalChunks <= MAX_CHUNKS_IN_BODY {
-		code, err := tu.client.SubmitTransaction(tu.transaction)
+	return tu.PostTransactionContext(context.Background())
+}
+
+// PostTransactionContext is PostTransaction bound to ctx.
+func (tu *TransactionUploader) PostTransactionContext(ctx context.Context) error {
+	// Data kept outside the transaction is always uploaded in chunks
+	if tu.TotalChunks <= MAX_CHUNKS_IN_BODY && tu.transaction.DataSource == nil {
+		code, err := tu.client.SubmitTransactionContext(ctx, tu.transaction)
 		if err != nil {
 			return err
 		}
-		tu.LastRequestTimeEnd = time.Now().UTC().UnixMilli()
+		tu.LastRequestTimeEnd = tu.client.Now().UnixMilli()
 		tu.LastResponseStatus = code
 		if code >= 200 && code < 400 {
 			tu.TxPosted = true
@@ -150,11 +175,11 @@ func (tu *TransactionUploader) PostTransaction() error {
 		// Post transaction with no data
 		t := tu.transaction
 		t.Data = ""
-		code, err := tu.client.SubmitTransaction(t)
+		code, err := tu.client.SubmitTransactionContext(ctx, t)
 		if err != nil {
 			return err
 		}
-		tu.LastRequestTimeEnd = time.Now().UTC().UnixMilli()
+		tu.LastRequestTimeEnd = tu.client.Now().UnixMilli()
 		tu.LastResponseStatus = code
 		if code >= 200 && code < 300 {
 			tu.TxPosted = true
@@ -206,42 +231,255 @@ func (tu *TransactionUploader) UploadChunk(chunkIndex int) error {
 		tu.TotalErrors = 0
 	}
 
-	if tu.TotalErrors == 100 {
+	if tu.TotalErrors >= tu.maxErrors() {
 		return fmt.Errorf("fatal: unable to complete upload: %d: %s", tu.LastResponseStatus, tu.LastResponseError)
 	}
 
 	var delay = 0.0
 	if tu.LastResponseError != "" {
-		delay = DELAY + math.Max(0, float64(tu.LastRequestTimeEnd)-float64(time.Now().UTC().UnixMilli()))
+		delay = DELAY + math.Max(0, float64(tu.LastRequestTimeEnd)-float64(tu.client.Now().UnixMilli()))
 	}
 
-	if delay > 0 {
-		delay = delay - delay*0.3*rand.Float64()
-		time.Sleep(time.Duration(delay) * time.Millisecond)
-	}
+	retry.Sleep(tu.getClock(), retry.Jitter(time.Duration(delay)*time.Millisecond, 0.3), nil)
+	tu.waitCircuit(nil)
 
 	if !tu.TxPosted {
 		return tu.PostTransaction()
 	}
 
-	chunk, err := tu.transaction.GetChunk(chunkIndex, tu.Data)
+	chunk, err := tu.chunk(chunkIndex)
 	if err != nil {
 		return err
 	}
 
 	code, err := tu.client.UploadChunk(chunk)
-	tu.LastRequestTimeEnd = time.Hour.Milliseconds()
+	tu.LastRequestTimeEnd = tu.client.Now().UnixMilli()
 	tu.LastResponseStatus = code
 
 	if tu.LastResponseStatus == 200 {
 		tu.ChunkIndex++
+		tu.tracker().posted(chunkIndex, code, tu.getClock().Now())
+		tu.reportProgress(tu.tracker(), chunkIndex)
+		return tu.mirror(chunk)
 	} else {
 		if err != nil {
 			tu.LastResponseError = err.Error()
 		}
-		if slices.Contains(FATAL_CHUNK_UPLOAD_ERRORS, tu.LastResponseError) {
-			return fmt.Errorf("fatal: unable to complete upload: %d: %s", tu.LastResponseStatus, tu.LastResponseError)
+		tu.tracker().failed(chunkIndex, code, tu.LastResponseError)
+		if isFatalChunkError(tu.LastResponseError) {
+			return goar.Wrap(goar.ErrChunkRejected, fmt.Errorf("fatal: unable to complete upload: %d: %s", tu.LastResponseStatus, tu.LastResponseError))
+		}
+	}
+	return nil
+}
+
+// UploadChunks uploads every chunk that has not been posted yet, in parallel.
+//
+// The number of parallel uploads is driven by ctl, which grows it while the
+// gateway accepts chunks and backs off on throttling, so the upload converges
+// to the gateway's sustainable throughput. Failed chunks are retried up to
+// MAX_CHUNK_ATTEMPTS times, and the upload stops after MaxErrors failed
+// requests in total or a fatal rejection. Progress can be observed
+// concurrently with Progress and Snapshot.
+//
+// With a client created with client.NewPool, failing gateways have their
+// circuit opened and chunks go to the others; while every circuit is open,
+// uploads wait for the first one to close instead of failing.
+//
+// The transaction header is posted first if needed.
+//
+// Parameters:
+//   - ctl: The concurrency controller, or nil for one bounded by
+//     DEFAULT_MIN_CONCURRENCY and the client's MaxStreams, or
+//     DEFAULT_MAX_CONCURRENCY if the client has no stream limit
+//
+// Returns an error if the header cannot be posted, a chunk is rejected
+// permanently or a chunk runs out of attempts.
+//
+// Example:
+//
+//	uploader.Data = data
+//	err := uploader.UploadChunks(NewController(1, 16))
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+func (tu *TransactionUploader) UploadChunks(ctl *Controller) error {
+	return tu.UploadChunksContext(context.Background(), ctl)
+}
+
+// UploadChunksContext is UploadChunks bound to ctx: no chunk is posted once
+// ctx is done, and the error of ctx is returned.
+func (tu *TransactionUploader) UploadChunksContext(ctx context.Context, ctl *Controller) error {
+	if tu.transaction.ChunkData == nil {
+		return errors.New("chunks have not been prepared")
+	}
+	if ctl == nil {
+		maximum := DEFAULT_MAX_CONCURRENCY
+		if streams := tu.client.MaxStreams(); streams > 0 {
+			maximum = streams
+		}
+		ctl = NewController(DEFAULT_MIN_CONCURRENCY, maximum)
+	}
+	if !tu.TxPosted {
+		if err := tu.PostTransactionContext(ctx); err != nil {
+			return err
+		}
+		if !tu.TxPosted {
+			return fmt.Errorf("unable to post transaction: %d", tu.LastResponseStatus)
+		}
+	}
+
+	tu.controller.Store(ctl)
+	ct := tu.tracker()
+
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+		failures atomic.Int64
+	)
+	pending := make(chan int)
+	done := make(chan struct{})
+	stop := func(err error) {
+		once.Do(func() {
+			firstErr = err
+			close(done)
+		})
+	}
+	for w := 0; w < ctl.Max; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range pending {
+				if err := tu.uploadChunkWithRetry(ctx, ctl, ct, i, &failures, done); err != nil {
+					stop(err)
+				}
+			}
+		}()
+	}
+	defer context.AfterFunc(ctx, func() { stop(goar.Wrap(goar.ErrNetwork, ctx.Err())) })()
+
+feed:
+	for _, s := range ct.snapshot() {
+		if s.State == ChunkPosted {
+			continue
+		}
+		select {
+		case pending <- s.Index:
+		case <-done:
+			break feed
 		}
 	}
+	close(pending)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		stop(goar.Wrap(goar.ErrNetwork, err))
+	}
+	if firstErr != nil {
+		return firstErr
+	}
+	tu.ChunkIndex = len(tu.transaction.ChunkData.Chunks)
 	return nil
 }
+
+// uploadChunkWithRetry uploads chunk i until it is accepted, retrying with a linear backoff.
+// Failed attempts are counted in failures, shared by the chunks of the upload.
+// It returns early without error when done is closed.
+func (tu *TransactionUploader) uploadChunkWithRetry(ctx context.Context, ctl *Controller, ct *chunkTracker, i int, failures *atomic.Int64, done <-chan struct{}) error {
+	chunk, err := tu.chunk(i)
+	if err != nil {
+		return err
+	}
+
+	for attempt := 1; ; attempt++ {
+		if !tu.waitCircuit(done) {
+			return nil
+		}
+		ctl.Acquire()
+		code, err := tu.client.UploadChunkContext(ctx, chunk)
+		ctl.Release(code, err)
+		if ctx.Err() != nil {
+			return nil
+		}
+
+		if err == nil && code == 200 {
+			ct.posted(i, code, tu.getClock().Now())
+			tu.reportProgress(ct, i)
+			return tu.mirror(chunk)
+		}
+		message := fmt.Sprint(code)
+		if err != nil {
+			message = err.Error()
+		}
+		ct.failed(i, code, message)
+		if isFatalChunkError(message) {
+			return goar.Wrap(goar.ErrChunkRejected, fmt.Errorf("fatal: unable to complete upload: chunk %d: %s", i, message))
+		}
+		if attempt >= MAX_CHUNK_ATTEMPTS {
+			return goar.Wrap(goar.CodeOf(err), fmt.Errorf("unable to upload chunk %d after %d attempts: %s", i, attempt, message))
+		}
+		if n := failures.Add(1); n >= int64(tu.maxErrors()) {
+			return goar.Wrap(goar.CodeOf(err), fmt.Errorf("unable to complete upload after %d failed chunk requests: chunk %d: %s", n, i, message))
+		}
+
+		if !retry.Sleep(tu.getClock(), retry.Backoff{Base: ctl.RetryDelay}.Delay(attempt), done) {
+			return nil
+		}
+	}
+}
+
+// waitCircuit waits until a gateway of the client accepts requests when
+// every circuit of its pool is open, see client.CircuitWait, so that no
+// error is counted while they cool down. It returns false if done is closed first.
+func (tu *TransactionUploader) waitCircuit(done <-chan struct{}) bool {
+	for {
+		wait := tu.client.CircuitWait()
+		if wait <= 0 {
+			return true
+		}
+		if !retry.Sleep(tu.getClock(), wait, done) {
+			return false
+		}
+	}
+}
+
+// maxErrors returns the number of failed chunk requests tolerated by the upload
+func (tu *TransactionUploader) maxErrors() int {
+	if tu.MaxErrors <= 0 {
+		return MAX_UPLOAD_ERRORS
+	}
+	return tu.MaxErrors
+}
+
+// isFatalChunkError reports whether a chunk upload error message contains one of FATAL_CHUNK_UPLOAD_ERRORS.
+// Gateway errors are prefixed with the HTTP status code, e.g. "400: invalid_proof".
+func isFatalChunkError(message string) bool {
+	return slices.ContainsFunc(FATAL_CHUNK_UPLOAD_ERRORS, func(e string) bool {
+		return strings.Contains(message, e)
+	})
+}
+
+// chunk reads chunk i from Source, the transaction's DataSource or Data, in that order
+func (tu *TransactionUploader) chunk(i int) (*transaction.GetChunkResult, error) {
+	src := tu.Source
+	if src == nil {
+		src = tu.transaction.DataSource
+	}
+	if src == nil {
+		src = bytes.NewReader(tu.Data)
+	}
+	info, err := tu.transaction.ChunkAt(i, src)
+	if err != nil {
+		return nil, err
+	}
+	return info.Result()
+}
+
+// getClock returns the clock retry delays are waited on
+func (tu *TransactionUploader) getClock() retry.Clock {
+	if tu.clock == nil {
+		return retry.SystemClock
+	}
+	return tu.clock
+}
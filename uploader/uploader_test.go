@@ -173,3 +173,11 @@ func TestPostTransactionSmall(t *testing.T) {
 	assert.NotPanics(t, func() { uploader.PostTransaction() })
 }
 */
+
+// TestIsFatalChunkError verifies fatal errors are detected in gateway responses
+func TestIsFatalChunkError(t *testing.T) {
+	assert.True(t, isFatalChunkError("invalid_proof"))
+	assert.True(t, isFatalChunkError("400: invalid_proof"))
+	assert.False(t, isFatalChunkError("503: Service Unavailable"))
+	assert.False(t, isFatalChunkError(""))
+}
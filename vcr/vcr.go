@@ -0,0 +1,273 @@
+// Package vcr records and replays the HTTP interactions of a client.
+//
+// A Recorder is an http.RoundTripper. In record mode it forwards requests to
+// a live gateway (e.g. arlocal) and stores every interaction in a cassette,
+// a JSON golden file. In replay mode it serves responses from the cassette
+// without any network access, and fails requests whose method, path or body
+// no longer match what was recorded, so changes in request shapes show up as
+// test failures.
+//
+// Example usage:
+//
+//	func TestSomething(t *testing.T) {
+//		r, err := vcr.New("testdata/something.json", vcr.ModeFromEnv())
+//		require.NoError(t, err)
+//		defer func() { require.NoError(t, r.Stop()) }()
+//
+//		c := r.Client("http://localhost:1984")
+//		info, err := c.GetNetworkInfo()
+//		...
+//	}
+//
+// Run the tests with GOAR_VCR=record against a live gateway to refresh the
+// cassettes.
+package vcr
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync"
+	"unicode/utf8"
+
+	"github.com/liteseed/goar/client"
+)
+
+// ENV_MODE is the environment variable read by ModeFromEnv
+const ENV_MODE = "GOAR_VCR"
+
+// Mode selects whether a Recorder talks to the network.
+type Mode int
+
+// Recorder modes
+const (
+	ModeReplay Mode = iota // Serve responses from the cassette
+	ModeRecord             // Forward requests and record them into the cassette
+)
+
+// ModeFromEnv returns ModeRecord if GOAR_VCR is set to "record", and ModeReplay otherwise.
+func ModeFromEnv() Mode {
+	if os.Getenv(ENV_MODE) == "record" {
+		return ModeRecord
+	}
+	return ModeReplay
+}
+
+// Body is an HTTP body stored in a cassette.
+//
+// It is written as a JSON string when it is valid UTF-8, which keeps
+// cassettes readable in reviews, and as {"base64": "..."} otherwise.
+type Body []byte
+
+// MarshalJSON encodes the body as a string or as base64.
+func (b Body) MarshalJSON() ([]byte, error) {
+	if utf8.Valid(b) {
+		return json.Marshal(string(b))
+	}
+	return json.Marshal(map[string]string{"base64": base64.StdEncoding.EncodeToString(b)})
+}
+
+// UnmarshalJSON decodes a body written by MarshalJSON.
+func (b *Body) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err == nil {
+		*b = Body(s)
+		return nil
+	}
+	var encoded map[string]string
+	if err := json.Unmarshal(data, &encoded); err != nil {
+		return err
+	}
+	raw, err := base64.StdEncoding.DecodeString(encoded["base64"])
+	if err != nil {
+		return err
+	}
+	*b = raw
+	return nil
+}
+
+// Interaction is one recorded request and its response.
+type Interaction struct {
+	Method       string `json:"method"`                  // HTTP method
+	URL          string `json:"url"`                     // Path and query, without the gateway host
+	RequestBody  Body   `json:"request_body,omitempty"`  // Request payload
+	Status       int    `json:"status"`                  // Response status code
+	ContentType  string `json:"content_type,omitempty"`  // Response Content-Type header
+	ResponseBody Body   `json:"response_body,omitempty"` // Response payload
+}
+
+// Cassette is the golden file content: every interaction in the order it happened.
+type Cassette struct {
+	Interactions []Interaction `json:"interactions"`
+}
+
+// Matcher reports why a request does not match a recorded interaction, or nil if it does.
+type Matcher func(recorded *Interaction, method string, url string, body []byte) error
+
+// DefaultMatcher requires the method, path and query, and body to be identical.
+func DefaultMatcher(recorded *Interaction, method string, url string, body []byte) error {
+	if recorded.Method != method || recorded.URL != url {
+		return fmt.Errorf("expected %s %s, got %s %s", recorded.Method, recorded.URL, method, url)
+	}
+	if !bytes.Equal(recorded.RequestBody, body) {
+		return fmt.Errorf("%s %s: request body differs from the recording:\nrecorded: %s\nactual:   %s", method, url, recorded.RequestBody, body)
+	}
+	return nil
+}
+
+// Recorder is an http.RoundTripper that records or replays a cassette.
+// It is safe for concurrent use.
+type Recorder struct {
+	Mode      Mode              // Record or replay
+	Path      string            // File the cassette is read from and written to
+	Transport http.RoundTripper // Transport used in record mode, http.DefaultTransport if nil
+	Matcher   Matcher           // Request matcher used in replay mode, DefaultMatcher if nil
+
+	mu       sync.Mutex
+	cassette Cassette
+	next     int // Index of the next interaction to replay
+}
+
+// New creates a Recorder for the cassette at path.
+//
+// In replay mode the cassette is loaded immediately and must exist. In
+// record mode the cassette starts empty and is written by Stop.
+func New(path string, mode Mode) (*Recorder, error) {
+	r := &Recorder{Mode: mode, Path: path}
+	if mode == ModeReplay {
+		b, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(b, &r.cassette); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return r, nil
+}
+
+// Client returns a goar client for gateway whose requests go through the recorder.
+//
+// In replay mode the gateway is never contacted, but it is still used to
+// build request URLs.
+func (r *Recorder) Client(gateway string) *client.Client {
+	c := client.New(gateway)
+	c.Client.Transport = r
+	return c
+}
+
+// RoundTrip records or replays a single request.
+func (r *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = io.ReadAll(req.Body)
+		if err != nil {
+			return nil, err
+		}
+		_ = req.Body.Close()
+		req.Body = io.NopCloser(bytes.NewReader(body))
+	}
+
+	if r.Mode == ModeRecord {
+		return r.record(req, body)
+	}
+	return r.replay(req, body)
+}
+
+func (r *Recorder) record(req *http.Request, body []byte) (*http.Response, error) {
+	transport := r.Transport
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	resp, err := transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	responseBody, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	r.mu.Lock()
+	r.cassette.Interactions = append(r.cassette.Interactions, Interaction{
+		Method:       req.Method,
+		URL:          req.URL.RequestURI(),
+		RequestBody:  body,
+		Status:       resp.StatusCode,
+		ContentType:  resp.Header.Get("Content-Type"),
+		ResponseBody: responseBody,
+	})
+	r.mu.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(responseBody))
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, body []byte) (*http.Response, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	url := req.URL.RequestURI()
+	if r.next >= len(r.cassette.Interactions) {
+		return nil, fmt.Errorf("vcr: unexpected request %s %s: cassette %s is exhausted", req.Method, url, r.Path)
+	}
+	matcher := r.Matcher
+	if matcher == nil {
+		matcher = DefaultMatcher
+	}
+	recorded := &r.cassette.Interactions[r.next]
+	if err := matcher(recorded, req.Method, url, body); err != nil {
+		return nil, fmt.Errorf("vcr: interaction %d of %s: %w", r.next, r.Path, err)
+	}
+	r.next++
+
+	header := http.Header{}
+	if recorded.ContentType != "" {
+		header.Set("Content-Type", recorded.ContentType)
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", recorded.Status, http.StatusText(recorded.Status)),
+		StatusCode:    recorded.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          io.NopCloser(bytes.NewReader(recorded.ResponseBody)),
+		ContentLength: int64(len(recorded.ResponseBody)),
+		Request:       req,
+	}, nil
+}
+
+// Stop finishes the session.
+//
+// In record mode it writes the cassette to Path, creating parent
+// directories as needed. In replay mode it returns an error if some
+// recorded interactions were never requested.
+func (r *Recorder) Stop() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if r.Mode == ModeReplay {
+		if r.next < len(r.cassette.Interactions) {
+			i := r.cassette.Interactions[r.next]
+			return fmt.Errorf("vcr: %d recorded interactions were not replayed, starting with %s %s", len(r.cassette.Interactions)-r.next, i.Method, i.URL)
+		}
+		return nil
+	}
+
+	b, err := json.MarshalIndent(r.cassette, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(r.Path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.Path, append(b, '\n'), 0o644)
+}
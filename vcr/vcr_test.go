@@ -0,0 +1,129 @@
+package vcr
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newGateway serves a minimal subset of the gateway API
+func newGateway() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/info":
+			w.Header().Set("Content-Type", "application/json")
+			fmt.Fprint(w, `{"network":"arweave.localnet","height":42,"current":"abc"}`)
+		case "/tx_anchor":
+			fmt.Fprint(w, "anchor")
+		case "/raw":
+			_, _ = w.Write([]byte{0xff, 0x00, 0xfe})
+		case "/chunk":
+			w.WriteHeader(http.StatusBadRequest)
+			fmt.Fprint(w, "invalid_proof")
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+}
+
+// TestRecordReplay verifies that recorded interactions replay without the network
+func TestRecordReplay(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cassette.json")
+	gateway := newGateway()
+
+	r, err := New(path, ModeRecord)
+	require.NoError(t, err)
+	c := r.Client(gateway.URL)
+
+	info, err := c.GetNetworkInfo()
+	require.NoError(t, err)
+	data, err := c.GetTransactionData("raw")
+	require.NoError(t, err)
+	_, err = c.UploadChunk(&transaction.GetChunkResult{Offset: "0"})
+	require.Error(t, err)
+	require.NoError(t, r.Stop())
+	gateway.Close()
+
+	t.Run("Replay", func(t *testing.T) {
+		r, err := New(path, ModeReplay)
+		require.NoError(t, err)
+		c := r.Client("http://unreachable.invalid")
+
+		replayed, err := c.GetNetworkInfo()
+		require.NoError(t, err)
+		assert.Equal(t, info, replayed)
+
+		raw, err := c.GetTransactionData("raw")
+		require.NoError(t, err)
+		assert.Equal(t, data, raw)
+
+		_, err = c.UploadChunk(&transaction.GetChunkResult{Offset: "0"})
+		assert.EqualError(t, err, "400: invalid_proof")
+		assert.NoError(t, r.Stop())
+	})
+
+	t.Run("Request shape mismatch", func(t *testing.T) {
+		r, err := New(path, ModeReplay)
+		require.NoError(t, err)
+		c := r.Client("http://unreachable.invalid")
+
+		_, err = c.GetTransactionAnchor()
+		assert.ErrorContains(t, err, "expected GET /info, got GET /tx_anchor")
+	})
+
+	t.Run("Body mismatch", func(t *testing.T) {
+		r, err := New(path, ModeReplay)
+		require.NoError(t, err)
+		c := r.Client("http://unreachable.invalid")
+
+		_, err = c.GetNetworkInfo()
+		require.NoError(t, err)
+		_, err = c.GetTransactionData("raw")
+		require.NoError(t, err)
+		_, err = c.UploadChunk(&transaction.GetChunkResult{Offset: "1"})
+		assert.ErrorContains(t, err, "request body differs")
+	})
+
+	t.Run("Unplayed interactions", func(t *testing.T) {
+		r, err := New(path, ModeReplay)
+		require.NoError(t, err)
+		_, err = r.Client("http://unreachable.invalid").GetNetworkInfo()
+		require.NoError(t, err)
+		assert.ErrorContains(t, r.Stop(), "2 recorded interactions were not replayed")
+	})
+
+	t.Run("Missing cassette", func(t *testing.T) {
+		_, err := New(filepath.Join(t.TempDir(), "missing.json"), ModeReplay)
+		assert.Error(t, err)
+	})
+}
+
+// TestGolden replays the committed cassette in testdata
+func TestGolden(t *testing.T) {
+	r, err := New("testdata/client.json", ModeReplay)
+	require.NoError(t, err)
+	c := r.Client("http://localhost:1984")
+
+	info, err := c.GetNetworkInfo()
+	require.NoError(t, err)
+	assert.Equal(t, int64(42), info.Height)
+
+	anchor, err := c.GetTransactionAnchor()
+	require.NoError(t, err)
+	assert.Equal(t, "anchor", anchor)
+
+	assert.NoError(t, r.Stop())
+}
+
+func TestModeFromEnv(t *testing.T) {
+	t.Setenv(ENV_MODE, "record")
+	assert.Equal(t, ModeRecord, ModeFromEnv())
+	t.Setenv(ENV_MODE, "")
+	assert.Equal(t, ModeReplay, ModeFromEnv())
+}
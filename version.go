@@ -0,0 +1,7 @@
+package goar
+
+// Version is the semantic version of this release of goar.
+//
+// Releases follow the rules of COMPATIBILITY.md: within major version 1,
+// the stable packages only change in backward compatible ways.
+const Version = "1.0.0"
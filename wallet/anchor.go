@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"crypto/rand"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// AnchorMode is how SignDataItem anchors the data items that have no anchor.
+//
+// ED25519 and Ethereum signatures are deterministic: two data items with
+// the same owner, data, tags, target and anchor then have the same
+// signature and ID, so a bundler cannot tell a new upload of identical
+// content from a replay of an earlier one. An anchor makes every item
+// unique, whatever the signer.
+//
+// Random anchors are free and unique, but say nothing about when the item
+// was signed. The transaction anchor of the gateway also dates the item to
+// a recent block, but costs a request per item and makes items signed in
+// the same block with identical contents collide. No anchor keeps IDs
+// deterministic, which content-addressed workflows may rely on.
+type AnchorMode string
+
+// Anchor modes
+const (
+	AnchorRandom AnchorMode = ""     // 32 random bytes, the default
+	AnchorTx     AnchorMode = "tx"   // The gateway transaction anchor, truncated to 32 bytes
+	AnchorNone   AnchorMode = "none" // No anchor
+)
+
+// anchorItem sets the anchor of di following w.ItemAnchors, unless it has one
+func (w *Wallet) anchorItem(di *data_item.DataItem) error {
+	if di.Anchor != "" {
+		return nil
+	}
+	var anchor goar.Anchor
+	switch w.ItemAnchors {
+	case AnchorRandom:
+		if _, err := rand.Read(anchor[:]); err != nil {
+			return err
+		}
+	case AnchorTx:
+		txAnchor, err := w.Client.GetTransactionAnchor()
+		if err != nil {
+			return err
+		}
+		b, err := crypto.Base64URLDecode(txAnchor)
+		if err != nil || len(b) < len(anchor) {
+			return goar.Errorf(goar.ErrDecode, "invalid transaction anchor %q", txAnchor)
+		}
+		copy(anchor[:], b)
+	case AnchorNone:
+		return nil
+	default:
+		return goar.Errorf(goar.ErrInvalidInput, "unknown anchor mode %q", w.ItemAnchors)
+	}
+	di.SetAnchor(anchor)
+	return nil
+}
@@ -0,0 +1,68 @@
+package wallet
+
+import (
+	"bytes"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestItemAnchors(t *testing.T) {
+	blockHash := bytes.Repeat([]byte{7}, 48)
+	txAnchor := crypto.Base64URLEncode(blockHash)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(txAnchor))
+	}))
+	defer server.Close()
+	w, err := FromPath("../test/signer.json", server.URL)
+	require.NoError(t, err)
+
+	sign := func(t *testing.T, mode AnchorMode) *data_item.DataItem {
+		w.ItemAnchors = mode
+		item, err := w.SignDataItem(w.CreateDataItem([]byte("hello"), "", "", nil))
+		require.NoError(t, err)
+		require.NoError(t, item.Verify())
+		return item
+	}
+
+	t.Run("Random", func(t *testing.T) {
+		a, b := sign(t, AnchorRandom), sign(t, AnchorRandom)
+		assert.Len(t, a.Anchor, 32)
+		assert.NotEqual(t, a.Anchor, b.Anchor)
+		assert.NotEqual(t, a.ID, b.ID)
+	})
+
+	t.Run("Transaction anchor", func(t *testing.T) {
+		item := sign(t, AnchorTx)
+		assert.Equal(t, string(blockHash[:32]), item.Anchor)
+	})
+
+	t.Run("None", func(t *testing.T) {
+		assert.Empty(t, sign(t, AnchorNone).Anchor)
+	})
+
+	t.Run("Caller anchor", func(t *testing.T) {
+		w.ItemAnchors = AnchorTx
+		anchor := string(bytes.Repeat([]byte{1}, 32))
+		item, err := w.SignDataItem(w.CreateDataItem([]byte("hello"), "", anchor, nil))
+		require.NoError(t, err)
+		assert.Equal(t, anchor, item.Anchor)
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		w.ItemAnchors = "block"
+		_, err := w.SignDataItem(w.CreateDataItem([]byte("hello"), "", "", nil))
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+
+		txAnchor = "c2hvcnQ"
+		w.ItemAnchors = AnchorTx
+		_, err = w.SignDataItem(w.CreateDataItem([]byte("hello"), "", "", nil))
+		assert.ErrorIs(t, err, goar.ErrDecode)
+	})
+}
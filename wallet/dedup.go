@@ -0,0 +1,50 @@
+package wallet
+
+import (
+	"errors"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/dedup"
+	"github.com/liteseed/goar/tag"
+)
+
+// UploadOnce uploads data in a transaction unless this wallet already uploaded it.
+//
+// The transaction carries a dedup.Tag holding the SHA-256 of data. Before
+// uploading, the gateway is queried for a transaction or data item of this
+// wallet with the same digest; if one exists its ID is returned and nothing
+// is paid for. Uploads made without the tag are not detected.
+//
+// Parameters:
+//   - data: The data to upload
+//   - tags: Optional metadata tags, the digest tag is added to them
+//
+// Returns the ID of the existing or new transaction, and whether a new
+// transaction was sent, or an error if the query, signing or upload fails.
+//
+// Example:
+//
+//	id, uploaded, err := wallet.UploadOnce(data, nil)
+func (w *Wallet) UploadOnce(data []byte, tags *[]tag.Tag) (string, bool, error) {
+	digest := dedup.Tag(data)
+	id, err := dedup.Find(w.Client, digest.Value, []string{w.Signer.Address})
+	if err == nil {
+		return id, false, nil
+	}
+	if !errors.Is(err, goar.ErrNotFound) {
+		return "", false, err
+	}
+
+	all := []tag.Tag{digest}
+	if tags != nil {
+		all = append(append([]tag.Tag{}, *tags...), digest)
+	}
+	tx, err := w.SignTransaction(w.CreateTransaction(data, "", "0", &all))
+	if err != nil {
+		return "", false, err
+	}
+	if err = w.SendTransaction(tx); err != nil {
+		return "", false, err
+	}
+	return tx.ID, true, nil
+}
@@ -0,0 +1,78 @@
+package wallet
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/liteseed/goar/dedup"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestUploadOnce uploads the same data twice to a gateway answering digest queries from its posted transactions
+func TestUploadOnce(t *testing.T) {
+	var mu sync.Mutex
+	var posted []*transaction.Transaction
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		switch {
+		case r.URL.Path == "/tx_anchor":
+			_, _ = w.Write([]byte("anchor"))
+		case strings.HasPrefix(r.URL.Path, "/price/"):
+			_, _ = w.Write([]byte("100"))
+		case r.URL.Path == "/graphql":
+			var req struct {
+				Variables struct {
+					Digest string `json:"digest"`
+				} `json:"variables"`
+			}
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+			for _, tx := range posted {
+				for _, tg := range decodeTags(tx.Tags) {
+					if tg.Name == dedup.TAG_NAME && tg.Value == req.Variables.Digest {
+						_, _ = w.Write([]byte(`{"data": {"transactions": {"edges": [{"node": {"id": "` + tx.ID + `"}}]}}}`))
+						return
+					}
+				}
+			}
+			_, _ = w.Write([]byte(`{"data": {"transactions": {"edges": []}}}`))
+		case r.Method == http.MethodPost && r.URL.Path == "/tx":
+			var tx transaction.Transaction
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&tx))
+			posted = append(posted, &tx)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer gateway.Close()
+
+	w, err := FromPath("../test/signer.json", gateway.URL)
+	require.NoError(t, err)
+
+	tags := []tag.Tag{{Name: "Content-Type", Value: "text/plain"}}
+	id, uploaded, err := w.UploadOnce([]byte("hello"), &tags)
+	require.NoError(t, err)
+	assert.True(t, uploaded)
+	require.Len(t, posted, 1)
+	assert.Equal(t, posted[0].ID, id)
+	assert.Equal(t, []tag.Tag{tags[0], dedup.Tag([]byte("hello"))}, decodeTags(posted[0].Tags))
+	assert.Len(t, tags, 1)
+
+	again, uploaded, err := w.UploadOnce([]byte("hello"), nil)
+	require.NoError(t, err)
+	assert.False(t, uploaded)
+	assert.Equal(t, id, again)
+	assert.Len(t, posted, 1)
+
+	_, uploaded, err = w.UploadOnce([]byte("world"), nil)
+	require.NoError(t, err)
+	assert.True(t, uploaded)
+	assert.Len(t, posted, 2)
+}
@@ -0,0 +1,199 @@
+package wallet
+
+import (
+	"math/big"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/transaction"
+)
+
+// Spend is a transaction counted by Limits.
+type Spend struct {
+	ID      string    `json:"id"`      // Transaction ID
+	Winston string    `json:"winston"` // Quantity plus reward in winston
+	Time    time.Time `json:"time"`    // When the transaction was signed or sent
+}
+
+// SpendStore persists the spends counted by Limits, so that the limits
+// still hold after a restart.
+type SpendStore interface {
+	Load() ([]Spend, error) // Returns the stored spends, called once before the first check
+	Save(s Spend) error     // Stores a new spend
+}
+
+// Limits bounds the transactions a Wallet signs and sends, protecting the
+// wallet from a runaway loop draining it.
+//
+// A transaction counts once, for its quantity plus its reward, when
+// SignTransaction signs it, or when SendTransaction sends it if it was
+// signed elsewhere. Windows slide: a transaction stops counting against
+// MaxWinstonPerHour an hour after it was counted. Data items cost nothing
+// on-chain and are not counted. A Limits is safe for concurrent use and
+// may be shared by several wallets spending from the same key.
+//
+// Example:
+//
+//	w.Limits = &wallet.Limits{MaxWinstonPerDay: "5000000000000", MaxTxPerMinute: 10}
+type Limits struct {
+	MaxWinstonPerHour string     // Highest amount spent in any hour in winston, empty for no limit
+	MaxWinstonPerDay  string     // Highest amount spent in any 24 hours in winston, empty for no limit
+	MaxTxPerMinute    int        // Highest number of transactions in any minute, 0 for no limit
+	Store             SpendStore // Optional store of the spends, nil to keep them in memory only
+
+	mu     sync.Mutex
+	spends []*Spend         // Spends of the last 24 hours, oldest first
+	loaded bool             // Whether Store was loaded
+	now    func() time.Time // Time source, time.Now if nil
+}
+
+// Spent returns the amount spent in winston and the number of transactions counted over the last window, at most 24 hours.
+func (l *Limits) Spent(window time.Duration) (string, int, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.load(); err != nil {
+		return "", 0, err
+	}
+	total, n := l.sum(l.clock().Add(-window))
+	return total.String(), n, nil
+}
+
+// reserve counts a transaction of cost winston, returning goar.ErrCostExceeded
+// or goar.ErrSigningDenied if it would exceed a limit; commit or cancel
+// must then be called once it is signed or not
+func (l *Limits) reserve(cost *big.Int) (*Spend, error) {
+	hourly, err := parseLimit("hourly", l.MaxWinstonPerHour)
+	if err != nil {
+		return nil, err
+	}
+	daily, err := parseLimit("daily", l.MaxWinstonPerDay)
+	if err != nil {
+		return nil, err
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.load(); err != nil {
+		return nil, err
+	}
+	now := l.clock()
+	l.spends = slices.DeleteFunc(l.spends, func(s *Spend) bool { return !s.Time.After(now.Add(-24 * time.Hour)) })
+
+	if l.MaxTxPerMinute > 0 {
+		if _, n := l.sum(now.Add(-time.Minute)); n >= l.MaxTxPerMinute {
+			return nil, goar.Errorf(goar.ErrSigningDenied, "%d transactions in the last minute, the limit is %d", n, l.MaxTxPerMinute)
+		}
+	}
+	for _, limit := range []struct {
+		name   string
+		max    *big.Int
+		window time.Duration
+	}{{"hour", hourly, time.Hour}, {"day", daily, 24 * time.Hour}} {
+		if limit.max == nil {
+			continue
+		}
+		spent, _ := l.sum(now.Add(-limit.window))
+		if spent.Add(spent, cost).Cmp(limit.max) > 0 {
+			return nil, goar.Errorf(goar.ErrCostExceeded, "spending %s winston would exceed the limit of %s winston per %s", cost, limit.max, limit.name)
+		}
+	}
+
+	s := &Spend{Winston: cost.String(), Time: now}
+	l.spends = append(l.spends, s)
+	return s, nil
+}
+
+// commit records reserved spend s as transaction id and saves it to Store
+func (l *Limits) commit(s *Spend, id string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	s.ID = id
+	if l.Store == nil {
+		return nil
+	}
+	return l.Store.Save(*s)
+}
+
+// cancel drops reserved spend s
+func (l *Limits) cancel(s *Spend) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.spends = slices.DeleteFunc(l.spends, func(o *Spend) bool { return o == s })
+}
+
+// counted reports whether transaction id is counted already
+func (l *Limits) counted(id string) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return slices.ContainsFunc(l.spends, func(s *Spend) bool { return s.ID == id })
+}
+
+// sum returns the total and number of the spends after since; l.mu must be held
+func (l *Limits) sum(since time.Time) (*big.Int, int) {
+	total, n := new(big.Int), 0
+	for _, s := range l.spends {
+		if !s.Time.After(since) {
+			continue
+		}
+		if v, ok := new(big.Int).SetString(s.Winston, 10); ok {
+			total.Add(total, v)
+		}
+		n++
+	}
+	return total, n
+}
+
+// load reads the spends of Store once; l.mu must be held
+func (l *Limits) load() error {
+	if l.loaded || l.Store == nil {
+		return nil
+	}
+	spends, err := l.Store.Load()
+	if err != nil {
+		return err
+	}
+	for _, s := range spends {
+		l.spends = append(l.spends, &s)
+	}
+	slices.SortStableFunc(l.spends, func(a, b *Spend) int { return a.Time.Compare(b.Time) })
+	l.loaded = true
+	return nil
+}
+
+// clock returns the current time
+func (l *Limits) clock() time.Time {
+	if l.now == nil {
+		return time.Now()
+	}
+	return l.now()
+}
+
+// parseLimit parses limit, nil if it is empty
+func parseLimit(name string, limit string) (*big.Int, error) {
+	if limit == "" {
+		return nil, nil
+	}
+	n, err := transaction.ParseWinston(limit)
+	if err != nil {
+		return nil, goar.Errorf(goar.ErrInvalidInput, "invalid %s limit %q", name, limit)
+	}
+	return n, nil
+}
+
+// transactionCost returns the quantity plus the reward of tx
+func transactionCost(tx *transaction.Transaction) (*big.Int, error) {
+	cost := new(big.Int)
+	for _, amount := range []string{tx.Quantity, tx.Reward} {
+		if amount == "" {
+			continue
+		}
+		n, err := transaction.ParseWinston(amount)
+		if err != nil {
+			return nil, err
+		}
+		cost.Add(cost, n)
+	}
+	return cost, nil
+}
@@ -0,0 +1,110 @@
+package wallet
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/client"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryStore is a SpendStore keeping spends in memory
+type memoryStore struct {
+	spends []Spend
+	err    error
+}
+
+func (m *memoryStore) Load() ([]Spend, error) { return m.spends, m.err }
+func (m *memoryStore) Save(s Spend) error     { m.spends = append(m.spends, s); return m.err }
+
+func TestLimits(t *testing.T) {
+	_, server := newChunkGateway(t)
+	w, err := FromPath("../test/signer.json", server.URL)
+	require.NoError(t, err)
+	w.Client = client.New(server.URL)
+
+	now := time.Unix(1_700_000_000, 0)
+	limited := func(l *Limits) *Limits {
+		l.now = func() time.Time { return now }
+		w.Limits = l
+		return l
+	}
+	sign := func(quantity string) error {
+		_, err := w.SignTransaction(transaction.New([]byte("data"), "", quantity, nil))
+		return err
+	}
+
+	t.Run("Hourly", func(t *testing.T) {
+		limited(&Limits{MaxWinstonPerHour: "32"})
+		require.NoError(t, sign("10")) // 10 + reward 6, the size of the base64url data
+		require.NoError(t, sign("10"))
+		err := sign("10")
+		assert.ErrorIs(t, err, goar.ErrCostExceeded)
+
+		now = now.Add(time.Hour)
+		assert.NoError(t, sign("10"))
+	})
+
+	t.Run("Daily", func(t *testing.T) {
+		l := limited(&Limits{MaxWinstonPerHour: "100", MaxWinstonPerDay: "156"})
+		for range 3 {
+			require.NoError(t, sign("46"))
+			now = now.Add(time.Hour)
+		}
+		assert.ErrorIs(t, sign("0"), goar.ErrCostExceeded)
+
+		spent, n, err := l.Spent(24 * time.Hour)
+		require.NoError(t, err)
+		assert.Equal(t, "156", spent)
+		assert.Equal(t, 3, n)
+
+		now = now.Add(22 * time.Hour)
+		assert.NoError(t, sign("46"))
+	})
+
+	t.Run("Rate", func(t *testing.T) {
+		limited(&Limits{MaxTxPerMinute: 2})
+		require.NoError(t, sign(""))
+		require.NoError(t, sign(""))
+		assert.ErrorIs(t, sign(""), goar.ErrSigningDenied)
+		now = now.Add(time.Minute)
+		assert.NoError(t, sign(""))
+	})
+
+	t.Run("Send", func(t *testing.T) {
+		limited(&Limits{MaxTxPerMinute: 1})
+		tx := transaction.New([]byte("data"), "", "", nil)
+		_, err := w.SignTransaction(tx)
+		require.NoError(t, err)
+		assert.NoError(t, w.SendTransaction(tx), "signed by the wallet, counted once")
+
+		other := transaction.New([]byte("data"), "", "", nil)
+		other.Owner = w.Signer.Owner()
+		other.Reward = "6"
+		require.NoError(t, other.Sign(w.Signer))
+		assert.ErrorIs(t, w.SendTransaction(other), goar.ErrSigningDenied)
+	})
+
+	t.Run("Store", func(t *testing.T) {
+		store := &memoryStore{spends: []Spend{{ID: "old", Winston: "90", Time: now.Add(-30 * time.Minute)}}}
+		limited(&Limits{MaxWinstonPerHour: "100", Store: store})
+		assert.ErrorIs(t, sign("10"), goar.ErrCostExceeded)
+		require.NoError(t, sign("0"))
+		require.Len(t, store.spends, 2)
+		assert.Equal(t, "6", store.spends[1].Winston)
+		assert.NotEmpty(t, store.spends[1].ID)
+
+		store.err = errors.New("disk full")
+		limited(&Limits{Store: store})
+		assert.ErrorContains(t, sign("0"), "disk full")
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		limited(&Limits{MaxWinstonPerDay: "1e12"})
+		assert.ErrorIs(t, sign("0"), goar.ErrInvalidInput)
+	})
+}
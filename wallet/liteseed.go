@@ -0,0 +1,156 @@
+package wallet
+
+import (
+	"math/big"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/liteseed"
+	"github.com/liteseed/goar/transaction"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// SendDataItems uploads data items through a Liteseed bundler and pays for them.
+//
+// The flow is:
+//  1. Unsigned items are signed with this wallet
+//  2. The bundler quotes the fee for the total size of the items
+//  3. The items are posted to the bundler
+//  4. The fee is transferred to the bundler's address in an AR transaction
+//     tagged with liteseed.PaymentTags
+//  5. The payment is attached to every item
+//  6. If timeout is positive, the bundler is polled until every item is
+//     included in a bundle (liteseed.StatusSent)
+//
+// Parameters:
+//   - b: The bundler client
+//   - items: The data items to upload
+//   - timeout: How long to wait for the items to be bundled, 0 to return once paid
+//
+// Returns the bundler's receipts, in the order of items, or the first
+// error. Items posted before a failure are not paid for.
+//
+// Example:
+//
+//	item := wallet.CreateDataItem(data, "", "", nil)
+//	receipts, err := wallet.SendDataItems(liteseed.New(liteseed.DEFAULT_URL), []*data_item.DataItem{item}, 10*time.Minute)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Data item %s bundled in %s\n", receipts[0].ID, receipts[0].BundleID)
+func (w *Wallet) SendDataItems(b *liteseed.Client, items []*data_item.DataItem, timeout time.Duration) ([]*liteseed.Receipt, error) {
+	var size int64
+	ids := make([]string, len(items))
+	for i, item := range items {
+		if item.ID == "" {
+			if _, err := w.SignDataItem(item); err != nil {
+				return nil, err
+			}
+		}
+		size += item.RawSize()
+		ids[i] = item.ID
+	}
+
+	quote, err := b.GetPrice(int(size))
+	if err != nil {
+		return nil, err
+	}
+
+	receipts := make([]*liteseed.Receipt, len(items))
+	for i, item := range items {
+		if receipts[i], err = b.PostDataItem(item); err != nil {
+			return nil, err
+		}
+	}
+
+	tags := liteseed.PaymentTags(ids)
+	payment, err := w.SignTransaction(w.CreateTransaction(nil, quote.Address, quote.Price, &tags))
+	if err != nil {
+		return nil, err
+	}
+	if err = w.SendTransaction(payment); err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		if receipts[i], err = b.SetPayment(id, payment.ID); err != nil {
+			return nil, err
+		}
+	}
+	if timeout <= 0 {
+		return receipts, nil
+	}
+
+	deadline := time.Now().Add(timeout)
+	for i, id := range ids {
+		if receipts[i], err = b.WaitForStatus(id, liteseed.StatusSent, time.Until(deadline)); err != nil {
+			return receipts, err
+		}
+	}
+	return receipts, nil
+}
+
+// FundBundlerAccount prepays upload credit on a Liteseed bundler.
+//
+// The flow is:
+//  1. The bundler's address and the current balance of the wallet are fetched
+//  2. amount winston are transferred to the bundler in an AR transaction
+//     tagged with liteseed.FundTags
+//  3. The transfer is registered with the bundler
+//  4. If timeout is positive, the balance is polled until it has grown by amount
+//
+// Parameters:
+//   - b: The bundler client
+//   - amount: The credit to buy, in winston
+//   - timeout: How long to wait for the credit, 0 to return once registered
+//
+// Returns the funding transaction and the account balance known to the
+// bundler when returning, or an error if amount is invalid or a step fails.
+//
+// Example:
+//
+//	tx, balance, err := wallet.FundBundlerAccount(liteseed.New(liteseed.DEFAULT_URL), "1000000000000", time.Hour)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Funded with %s, balance %s winston\n", tx.ID, balance.Balance)
+func (w *Wallet) FundBundlerAccount(b *liteseed.Client, amount string, timeout time.Duration) (*transaction.Transaction, *liteseed.Balance, error) {
+	quantity, ok := new(big.Int).SetString(amount, 10)
+	if !ok || quantity.Sign() <= 0 {
+		return nil, nil, goar.Errorf(goar.ErrInvalidInput, "invalid amount %q", amount)
+	}
+	info, err := b.GetInfo()
+	if err != nil {
+		return nil, nil, err
+	}
+	before, err := w.BundlerBalance(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tags := liteseed.FundTags(w.Signer.Address)
+	tx, err := w.SignTransaction(w.CreateTransaction(nil, info.Address, amount, &tags))
+	if err != nil {
+		return nil, nil, err
+	}
+	if err = w.SendTransaction(tx); err != nil {
+		return nil, nil, err
+	}
+
+	balance, err := b.Fund(tx.ID)
+	if err != nil {
+		return tx, nil, err
+	}
+	if timeout <= 0 {
+		return tx, balance, nil
+	}
+
+	current, _ := new(big.Int).SetString(before.Balance, 10)
+	balance, err = b.WaitForBalance(w.Signer.Address, current.Add(current, quantity), timeout)
+	return tx, balance, err
+}
+
+// BundlerBalance returns the upload credit of this wallet on a Liteseed bundler.
+func (w *Wallet) BundlerBalance(b *liteseed.Client) (*liteseed.Balance, error) {
+	return b.GetBalance(w.Signer.Address)
+}
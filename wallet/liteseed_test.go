@@ -0,0 +1,158 @@
+package wallet
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/liteseed"
+	"github.com/liteseed/goar/transaction"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeGateway accepts transactions and returns a function reporting the last one posted
+func fakeGateway(t *testing.T) (*httptest.Server, func() *transaction.Transaction) {
+	var mu sync.Mutex
+	var posted *transaction.Transaction
+	gateway := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path == "/tx_anchor":
+			_, _ = w.Write([]byte("anchor"))
+		case r.URL.Path == "/price/0":
+			_, _ = w.Write([]byte("100"))
+		case r.Method == http.MethodPost && r.URL.Path == "/tx":
+			mu.Lock()
+			defer mu.Unlock()
+			require.NoError(t, json.NewDecoder(r.Body).Decode(&posted))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(gateway.Close)
+	return gateway, func() *transaction.Transaction {
+		mu.Lock()
+		defer mu.Unlock()
+		return posted
+	}
+}
+
+// TestSendDataItems runs the Liteseed upload flow against a fake gateway and bundler
+func TestSendDataItems(t *testing.T) {
+	gateway, posted := fakeGateway(t)
+
+	var mu sync.Mutex
+	receipts := map[string]*liteseed.Receipt{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /price/{size}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(liteseed.Quote{Price: "5000", Address: "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek"})
+	})
+	mux.HandleFunc("POST /tx", func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		item, err := data_item.Decode(raw)
+		require.NoError(t, err)
+		mu.Lock()
+		defer mu.Unlock()
+		receipts[item.ID] = &liteseed.Receipt{ID: item.ID, Status: liteseed.StatusQueued}
+		_ = json.NewEncoder(w).Encode(receipts[item.ID])
+	})
+	mux.HandleFunc("PUT /tx/{id}/{payment}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		receipt := receipts[r.PathValue("id")]
+		receipt.PaymentID = r.PathValue("payment")
+		receipt.Status = liteseed.StatusSent
+		receipt.BundleID = "bundle"
+		_ = json.NewEncoder(w).Encode(receipt)
+	})
+	mux.HandleFunc("GET /tx/{id}/status", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = json.NewEncoder(w).Encode(receipts[r.PathValue("id")])
+	})
+	bundler := httptest.NewServer(mux)
+	defer bundler.Close()
+
+	w, err := FromPath("../test/signer.json", gateway.URL)
+	require.NoError(t, err)
+	b := liteseed.New(bundler.URL)
+	b.PollInterval = time.Millisecond
+
+	items := []*data_item.DataItem{
+		w.CreateDataItem([]byte("first"), "", "", nil),
+		w.CreateDataItem([]byte("second"), "", "", nil),
+	}
+	result, err := w.SendDataItems(b, items, time.Second)
+	require.NoError(t, err)
+	require.Len(t, result, 2)
+
+	payment := posted()
+	require.NotNil(t, payment)
+	assert.Equal(t, "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek", payment.Target)
+	assert.Equal(t, "5000", payment.Quantity)
+	assert.Equal(t, liteseed.PaymentTags([]string{items[0].ID, items[1].ID}), decodeTags(payment.Tags))
+
+	for i, receipt := range result {
+		assert.Equal(t, items[i].ID, receipt.ID)
+		assert.Equal(t, payment.ID, receipt.PaymentID)
+		assert.Equal(t, "bundle", receipt.BundleID)
+	}
+}
+
+// TestFundBundlerAccount runs the account funding flow against a fake gateway and bundler
+func TestFundBundlerAccount(t *testing.T) {
+	gateway, posted := fakeGateway(t)
+
+	var mu sync.Mutex
+	var balance, polls int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /{$}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(liteseed.Info{Address: "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek"})
+	})
+	mux.HandleFunc("GET /account/{address}/balance", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		// The funding is credited on the third query
+		if polls++; polls == 3 {
+			balance += 2500
+		}
+		_ = json.NewEncoder(w).Encode(liteseed.Balance{Address: r.PathValue("address"), Balance: strconv.FormatInt(balance, 10)})
+	})
+	mux.HandleFunc("POST /account/fund/{payment}", func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		defer mu.Unlock()
+		_ = json.NewEncoder(w).Encode(liteseed.Balance{Balance: strconv.FormatInt(balance, 10)})
+	})
+	bundler := httptest.NewServer(mux)
+	defer bundler.Close()
+
+	w, err := FromPath("../test/signer.json", gateway.URL)
+	require.NoError(t, err)
+	b := liteseed.New(bundler.URL)
+	b.PollInterval = time.Millisecond
+
+	_, _, err = w.FundBundlerAccount(b, "-5", 0)
+	assert.ErrorIs(t, err, goar.ErrInvalidInput)
+
+	tx, result, err := w.FundBundlerAccount(b, "2500", time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, "2500", result.Balance)
+
+	payment := posted()
+	require.NotNil(t, payment)
+	assert.Equal(t, tx.ID, payment.ID)
+	assert.Equal(t, "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek", payment.Target)
+	assert.Equal(t, "2500", payment.Quantity)
+	assert.Equal(t, liteseed.FundTags(w.Signer.Address), decodeTags(payment.Tags))
+
+	current, err := w.BundlerBalance(b)
+	require.NoError(t, err)
+	assert.Equal(t, "2500", current.Balance)
+}
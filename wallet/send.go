@@ -0,0 +1,99 @@
+package wallet
+
+import (
+	"context"
+	"io"
+	"os"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction"
+	"github.com/liteseed/goar/uploader"
+)
+
+// SendData stores size bytes of data read from data in a transaction and uploads it completely.
+//
+// It runs the whole pipeline: the data is chunked and its Merkle root
+// computed, the transaction is created with the wallet's tag policy and
+// signed with a fresh anchor and price, the header is posted and every
+// chunk is uploaded in parallel, see uploader.UploadChunks. The data is
+// never held in memory: when data is an io.ReaderAt, such as an *os.File
+// or a *bytes.Reader, its first size bytes are read from offset 0 as
+// needed; otherwise it is copied to a temporary file that is removed
+// before SendData returns.
+//
+// Progress, when set, is called each time a chunk is uploaded, see
+// uploader.ProgressFunc.
+//
+// Parameters:
+//   - ctx: Bounds the whole upload
+//   - data: The data to store
+//   - size: The size of the data in bytes
+//   - tags: Optional metadata tags
+//
+// Returns the transaction ID. When the transaction was signed but the
+// upload failed, the ID is returned with the error, so the upload can be
+// completed with uploader.FromTransactionID. The error has code
+// goar.ErrInvalidInput if data holds fewer than size bytes.
+//
+// Example:
+//
+//	f, err := os.Open("video.mp4")
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	defer f.Close()
+//	info, _ := f.Stat()
+//	tags := []tag.Tag{{Name: "Content-Type", Value: "video/mp4"}}
+//	id, err := w.SendData(ctx, f, info.Size(), &tags)
+func (w *Wallet) SendData(ctx context.Context, data io.Reader, size int64, tags *[]tag.Tag) (string, error) {
+	src, cleanup, err := spool(data, size)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+
+	if w.Tags != nil {
+		tags = w.Tags.Apply(tags)
+	}
+	tx, err := transaction.NewDataTransactionFromReader(src, size, tags)
+	if err != nil {
+		return "", err
+	}
+	if _, err := w.SignTransactionContext(ctx, tx); err != nil {
+		return "", err
+	}
+
+	tu, err := uploader.New(w.Client, tx)
+	if err != nil {
+		return tx.ID, err
+	}
+	tu.OnProgress = w.Progress
+	return tx.ID, tu.UploadChunksContext(ctx, nil)
+}
+
+// spool returns data as an io.ReaderAt, copying it to a temporary file
+// unless it is one, and the function releasing it
+func spool(data io.Reader, size int64) (io.ReaderAt, func(), error) {
+	if r, ok := data.(io.ReaderAt); ok {
+		return r, func() {}, nil
+	}
+	f, err := os.CreateTemp("", "goar-send-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	cleanup := func() {
+		f.Close()
+		os.Remove(f.Name())
+	}
+	n, err := io.CopyN(f, data, size)
+	if err == io.EOF {
+		cleanup()
+		return nil, nil, goar.Errorf(goar.ErrInvalidInput, "data holds %d bytes, expected %d", n, size)
+	}
+	if err != nil {
+		cleanup()
+		return nil, nil, err
+	}
+	return f, cleanup, nil
+}
@@ -0,0 +1,137 @@
+package wallet
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// chunkGateway accepts transaction headers and chunks, keeping what it received
+type chunkGateway struct {
+	mu     sync.Mutex
+	txs    []*transaction.Transaction
+	chunks []transaction.GetChunkResult
+}
+
+func newChunkGateway(t *testing.T) (*chunkGateway, *httptest.Server) {
+	g := &chunkGateway{}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tx_anchor", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("anchor"))
+	})
+	mux.HandleFunc("GET /price/{size}", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(r.PathValue("size")))
+	})
+	mux.HandleFunc("POST /tx", func(w http.ResponseWriter, r *http.Request) {
+		var tx transaction.Transaction
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&tx))
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.txs = append(g.txs, &tx)
+	})
+	mux.HandleFunc("POST /chunk", func(w http.ResponseWriter, r *http.Request) {
+		var chunk transaction.GetChunkResult
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&chunk))
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		g.chunks = append(g.chunks, chunk)
+	})
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return g, server
+}
+
+// data reassembles the chunks received in offset order
+func (g *chunkGateway) data(t *testing.T) []byte {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	sort.Slice(g.chunks, func(i, j int) bool {
+		a, _ := strconv.Atoi(g.chunks[i].Offset)
+		b, _ := strconv.Atoi(g.chunks[j].Offset)
+		return a < b
+	})
+	var data []byte
+	for _, chunk := range g.chunks {
+		b, err := crypto.Base64URLDecode(chunk.Chunk)
+		require.NoError(t, err)
+		data = append(data, b...)
+	}
+	return data
+}
+
+func TestSendData(t *testing.T) {
+	data := make([]byte, 3*transaction.MAX_CHUNK_SIZE/2+1000)
+	_, _ = rand.Read(data)
+	tags := []tag.Tag{{Name: "Content-Type", Value: "application/octet-stream"}}
+
+	for name, reader := range map[string]func() io.Reader{
+		"ReaderAt": func() io.Reader { return bytes.NewReader(data) },
+		"Reader":   func() io.Reader { return io.MultiReader(bytes.NewReader(data)) },
+	} {
+		t.Run(name, func(t *testing.T) {
+			g, server := newChunkGateway(t)
+			w, err := FromPath("../test/signer.json", server.URL)
+			require.NoError(t, err)
+			var uploaded []int64
+			var indexes []int
+			w.Progress = func(uploadedBytes int64, totalBytes int64, chunkIndex int, totalChunks int) {
+				assert.Equal(t, int64(len(data)), totalBytes)
+				assert.Equal(t, 2, totalChunks)
+				uploaded = append(uploaded, uploadedBytes)
+				indexes = append(indexes, chunkIndex)
+			}
+
+			id, err := w.SendData(context.Background(), reader(), int64(len(data)), &tags)
+			require.NoError(t, err)
+
+			require.Len(t, g.txs, 1)
+			tx := g.txs[0]
+			assert.Equal(t, id, tx.ID)
+			assert.Empty(t, tx.Data)
+			assert.Equal(t, strconv.Itoa(len(data)), tx.DataSize)
+			assert.Equal(t, strconv.Itoa(len(data)), tx.Reward)
+			expected, err := transaction.NewDataTransactionFromReader(bytes.NewReader(data), int64(len(data)), nil)
+			require.NoError(t, err)
+			assert.Equal(t, expected.DataRoot, tx.DataRoot)
+			assert.Equal(t, data, g.data(t))
+
+			require.Len(t, uploaded, 2)
+			assert.Equal(t, int64(len(data)), uploaded[1])
+			assert.ElementsMatch(t, []int{0, 1}, indexes)
+		})
+	}
+
+	t.Run("ShortData", func(t *testing.T) {
+		_, server := newChunkGateway(t)
+		w, err := FromPath("../test/signer.json", server.URL)
+		require.NoError(t, err)
+		_, err = w.SendData(context.Background(), io.MultiReader(bytes.NewReader(data)), int64(len(data)+1), nil)
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+	})
+
+	t.Run("Canceled", func(t *testing.T) {
+		g, server := newChunkGateway(t)
+		w, err := FromPath("../test/signer.json", server.URL)
+		require.NoError(t, err)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err = w.SendData(ctx, bytes.NewReader(data), int64(len(data)), nil)
+		assert.ErrorIs(t, err, context.Canceled)
+		assert.Empty(t, g.txs)
+	})
+}
@@ -0,0 +1,98 @@
+package wallet
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/tag"
+)
+
+// TagPolicy holds the tag conventions a wallet enforces on everything it creates.
+//
+// Tag names are compared case-insensitively.
+type TagPolicy struct {
+	Defaults  []tag.Tag // Tags added by CreateTransaction and CreateDataItem; a tag with the same name passed to the call overrides the default
+	Forbidden []string  // Tag names callers may not set; a default with a forbidden name is still allowed with its default value
+}
+
+// Apply returns tags merged with the default tags.
+//
+// Tags passed by the caller come first, followed by every default whose
+// name the caller did not set. The input is not modified.
+//
+// Example:
+//
+//	policy := &TagPolicy{Defaults: []tag.Tag{{Name: "App-Name", Value: "MyApp"}}}
+//	tags := policy.Apply(&[]tag.Tag{{Name: "Content-Type", Value: "text/plain"}})
+//	// tags: Content-Type=text/plain, App-Name=MyApp
+func (p *TagPolicy) Apply(tags *[]tag.Tag) *[]tag.Tag {
+	merged := []tag.Tag{}
+	if tags != nil {
+		merged = append(merged, *tags...)
+	}
+	for _, d := range p.Defaults {
+		if !hasTag(merged, d.Name) {
+			merged = append(merged, d)
+		}
+	}
+	return &merged
+}
+
+// Validate checks that tags do not set a forbidden tag name.
+//
+// A forbidden tag is accepted only if it has exactly the value of the
+// default with the same name.
+//
+// Returns an error carrying goar.ErrInvalidInput for the first violation.
+func (p *TagPolicy) Validate(tags []tag.Tag) error {
+	for _, t := range tags {
+		if !p.isForbidden(t.Name) {
+			continue
+		}
+		allowed := false
+		for _, d := range p.Defaults {
+			if strings.EqualFold(d.Name, t.Name) && d.Value == t.Value {
+				allowed = true
+				break
+			}
+		}
+		if !allowed {
+			return goar.Wrap(goar.ErrInvalidInput, fmt.Errorf("tag %q is reserved by the wallet", t.Name))
+		}
+	}
+	return nil
+}
+
+func (p *TagPolicy) isForbidden(name string) bool {
+	for _, f := range p.Forbidden {
+		if strings.EqualFold(f, name) {
+			return true
+		}
+	}
+	return false
+}
+
+func hasTag(tags []tag.Tag, name string) bool {
+	for _, t := range tags {
+		if strings.EqualFold(t.Name, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// decodeTags returns transaction tags with base64url-decoded names and values.
+func decodeTags(tags *[]tag.Tag) []tag.Tag {
+	decoded := []tag.Tag{}
+	if tags == nil {
+		return decoded
+	}
+	for _, t := range *tags {
+		name, _ := crypto.Base64URLDecode(t.Name)
+		value, _ := crypto.Base64URLDecode(t.Value)
+		decoded = append(decoded, tag.Tag{Name: string(name), Value: string(value)})
+	}
+	return decoded
+}
@@ -0,0 +1,61 @@
+package wallet
+
+import (
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/tag"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestTagPolicy verifies that default tags are applied and reserved tag names are enforced
+func TestTagPolicy(t *testing.T) {
+	w, err := FromPath("../test/signer.json", "http://localhost:1984")
+	require.NoError(t, err)
+	w.Tags = &TagPolicy{
+		Defaults: []tag.Tag{
+			{Name: "App-Name", Value: "goar"},
+			{Name: "Content-Type", Value: "application/octet-stream"},
+		},
+		Forbidden: []string{"App-Name"},
+	}
+
+	t.Run("Defaults", func(t *testing.T) {
+		di := w.CreateDataItem([]byte("hello"), "", "", nil)
+		assert.Equal(t, w.Tags.Defaults, *di.Tags)
+
+		_, err := w.SignDataItem(di)
+		assert.NoError(t, err)
+	})
+
+	t.Run("Override", func(t *testing.T) {
+		tags := &[]tag.Tag{{Name: "content-type", Value: "text/plain"}}
+		tx := w.CreateTransaction([]byte("hello"), "", "0", tags)
+		assert.Equal(t, []tag.Tag{
+			{Name: "content-type", Value: "text/plain"},
+			{Name: "App-Name", Value: "goar"},
+		}, decodeTags(tx.Tags))
+		assert.Len(t, *tags, 1)
+	})
+
+	t.Run("Forbidden", func(t *testing.T) {
+		tags := &[]tag.Tag{{Name: "app-name", Value: "other"}}
+
+		di := w.CreateDataItem([]byte("hello"), "", "", tags)
+		_, err := w.SignDataItem(di)
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+		assert.Empty(t, di.ID)
+
+		tx := w.CreateTransaction([]byte("hello"), "", "0", tags)
+		_, err = w.SignTransaction(tx)
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+		assert.Empty(t, tx.Signature)
+	})
+
+	t.Run("Transaction tags are decoded", func(t *testing.T) {
+		encoded := []tag.Tag{{Name: crypto.Base64URLEncode([]byte("App-Name")), Value: crypto.Base64URLEncode([]byte("goar"))}}
+		assert.NoError(t, w.Tags.Validate(decodeTags(&encoded)))
+	})
+}
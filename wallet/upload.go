@@ -0,0 +1,181 @@
+package wallet
+
+import (
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/liteseed"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction/bundle"
+	"github.com/liteseed/goar/transaction/data_item"
+)
+
+// Route is the way Upload stores data.
+type Route string
+
+// Upload routes
+const (
+	RouteL1      Route = "l1"      // A plain transaction holding the data
+	RouteBundle  Route = "bundle"  // A data item in a bundle of its own, posted as a transaction by this wallet
+	RouteBundler Route = "bundler" // A data item posted to, and paid to, a third-party bundler
+)
+
+// UploadPolicy configures how Upload routes data.
+//
+// The bundler is tried first when it is set, the upload is not urgent and
+// the data is at most BundlerMaxSize bytes. Otherwise, or when it costs more
+// than MaxCost, the data goes in an L1 transaction, or in a bundle of its
+// own first when PreferItem is set. The first route within MaxCost wins.
+type UploadPolicy struct {
+	Bundler        *liteseed.Client // Third-party bundler, nil to never use one
+	BundlerMaxSize int64            // Largest data sent to Bundler in bytes, 0 for no limit
+	Urgent         bool             // Skip the bundler queue, the data is posted by this wallet
+	PreferItem     bool             // Prefer a data item in an own bundle to a plain L1 transaction
+	MaxCost        string           // Highest acceptable cost in winston, empty for no cap
+	Timeout        time.Duration    // How long to wait for the bundler to bundle the item, 0 to return once paid
+}
+
+// UploadResult describes a completed Upload.
+type UploadResult struct {
+	Route   Route             // The route taken
+	ID      string            // ID of the data: the transaction for RouteL1, the data item otherwise
+	TxID    string            // ID of the L1 transaction sent: the data, the bundle or the bundler payment
+	Cost    string            // Estimated cost in winston
+	Receipt *liteseed.Receipt // Bundler receipt for RouteBundler
+}
+
+// Upload stores data with tags through the route chosen by policy.
+//
+// Parameters:
+//   - data: The data to upload
+//   - tags: Optional metadata tags of the data
+//   - policy: The routing rules, nil for a plain L1 transaction
+//
+// Returns the result of the upload, an error with code goar.ErrCostExceeded
+// if every route costs more than policy.MaxCost, or the error of the chosen
+// route.
+//
+// Example:
+//
+//	policy := &wallet.UploadPolicy{Bundler: liteseed.New(liteseed.DEFAULT_URL), BundlerMaxSize: 100 * 1024, MaxCost: "1000000000"}
+//	result, err := w.Upload(data, &tags, policy)
+//	if err != nil {
+//		log.Fatal(err)
+//	}
+//	fmt.Printf("Uploaded %s via %s for %s winston\n", result.ID, result.Route, result.Cost)
+func (w *Wallet) Upload(data []byte, tags *[]tag.Tag, policy *UploadPolicy) (*UploadResult, error) {
+	if policy == nil {
+		policy = &UploadPolicy{}
+	}
+	var limit *big.Int
+	if policy.MaxCost != "" {
+		var ok bool
+		if limit, ok = new(big.Int).SetString(policy.MaxCost, 10); !ok {
+			return nil, goar.Errorf(goar.ErrInvalidInput, "invalid maximum cost %q", policy.MaxCost)
+		}
+	}
+
+	var costs []string
+	for _, route := range policy.routes(int64(len(data))) {
+		cost, item, err := w.estimate(route, data, tags, policy)
+		if err != nil {
+			return nil, err
+		}
+		costs = append(costs, fmt.Sprintf("%s: %s", route, cost))
+		if limit != nil {
+			if c, ok := new(big.Int).SetString(cost, 10); !ok || c.Cmp(limit) > 0 {
+				continue
+			}
+		}
+		return w.upload(route, data, tags, item, cost, policy)
+	}
+	return nil, goar.Errorf(goar.ErrCostExceeded, "every route costs more than %s winston %v", policy.MaxCost, costs)
+}
+
+// routes returns the routes to try for size bytes of data, in order
+func (p *UploadPolicy) routes(size int64) []Route {
+	var routes []Route
+	if p.Bundler != nil && !p.Urgent && (p.BundlerMaxSize <= 0 || size <= p.BundlerMaxSize) {
+		routes = append(routes, RouteBundler)
+	}
+	if p.PreferItem {
+		return append(routes, RouteBundle, RouteL1)
+	}
+	return append(routes, RouteL1, RouteBundle)
+}
+
+// estimate returns the cost of uploading data through route, and the signed
+// data item to upload for item routes
+func (w *Wallet) estimate(route Route, data []byte, tags *[]tag.Tag, policy *UploadPolicy) (string, *data_item.DataItem, error) {
+	if route == RouteL1 {
+		cost, err := w.Client.GetTransactionPrice(len(data), "")
+		return cost, nil, err
+	}
+
+	item, err := w.SignDataItem(w.CreateDataItem(data, "", "", tags))
+	if err != nil {
+		return "", nil, err
+	}
+	if route == RouteBundle {
+		// The bundle adds the item count and one 64-byte header entry
+		cost, err := w.Client.GetTransactionPrice(int(32+64+item.RawSize()), "")
+		return cost, item, err
+	}
+
+	quote, err := policy.Bundler.GetPrice(int(item.RawSize()))
+	if err != nil {
+		return "", nil, err
+	}
+	fee, err := w.Client.GetTransactionPrice(0, quote.Address)
+	if err != nil {
+		return "", nil, err
+	}
+	price, ok := new(big.Int).SetString(quote.Price, 10)
+	if !ok {
+		return "", nil, goar.Errorf(goar.ErrDecode, "invalid bundler price %q", quote.Price)
+	}
+	transfer, ok := new(big.Int).SetString(fee, 10)
+	if !ok {
+		return "", nil, goar.Errorf(goar.ErrDecode, "invalid transaction price %q", fee)
+	}
+	return price.Add(price, transfer).String(), item, nil
+}
+
+// upload sends data through route
+func (w *Wallet) upload(route Route, data []byte, tags *[]tag.Tag, item *data_item.DataItem, cost string, policy *UploadPolicy) (*UploadResult, error) {
+	result := &UploadResult{Route: route, Cost: cost}
+	switch route {
+	case RouteL1:
+		tx, err := w.SignTransaction(w.CreateTransaction(data, "", "0", tags))
+		if err != nil {
+			return nil, err
+		}
+		if err = w.SendTransaction(tx); err != nil {
+			return nil, err
+		}
+		result.ID, result.TxID = tx.ID, tx.ID
+	case RouteBundle:
+		b, err := bundle.New(&[]data_item.DataItem{*item})
+		if err != nil {
+			return nil, err
+		}
+		bundleTags := bundle.Tags()
+		tx, err := w.SignTransaction(w.CreateTransaction(b.Raw, "", "0", &bundleTags))
+		if err != nil {
+			return nil, err
+		}
+		if err = w.SendTransaction(tx); err != nil {
+			return nil, err
+		}
+		result.ID, result.TxID = item.ID, tx.ID
+	case RouteBundler:
+		receipts, err := w.SendDataItems(policy.Bundler, []*data_item.DataItem{item}, policy.Timeout)
+		if err != nil {
+			return nil, err
+		}
+		result.ID, result.TxID, result.Receipt = item.ID, receipts[0].PaymentID, receipts[0]
+	}
+	return result, nil
+}
@@ -0,0 +1,153 @@
+package wallet
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/liteseed/goar"
+	"github.com/liteseed/goar/crypto"
+	"github.com/liteseed/goar/liteseed"
+	"github.com/liteseed/goar/tag"
+	"github.com/liteseed/goar/transaction"
+	"github.com/liteseed/goar/transaction/bundle"
+	"github.com/liteseed/goar/transaction/data_item"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const bundlerAddress = "Cbj95zDZBBhmyht6iFlEf7xmSCSVZGw436V6HWmm9Ek"
+
+// newRoutingNetwork starts a gateway charging 10 winston per byte plus 100
+// per transaction, and a bundler charging 2 winston per byte
+func newRoutingNetwork(t *testing.T) (gateway string, b *liteseed.Client, posted func() []*transaction.Transaction) {
+	var mu sync.Mutex
+	var txs []*transaction.Transaction
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tx_anchor", func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte("anchor"))
+	})
+	price := func(w http.ResponseWriter, r *http.Request) {
+		size, err := strconv.Atoi(r.PathValue("size"))
+		require.NoError(t, err)
+		_, _ = w.Write([]byte(strconv.Itoa(100 + 10*size)))
+	}
+	mux.HandleFunc("GET /price/{size}", price)
+	mux.HandleFunc("GET /price/{size}/{target}", price)
+	mux.HandleFunc("POST /tx", func(w http.ResponseWriter, r *http.Request) {
+		var tx transaction.Transaction
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&tx))
+		mu.Lock()
+		defer mu.Unlock()
+		txs = append(txs, &tx)
+	})
+	g := httptest.NewServer(mux)
+	t.Cleanup(g.Close)
+
+	bundlerMux := http.NewServeMux()
+	bundlerMux.HandleFunc("GET /price/{size}", func(w http.ResponseWriter, r *http.Request) {
+		size, _ := strconv.Atoi(r.PathValue("size"))
+		_ = json.NewEncoder(w).Encode(liteseed.Quote{Price: strconv.Itoa(2 * size), Address: bundlerAddress})
+	})
+	bundlerMux.HandleFunc("POST /tx", func(w http.ResponseWriter, r *http.Request) {
+		raw, _ := io.ReadAll(r.Body)
+		item, err := data_item.Decode(raw)
+		require.NoError(t, err)
+		_ = json.NewEncoder(w).Encode(liteseed.Receipt{ID: item.ID, Status: liteseed.StatusQueued})
+	})
+	bundlerMux.HandleFunc("PUT /tx/{id}/{payment}", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(liteseed.Receipt{ID: r.PathValue("id"), PaymentID: r.PathValue("payment"), Status: liteseed.StatusQueued})
+	})
+	bundler := httptest.NewServer(bundlerMux)
+	t.Cleanup(bundler.Close)
+
+	return g.URL, liteseed.New(bundler.URL), func() []*transaction.Transaction {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]*transaction.Transaction(nil), txs...)
+	}
+}
+
+func TestUpload(t *testing.T) {
+	data := []byte("hello world")
+	tags := []tag.Tag{{Name: "Content-Type", Value: "text/plain"}}
+
+	setup := func(t *testing.T) (*Wallet, *liteseed.Client, func() []*transaction.Transaction) {
+		gateway, b, posted := newRoutingNetwork(t)
+		w, err := FromPath("../test/signer.json", gateway)
+		require.NoError(t, err)
+		return w, b, posted
+	}
+
+	t.Run("L1 by default", func(t *testing.T) {
+		w, _, posted := setup(t)
+		result, err := w.Upload(data, &tags, nil)
+		require.NoError(t, err)
+		assert.Equal(t, RouteL1, result.Route)
+		assert.Equal(t, strconv.Itoa(100+10*len(data)), result.Cost)
+		require.Len(t, posted(), 1)
+		assert.Equal(t, result.ID, posted()[0].ID)
+		assert.Equal(t, crypto.Base64URLEncode(data), posted()[0].Data)
+	})
+
+	t.Run("Bundler", func(t *testing.T) {
+		w, b, posted := setup(t)
+		result, err := w.Upload(data, &tags, &UploadPolicy{Bundler: b, BundlerMaxSize: 1024})
+		require.NoError(t, err)
+		assert.Equal(t, RouteBundler, result.Route)
+		require.NotNil(t, result.Receipt)
+		assert.Equal(t, result.ID, result.Receipt.ID)
+		// Only the payment reaches the gateway
+		require.Len(t, posted(), 1)
+		assert.Equal(t, result.TxID, posted()[0].ID)
+		assert.Equal(t, bundlerAddress, posted()[0].Target)
+	})
+
+	t.Run("Too large or urgent for the bundler", func(t *testing.T) {
+		for _, policy := range []*UploadPolicy{{BundlerMaxSize: 4}, {Urgent: true}} {
+			w, b, _ := setup(t)
+			policy.Bundler = b
+			result, err := w.Upload(data, &tags, policy)
+			require.NoError(t, err)
+			assert.Equal(t, RouteL1, result.Route)
+		}
+	})
+
+	t.Run("Own bundle", func(t *testing.T) {
+		w, _, posted := setup(t)
+		result, err := w.Upload(data, &tags, &UploadPolicy{PreferItem: true})
+		require.NoError(t, err)
+		assert.Equal(t, RouteBundle, result.Route)
+		require.Len(t, posted(), 1)
+		tx := posted()[0]
+		assert.Equal(t, result.TxID, tx.ID)
+		assert.Contains(t, decodeTags(tx.Tags), tag.Tag{Name: "Bundle-Format", Value: "binary"})
+
+		raw, err := crypto.Base64URLDecode(tx.Data)
+		require.NoError(t, err)
+		b, err := bundle.Decode(raw)
+		require.NoError(t, err)
+		require.Len(t, b.Items, 1)
+		assert.Equal(t, result.ID, b.Items[0].ID)
+		assert.Equal(t, data, b.Items[0].RawData())
+	})
+
+	t.Run("Cost cap", func(t *testing.T) {
+		w, b, posted := setup(t)
+		// The bundler quote plus the payment fee exceeds the cap, the L1 transaction does not
+		result, err := w.Upload(data, &tags, &UploadPolicy{Bundler: b, MaxCost: strconv.Itoa(100 + 10*len(data))})
+		require.NoError(t, err)
+		assert.Equal(t, RouteL1, result.Route)
+
+		_, err = w.Upload(data, &tags, &UploadPolicy{Bundler: b, MaxCost: "10"})
+		assert.ErrorIs(t, err, goar.ErrCostExceeded)
+		assert.Len(t, posted(), 1)
+
+		_, err = w.Upload(data, &tags, &UploadPolicy{MaxCost: "ten"})
+		assert.ErrorIs(t, err, goar.ErrInvalidInput)
+	})
+}
@@ -25,9 +25,12 @@
 package wallet
 
 import (
+	"context"
 	"errors"
 	"os"
+	"strconv"
 
+	"github.com/liteseed/goar"
 	"github.com/liteseed/goar/client"
 	"github.com/liteseed/goar/signer"
 	"github.com/liteseed/goar/tag"
@@ -46,6 +49,15 @@ import (
 type Wallet struct {
 	Client *client.Client // HTTP client for communicating with Arweave nodes
 	Signer *signer.Signer // Cryptographic signer for transaction signing
+	Tags   *TagPolicy     // Optional default tags and forbidden tag names, see TagPolicy
+	Limits *Limits        // Optional rate and spend limits of the transactions signed and sent, see Limits
+
+	MaxAnchorDepth int64 // Maximum depth in blocks of the anchor used by SignTransaction, 0 to skip the check
+	MaxItemSize    int64 // Maximum data size of the data items created and signed, 0 for no limit
+
+	ItemAnchors AnchorMode // How SignDataItem anchors the data items that have no anchor, random by default
+
+	Progress uploader.ProgressFunc // Optional callback reporting the chunks uploaded by SendData
 }
 
 // New creates a new wallet with a randomly generated private key.
@@ -138,6 +150,18 @@ func FromJWK(jwk []byte, gateway string) (*Wallet, error) {
 	}, nil
 }
 
+// FromEncryptedKeystore creates a wallet from a keystore file encrypted with passphrase, see signer.FromEncryptedKeystore.
+func FromEncryptedKeystore(path string, passphrase string, gateway string) (*Wallet, error) {
+	s, err := signer.FromEncryptedKeystore(path, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	return &Wallet{
+		Client: client.New(gateway),
+		Signer: s,
+	}, nil
+}
+
 // CreateTransaction creates a new Arweave transaction.
 //
 // This method creates a transaction with the provided data and metadata.
@@ -150,6 +174,8 @@ func FromJWK(jwk []byte, gateway string) (*Wallet, error) {
 //   - quantity: The amount of AR to transfer in Winston units ("0" for data-only)
 //   - tags: Optional metadata tags (can be nil)
 //
+// The wallet's default tags are added unless tags sets a tag with the same name.
+//
 // Returns a new Transaction instance ready for signing.
 //
 // Example:
@@ -161,6 +187,9 @@ func FromJWK(jwk []byte, gateway string) (*Wallet, error) {
 //	// AR transfer
 //	tx := wallet.CreateTransaction(nil, targetAddr, "1000000000000", nil)
 func (w *Wallet) CreateTransaction(data []byte, target string, quantity string, tags *[]tag.Tag) *transaction.Transaction {
+	if w.Tags != nil {
+		tags = w.Tags.Apply(tags)
+	}
 	return transaction.New(data, target, quantity, tags)
 }
 
@@ -168,7 +197,8 @@ func (w *Wallet) CreateTransaction(data []byte, target string, quantity string,
 //
 // This method performs several operations:
 // 1. Sets the transaction owner to this wallet's public key
-// 2. Gets the current transaction anchor from the network
+// 2. Gets the current transaction anchor from the network, refreshing it
+// once if it is deeper than MaxAnchorDepth
 // 3. Calculates the required transaction fee
 // 4. Signs the transaction with this wallet's private key
 //
@@ -176,7 +206,10 @@ func (w *Wallet) CreateTransaction(data []byte, target string, quantity string,
 //   - tx: The transaction to sign (created with CreateTransaction)
 //
 // Returns the signed transaction with all fields populated, or an error if
-// any network calls fail or signing fails.
+// a tag violates the wallet's tag policy, the anchor is stale
+// (goar.ErrAnchorExpired), the transaction exceeds the wallet's Limits
+// (goar.ErrCostExceeded or goar.ErrSigningDenied), any network calls fail
+// or signing fails.
 //
 // Example:
 //
@@ -188,26 +221,70 @@ func (w *Wallet) CreateTransaction(data []byte, target string, quantity string,
 //	}
 //	fmt.Printf("Transaction signed with ID: %s\n", signedTx.ID)
 func (w *Wallet) SignTransaction(tx *transaction.Transaction) (*transaction.Transaction, error) {
+	return w.SignTransactionContext(context.Background(), tx)
+}
+
+// SignTransactionContext is SignTransaction bound to ctx.
+func (w *Wallet) SignTransactionContext(ctx context.Context, tx *transaction.Transaction) (*transaction.Transaction, error) {
+	if w.Tags != nil {
+		if err := w.Tags.Validate(decodeTags(tx.Tags)); err != nil {
+			return nil, err
+		}
+	}
 	tx.Owner = w.Signer.Owner()
 
-	anchor, err := w.Client.GetTransactionAnchor()
+	anchor, err := w.anchor(ctx)
 	if err != nil {
 		return nil, err
 	}
 	tx.LastTx = anchor
 
-	reward, err := w.Client.GetTransactionPrice(len(tx.Data), "")
+	size := len(tx.Data)
+	if tx.DataSource != nil {
+		// The price of a wrong size would sign the transaction with a wrong reward
+		if size, err = strconv.Atoi(tx.DataSize); err != nil || size < 0 {
+			return nil, goar.Errorf(goar.ErrInvalidInput, "invalid data size %q", tx.DataSize)
+		}
+	}
+	reward, err := w.Client.GetTransactionPriceContext(ctx, size, "")
 	if err != nil {
 		return nil, err
 	}
-	tx.Reward = reward
+	if tx.Reward, err = transaction.NormalizeWinston(reward); err != nil {
+		return nil, goar.Errorf(goar.ErrDecode, "invalid price %q", reward)
+	}
 
+	if w.Limits == nil {
+		if err = tx.Sign(w.Signer); err != nil {
+			return nil, err
+		}
+		return tx, nil
+	}
+	cost, err := transactionCost(tx)
+	if err != nil {
+		return nil, err
+	}
+	spend, err := w.Limits.reserve(cost)
+	if err != nil {
+		return nil, err
+	}
 	if err = tx.Sign(w.Signer); err != nil {
+		w.Limits.cancel(spend)
+		return nil, err
+	}
+	if err = w.Limits.commit(spend, tx.ID); err != nil {
 		return nil, err
 	}
 	return tx, nil
 }
 
+func (w *Wallet) anchor(ctx context.Context) (string, error) {
+	if w.MaxAnchorDepth > 0 {
+		return w.Client.GetRecentAnchorContext(ctx, w.MaxAnchorDepth)
+	}
+	return w.Client.GetTransactionAnchorContext(ctx)
+}
+
 // SendTransaction sends a signed transaction to the Arweave network.
 //
 // This method uploads the transaction to the configured Arweave gateway.
@@ -216,7 +293,11 @@ func (w *Wallet) SignTransaction(tx *transaction.Transaction) (*transaction.Tran
 // Parameters:
 //   - tx: The signed transaction to send
 //
-// Returns an error if the transaction is not signed or if the upload fails.
+// A transaction signed without SignTransaction is counted against the
+// wallet's Limits before it is sent.
+//
+// Returns an error if the transaction is not signed, exceeds the wallet's
+// Limits or if the upload fails.
 //
 // Example:
 //
@@ -230,6 +311,19 @@ func (w *Wallet) SendTransaction(tx *transaction.Transaction) error {
 	if tx.ID == "" || tx.Signature == "" {
 		return errors.New("transaction not signed")
 	}
+	if w.Limits != nil && !w.Limits.counted(tx.ID) {
+		cost, err := transactionCost(tx)
+		if err != nil {
+			return err
+		}
+		spend, err := w.Limits.reserve(cost)
+		if err != nil {
+			return err
+		}
+		if err = w.Limits.commit(spend, tx.ID); err != nil {
+			return err
+		}
+	}
 	tu, err := uploader.New(w.Client, tx)
 	if err != nil {
 		return err
@@ -252,14 +346,23 @@ func (w *Wallet) SendTransaction(tx *transaction.Transaction) error {
 //   - anchor: Optional anchor value for the data item
 //   - tags: Optional metadata tags
 //
-// Returns a new DataItem instance ready for signing.
+// The wallet's default tags are added unless tags sets a tag with the same name,
+// and the item's MaxSize is set to the wallet's MaxItemSize.
+//
+// Returns a new DataItem instance ready for signing; call CheckSize on it to
+// fail early when the data is too large.
 //
 // Example:
 //
 //	tags := []tag.Tag{{Name: "Content-Type", Value: "image/jpeg"}}
 //	dataItem := wallet.CreateDataItem(imageData, "", "", &tags)
 func (w *Wallet) CreateDataItem(data []byte, target string, anchor string, tags *[]tag.Tag) *data_item.DataItem {
-	return data_item.New(data, target, anchor, tags)
+	if w.Tags != nil {
+		tags = w.Tags.Apply(tags)
+	}
+	d := data_item.New(data, target, anchor, tags)
+	d.MaxSize = w.MaxItemSize
+	return d
 }
 
 // SignDataItem signs a data item with this wallet's private key.
@@ -270,7 +373,12 @@ func (w *Wallet) CreateDataItem(data []byte, target string, anchor string, tags
 // Parameters:
 //   - di: The data item to sign
 //
-// Returns the signed data item, or an error if signing fails.
+// A data item without an anchor is anchored first, see ItemAnchors and
+// AnchorMode.
+//
+// Returns the signed data item, or an error if a tag violates the wallet's
+// tag policy, the data exceeds MaxItemSize (goar.ErrItemTooLarge), the
+// anchor cannot be retrieved or signing fails.
 //
 // Example:
 //
@@ -282,6 +390,17 @@ func (w *Wallet) CreateDataItem(data []byte, target string, anchor string, tags
 //	}
 //	fmt.Printf("Data item signed with ID: %s\n", signedItem.ID)
 func (w *Wallet) SignDataItem(di *data_item.DataItem) (*data_item.DataItem, error) {
+	if w.Tags != nil && di.Tags != nil {
+		if err := w.Tags.Validate(*di.Tags); err != nil {
+			return nil, err
+		}
+	}
+	if di.MaxSize == 0 {
+		di.MaxSize = w.MaxItemSize
+	}
+	if err := w.anchorItem(di); err != nil {
+		return nil, err
+	}
 	if err := di.Sign(w.Signer); err != nil {
 		return nil, err
 	}
@@ -311,3 +430,11 @@ func (w *Wallet) SignDataItem(di *data_item.DataItem) (*data_item.DataItem, erro
 func (w *Wallet) CreateBundle(dataItems *[]data_item.DataItem) (*bundle.Bundle, error) {
 	return bundle.New(dataItems)
 }
+
+// Close closes the wallet's client, stopping its background work and closing its idle connections, see client.Client.Close.
+func (w *Wallet) Close() error {
+	if w.Client == nil {
+		return nil
+	}
+	return w.Client.Close()
+}
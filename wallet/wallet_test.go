@@ -1,22 +1,27 @@
 package wallet
 
 import (
+	"bytes"
+	"strconv"
 	"testing"
 
+	"github.com/liteseed/goar"
 	"github.com/liteseed/goar/client"
 	"github.com/liteseed/goar/transaction"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func mint(t *testing.T, c *client.Client, address string) {
-	_, err := c.Client.Get(c.Gateway + "/mint/" + address + "/10000000000")
+	dev := &client.DevClient{Client: c}
+	_, err := dev.Mint(address, "10000000000")
 	assert.NoError(t, err)
 	mine(t, c)
 }
 
 func mine(t *testing.T, c *client.Client) {
-	_, err := c.Client.Get(c.Gateway + "/mine")
-	assert.NoError(t, err)
+	dev := &client.DevClient{Client: c}
+	assert.NoError(t, dev.Mine(1))
 }
 
 func createTransaction(t *testing.T, w *Wallet) *transaction.Transaction {
@@ -53,6 +58,32 @@ func TestSignTransaction(t *testing.T) {
 	})
 }
 
+// TestSignTransactionFromSource verifies the reward of a transaction whose data is kept in a reader
+func TestSignTransactionFromSource(t *testing.T) {
+	gateway, _, _ := newRoutingNetwork(t)
+	w, err := FromPath("../test/signer.json", gateway)
+	require.NoError(t, err)
+	data := bytes.Repeat([]byte{1}, 1000)
+
+	t.Run("Sign", func(t *testing.T) {
+		tx, err := transaction.NewDataTransactionFromReader(bytes.NewReader(data), int64(len(data)), nil)
+		require.NoError(t, err)
+		tx, err = w.SignTransaction(tx)
+		require.NoError(t, err)
+		assert.Equal(t, strconv.Itoa(100+10*len(data)), tx.Reward)
+	})
+
+	t.Run("Invalid data size", func(t *testing.T) {
+		for _, size := range []string{"", "1k", "-1"} {
+			tx, err := transaction.NewDataTransactionFromReader(bytes.NewReader(data), int64(len(data)), nil)
+			require.NoError(t, err)
+			tx.DataSize = size
+			_, err = w.SignTransaction(tx)
+			assert.ErrorIs(t, err, goar.ErrInvalidInput, size)
+		}
+	})
+}
+
 func TestSendTransaction(t *testing.T) {
 	w, err := FromPath("../test/signer.json", "http://localhost:1984")
 	assert.NoError(t, err)